@@ -0,0 +1,143 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"db_backend/db"
+	"db_backend/dto"
+	"db_backend/services"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+var (
+	Logger  = log.New(os.Stderr, "Backup:\t", log.LstdFlags)
+	migrate bool
+)
+
+func printTables(tables []dto.ArchiveTable) {
+	total := 0
+	for _, table := range tables {
+		Logger.Printf("%-32s %d rows", table.Name, table.Rows)
+		total += table.Rows
+	}
+	Logger.Printf("%d tables, %d rows", len(tables), total)
+}
+
+func exportTo(path string) ([]dto.ArchiveTable, error) {
+	var out io.Writer = os.Stdout
+	var file *os.File
+	if path != "-" {
+		var err error
+		file, err = os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		out = file
+	}
+	var zipped *gzip.Writer
+	if strings.HasSuffix(path, ".gz") {
+		zipped = gzip.NewWriter(out)
+		out = zipped
+	}
+
+	tables, err := services.ExportArchive(out)
+	if err != nil {
+		return nil, err
+	}
+	if zipped != nil {
+		err = zipped.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if file != nil {
+		err = file.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
+func restoreFrom(path string) ([]dto.ArchiveTable, error) {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		in = file
+	}
+	if strings.HasSuffix(path, ".gz") {
+		zipped, err := gzip.NewReader(in)
+		if err != nil {
+			return nil, err
+		}
+		defer zipped.Close()
+		in = zipped
+	}
+	return services.RestoreArchive(in)
+}
+
+// backup writes all club data to an NDJSON archive, gzipped when the file name
+// ends with .gz, or restores such an archive into an empty database. Tour
+// report attachments live in the blob directory and are not part of it, and
+// neither is service state: login sessions are not carried over, so everyone
+// gets a new token after a restore, and overdue alerts and statistics refresh
+// times start afresh.
+func main() {
+	flag.StringVar(&db.ConnString, "conn", "postgres://", "connection string to postgres")
+	flag.BoolVar(&migrate, "migrate", false, "apply pending schema migrations before restoring")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] export|restore file.ndjson[.gz]|-\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	command, path := flag.Arg(0), flag.Arg(1)
+
+	switch command {
+	case "export":
+		tables, err := exportTo(path)
+		if err != nil {
+			Logger.Fatal(err)
+		}
+		printTables(tables)
+	case "restore":
+		if migrate {
+			pg, err := db.NewPG(context.Background())
+			if err != nil {
+				Logger.Fatal(err)
+			}
+			applied, err := pg.Migrate(context.Background())
+			if err != nil {
+				Logger.Fatal(err)
+			}
+			for _, name := range applied {
+				Logger.Printf("applied migration %s", name)
+			}
+		}
+		tables, err := restoreFrom(path)
+		if err != nil {
+			Logger.Fatal(err)
+		}
+		printTables(tables)
+		err = services.RefreshStats()
+		if err != nil {
+			Logger.Printf("unable to refresh statistics: %v", err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"db_backend/db"
+	"db_backend/services"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+var (
+	Logger = log.New(os.Stderr, "Import:\t", log.LstdFlags)
+	dryRun bool
+)
+
+// import reads new members from a CSV or XLSX spreadsheet, the same way
+// POST /persons/import does.
+func main() {
+	flag.StringVar(&db.ConnString, "conn", "postgres://", "connection string to postgres")
+	flag.BoolVar(&dryRun, "dry-run", false, "validate and apply the rows, then roll everything back")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] file.csv|file.xlsx\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		Logger.Fatal(err)
+	}
+	if len(data) > services.MaxImportSize {
+		Logger.Fatalf("%s is larger than %d bytes", flag.Arg(0), services.MaxImportSize)
+	}
+
+	report, err := services.ImportPersons(data, dryRun)
+	if err != nil {
+		Logger.Fatal(err)
+	}
+	for _, warning := range report.Warnings {
+		fmt.Printf("row %d: warning: %s\n", warning.Row, warning.Error)
+	}
+	for _, problem := range report.Errors {
+		if problem.Column != "" {
+			fmt.Printf("row %d: %s: %s\n", problem.Row, problem.Column, problem.Error)
+		} else {
+			fmt.Printf("row %d: %s\n", problem.Row, problem.Error)
+		}
+	}
+	if len(report.Errors) > 0 {
+		fmt.Printf("%d rows read, %d errors, nothing imported\n", report.Rows, len(report.Errors))
+		os.Exit(1)
+	}
+	if dryRun {
+		fmt.Printf("%d rows read, all valid, nothing imported (dry run)\n", report.Rows)
+		return
+	}
+	fmt.Printf("%d rows read, %d persons imported\n", report.Rows, report.Imported)
+}
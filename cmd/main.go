@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
+	"db_backend/blobstore"
 	"db_backend/db"
 	"db_backend/handlers"
+	"db_backend/notify"
+	"db_backend/repository"
+	"db_backend/services"
+	"db_backend/utils"
 	"flag"
 	"fmt"
 	gorillahandlers "github.com/gorilla/handlers"
@@ -10,19 +16,85 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 )
 
 var (
-	Logger     = log.New(os.Stdout, "Server:\t", log.LstdFlags)
-	listenPort string
+	Logger       = log.New(os.Stdout, "Server:\t", log.LstdFlags)
+	listenPort   string
+	migrate      bool
+	blockDebtors bool
+	healthKey    string
+	blobDir      string
+	issueToken   int
+
+	notifyTarget    string
+	overdueInterval time.Duration
+	statsInterval   time.Duration
 )
 
 func main() {
 	//flags
 	flag.StringVar(&listenPort, "port", "8080", "server's port")
 	flag.StringVar(&db.ConnString, "conn", "postgres://", "connection string to postgres")
+	flag.BoolVar(&migrate, "migrate", false, "apply pending schema migrations before start")
+	flag.BoolVar(&blockDebtors, "block-debtors", false, "forbid tour enrollment and championship registration for members with unpaid fees")
+	flag.StringVar(&healthKey, "health-key", os.Getenv("HEALTH_KEY"), "secret for health record encryption, defaults to $HEALTH_KEY")
+	flag.StringVar(&notifyTarget, "notify", "log", "where overdue alerts go: log, a webhook URL or smtp://host:port?from=...&to=...")
+	flag.DurationVar(&services.OverdueGrace, "overdue-grace", services.OverdueGrace, "how long a tour may stay out after its last day")
+	flag.IntVar(&issueToken, "issue-token", 0, "print a new bearer token for the person with this id and exit")
+	flag.IntVar(&services.TokenDays, "token-days", services.TokenDays, "how many days issued tokens stay valid")
+	flag.StringVar(&blobDir, "blob-dir", "blobs", "directory for tour report attachments")
+	flag.DurationVar(&overdueInterval, "overdue-interval", 15*time.Minute, "how often to look for overdue tours, 0 disables the watcher")
+	flag.DurationVar(&statsInterval, "stats-interval", time.Hour, "how often to refresh the statistics summaries, 0 disables it")
 	flag.Parse()
 
+	err := utils.SetEncryptionKey(healthKey)
+	if err != nil {
+		Logger.Fatal(err)
+	}
+
+	services.Blobs, err = blobstore.NewLocalStore(blobDir)
+	if err != nil {
+		Logger.Fatal(err)
+	}
+
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		Logger.Fatal(err)
+	}
+	club := handlers.NewClub(services.NewClub(repository.NewPostgres(pg), blockDebtors))
+
+	if migrate {
+		applied, err := pg.Migrate(context.Background())
+		if err != nil {
+			Logger.Fatal(err)
+		}
+		for _, name := range applied {
+			Logger.Printf("applied migration %s", name)
+		}
+	}
+
+	if issueToken > 0 {
+		token, err := services.IssueToken(issueToken, services.TokenDays)
+		if err != nil {
+			Logger.Fatal(err)
+		}
+		fmt.Printf("%s\t%s\n", token.Token, token.ExpiresAt)
+		return
+	}
+
+	if overdueInterval > 0 {
+		notifier, err := notify.New(notifyTarget, Logger)
+		if err != nil {
+			Logger.Fatal(err)
+		}
+		go services.WatchOverdueTours(context.Background(), notifier, overdueInterval, Logger)
+	}
+	if statsInterval > 0 {
+		go services.WatchStats(context.Background(), statsInterval, Logger)
+	}
+
 	//tests
 
 	r := mux.NewRouter()
@@ -30,7 +102,7 @@ func main() {
 	corsOptions := []gorillahandlers.CORSOption{
 		gorillahandlers.AllowedOrigins([]string{"*", "null"}), // Добавьте "null"
 		gorillahandlers.AllowedMethods([]string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
-		gorillahandlers.AllowedHeaders([]string{"Content-Type", "Authorization", "From"}),
+		gorillahandlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
 		gorillahandlers.AllowCredentials(),
 	}
 	h := gorillahandlers.CORS(corsOptions...)(r)
@@ -41,70 +113,183 @@ func main() {
 			next.ServeHTTP(w, r)
 		})
 	})
+	r.Use(handlers.Authenticate)
+
+	r.HandleFunc("/auth/tokens", handlers.IssueToken).Methods("POST")
+	r.HandleFunc("/auth/tokens", handlers.RevokeToken).Methods("DELETE")
+
+	r.HandleFunc("/persons/create", club.CreatePerson).Methods("POST")
+	r.HandleFunc("/persons/import", handlers.ImportPersons).Methods("POST")
+	r.HandleFunc("/tourists/filter", club.FindTourists).Methods("GET")
+	r.HandleFunc("/trainers/filter", club.FindTrainers).Methods("GET")
+	r.HandleFunc("/managers/filter", club.FindManagers).Methods("GET")
+	r.HandleFunc("/championships/filter", club.FindChampionships).Methods("GET")
+	r.HandleFunc("/championships/register", club.RegisterForChampionship).Methods("POST")
+	r.HandleFunc("/championships/register", club.UnregisterFromChampionship).Methods("DELETE")
+	r.HandleFunc("/trainers/workout-filter", club.FindTrainersByWorkouts).Methods("GET")
+	r.HandleFunc("/workouts/strain", club.GetStrain).Methods("GET")
+	r.HandleFunc("/workouts/strain/report", club.GetStrainReport).Methods("GET")
+	r.HandleFunc("/workouts/attendance", handlers.GetWorkoutAttendance).Methods("GET")
+	r.HandleFunc("/workouts/attendance", handlers.MarkAttendance).Methods("POST")
+	r.HandleFunc("/workouts/attendance/group", handlers.MarkGroupAttendance).Methods("POST")
+	r.HandleFunc("/workouts/attendance/group", handlers.GetGroupAttendance).Methods("GET")
+	r.HandleFunc("/workouts/attendance/person", handlers.GetPersonAttendance).Methods("GET")
+	r.HandleFunc("/workouts/attendance/low", handlers.GetLowAttendance).Methods("GET")
+
+	r.HandleFunc("/payroll/rates", handlers.GetPayrollRates).Methods("GET")
+	r.HandleFunc("/payroll/rates", handlers.CreatePayrollRate).Methods("POST")
+	r.HandleFunc("/payroll/rates", handlers.DeletePayrollRate).Methods("DELETE")
+	r.HandleFunc("/payroll/report", handlers.GetPayrollReport).Methods("GET")
+	r.HandleFunc("/payroll/close", handlers.ClosePayPeriod).Methods("POST")
+	r.HandleFunc("/payroll/periods", handlers.GetPayPeriods).Methods("GET")
+
+	r.HandleFunc("/plans", handlers.GetTrainingPlans).Methods("GET")
+	r.HandleFunc("/plans", handlers.CreateTrainingPlan).Methods("POST")
+	r.HandleFunc("/plans/plan", handlers.GetTrainingPlan).Methods("GET")
+	r.HandleFunc("/plans/plan", handlers.DeleteTrainingPlan).Methods("DELETE")
+	r.HandleFunc("/plans/cycles", handlers.CreateTrainingCycle).Methods("POST")
+	r.HandleFunc("/plans/cycles", handlers.DeleteTrainingCycle).Methods("DELETE")
+	r.HandleFunc("/plans/targets", handlers.SetCycleTargets).Methods("PUT")
+	r.HandleFunc("/plans/compare", handlers.ComparePlan).Methods("GET")
+
+	r.HandleFunc("/stats/overview", handlers.GetStatsOverview).Methods("GET")
+	r.HandleFunc("/stats/refresh", handlers.RefreshStats).Methods("POST")
+
+	r.HandleFunc("/fitness/tests", handlers.GetFitnessTests).Methods("GET")
+	r.HandleFunc("/fitness/tests", handlers.CreateFitnessTest).Methods("POST")
+	r.HandleFunc("/fitness/tests", handlers.DeleteFitnessTest).Methods("DELETE")
+	r.HandleFunc("/fitness/results", handlers.GetFitnessResults).Methods("GET")
+	r.HandleFunc("/fitness/results", handlers.AddFitnessResult).Methods("POST")
+	r.HandleFunc("/fitness/results", handlers.DeleteFitnessResult).Methods("DELETE")
+	r.HandleFunc("/fitness/bests", handlers.GetPersonalBests).Methods("GET")
+	r.HandleFunc("/fitness/ranking", handlers.GetFitnessRanking).Methods("GET")
+
+	r.HandleFunc("/tourists/tour-filter", club.FindTouristsByTour).Methods("GET")
+	r.HandleFunc("/routes/filter", club.FindRoutes).Methods("GET")
+	r.HandleFunc("/routes/geofilter", club.FindRoutesWithGeo).Methods("GET")
+	r.HandleFunc("/instructors/filter", club.FindInstructors).Methods("GET")
+	r.HandleFunc("/tourists/trainer-instructor", club.FindTouristsWithTrainerInstructor).Methods("GET")
+	r.HandleFunc("/tourists/completed-all", club.FindTouristsCompletedAll).Methods("GET")
+	r.HandleFunc("/tourists/completed", club.FindTouristsCompletedRoutes).Methods("GET")
+	r.HandleFunc("/tourists/route-filter", club.GetTouristsByTour).Methods("GET")
+
+	r.HandleFunc("/persons/health", handlers.GetHealthRecord).Methods("GET")
+	r.HandleFunc("/persons/health", handlers.SetHealthRecord).Methods("PUT")
+	r.HandleFunc("/persons/health", handlers.DeleteHealthRecord).Methods("DELETE")
+	r.HandleFunc("/persons/health/alerts", handlers.GetClearanceAlerts).Methods("GET")
+	r.HandleFunc("/persons/contacts", handlers.GetEmergencyContacts).Methods("GET")
+	r.HandleFunc("/persons/contacts", handlers.AddEmergencyContact).Methods("POST")
+	r.HandleFunc("/persons/contacts", handlers.DeleteEmergencyContact).Methods("DELETE")
+	r.HandleFunc("/persons/{id:[0-9]+}/recommended-routes", handlers.GetRecommendedRoutes).Methods("GET")
+	r.HandleFunc("/tours/{id:[0-9]+}/roster", handlers.GetTourRoster).Methods("GET")
+	r.HandleFunc("/tours/checkins", handlers.GetTourCheckins).Methods("GET")
+	r.HandleFunc("/tours/checkins", handlers.AddTourCheckin).Methods("POST")
+	r.HandleFunc("/tours/overdue", handlers.GetOverdueTours).Methods("GET")
+	r.HandleFunc("/tours/itinerary", handlers.GetTourItinerary).Methods("GET")
+	r.HandleFunc("/tours/itinerary", handlers.SetTourItinerary).Methods("PUT")
+	r.HandleFunc("/tours/itinerary/validate", handlers.ValidateTourItinerary).Methods("POST")
+	r.HandleFunc("/tours/positions", handlers.GetGroupPositions).Methods("GET")
+	r.HandleFunc("/tours/report", handlers.GetTourReport).Methods("GET")
+	r.HandleFunc("/tours/report", handlers.SaveTourReport).Methods("PUT")
+	r.HandleFunc("/tours/report", handlers.DeleteTourReport).Methods("DELETE")
+	r.HandleFunc("/tours/report/attachments", handlers.AddReportAttachment).Methods("POST")
+	r.HandleFunc("/tours/report/attachments", handlers.DeleteReportAttachment).Methods("DELETE")
+	r.HandleFunc("/reports/attachments/{id:[0-9]+}", handlers.GetReportAttachment).Methods("GET")
+	r.HandleFunc("/reports/attachments/{id:[0-9]+}/thumbnail", handlers.GetReportAttachmentThumbnail).Methods("GET")
+	r.HandleFunc("/routes/{id:[0-9]+}/reports", handlers.GetRouteReports).Methods("GET")
+
+	r.HandleFunc("/persons/roles", club.GetPersonRole).Methods("GET")
+	r.HandleFunc("/persons/roles", club.SetPersonRole).Methods("POST")
+	r.HandleFunc("/persons/roles", club.DeletePersonRole).Methods("DELETE")
+
+	r.HandleFunc("/roles/list", club.GetAllRoles).Methods("GET")
+
+	r.HandleFunc("/persons/attribute/int", club.GetPersonIntAttribute).Methods("GET")
+	r.HandleFunc("/persons/attribute/int", club.SetPersonIntAttribute).Methods("POST")
+	r.HandleFunc("/persons/attribute/int", club.DeletePersonIntAttribute).Methods("DELETE")
+
+	r.HandleFunc("/persons/attribute/float", club.GetPersonFloatAttribute).Methods("GET")
+	r.HandleFunc("/persons/attribute/float", club.SetPersonFloatAttribute).Methods("POST")
+	r.HandleFunc("/persons/attribute/float", club.DeletePersonFloatAttribute).Methods("DELETE")
+
+	r.HandleFunc("/persons/attribute/string", club.GetPersonStringAttribute).Methods("GET")
+	r.HandleFunc("/persons/attribute/string", club.SetPersonStringAttribute).Methods("POST")
+	r.HandleFunc("/persons/attribute/string", club.DeletePersonStringAttribute).Methods("DELETE")
+
+	r.HandleFunc("/persons/attribute/date", club.GetPersonDateAttribute).Methods("GET")
+	r.HandleFunc("/persons/attribute/date", club.SetPersonDateAttribute).Methods("POST")
+	r.HandleFunc("/persons/attribute/date", club.DeletePersonDateAttribute).Methods("DELETE")
+
+	r.HandleFunc("/person-attributes/attribute", club.CreatePersonAttribute).Methods("POST")
+	r.HandleFunc("/person-attributes/attribute", club.GetPersonAttribute).Methods("GET")
+	r.HandleFunc("/person-attributes/attribute", club.SetPersonAttribute).Methods("PATCH")
+	r.HandleFunc("/person-attributes/attribute", club.DeletePersonAttribute).Methods("DELETE")
+	r.HandleFunc("/person-attributes/list", club.GetAllPersonAttributes).Methods("GET")
+
+	r.HandleFunc("/groups/group", club.CreateGroup).Methods("POST")
+	r.HandleFunc("/groups/group", club.GetGroup).Methods("GET")
+	r.HandleFunc("/groups/group", club.UpdateGroup).Methods("PATCH")
+	r.HandleFunc("/groups/group", club.DeleteGroup).Methods("DELETE")
+
+	r.HandleFunc("/groups/members", club.GetGroupMembers).Methods("GET")
+	r.HandleFunc("/groups/members/add", club.AddGroupMember).Methods("POST")
+	r.HandleFunc("/groups/members/remove", club.RemoveGroupMember).Methods("DELETE")
+
+	r.HandleFunc("/groups/list", club.GetAllGroups).Methods("GET")
+	r.HandleFunc("/groups/suggest", club.SuggestGroup).Methods("GET")
+	r.HandleFunc("/groups/rebalance", club.GetGroupRebalanceReport).Methods("GET")
+
+	r.HandleFunc("/sections/section", club.GetSection).Methods("GET")
+	r.HandleFunc("/sections/section", club.CreateSection).Methods("POST")
+	r.HandleFunc("/sections/section", club.UpdateSection).Methods("PATCH")
+	r.HandleFunc("/sections/section", club.DeleteSection).Methods("DELETE")
+	r.HandleFunc("/sections/list", club.GetAllSections).Methods("GET")
+	r.HandleFunc("/sections/groups", club.GetGroupsFromSections).Methods("GET")
+
+	r.HandleFunc("/routes/types", club.GetAllRouteTypes).Methods("GET")
+	r.HandleFunc("/routes/route", handlers.GetRoute).Methods("GET")
+	r.HandleFunc("/routes/places", handlers.SetRoutePlaceOrder).Methods("PUT")
+	r.HandleFunc("/routes/reviews", handlers.GetRouteReviews).Methods("GET")
+	r.HandleFunc("/routes/reviews", handlers.SaveRouteReview).Methods("PUT")
+	r.HandleFunc("/routes/reviews", handlers.DeleteRouteReview).Methods("DELETE")
+
+	r.HandleFunc("/tours/tour", handlers.GetTour).Methods("GET")
+	r.HandleFunc("/tours/status", handlers.ChangeTourStatus).Methods("POST")
+	r.HandleFunc("/tours/outcome", handlers.SetParticipantOutcome).Methods("POST")
+	r.HandleFunc("/tours/capacity", handlers.SetTourCapacity).Methods("PATCH")
+	r.HandleFunc("/tours/participants", handlers.GetTourParticipants).Methods("GET")
+	r.HandleFunc("/tours/enroll", club.EnrollTourist).Methods("POST")
+	r.HandleFunc("/tours/cancel", handlers.CancelTourEnrollment).Methods("POST")
+	r.HandleFunc("/tours/enrollment/close", handlers.CloseTourEnrollment).Methods("POST")
+	r.HandleFunc("/tours/enrollment/open", handlers.OpenTourEnrollment).Methods("POST")
+	r.HandleFunc("/tours/at-risk", handlers.GetToursAtRisk).Methods("GET")
+	r.HandleFunc("/tours/planner", club.PlanTourGroups).Methods("POST")
+	r.HandleFunc("/tours/planner", handlers.GetPlanDraft).Methods("GET")
+	r.HandleFunc("/tours/planner", handlers.DiscardPlanDraft).Methods("DELETE")
+	r.HandleFunc("/tours/planner/accept", club.AcceptPlanDraft).Methods("POST")
+
+	r.HandleFunc("/tours/expenses", handlers.GetTourExpenses).Methods("GET")
+	r.HandleFunc("/tours/expenses", handlers.AddTourExpense).Methods("POST")
+	r.HandleFunc("/tours/expenses", handlers.DeleteTourExpense).Methods("DELETE")
+	r.HandleFunc("/tours/settlement", handlers.GetTourSettlement).Methods("GET")
+	r.HandleFunc("/sections/expenses", handlers.GetSectionExpenseSummary).Methods("GET")
+
+	r.HandleFunc("/fees/schedule", handlers.GetMembershipFees).Methods("GET")
+	r.HandleFunc("/fees/schedule", handlers.CreateMembershipFee).Methods("POST")
+	r.HandleFunc("/fees/schedule", handlers.DeleteMembershipFee).Methods("DELETE")
+	r.HandleFunc("/fees/payments", handlers.GetMembershipPayments).Methods("GET")
+	r.HandleFunc("/fees/payments", handlers.AddMembershipPayment).Methods("POST")
+	r.HandleFunc("/fees/arrears", handlers.GetArrears).Methods("GET")
 
-	r.HandleFunc("/persons/create", handlers.CreatePerson).Methods("POST")
-	r.HandleFunc("/tourists/filter", handlers.FindTourists).Methods("GET")
-	r.HandleFunc("/trainers/filter", handlers.FindTrainers).Methods("GET")
-	r.HandleFunc("/managers/filter", handlers.FindManagers).Methods("GET")
-	r.HandleFunc("/championships/filter", handlers.FindChampionships).Methods("GET")
-	r.HandleFunc("/trainers/workout-filter", handlers.FindTrainersByWorkouts).Methods("GET")
-	r.HandleFunc("/workouts/strain", handlers.GetStrain).Methods("GET")
-	r.HandleFunc("/tourists/tour-filter", handlers.FindTouristsByTour).Methods("GET")
-	r.HandleFunc("/routes/filter", handlers.FindRoutes).Methods("GET")
-	r.HandleFunc("/routes/geofilter", handlers.FindRoutesWithGeo).Methods("GET")
-	r.HandleFunc("/instructors/filter", handlers.FindInstructors).Methods("GET")
-	r.HandleFunc("/tourists/trainer-instructor", handlers.FindTouristsWithTrainerInstructor).Methods("GET")
-	r.HandleFunc("/tourists/completed-all", handlers.FindTouristsCompletedAll).Methods("GET")
-	r.HandleFunc("/tourists/completed", handlers.FindTouristsCompletedRoutes).Methods("GET")
-	r.HandleFunc("/tourists/route-filter", handlers.GetTouristsByTour).Methods("GET")
-
-	r.HandleFunc("/persons/roles", handlers.GetPersonRole).Methods("GET")
-	r.HandleFunc("/persons/roles", handlers.SetPersonRole).Methods("POST")
-	r.HandleFunc("/persons/roles", handlers.DeletePersonRole).Methods("DELETE")
-
-	r.HandleFunc("/roles/list", handlers.GetAllRoles).Methods("GET")
-
-	r.HandleFunc("/persons/attribute/int", handlers.GetPersonIntAttribute).Methods("GET")
-	r.HandleFunc("/persons/attribute/int", handlers.SetPersonIntAttribute).Methods("POST")
-	r.HandleFunc("/persons/attribute/int", handlers.DeletePersonIntAttribute).Methods("DELETE")
-
-	r.HandleFunc("/persons/attribute/float", handlers.GetPersonFloatAttribute).Methods("GET")
-	r.HandleFunc("/persons/attribute/float", handlers.SetPersonFloatAttribute).Methods("POST")
-	r.HandleFunc("/persons/attribute/float", handlers.DeletePersonFloatAttribute).Methods("DELETE")
-
-	r.HandleFunc("/persons/attribute/string", handlers.GetPersonStringAttribute).Methods("GET")
-	r.HandleFunc("/persons/attribute/string", handlers.SetPersonStringAttribute).Methods("POST")
-	r.HandleFunc("/persons/attribute/string", handlers.DeletePersonStringAttribute).Methods("DELETE")
-
-	r.HandleFunc("/persons/attribute/date", handlers.GetPersonDateAttribute).Methods("GET")
-	r.HandleFunc("/persons/attribute/date", handlers.SetPersonDateAttribute).Methods("POST")
-	r.HandleFunc("/persons/attribute/date", handlers.DeletePersonDateAttribute).Methods("DELETE")
-
-	r.HandleFunc("/person-attributes/attribute", handlers.CreatePersonAttribute).Methods("POST")
-	r.HandleFunc("/person-attributes/attribute", handlers.GetPersonAttribute).Methods("GET")
-	r.HandleFunc("/person-attributes/attribute", handlers.SetPersonAttribute).Methods("PATCH")
-	r.HandleFunc("/person-attributes/attribute", handlers.DeletePersonAttribute).Methods("DELETE")
-	r.HandleFunc("/person-attributes/list", handlers.GetAllPersonAttributes).Methods("GET")
-
-	r.HandleFunc("/groups/group", handlers.CreateGroup).Methods("POST")
-	r.HandleFunc("/groups/group", handlers.GetGroup).Methods("GET")
-	r.HandleFunc("/groups/group", handlers.UpdateGroup).Methods("PATCH")
-	r.HandleFunc("/groups/group", handlers.DeleteGroup).Methods("DELETE")
-
-	r.HandleFunc("/groups/members", handlers.GetGroupMembers).Methods("GET")
-	r.HandleFunc("/groups/members/add", handlers.AddGroupMember).Methods("POST")
-	r.HandleFunc("/groups/members/remove", handlers.RemoveGroupMember).Methods("DELETE")
-
-	r.HandleFunc("/groups/list", handlers.GetAllGroups).Methods("GET")
-
-	r.HandleFunc("/sections/section", handlers.GetSection).Methods("GET")
-	r.HandleFunc("/sections/section", handlers.CreateSection).Methods("POST")
-	r.HandleFunc("/sections/section", handlers.UpdateSection).Methods("PATCH")
-	r.HandleFunc("/sections/section", handlers.DeleteSection).Methods("DELETE")
-	r.HandleFunc("/sections/list", handlers.GetAllSections).Methods("GET")
-	r.HandleFunc("/sections/groups", handlers.GetGroupsFromSections).Methods("GET")
-
-	r.HandleFunc("/routes/types", handlers.GetAllRouteTypes).Methods("GET")
+	r.HandleFunc("/equipment/item", handlers.CreateEquipment).Methods("POST")
+	r.HandleFunc("/equipment/item", handlers.GetEquipment).Methods("GET")
+	r.HandleFunc("/equipment/item", handlers.UpdateEquipment).Methods("PATCH")
+	r.HandleFunc("/equipment/item", handlers.DeleteEquipment).Methods("DELETE")
+	r.HandleFunc("/equipment/list", handlers.GetEquipmentList).Methods("GET")
+	r.HandleFunc("/equipment/checkout", handlers.CheckoutEquipment).Methods("POST")
+	r.HandleFunc("/equipment/checkin", handlers.CheckinEquipment).Methods("POST")
+	r.HandleFunc("/equipment/checkouts", handlers.GetEquipmentCheckouts).Methods("GET")
+	r.HandleFunc("/equipment/availability", handlers.GetEquipmentAvailability).Methods("GET")
 
 	//listen
 	addr := fmt.Sprintf(":%s", listenPort)
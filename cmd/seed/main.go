@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/services"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+var (
+	Logger  = log.New(os.Stderr, "Seed:\t", log.LstdFlags)
+	seed    uint64
+	scale   int
+	today   string
+	migrate bool
+)
+
+// seed fills an empty database with a synthetic club. The same seed, scale and
+// today always give the same data, so a query misbehaving on it can be
+// reproduced elsewhere.
+func main() {
+	flag.StringVar(&db.ConnString, "conn", "postgres://", "connection string to postgres")
+	flag.Uint64Var(&seed, "seed", 1, "seed of the random generator")
+	flag.IntVar(&scale, "scale", 3, "number of sections; places, routes, tours and championships grow with it")
+	flag.StringVar(&today, "today", "2025-09-01", "date the generated history leads up to, YYYY-MM-DD")
+	flag.BoolVar(&migrate, "migrate", false, "apply pending schema migrations before seeding")
+	flag.Parse()
+
+	day, err := time.Parse("2006-01-02", today)
+	if err != nil {
+		Logger.Fatalf("invalid -today: %v", err)
+	}
+
+	if migrate {
+		pg, err := db.NewPG(context.Background())
+		if err != nil {
+			Logger.Fatal(err)
+		}
+		applied, err := pg.Migrate(context.Background())
+		if err != nil {
+			Logger.Fatal(err)
+		}
+		for _, name := range applied {
+			Logger.Printf("applied migration %s", name)
+		}
+	}
+
+	summary, err := services.SeedClub(seed, scale, day)
+	if err != nil {
+		Logger.Fatal(err)
+	}
+	err = services.RefreshStats()
+	if err != nil {
+		Logger.Printf("unable to refresh statistics: %v", err)
+	}
+
+	fmt.Printf("seed %d, scale %d, today %s\n", summary.Seed, summary.Scale, today)
+	fmt.Printf("%d sections, %d groups, %d persons\n", summary.Sections, summary.Groups, summary.Persons)
+	fmt.Printf("%d places, %d routes, %d tours with %d participants\n", summary.Places, summary.Routes, summary.Tours, summary.Participants)
+	fmt.Printf("%d workouts with %d attendance marks\n", summary.Workouts, summary.Attendance)
+	fmt.Printf("%d championships with %d registrations\n", summary.Championships, summary.Registrations)
+}
@@ -3,12 +3,24 @@ package db
 import (
 	"context"
 	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"sync"
 )
 
+// DBTX is implemented by both the connection pool and a transaction,
+// so queries work the same way inside and outside of InTx.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
 type Postgres struct {
-	Db *pgxpool.Pool
+	Db   DBTX
+	pool *pgxpool.Pool
 }
 
 var (
@@ -26,7 +38,7 @@ func NewPG(ctx context.Context) (*Postgres, error) {
 			return
 		}
 
-		pgInstance = &Postgres{db}
+		pgInstance = &Postgres{db, db}
 	})
 
 	if pgErr != nil {
@@ -36,10 +48,29 @@ func NewPG(ctx context.Context) (*Postgres, error) {
 	return pgInstance, nil
 }
 
+// InTx runs fn inside a transaction and commits it if fn returns nil.
+// Calling InTx on a Postgres that is already in a transaction creates a savepoint.
+func (pg *Postgres) InTx(ctx context.Context, fn func(tx *Postgres) error) error {
+	tx, err := pg.Db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+
+	if err := fn(&Postgres{tx, pg.pool}); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+	return nil
+}
+
 func (pg *Postgres) Ping(ctx context.Context) error {
-	return pg.Db.Ping(ctx)
+	return pg.pool.Ping(ctx)
 }
 
 func (pg *Postgres) Close() {
-	pg.Db.Close()
+	pg.pool.Close()
 }
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrate applies the schema changes from db/migrations that are not yet
+// recorded in schema_migrations, in file name order.
+func (pg *Postgres) Migrate(ctx context.Context) ([]string, error) {
+	query := `create table if not exists schema_migrations (
+			      name       varchar(255) primary key,
+			      applied_at timestamp not null default now()
+			  )`
+	_, err := pg.Db.Exec(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create schema_migrations: %w", err)
+	}
+
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	var applied []string
+	for _, name := range names {
+		var done bool
+		err = pg.Db.QueryRow(ctx, `select exists(select 1 from schema_migrations where name = $1)`, name).Scan(&done)
+		if err != nil {
+			return applied, fmt.Errorf("unable to check migration %s: %w", name, err)
+		}
+		if done {
+			continue
+		}
+
+		script, err := migrations.ReadFile(name)
+		if err != nil {
+			return applied, err
+		}
+
+		err = pg.InTx(ctx, func(tx *Postgres) error {
+			if _, err := tx.Db.Exec(ctx, string(script)); err != nil {
+				return err
+			}
+			_, err := tx.Db.Exec(ctx, `insert into schema_migrations (name) values ($1)`, name)
+			return err
+		})
+		if err != nil {
+			return applied, fmt.Errorf("unable to apply migration %s: %w", name, err)
+		}
+		applied = append(applied, name)
+	}
+	return applied, nil
+}
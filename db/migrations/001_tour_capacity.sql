@@ -0,0 +1,15 @@
+alter table tours
+    add column min_participants  integer check (min_participants >= 0),
+    add column max_participants  integer check (max_participants > 0),
+    add column enrollment_closed boolean not null default false,
+    add column at_risk           boolean not null default false;
+
+alter table persons_tours
+    add column status       varchar(16) not null default 'enrolled'
+        check (status in ('enrolled', 'waitlisted', 'cancelled')),
+    add column requested_at timestamp   not null default now(),
+    add column cancelled_at timestamp;
+
+create index persons_tours_waitlist_idx
+    on persons_tours (tour, requested_at)
+    where status = 'waitlisted';
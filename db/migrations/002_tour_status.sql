@@ -0,0 +1,28 @@
+alter table tours
+    add column status      varchar(16) not null default 'planned'
+        check (status in ('planned', 'active', 'completed', 'cancelled')),
+    add column started_on  date,
+    add column finished_on date;
+
+alter table persons_tours
+    add column outcome      varchar(16)
+        check (outcome in ('completed', 'withdrew', 'evacuated')),
+    add column outcome_date date;
+
+-- tours that already took place keep counting as completed routes
+update tours
+set status      = 'completed',
+    started_on  = start,
+    finished_on = start + duration_days - 1
+where start + duration_days <= current_date;
+
+update tours
+set status     = 'active',
+    started_on = start
+where start <= current_date and start + duration_days > current_date;
+
+update persons_tours
+set outcome      = 'completed',
+    outcome_date = tours.finished_on
+from tours
+where tours.id = persons_tours.tour and tours.status = 'completed' and persons_tours.status = 'enrolled';
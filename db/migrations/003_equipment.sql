@@ -0,0 +1,27 @@
+create table equipment (
+    id        serial primary key,
+    title     varchar(255) not null,
+    category  varchar(64)  not null,
+    quantity  integer      not null check (quantity >= 0),
+    condition varchar(16)  not null default 'good'
+        check (condition in ('new', 'good', 'worn', 'damaged')),
+    section   integer references sections (id) on delete set null,
+    notes     text         not null default ''
+);
+
+create table equipment_checkouts (
+    id             serial primary key,
+    equipment      integer not null references equipment (id) on delete cascade,
+    quantity       integer not null check (quantity > 0),
+    tour           integer references tours (id) on delete cascade,
+    person         integer references persons (id) on delete cascade,
+    checked_out_on date    not null default current_date,
+    due_on         date,
+    checked_in_on  date,
+    condition_in   varchar(16)
+        check (condition_in in ('new', 'good', 'worn', 'damaged', 'written_off')),
+    report         text,
+    check ((tour is null) <> (person is null))
+);
+
+create index equipment_checkouts_open_idx on equipment_checkouts (equipment) where checked_in_on is null;
@@ -0,0 +1,20 @@
+create table tour_expenses (
+    id          serial primary key,
+    tour        integer        not null references tours (id) on delete cascade,
+    payer       integer        not null references persons (id),
+    category    varchar(16)    not null
+        check (category in ('transport', 'food', 'permits', 'gear_rental', 'other')),
+    amount      numeric(12, 2) not null check (amount > 0),
+    description text           not null default '',
+    spent_on    date           not null default current_date
+);
+
+create table tour_expense_shares (
+    expense integer        not null references tour_expenses (id) on delete cascade,
+    person  integer        not null references persons (id),
+    weight  numeric(8, 3)  not null check (weight > 0),
+    amount  numeric(12, 2) not null,
+    primary key (expense, person)
+);
+
+create index tour_expenses_tour_idx on tour_expenses (tour);
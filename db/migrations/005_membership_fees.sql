@@ -0,0 +1,24 @@
+-- members who joined before this migration keep an unknown join date
+alter table persons_roles add column joined_on date;
+alter table persons_roles alter column joined_on set default current_date;
+
+create table membership_fees (
+    id      serial primary key,
+    section integer        not null references sections (id) on delete cascade,
+    role    integer        not null,
+    season  varchar(16)    not null,
+    amount  numeric(12, 2) not null check (amount >= 0),
+    due_on  date           not null,
+    unique (section, role, season)
+);
+
+create table membership_payments (
+    id      serial primary key,
+    person  integer        not null references persons (id) on delete cascade,
+    fee     integer        not null references membership_fees (id) on delete cascade,
+    amount  numeric(12, 2) not null check (amount > 0),
+    paid_on date           not null default current_date,
+    note    text           not null default ''
+);
+
+create index membership_payments_person_idx on membership_payments (person, fee);
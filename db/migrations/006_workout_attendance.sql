@@ -0,0 +1,14 @@
+alter table workouts add column if not exists id serial;
+create unique index if not exists workouts_id_idx on workouts (id);
+
+create table workout_attendance (
+    workout   integer     not null references workouts (id) on delete cascade,
+    person    integer     not null references persons (id) on delete cascade,
+    status    varchar(16) not null check (status in ('present', 'absent', 'excused', 'late')),
+    marked_by integer references persons (id) on delete set null,
+    marked_at timestamp   not null default now(),
+    note      text        not null default '',
+    primary key (workout, person)
+);
+
+create index workout_attendance_person_idx on workout_attendance (person);
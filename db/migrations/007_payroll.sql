@@ -0,0 +1,27 @@
+create table payroll_rates (
+    id           serial primary key,
+    trainer      integer references persons (id) on delete cascade,
+    workout_type varchar(255),
+    hourly_rate  numeric(12, 2) not null check (hourly_rate >= 0)
+);
+
+create unique index payroll_rates_key_idx on payroll_rates (coalesce(trainer, 0), coalesce(workout_type, ''));
+
+create table pay_periods (
+    id        serial primary key,
+    section   integer        not null references sections (id) on delete cascade,
+    month     date           not null,
+    closed_at timestamp      not null default now(),
+    total     numeric(12, 2) not null,
+    unique (section, month)
+);
+
+create table payroll_entries (
+    period       integer        not null references pay_periods (id) on delete cascade,
+    trainer      integer        not null references persons (id) on delete cascade,
+    workout_type varchar(255)   not null,
+    minutes      integer        not null,
+    rate         numeric(12, 2),
+    amount       numeric(12, 2) not null,
+    primary key (period, trainer, workout_type)
+);
@@ -0,0 +1,27 @@
+create table training_plans (
+    id        serial primary key,
+    group_id  integer      not null references groups (id) on delete cascade,
+    title     varchar(255) not null,
+    starts_on date         not null,
+    ends_on   date         not null,
+    check (ends_on >= starts_on)
+);
+
+create table training_cycles (
+    id        serial primary key,
+    plan      integer      not null references training_plans (id) on delete cascade,
+    parent    integer references training_cycles (id) on delete cascade,
+    kind      varchar(8)   not null check (kind in ('meso', 'micro')),
+    title     varchar(255) not null default '',
+    starts_on date         not null,
+    ends_on   date         not null,
+    check (ends_on >= starts_on),
+    check ((kind = 'meso') = (parent is null))
+);
+
+create table training_cycle_targets (
+    cycle        integer      not null references training_cycles (id) on delete cascade,
+    workout_type varchar(255) not null,
+    minutes      integer      not null check (minutes > 0),
+    primary key (cycle, workout_type)
+);
@@ -0,0 +1,17 @@
+create table fitness_tests (
+    id               serial primary key,
+    title            varchar(255) not null unique,
+    unit             varchar(32)  not null,
+    higher_is_better boolean      not null
+);
+
+create table fitness_results (
+    id       serial primary key,
+    test     integer          not null references fitness_tests (id) on delete cascade,
+    person   integer          not null references persons (id) on delete cascade,
+    value    double precision not null,
+    taken_on date             not null default current_date,
+    note     text             not null default ''
+);
+
+create index fitness_results_person_idx on fitness_results (person, test, taken_on);
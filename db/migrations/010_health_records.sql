@@ -0,0 +1,11 @@
+create table persons_health (
+    person          integer primary key references persons (id) on delete cascade,
+    clearance_until date,
+    blood_type      bytea,
+    allergies       bytea,
+    medical_notes   bytea,
+    updated_at      timestamp not null default now(),
+    updated_by      integer references persons (id) on delete set null
+);
+
+create index persons_health_clearance_idx on persons_health (clearance_until);
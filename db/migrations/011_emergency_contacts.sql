@@ -0,0 +1,10 @@
+create table persons_emergency_contacts (
+    id       serial primary key,
+    person   integer not null references persons (id) on delete cascade,
+    name     bytea   not null,
+    phone    bytea   not null,
+    relation bytea,
+    priority integer not null default 1
+);
+
+create index persons_emergency_contacts_person_idx on persons_emergency_contacts (person, priority);
@@ -0,0 +1,17 @@
+create table tour_checkins (
+    id          serial primary key,
+    tour        integer     not null references tours (id) on delete cascade,
+    kind        varchar(16) not null check (kind in ('departure', 'checkin', 'return')),
+    reported_at timestamp   not null default now(),
+    reported_by integer references persons (id) on delete set null,
+    location    text        not null default '',
+    note        text        not null default ''
+);
+
+create unique index tour_checkins_departure_idx on tour_checkins (tour) where kind = 'departure';
+create unique index tour_checkins_return_idx on tour_checkins (tour) where kind = 'return';
+
+create table tour_overdue_alerts (
+    tour       integer primary key references tours (id) on delete cascade,
+    alerted_at timestamp not null default now()
+);
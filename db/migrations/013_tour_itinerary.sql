@@ -0,0 +1,22 @@
+create table tour_stages (
+    id          serial primary key,
+    tour        integer        not null references tours (id) on delete cascade,
+    day         integer        not null check (day >= 1),
+    start_place integer        not null references places (id),
+    end_place   integer        not null references places (id),
+    distance_km numeric(8, 2)  not null check (distance_km >= 0),
+    camp        text           not null default '',
+    unique (tour, day)
+);
+
+create table tour_stage_checkpoints (
+    id           serial primary key,
+    stage        integer not null references tour_stages (id) on delete cascade,
+    position     integer not null,
+    place        integer references places (id),
+    title        text    not null,
+    planned_time time    not null
+);
+
+-- the order a route passes its places in; unknown until it is set for the route
+alter table places_routes add column position integer;
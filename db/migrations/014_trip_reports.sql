@@ -0,0 +1,27 @@
+create table tour_reports (
+    id          serial primary key,
+    tour        integer   not null unique references tours (id) on delete cascade,
+    author      integer   not null references persons (id),
+    edited_by   integer   references persons (id),
+    title       text      not null,
+    body        text      not null default '',
+    gps_track   text,
+    track_km    numeric(8, 2),
+    published   boolean   not null default false,
+    created_at  timestamp not null default now(),
+    updated_at  timestamp not null default now()
+);
+
+create table tour_report_attachments (
+    id           serial primary key,
+    report       integer      not null references tour_reports (id) on delete cascade,
+    kind         varchar(16)  not null check (kind in ('photo', 'document')),
+    filename     text         not null,
+    content_type text         not null,
+    size         bigint       not null,
+    blob_key     text         not null unique,
+    thumb_key    text,
+    uploaded_at  timestamp    not null default now()
+);
+
+create index tour_report_attachments_report_idx on tour_report_attachments (report);
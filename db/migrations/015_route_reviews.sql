@@ -0,0 +1,16 @@
+create table route_reviews (
+    id                   serial primary key,
+    route                integer     not null references routes (id) on delete cascade,
+    person               integer     not null references persons (id) on delete cascade,
+    tour                 integer     not null references tours (id) on delete cascade,
+    rating               integer     not null check (rating between 1 and 5),
+    perceived_difficulty integer     not null check (perceived_difficulty >= 0),
+    season               varchar(16) not null check (season in ('winter', 'spring', 'summer', 'autumn')),
+    conditions           text        not null default '',
+    review               text        not null default '',
+    created_at           timestamp   not null default now(),
+    updated_at           timestamp   not null default now(),
+    unique (route, person)
+);
+
+create index route_reviews_route_idx on route_reviews (route);
@@ -0,0 +1,36 @@
+create table tour_plan_drafts (
+    id               serial primary key,
+    section          integer references sections (id) on delete set null,
+    created_by       integer references persons (id) on delete set null,
+    created_at       timestamp   not null default now(),
+    instructor_ratio integer     not null check (instructor_ratio > 0),
+    status           varchar(16) not null default 'draft'
+        check (status in ('draft', 'accepted', 'discarded')),
+    accepted_at      timestamp,
+    warnings         text[]      not null default '{}'
+);
+
+create table tour_plan_draft_tours (
+    id               serial primary key,
+    draft            integer not null references tour_plan_drafts (id) on delete cascade,
+    route            integer not null references routes (id),
+    start            date    not null,
+    duration_days    integer not null check (duration_days > 0),
+    instructor       integer references persons (id),
+    min_participants integer check (min_participants >= 0),
+    max_participants integer not null check (max_participants > 0),
+    tour             integer references tours (id) on delete set null
+);
+
+create table tour_plan_draft_members (
+    draft_tour integer not null references tour_plan_draft_tours (id) on delete cascade,
+    person     integer not null references persons (id) on delete cascade,
+    primary key (draft_tour, person)
+);
+
+create table tour_plan_draft_unassigned (
+    draft  integer not null references tour_plan_drafts (id) on delete cascade,
+    person integer not null references persons (id) on delete cascade,
+    reason text    not null,
+    primary key (draft, person)
+);
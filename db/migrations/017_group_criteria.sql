@@ -0,0 +1,6 @@
+alter table groups
+    add column min_age           integer check (min_age >= 0),
+    add column max_age           integer check (max_age >= 0),
+    add column sex               integer,
+    add column min_qualification integer check (min_qualification >= 0),
+    add constraint groups_age_range check (min_age <= max_age);
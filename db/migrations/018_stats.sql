@@ -0,0 +1,102 @@
+-- section 0 in the summaries below stands for all sections together
+
+create materialized view stats_members as
+select coalesce(section, 0) as section, role, count(distinct person)::int as members
+from persons_roles
+group by grouping sets ((section, role), (role));
+
+create unique index stats_members_key on stats_members (section, role);
+
+create materialized view stats_new_members as
+select coalesce(section, 0) as section, month, count(distinct person)::int as members
+from (select section, person, date_trunc('month', joined_on)::date as month
+      from persons_roles
+      where joined_on is not null) as joined
+group by grouping sets ((section, month), (month));
+
+create unique index stats_new_members_key on stats_new_members (section, month);
+
+create materialized view stats_tours as
+select coalesce(section, 0) as section, month, count(distinct tour)::int as tours,
+       coalesce(case when grouping(section) = 1 then sum(length_km) filter (where first_overall)
+                     else sum(length_km) filter (where first_in_section) end, 0)::float8 as km,
+       count(distinct person)::int as participants
+from (select persons_roles.section, tours.id as tour, persons_tours.person,
+             date_trunc('month', tours.start)::date as month, routes.length_km,
+             row_number() over (partition by tours.id, persons_roles.section) = 1 as first_in_section,
+             row_number() over (partition by tours.id) = 1 as first_overall
+      from tours
+      join routes
+      on routes.id = tours.route
+      join persons_tours
+      on persons_tours.tour = tours.id and persons_tours.status = 'enrolled'
+      join persons_roles
+      on persons_roles.person = persons_tours.person and persons_roles.role in (0, 1)
+      where tours.status = 'completed') as runs
+group by grouping sets ((section, month), (month));
+
+create unique index stats_tours_key on stats_tours (section, month);
+
+-- a session counts as delivered under the same rule as payroll
+create materialized view stats_workouts as
+select coalesce(section, 0) as section, month, count(distinct workout)::int as workouts,
+       (extract(epoch from case when grouping(section) = 1 then sum(duration) filter (where first_overall)
+                                else sum(duration) filter (where first_in_section) end) / 3600)::float8 as hours
+from (select groups.section, workouts.id as workout, date_trunc('month', workouts.date)::date as month,
+             workouts.finish_time - workouts.start_time as duration,
+             row_number() over (partition by workouts.id, groups.section) = 1 as first_in_section,
+             row_number() over (partition by workouts.id) = 1 as first_overall
+      from workouts
+      join groups_workouts
+      on groups_workouts.workout = workouts.description
+      join groups
+      on groups.id = groups_workouts.group_id
+      where workouts.date <= current_date
+        and (exists (select 1 from workout_attendance as wa
+                     where wa.workout = workouts.id and wa.status in ('present', 'late'))
+             or not exists (select 1 from workout_attendance as wa where wa.workout = workouts.id))) as sessions
+group by grouping sets ((section, month), (month));
+
+create unique index stats_workouts_key on stats_workouts (section, month);
+
+create materialized view stats_championships as
+select coalesce(section, 0) as section, month, count(distinct championship)::int as championships,
+       count(distinct person)::int as participants
+from (select persons_roles.section, championships.id as championship, persons_championships.person,
+             date_trunc('month', championships.date)::date as month
+      from championships
+      join persons_championships
+      on persons_championships.championship = championships.id
+      join persons_roles
+      on persons_roles.person = persons_championships.person) as entries
+group by grouping sets ((section, month), (month));
+
+create unique index stats_championships_key on stats_championships (section, month);
+
+-- sex is attribute 1 (-1 when unknown), age comes from the birth date attribute 2
+-- as of the last refresh
+create materialized view stats_demographics as
+select coalesce(section, 0) as section, sex, age_group, count(distinct person)::int as members
+from (select persons_roles.section, persons_roles.person, coalesce(sex.value, -1) as sex,
+             case
+                 when birth.value is null then 'unknown'
+                 when age(birth.value) < interval '18 years' then '0-17'
+                 when age(birth.value) < interval '25 years' then '18-24'
+                 when age(birth.value) < interval '35 years' then '25-34'
+                 when age(birth.value) < interval '45 years' then '35-44'
+                 when age(birth.value) < interval '55 years' then '45-54'
+                 else '55+'
+             end as age_group
+      from persons_roles
+      left join persons_attrs_int as sex
+      on sex.person = persons_roles.person and sex.attr = 1
+      left join persons_attrs_date as birth
+      on birth.person = persons_roles.person and birth.attr = 2) as members
+group by grouping sets ((section, sex, age_group), (sex, age_group));
+
+create unique index stats_demographics_key on stats_demographics (section, sex, age_group);
+
+create table stats_refreshes (
+    id           serial primary key,
+    refreshed_at timestamp not null default now()
+);
@@ -0,0 +1,11 @@
+-- bearer tokens are kept as sha256 hashes; "if not exists" because the table
+-- briefly shipped with 010_health_records
+
+create table if not exists auth_tokens (
+    token_hash bytea primary key,
+    person     integer not null references persons (id) on delete cascade,
+    created_at timestamp not null default now(),
+    expires_at timestamp not null
+);
+
+create index if not exists auth_tokens_person_idx on auth_tokens (person);
@@ -0,0 +1,219 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+// scheduledSessions pairs the sessions of every workout with the members of the
+// groups it is scheduled for.
+const scheduledSessions = `select groups_persons.person, workouts.id as workout
+			  from workouts
+			  join groups_workouts
+			  on groups_workouts.workout = workouts.description
+			  join groups_persons
+			  on groups_persons.group_id = groups_workouts.group_id`
+
+// attendanceStatsQuery counts the marks every person got at the sessions they
+// were expected at between @from and @to. expected selects the person and
+// workout pairs; a session without a mark is counted as unmarked once its day
+// is over, so members the trainer never marks still show up.
+func attendanceStatsQuery(expected string) string {
+	return `select persons.id, persons.name, persons.surname, persons.patronymic,
+			         count(*) filter (where wa.status = 'present')::int,
+			         count(*) filter (where wa.status = 'absent')::int,
+			         count(*) filter (where wa.status = 'excused')::int,
+			         count(*) filter (where wa.status = 'late')::int,
+			         count(*) filter (where wa.status is null)::int
+			  from (` + expected + `) as expected
+			  join workouts
+			  on workouts.id = expected.workout
+			  join persons
+			  on persons.id = expected.person
+			  left join workout_attendance as wa
+			  on wa.workout = expected.workout and wa.person = expected.person
+			  where workouts.date between @from and @to
+			    and (workouts.date < current_date or wa.status is not null)
+			  group by persons.id, persons.name, persons.surname, persons.patronymic
+			  order by persons.surname, persons.name`
+}
+
+func rows2AttendanceStats(rows pgx.Rows) ([]model.AttendanceStats, error) {
+	var stats []model.AttendanceStats
+	for rows.Next() {
+		s := model.AttendanceStats{}
+		err := rows.Scan(&s.Person.Id, &s.Person.Name, &s.Person.Surname, &s.Person.Patronymic,
+			&s.Present, &s.Absent, &s.Excused, &s.Late, &s.Unmarked)
+		if err != nil {
+			return nil, fmt.Errorf("convert to attendance stats model error: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+func GetWorkoutSession(pg *db.Postgres, ctx context.Context, id int) (*model.WorkoutSession, error) {
+	query := `select workouts.id, workouts.description, wd.trainer, workouts.date, workouts.start_time, workouts.finish_time
+			  from workouts
+			  join workout_descriptions as wd
+			  on wd.id = workouts.description
+			  where workouts.id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	var session model.WorkoutSession
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&session.Id, &session.Description, &session.Trainer,
+		&session.Date, &session.StartTime, &session.FinishTime)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve workout in GetWorkoutSession: %w", err)
+	}
+	return &session, nil
+}
+
+// GetWorkoutAttendees returns members of the groups the session is scheduled for,
+// restricted to a single group when one is given.
+func GetWorkoutAttendees(pg *db.Postgres, ctx context.Context, workout int, group int) ([]int32, error) {
+	query := `select distinct groups_persons.person
+			  from workouts
+			  join groups_workouts
+			  on groups_workouts.workout = workouts.description
+			  join groups_persons
+			  on groups_persons.group_id = groups_workouts.group_id
+			  where workouts.id = @workout and (@group = 0 or groups_workouts.group_id = @group)
+			  order by groups_persons.person`
+	args := pgx.NamedArgs{
+		"workout": workout,
+		"group":   group,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve attendees in GetWorkoutAttendees: %w", err)
+	}
+	defer rows.Close()
+
+	var persons []int32
+	for rows.Next() {
+		var person int32
+		err := rows.Scan(&person)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve attendees in GetWorkoutAttendees: %w", err)
+		}
+		persons = append(persons, person)
+	}
+	return persons, nil
+}
+
+func IsGroupScheduled(pg *db.Postgres, ctx context.Context, workout int, group int) (bool, error) {
+	query := `select exists (
+			      select 1 from workouts
+			      join groups_workouts
+			      on groups_workouts.workout = workouts.description
+			      where workouts.id = @workout and groups_workouts.group_id = @group)`
+	args := pgx.NamedArgs{
+		"workout": workout,
+		"group":   group,
+	}
+	var scheduled bool
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&scheduled)
+	if err != nil {
+		return false, fmt.Errorf("unable to do query IsGroupScheduled: %w", err)
+	}
+	return scheduled, nil
+}
+
+func MarkAttendance(pg *db.Postgres, ctx context.Context, attendance model.Attendance) error {
+	query := `insert into workout_attendance (workout, person, status, marked_by, note)
+			  values (@workout, @person, @status, @markedBy, @note)
+			  on conflict (workout, person) do update
+			  set status = excluded.status, marked_by = excluded.marked_by, marked_at = now(), note = excluded.note`
+	args := pgx.NamedArgs{
+		"workout":  attendance.Workout,
+		"person":   attendance.Person,
+		"status":   attendance.Status,
+		"markedBy": attendance.MarkedBy,
+		"note":     attendance.Note,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to insert row in MarkAttendance: %w", err)
+	}
+	return nil
+}
+
+func GetWorkoutAttendance(pg *db.Postgres, ctx context.Context, workout int) ([]model.Attendance, error) {
+	query := `select workout, person, status, marked_by, marked_at, note
+			  from workout_attendance
+			  where workout = @workout
+			  order by person`
+	args := pgx.NamedArgs{
+		"workout": workout,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetWorkoutAttendance: %w", err)
+	}
+	defer rows.Close()
+
+	var marks []model.Attendance
+	for rows.Next() {
+		mark := model.Attendance{}
+		err := rows.Scan(&mark.Workout, &mark.Person, &mark.Status, &mark.MarkedBy, &mark.MarkedAt, &mark.Note)
+		if err != nil {
+			return nil, fmt.Errorf("convert to attendance model error: %w", err)
+		}
+		marks = append(marks, mark)
+	}
+	return marks, nil
+}
+
+// GetPersonAttendanceStats counts the sessions of the person's groups together
+// with any other session the person got a mark at.
+func GetPersonAttendanceStats(pg *db.Postgres, ctx context.Context, person int, from string, to string) (*model.AttendanceStats, error) {
+	query := attendanceStatsQuery(scheduledSessions + ` where groups_persons.person = @person
+			  union
+			  select person, workout from workout_attendance where person = @person`)
+	args := pgx.NamedArgs{
+		"person": person,
+		"from":   from,
+		"to":     to,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetPersonAttendanceStats: %w", err)
+	}
+	defer rows.Close()
+
+	stats, err := rows2AttendanceStats(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(stats) == 0 {
+		return nil, nil
+	}
+	return &stats[0], nil
+}
+
+// GetGroupAttendanceStats only counts sessions scheduled for the group, so marks
+// a member got while training with another group do not leak in.
+func GetGroupAttendanceStats(pg *db.Postgres, ctx context.Context, group int, from string, to string) ([]model.AttendanceStats, error) {
+	query := attendanceStatsQuery(scheduledSessions + ` where groups_workouts.group_id = @group`)
+	args := pgx.NamedArgs{
+		"group": group,
+		"from":  from,
+		"to":    to,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetGroupAttendanceStats: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2AttendanceStats(rows)
+}
@@ -0,0 +1,56 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"time"
+)
+
+func CreateAuthToken(pg *db.Postgres, ctx context.Context, hash []byte, person int, expiresAt time.Time) error {
+	query := `insert into auth_tokens (token_hash, person, expires_at)
+			  values (@hash, @person, @expiresAt)`
+	args := pgx.NamedArgs{
+		"hash":      hash,
+		"person":    person,
+		"expiresAt": expiresAt,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to insert row in CreateAuthToken: %w", err)
+	}
+	return nil
+}
+
+// GetAuthTokenPerson returns the person a token was issued to, or an invalid
+// value when the token is unknown or has expired.
+func GetAuthTokenPerson(pg *db.Postgres, ctx context.Context, hash []byte) (pgtype.Int4, error) {
+	query := `select person from auth_tokens where token_hash = @hash and expires_at > now()`
+	args := pgx.NamedArgs{
+		"hash": hash,
+	}
+	var person pgtype.Int4
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&person)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return pgtype.Int4{}, nil
+	}
+	if err != nil {
+		return pgtype.Int4{}, fmt.Errorf("unable to do query GetAuthTokenPerson: %w", err)
+	}
+	return person, nil
+}
+
+func DeleteAuthToken(pg *db.Postgres, ctx context.Context, hash []byte) error {
+	query := `delete from auth_tokens where token_hash = @hash`
+	args := pgx.NamedArgs{
+		"hash": hash,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove token in DeleteAuthToken: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"sort"
+	"strings"
+)
+
+func rows2Strings(rows pgx.Rows) ([]string, error) {
+	defer rows.Close()
+	var values []string
+	for rows.Next() {
+		var value string
+		err := rows.Scan(&value)
+		if err != nil {
+			return nil, fmt.Errorf("convert to string error: %w", err)
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+func quoteColumns(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = pgx.Identifier{column}.Sanitize()
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// OperationalTables hold the state of the running service rather than club
+// data: the migrations bookkeeping, login sessions, sent overdue alerts and
+// statistics refresh times. Archives leave them out, so a restored database
+// starts with no sessions.
+var OperationalTables = []string{"schema_migrations", "auth_tokens", "tour_overdue_alerts", "stats_refreshes"}
+
+// GetDataTables returns the tables of the public schema but the operational
+// ones, ordered so that every table comes after the tables its foreign keys
+// refer to.
+func GetDataTables(pg *db.Postgres, ctx context.Context) ([]string, error) {
+	query := `select c.relname::text
+			  from pg_class as c
+			  join pg_namespace as n
+			  on n.oid = c.relnamespace
+			  where n.nspname = 'public' and c.relkind = 'r' and c.relname <> all(@operational)
+			  order by c.relname`
+	args := pgx.NamedArgs{
+		"operational": OperationalTables,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetDataTables: %w", err)
+	}
+	tables, err := rows2Strings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetDataTables: %w", err)
+	}
+
+	query = `select child.relname::text, parent.relname::text
+			  from pg_constraint as con
+			  join pg_class as child
+			  on child.oid = con.conrelid
+			  join pg_class as parent
+			  on parent.oid = con.confrelid
+			  join pg_namespace as n
+			  on n.oid = con.connamespace
+			  where con.contype = 'f' and n.nspname = 'public' and con.conrelid <> con.confrelid`
+	rows, err = pg.Db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetDataTables: %w", err)
+	}
+	defer rows.Close()
+	parents := make(map[string][]string)
+	for rows.Next() {
+		var child, parent string
+		err := rows.Scan(&child, &parent)
+		if err != nil {
+			return nil, fmt.Errorf("convert to foreign key error: %w", err)
+		}
+		parents[child] = append(parents[child], parent)
+	}
+
+	// Tables caught in a cycle of foreign keys keep the alphabetical order.
+	// Foreign keys to operational tables do not hold a table back.
+	ordered := []string{}
+	done := make(map[string]bool)
+	for _, table := range OperationalTables {
+		done[table] = true
+	}
+	for len(ordered) < len(tables) {
+		progress := false
+		for _, table := range tables {
+			if done[table] {
+				continue
+			}
+			ready := true
+			for _, parent := range parents[table] {
+				if !done[parent] {
+					ready = false
+				}
+			}
+			if ready {
+				ordered = append(ordered, table)
+				done[table] = true
+				progress = true
+			}
+		}
+		if !progress {
+			var rest []string
+			for _, table := range tables {
+				if !done[table] {
+					rest = append(rest, table)
+					done[table] = true
+				}
+			}
+			sort.Strings(rest)
+			ordered = append(ordered, rest...)
+		}
+	}
+	return ordered, nil
+}
+
+// GetTableColumns returns the columns of a table that can be written, that
+// is all but generated ones.
+func GetTableColumns(pg *db.Postgres, ctx context.Context, table string) ([]string, error) {
+	query := `select column_name::text
+			  from information_schema.columns
+			  where table_schema = 'public' and table_name = @table and is_generated = 'NEVER'
+			  order by ordinal_position`
+	args := pgx.NamedArgs{
+		"table": table,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetTableColumns: %w", err)
+	}
+	columns, err := rows2Strings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetTableColumns: %w", err)
+	}
+	return columns, nil
+}
+
+func getPrimaryKey(pg *db.Postgres, ctx context.Context, table string) ([]string, error) {
+	query := `select a.attname::text
+			  from pg_index as i
+			  join pg_attribute as a
+			  on a.attrelid = i.indrelid and a.attnum = any(i.indkey)
+			  where i.indrelid = @table::regclass and i.indisprimary
+			  order by array_position(i.indkey::int2[], a.attnum)`
+	args := pgx.NamedArgs{
+		"table": pgx.Identifier{table}.Sanitize(),
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query getPrimaryKey: %w", err)
+	}
+	columns, err := rows2Strings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query getPrimaryKey: %w", err)
+	}
+	return columns, nil
+}
+
+func CountTableRows(pg *db.Postgres, ctx context.Context, table string) (int, error) {
+	query := `select count(*) from ` + pgx.Identifier{table}.Sanitize()
+	var count int
+	err := pg.Db.QueryRow(ctx, query).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("unable to do query CountTableRows: %w", err)
+	}
+	return count, nil
+}
+
+// ExportTableRows hands every row of a table as a JSON object to fn, ordered
+// by primary key.
+func ExportTableRows(pg *db.Postgres, ctx context.Context, table string, fn func(row []byte) error) error {
+	key, err := getPrimaryKey(pg, ctx, table)
+	if err != nil {
+		return err
+	}
+	query := `select row_to_json(t)::text from ` + pgx.Identifier{table}.Sanitize() + ` as t`
+	if len(key) > 0 {
+		query += ` order by ` + quoteColumns(key)
+	}
+	rows, err := pg.Db.Query(ctx, query)
+	if err != nil {
+		return fmt.Errorf("unable to do query ExportTableRows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row []byte
+		err := rows.Scan(&row)
+		if err != nil {
+			return fmt.Errorf("convert to json row error: %w", err)
+		}
+		err = fn(row)
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ImportTableRows inserts rows given as a JSON array of objects. Columns left
+// out of columns get their defaults, identity columns keep the stored values.
+func ImportTableRows(pg *db.Postgres, ctx context.Context, table string, columns []string, rows []byte) error {
+	name := pgx.Identifier{table}.Sanitize()
+	query := `insert into ` + name + ` (` + quoteColumns(columns) + `) overriding system value
+			  select ` + quoteColumns(columns) + ` from json_populate_recordset(null::` + name + `, @rows::json)`
+	args := pgx.NamedArgs{
+		"rows": string(rows),
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to insert rows of %s in ImportTableRows: %w", table, err)
+	}
+	return nil
+}
+
+// ResetSequences moves the sequences behind serial and identity columns of a
+// table past the largest stored value.
+func ResetSequences(pg *db.Postgres, ctx context.Context, table string) error {
+	name := pgx.Identifier{table}.Sanitize()
+	query := `select column_name::text
+			  from information_schema.columns
+			  where table_schema = 'public' and table_name = @table
+			    and pg_get_serial_sequence(@name, column_name::text) is not null`
+	args := pgx.NamedArgs{
+		"table": table,
+		"name":  name,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to do query ResetSequences: %w", err)
+	}
+	columns, err := rows2Strings(rows)
+	if err != nil {
+		return fmt.Errorf("unable to do query ResetSequences: %w", err)
+	}
+
+	for _, column := range columns {
+		query = `select setval(pg_get_serial_sequence(@name, @column), coalesce(max(` + pgx.Identifier{column}.Sanitize() + `), 0) + 1, false)
+				 from ` + name
+		args = pgx.NamedArgs{
+			"name":   name,
+			"column": column,
+		}
+		_, err = pg.Db.Exec(ctx, query, args)
+		if err != nil {
+			return fmt.Errorf("unable to reset sequence of %s.%s: %w", table, column, err)
+		}
+	}
+	return nil
+}
+
+func GetAppliedMigrations(pg *db.Postgres, ctx context.Context) ([]string, error) {
+	query := `select name from schema_migrations order by name`
+	rows, err := pg.Db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetAppliedMigrations: %w", err)
+	}
+	names, err := rows2Strings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetAppliedMigrations: %w", err)
+	}
+	return names, nil
+}
+
+// UseSnapshot makes the transaction read only and lets all of its queries see
+// the same snapshot of the data.
+func UseSnapshot(pg *db.Postgres, ctx context.Context) error {
+	_, err := pg.Db.Exec(ctx, `set transaction isolation level repeatable read, read only`)
+	if err != nil {
+		return fmt.Errorf("unable to set snapshot in UseSnapshot: %w", err)
+	}
+	return nil
+}
@@ -70,3 +70,53 @@ func GetAllChampionshipsBySection(pg *db.Postgres, ctx context.Context, section
 
 	return championships, nil
 }
+
+func GetChampionship(pg *db.Postgres, ctx context.Context, id int) (*model.Championship, error) {
+	query := `select id, title, date from championships where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve championship: %w", err)
+	}
+	defer rows.Close()
+
+	championships, err := rows2Champ(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(championships) == 0 {
+		return nil, nil
+	}
+	return &championships[0], nil
+}
+
+func RegisterForChampionship(pg *db.Postgres, ctx context.Context, championship int, person int) error {
+	query := `insert into persons_championships (person, championship)
+			  select @person, @championship
+			  where not exists (
+			      select 1 from persons_championships where person = @person and championship = @championship)`
+	args := pgx.NamedArgs{
+		"person":       person,
+		"championship": championship,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to insert row in RegisterForChampionship: %w", err)
+	}
+	return nil
+}
+
+func UnregisterFromChampionship(pg *db.Postgres, ctx context.Context, championship int, person int) error {
+	query := `delete from persons_championships where person = @person and championship = @championship`
+	args := pgx.NamedArgs{
+		"person":       person,
+		"championship": championship,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove row in UnregisterFromChampionship: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+func AddTourCheckin(pg *db.Postgres, ctx context.Context, checkin model.TourCheckin) (int, error) {
+	query := `insert into tour_checkins (tour, kind, reported_by, location, note)
+			  values (@tour, @kind, @reportedBy, @location, @note)
+			  returning id`
+	args := pgx.NamedArgs{
+		"tour":       checkin.Tour,
+		"kind":       checkin.Kind,
+		"reportedBy": checkin.ReportedBy,
+		"location":   checkin.Location,
+		"note":       checkin.Note,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in AddTourCheckin: %w", err)
+	}
+	return id, nil
+}
+
+func GetTourCheckins(pg *db.Postgres, ctx context.Context, tour int) ([]model.TourCheckin, error) {
+	query := `select id, tour, kind, reported_at, reported_by, location, note
+			  from tour_checkins
+			  where tour = @tour
+			  order by reported_at, id`
+	args := pgx.NamedArgs{
+		"tour": tour,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetTourCheckins: %w", err)
+	}
+	defer rows.Close()
+
+	var checkins []model.TourCheckin
+	for rows.Next() {
+		checkin := model.TourCheckin{}
+		err := rows.Scan(&checkin.Id, &checkin.Tour, &checkin.Kind, &checkin.ReportedAt, &checkin.ReportedBy,
+			&checkin.Location, &checkin.Note)
+		if err != nil {
+			return nil, fmt.Errorf("convert to tour checkin model error: %w", err)
+		}
+		checkins = append(checkins, checkin)
+	}
+	return checkins, nil
+}
+
+// GetOverdueTours finds planned or active tours that have not checked out within
+// grace seconds after the end of their last day; a planned tour may well have
+// left without anyone reporting the departure. With pendingOnly set, tours
+// that were already alerted about are skipped.
+func GetOverdueTours(pg *db.Postgres, ctx context.Context, grace int, pendingOnly bool) ([]model.OverdueTour, error) {
+	query := `select ` + tourColumns + `,
+			         (tours.start + tours.duration_days)::timestamp + make_interval(secs => @grace::int) as due,
+			         last_checkin.reported_at, last_checkin.location
+			  from tours
+			  left join lateral (
+			      select reported_at, location
+			      from tour_checkins
+			      where tour_checkins.tour = tours.id
+			      order by reported_at desc, id desc
+			      limit 1
+			  ) as last_checkin on true
+			  where tours.status not in ('cancelled', 'completed')
+			    and not exists (select 1 from tour_checkins where tour = tours.id and kind = 'return')
+			    and (tours.start + tours.duration_days)::timestamp + make_interval(secs => @grace::int) < now()
+			    and (not @pendingOnly or not exists (select 1 from tour_overdue_alerts where tour = tours.id))
+			  order by due`
+	args := pgx.NamedArgs{
+		"grace":       grace,
+		"pendingOnly": pendingOnly,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetOverdueTours: %w", err)
+	}
+	defer rows.Close()
+
+	var overdue []model.OverdueTour
+	for rows.Next() {
+		o := model.OverdueTour{}
+		tour := &o.Tour
+		err := rows.Scan(&tour.Id, &tour.Route, &tour.Instructor, &tour.Start, &tour.DurationDays,
+			&tour.MinParticipants, &tour.MaxParticipants, &tour.EnrollmentClosed, &tour.AtRisk,
+			&tour.Status, &tour.StartedOn, &tour.FinishedOn, &o.Due, &o.LastCheckin, &o.Location)
+		if err != nil {
+			return nil, fmt.Errorf("convert to overdue tour model error: %w", err)
+		}
+		overdue = append(overdue, o)
+	}
+	return overdue, nil
+}
+
+func MarkOverdueAlerted(pg *db.Postgres, ctx context.Context, tour int) error {
+	query := `insert into tour_overdue_alerts (tour) values (@tour) on conflict (tour) do nothing`
+	args := pgx.NamedArgs{
+		"tour": tour,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to insert row in MarkOverdueAlerted: %w", err)
+	}
+	return nil
+}
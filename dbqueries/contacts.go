@@ -0,0 +1,141 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"db_backend/utils"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+func AddEmergencyContact(pg *db.Postgres, ctx context.Context, contact model.EmergencyContact) (int, error) {
+	name, err := utils.Encrypt(contact.Name, contact.Person, "persons_emergency_contacts.name")
+	if err != nil {
+		return 0, err
+	}
+	phone, err := utils.Encrypt(contact.Phone, contact.Person, "persons_emergency_contacts.phone")
+	if err != nil {
+		return 0, err
+	}
+	relation, err := utils.Encrypt(contact.Relation, contact.Person, "persons_emergency_contacts.relation")
+	if err != nil {
+		return 0, err
+	}
+
+	query := `insert into persons_emergency_contacts (person, name, phone, relation, priority)
+			  values (@person, @name, @phone, @relation, @priority)
+			  returning id`
+	args := pgx.NamedArgs{
+		"person":   contact.Person,
+		"name":     name,
+		"phone":    phone,
+		"relation": relation,
+		"priority": contact.Priority,
+	}
+	var id int
+	err = pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in AddEmergencyContact: %w", err)
+	}
+	return id, nil
+}
+
+func DeleteEmergencyContact(pg *db.Postgres, ctx context.Context, person int, id int) error {
+	query := `delete from persons_emergency_contacts where id = @id and person = @person`
+	args := pgx.NamedArgs{
+		"id":     id,
+		"person": person,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove contact in DeleteEmergencyContact: %w", err)
+	}
+	return nil
+}
+
+func GetEmergencyContacts(pg *db.Postgres, ctx context.Context, person int) ([]model.EmergencyContact, error) {
+	query := `select id, person, name, phone, relation, priority
+			  from persons_emergency_contacts
+			  where person = @person
+			  order by priority, id`
+	args := pgx.NamedArgs{
+		"person": person,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetEmergencyContacts: %w", err)
+	}
+	defer rows.Close()
+
+	var contacts []model.EmergencyContact
+	for rows.Next() {
+		contact := model.EmergencyContact{}
+		var name, phone, relation []byte
+		err := rows.Scan(&contact.Id, &contact.Person, &name, &phone, &relation, &contact.Priority)
+		if err != nil {
+			return nil, fmt.Errorf("convert to emergency contact model error: %w", err)
+		}
+		contact.Name, err = utils.Decrypt(name, contact.Person, "persons_emergency_contacts.name")
+		if err != nil {
+			return nil, err
+		}
+		contact.Phone, err = utils.Decrypt(phone, contact.Person, "persons_emergency_contacts.phone")
+		if err != nil {
+			return nil, err
+		}
+		contact.Relation, err = utils.Decrypt(relation, contact.Person, "persons_emergency_contacts.relation")
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, contact)
+	}
+	return contacts, nil
+}
+
+// GetRouteDetails returns the route type, difficulty and length of a route as
+// display strings, ready for printed documents.
+func GetRouteDetails(pg *db.Postgres, ctx context.Context, route int) (string, string, string, error) {
+	query := `select coalesce(route_types.type, ''), coalesce(routes.difficulty::text, ''), coalesce(routes.length_km::text, '')
+			  from routes
+			  left join route_types
+			  on route_types.id = routes.type
+			  where routes.id = @route`
+	args := pgx.NamedArgs{
+		"route": route,
+	}
+	var routeType, difficulty, length string
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&routeType, &difficulty, &length)
+	if err != nil {
+		return "", "", "", fmt.Errorf("unable to retrieve route in GetRouteDetails: %w", err)
+	}
+	return routeType, difficulty, length, nil
+}
+
+func GetRoutePlaces(pg *db.Postgres, ctx context.Context, route int) ([]string, error) {
+	query := `select places.title
+			  from places_routes
+			  join places
+			  on places.id = places_routes.place
+			  where places_routes.route = @route
+			  order by places_routes.position, places.id`
+	args := pgx.NamedArgs{
+		"route": route,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetRoutePlaces: %w", err)
+	}
+	defer rows.Close()
+
+	var places []string
+	for rows.Next() {
+		var place string
+		err := rows.Scan(&place)
+		if err != nil {
+			return nil, fmt.Errorf("unable to do query GetRoutePlaces: %w", err)
+		}
+		places = append(places, place)
+	}
+	return places, nil
+}
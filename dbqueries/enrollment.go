@@ -0,0 +1,287 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"sort"
+)
+
+const tourColumns = `tours.id, tours.route, tours.instructor, tours.start, tours.duration_days,
+			  tours.min_participants, tours.max_participants, tours.enrollment_closed, tours.at_risk,
+			  tours.status, tours.started_on, tours.finished_on`
+
+func rows2Tours(rows pgx.Rows) ([]model.Tour, error) {
+	var tours []model.Tour
+	for rows.Next() {
+		tour := model.Tour{}
+		err := rows.Scan(&tour.Id, &tour.Route, &tour.Instructor, &tour.Start, &tour.DurationDays,
+			&tour.MinParticipants, &tour.MaxParticipants, &tour.EnrollmentClosed, &tour.AtRisk,
+			&tour.Status, &tour.StartedOn, &tour.FinishedOn)
+		if err != nil {
+			return nil, fmt.Errorf("convert to tour model error: %w", err)
+		}
+		tours = append(tours, tour)
+	}
+	return tours, nil
+}
+
+func getTour(pg *db.Postgres, ctx context.Context, query string, id int) (*model.Tour, error) {
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve tour: %w", err)
+	}
+	defer rows.Close()
+
+	tours, err := rows2Tours(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(tours) == 0 {
+		return nil, nil
+	}
+	return &tours[0], nil
+}
+
+func GetTour(pg *db.Postgres, ctx context.Context, id int) (*model.Tour, error) {
+	query := `select ` + tourColumns + ` from tours where id = @id`
+	return getTour(pg, ctx, query, id)
+}
+
+// GetTourForUpdate locks the tour row until the end of the transaction,
+// which serializes enrollment changes for the same tour.
+func GetTourForUpdate(pg *db.Postgres, ctx context.Context, id int) (*model.Tour, error) {
+	query := `select ` + tourColumns + ` from tours where id = @id for update`
+	return getTour(pg, ctx, query, id)
+}
+
+func UpdateTourCapacity(pg *db.Postgres, ctx context.Context, tour int, minParticipants pgtype.Int4, maxParticipants pgtype.Int4) error {
+	query := `update tours set min_participants = @min, max_participants = @max where id = @id`
+	args := pgx.NamedArgs{
+		"id":  tour,
+		"min": minParticipants,
+		"max": maxParticipants,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to update in UpdateTourCapacity: %w", err)
+	}
+	return nil
+}
+
+func SetTourEnrollmentClosed(pg *db.Postgres, ctx context.Context, tour int, closed bool) error {
+	query := `update tours set enrollment_closed = @closed where id = @id`
+	args := pgx.NamedArgs{
+		"id":     tour,
+		"closed": closed,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to update in SetTourEnrollmentClosed: %w", err)
+	}
+	return nil
+}
+
+func SetTourAtRisk(pg *db.Postgres, ctx context.Context, tour int, atRisk bool) error {
+	query := `update tours set at_risk = @atRisk where id = @id`
+	args := pgx.NamedArgs{
+		"id":     tour,
+		"atRisk": atRisk,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to update in SetTourAtRisk: %w", err)
+	}
+	return nil
+}
+
+func GetToursAtRisk(pg *db.Postgres, ctx context.Context) ([]model.Tour, error) {
+	query := `select ` + tourColumns + `
+			  from tours
+			  where at_risk
+			  order by start`
+	rows, err := pg.Db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetToursAtRisk: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2Tours(rows)
+}
+
+func CountTourParticipants(pg *db.Postgres, ctx context.Context, tour int, status string) (int, error) {
+	query := `select count(*) from persons_tours where tour = @tour and status = @status`
+	args := pgx.NamedArgs{
+		"tour":   tour,
+		"status": status,
+	}
+	var cnt int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&cnt)
+	if err != nil {
+		return 0, fmt.Errorf("unable to do query CountTourParticipants: %w", err)
+	}
+	return cnt, nil
+}
+
+func GetEnrollmentStatus(pg *db.Postgres, ctx context.Context, tour int, person int) (*string, error) {
+	query := `select status from persons_tours where tour = @tour and person = @person`
+	args := pgx.NamedArgs{
+		"tour":   tour,
+		"person": person,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetEnrollmentStatus: %w", err)
+	}
+	defer rows.Close()
+
+	var status string
+	if rows.Next() {
+		err = rows.Scan(&status)
+		if err != nil {
+			return nil, err
+		}
+		return &status, nil
+	}
+	return nil, nil
+}
+
+// GetWaitlistPosition returns the 1-based place of the person in the tour's waitlist.
+func GetWaitlistPosition(pg *db.Postgres, ctx context.Context, tour int, person int) (int, error) {
+	query := `select count(*)
+			  from persons_tours as pt
+			  join persons_tours as me
+			  on me.tour = pt.tour and me.person = @person
+			  where pt.tour = @tour and pt.status = 'waitlisted' and pt.requested_at <= me.requested_at`
+	args := pgx.NamedArgs{
+		"tour":   tour,
+		"person": person,
+	}
+	var position int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&position)
+	if err != nil {
+		return 0, fmt.Errorf("unable to do query GetWaitlistPosition: %w", err)
+	}
+	return position, nil
+}
+
+func EnrollPerson(pg *db.Postgres, ctx context.Context, tour int, person int, status string) error {
+	query := `update persons_tours
+			  set status = @status, requested_at = now(), cancelled_at = null
+			  where tour = @tour and person = @person`
+	args := pgx.NamedArgs{
+		"tour":   tour,
+		"person": person,
+		"status": status,
+	}
+	tag, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to update row in EnrollPerson: %w", err)
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	query = `insert into persons_tours (person, tour, status) values (@person, @tour, @status)`
+	_, err = pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to insert row in EnrollPerson: %w", err)
+	}
+	return nil
+}
+
+func CancelEnrollment(pg *db.Postgres, ctx context.Context, tour int, person int) error {
+	query := `update persons_tours
+			  set status = 'cancelled', cancelled_at = now()
+			  where tour = @tour and person = @person`
+	args := pgx.NamedArgs{
+		"tour":   tour,
+		"person": person,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to update row in CancelEnrollment: %w", err)
+	}
+	return nil
+}
+
+// PromoteWaitlisted moves up to limit people from the head of the waitlist to the
+// participants and returns their ids in queue order.
+func PromoteWaitlisted(pg *db.Postgres, ctx context.Context, tour int, limit int) ([]int, error) {
+	query := `update persons_tours
+			  set status = 'enrolled'
+			  where tour = @tour and person in (
+			      select person
+			      from persons_tours
+			      where tour = @tour and status = 'waitlisted'
+			      order by requested_at, person
+			      limit @limit)
+			  returning person, requested_at`
+	args := pgx.NamedArgs{
+		"tour":  tour,
+		"limit": limit,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to update rows in PromoteWaitlisted: %w", err)
+	}
+	defer rows.Close()
+
+	type promotion struct {
+		person      int
+		requestedAt pgtype.Timestamp
+	}
+	var promoted []promotion
+	for rows.Next() {
+		var p promotion
+		err := rows.Scan(&p.person, &p.requestedAt)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve promoted person: %w", err)
+		}
+		promoted = append(promoted, p)
+	}
+
+	sort.Slice(promoted, func(i, j int) bool {
+		return promoted[i].requestedAt.Time.Before(promoted[j].requestedAt.Time)
+	})
+
+	var persons []int
+	for _, p := range promoted {
+		persons = append(persons, p.person)
+	}
+	return persons, nil
+}
+
+func GetTourParticipants(pg *db.Postgres, ctx context.Context, tour int) ([]model.TourParticipant, error) {
+	query := `select persons.id, name, surname, patronymic, status, requested_at, outcome, outcome_date
+			  from persons
+			  join persons_tours
+			  on persons.id = persons_tours.person
+			  where tour = @tour and status <> 'cancelled'
+			  order by status, requested_at, persons.id`
+	args := pgx.NamedArgs{
+		"tour": tour,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetTourParticipants: %w", err)
+	}
+	defer rows.Close()
+
+	var participants []model.TourParticipant
+	for rows.Next() {
+		p := model.TourParticipant{}
+		err := rows.Scan(&p.Person.Id, &p.Person.Name, &p.Person.Surname, &p.Person.Patronymic, &p.Status, &p.RequestedAt, &p.Outcome, &p.OutcomeDate)
+		if err != nil {
+			return nil, fmt.Errorf("convert to tour participant model error: %w", err)
+		}
+		participants = append(participants, p)
+	}
+	return participants, nil
+}
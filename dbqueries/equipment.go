@@ -0,0 +1,284 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const equipmentColumns = `equipment.id, equipment.title, equipment.category, equipment.quantity,
+			  equipment.condition, equipment.section, equipment.notes`
+
+const equipmentCheckoutColumns = `id, equipment, quantity, tour, person, checked_out_on, due_on,
+			  checked_in_on, condition_in, report`
+
+// checkoutWindows gives the dates each checkout keeps its units away from the store:
+// tour checkouts cover the tour dates, personal ones run until the due date,
+// and a check-in releases the units on the check-in day.
+const checkoutWindows = `select c.equipment, c.quantity,
+			         case when c.tour is not null then least(c.checked_out_on, t.start) else c.checked_out_on end as from_date,
+			         coalesce(c.checked_in_on,
+			                  case when c.tour is not null then t.start + t.duration_days else c.due_on + 1 end,
+			                  'infinity'::date) as to_date
+			  from equipment_checkouts as c
+			  left join tours as t
+			  on t.id = c.tour`
+
+func rows2Equipment(rows pgx.Rows) ([]model.Equipment, error) {
+	var items []model.Equipment
+	for rows.Next() {
+		item := model.Equipment{}
+		err := rows.Scan(&item.Id, &item.Title, &item.Category, &item.Quantity, &item.Condition, &item.Section, &item.Notes)
+		if err != nil {
+			return nil, fmt.Errorf("convert to equipment model error: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func rows2EquipmentCheckouts(rows pgx.Rows) ([]model.EquipmentCheckout, error) {
+	var checkouts []model.EquipmentCheckout
+	for rows.Next() {
+		c := model.EquipmentCheckout{}
+		err := rows.Scan(&c.Id, &c.Equipment, &c.Quantity, &c.Tour, &c.Person, &c.CheckedOutOn, &c.DueOn,
+			&c.CheckedInOn, &c.ConditionIn, &c.Report)
+		if err != nil {
+			return nil, fmt.Errorf("convert to equipment checkout model error: %w", err)
+		}
+		checkouts = append(checkouts, c)
+	}
+	return checkouts, nil
+}
+
+func CreateEquipment(pg *db.Postgres, ctx context.Context, item model.Equipment) (int, error) {
+	query := `insert into equipment (title, category, quantity, condition, section, notes)
+			  values (@title, @category, @quantity, @condition, @section, @notes)
+			  returning id`
+	args := pgx.NamedArgs{
+		"title":     item.Title,
+		"category":  item.Category,
+		"quantity":  item.Quantity,
+		"condition": item.Condition,
+		"section":   item.Section,
+		"notes":     item.Notes,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateEquipment: %w", err)
+	}
+	return id, nil
+}
+
+func getEquipment(pg *db.Postgres, ctx context.Context, query string, id int) (*model.Equipment, error) {
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve equipment: %w", err)
+	}
+	defer rows.Close()
+
+	items, err := rows2Equipment(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return &items[0], nil
+}
+
+func GetEquipment(pg *db.Postgres, ctx context.Context, id int) (*model.Equipment, error) {
+	query := `select ` + equipmentColumns + ` from equipment where id = @id`
+	return getEquipment(pg, ctx, query, id)
+}
+
+func GetEquipmentForUpdate(pg *db.Postgres, ctx context.Context, id int) (*model.Equipment, error) {
+	query := `select ` + equipmentColumns + ` from equipment where id = @id for update`
+	return getEquipment(pg, ctx, query, id)
+}
+
+func UpdateEquipment(pg *db.Postgres, ctx context.Context, item model.Equipment) error {
+	query := `update equipment
+			  set title = @title, category = @category, quantity = @quantity,
+			      condition = @condition, section = @section, notes = @notes
+			  where id = @id`
+	args := pgx.NamedArgs{
+		"id":        item.Id,
+		"title":     item.Title,
+		"category":  item.Category,
+		"quantity":  item.Quantity,
+		"condition": item.Condition,
+		"section":   item.Section,
+		"notes":     item.Notes,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to update in UpdateEquipment: %w", err)
+	}
+	return nil
+}
+
+func DeleteEquipment(pg *db.Postgres, ctx context.Context, id int) error {
+	query := `delete from equipment where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove equipment in DeleteEquipment: %w", err)
+	}
+	return nil
+}
+
+func GetEquipmentList(pg *db.Postgres, ctx context.Context, section pgtype.Int4, category string) ([]model.Equipment, error) {
+	query := `select ` + equipmentColumns + `
+			  from equipment
+			  where (@section::int is null or section = @section) and (@category = '' or category = @category)
+			  order by category, title`
+	args := pgx.NamedArgs{
+		"section":  section,
+		"category": category,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetEquipmentList: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2Equipment(rows)
+}
+
+func CreateEquipmentCheckout(pg *db.Postgres, ctx context.Context, checkout model.EquipmentCheckout) (int, error) {
+	query := `insert into equipment_checkouts (equipment, quantity, tour, person, checked_out_on, due_on)
+			  values (@equipment, @quantity, @tour, @person, @checkedOutOn, @dueOn)
+			  returning id`
+	args := pgx.NamedArgs{
+		"equipment":    checkout.Equipment,
+		"quantity":     checkout.Quantity,
+		"tour":         checkout.Tour,
+		"person":       checkout.Person,
+		"checkedOutOn": checkout.CheckedOutOn,
+		"dueOn":        checkout.DueOn,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateEquipmentCheckout: %w", err)
+	}
+	return id, nil
+}
+
+func GetEquipmentCheckoutForUpdate(pg *db.Postgres, ctx context.Context, id int) (*model.EquipmentCheckout, error) {
+	query := `select ` + equipmentCheckoutColumns + ` from equipment_checkouts where id = @id for update`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve equipment checkout: %w", err)
+	}
+	defer rows.Close()
+
+	checkouts, err := rows2EquipmentCheckouts(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(checkouts) == 0 {
+		return nil, nil
+	}
+	return &checkouts[0], nil
+}
+
+func CheckinEquipment(pg *db.Postgres, ctx context.Context, checkout int, date pgtype.Date, condition pgtype.Text, report pgtype.Text) error {
+	query := `update equipment_checkouts
+			  set checked_in_on = @date, condition_in = @condition, report = @report
+			  where id = @id`
+	args := pgx.NamedArgs{
+		"id":        checkout,
+		"date":      date,
+		"condition": condition,
+		"report":    report,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to update row in CheckinEquipment: %w", err)
+	}
+	return nil
+}
+
+func GetEquipmentCheckouts(pg *db.Postgres, ctx context.Context, tour pgtype.Int4, person pgtype.Int4, openOnly bool) ([]model.EquipmentCheckout, error) {
+	query := `select ` + equipmentCheckoutColumns + `
+			  from equipment_checkouts
+			  where (@tour::int is null or tour = @tour)
+			    and (@person::int is null or person = @person)
+			    and (not @openOnly or checked_in_on is null)
+			  order by checked_out_on, id`
+	args := pgx.NamedArgs{
+		"tour":     tour,
+		"person":   person,
+		"openOnly": openOnly,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetEquipmentCheckouts: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2EquipmentCheckouts(rows)
+}
+
+// GetEquipmentAvailability returns every item (or only the given one) with the
+// largest number of units allocated at the same time within the [fromDate, toDate)
+// window. The load only grows where a checkout starts, so it is enough to sum the
+// checkouts covering each of those points and take the maximum.
+func GetEquipmentAvailability(pg *db.Postgres, ctx context.Context, fromDate string, toDate string, equipment pgtype.Int4) ([]model.EquipmentAvailability, error) {
+	query := `with windows as (` + checkoutWindows + `)
+			  select ` + equipmentColumns + `, coalesce(peak.quantity, 0)::int
+			  from equipment
+			  left join lateral (
+			      select max(load.quantity) as quantity
+			      from (
+			          select sum(w.quantity) as quantity
+			          from (
+			              select distinct greatest(w.from_date, @fromDate::date) as day
+			              from windows as w
+			              where w.equipment = equipment.id and w.from_date < @toDate::date and w.to_date > @fromDate::date
+			          ) as points
+			          join windows as w
+			          on w.equipment = equipment.id and w.from_date <= points.day and w.to_date > points.day
+			          group by points.day
+			      ) as load
+			  ) as peak
+			  on true
+			  where @equipment::int is null or equipment.id = @equipment
+			  order by equipment.category, equipment.title`
+	args := pgx.NamedArgs{
+		"fromDate":  fromDate,
+		"toDate":    toDate,
+		"equipment": equipment,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetEquipmentAvailability: %w", err)
+	}
+	defer rows.Close()
+
+	var result []model.EquipmentAvailability
+	for rows.Next() {
+		a := model.EquipmentAvailability{}
+		err := rows.Scan(&a.Equipment.Id, &a.Equipment.Title, &a.Equipment.Category, &a.Equipment.Quantity,
+			&a.Equipment.Condition, &a.Equipment.Section, &a.Equipment.Notes, &a.Allocated)
+		if err != nil {
+			return nil, fmt.Errorf("convert to equipment availability model error: %w", err)
+		}
+		result = append(result, a)
+	}
+	return result, nil
+}
@@ -0,0 +1,192 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func CreateTourExpense(pg *db.Postgres, ctx context.Context, expense model.TourExpense) (int, error) {
+	query := `insert into tour_expenses (tour, payer, category, amount, description, spent_on)
+			  values (@tour, @payer, @category, @amount::numeric / 100, @description, @spentOn)
+			  returning id`
+	args := pgx.NamedArgs{
+		"tour":        expense.Tour,
+		"payer":       expense.Payer,
+		"category":    expense.Category,
+		"amount":      expense.Amount,
+		"description": expense.Description,
+		"spentOn":     expense.SpentOn,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateTourExpense: %w", err)
+	}
+	return id, nil
+}
+
+func CreateExpenseShare(pg *db.Postgres, ctx context.Context, share model.ExpenseShare) error {
+	query := `insert into tour_expense_shares (expense, person, weight, amount)
+			  values (@expense, @person, @weight, @amount::numeric / 100)`
+	args := pgx.NamedArgs{
+		"expense": share.Expense,
+		"person":  share.Person,
+		"weight":  share.Weight,
+		"amount":  share.Amount,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to insert row in CreateExpenseShare: %w", err)
+	}
+	return nil
+}
+
+func DeleteTourExpense(pg *db.Postgres, ctx context.Context, id int) error {
+	query := `delete from tour_expenses where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove expense in DeleteTourExpense: %w", err)
+	}
+	return nil
+}
+
+func GetTourExpenses(pg *db.Postgres, ctx context.Context, tour int) ([]model.TourExpense, error) {
+	query := `select id, tour, payer, category, (amount * 100)::bigint, description, spent_on
+			  from tour_expenses
+			  where tour = @tour
+			  order by spent_on, id`
+	args := pgx.NamedArgs{
+		"tour": tour,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetTourExpenses: %w", err)
+	}
+	defer rows.Close()
+
+	var expenses []model.TourExpense
+	for rows.Next() {
+		e := model.TourExpense{}
+		err := rows.Scan(&e.Id, &e.Tour, &e.Payer, &e.Category, &e.Amount, &e.Description, &e.SpentOn)
+		if err != nil {
+			return nil, fmt.Errorf("convert to tour expense model error: %w", err)
+		}
+		expenses = append(expenses, e)
+	}
+	return expenses, nil
+}
+
+func GetTourExpenseShares(pg *db.Postgres, ctx context.Context, tour int) ([]model.ExpenseShare, error) {
+	query := `select s.expense, s.person, s.weight::float8, (s.amount * 100)::bigint
+			  from tour_expense_shares as s
+			  join tour_expenses as e
+			  on e.id = s.expense
+			  where e.tour = @tour
+			  order by s.expense, s.person`
+	args := pgx.NamedArgs{
+		"tour": tour,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetTourExpenseShares: %w", err)
+	}
+	defer rows.Close()
+
+	var shares []model.ExpenseShare
+	for rows.Next() {
+		s := model.ExpenseShare{}
+		err := rows.Scan(&s.Expense, &s.Person, &s.Weight, &s.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("convert to expense share model error: %w", err)
+		}
+		shares = append(shares, s)
+	}
+	return shares, nil
+}
+
+func GetTourBalances(pg *db.Postgres, ctx context.Context, tour int) ([]model.PersonBalance, error) {
+	query := `with paid as (
+			      select payer as person, sum(amount) as amount
+			      from tour_expenses
+			      where tour = @tour
+			      group by payer),
+			  owed as (
+			      select s.person, sum(s.amount) as amount
+			      from tour_expense_shares as s
+			      join tour_expenses as e
+			      on e.id = s.expense
+			      where e.tour = @tour
+			      group by s.person)
+			  select persons.id, name, surname, patronymic,
+			         coalesce((paid.amount * 100)::bigint, 0), coalesce((owed.amount * 100)::bigint, 0)
+			  from persons
+			  join (select person from paid union select person from owed) as involved
+			  on involved.person = persons.id
+			  left join paid
+			  on paid.person = persons.id
+			  left join owed
+			  on owed.person = persons.id
+			  order by persons.id`
+	args := pgx.NamedArgs{
+		"tour": tour,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetTourBalances: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []model.PersonBalance
+	for rows.Next() {
+		b := model.PersonBalance{}
+		err := rows.Scan(&b.Person.Id, &b.Person.Name, &b.Person.Surname, &b.Person.Patronymic, &b.Paid, &b.Owed)
+		if err != nil {
+			return nil, fmt.Errorf("convert to balance model error: %w", err)
+		}
+		balances = append(balances, b)
+	}
+	return balances, nil
+}
+
+// GetSectionExpenseSummary sums the shares of section tourists per category.
+// A tourist registered in several sections counts towards each of them.
+func GetSectionExpenseSummary(pg *db.Postgres, ctx context.Context, year int, section pgtype.Int4) ([]model.SectionExpenseSummary, error) {
+	query := `select sections.id, sections.title, e.category, (sum(s.amount) * 100)::bigint
+			  from tour_expense_shares as s
+			  join tour_expenses as e
+			  on e.id = s.expense
+			  join (select distinct person, section from persons_roles where role = 0 or role = 1) as pr
+			  on pr.person = s.person
+			  join sections
+			  on sections.id = pr.section
+			  where extract(year from e.spent_on) = @year and (@section::int is null or sections.id = @section)
+			  group by sections.id, sections.title, e.category
+			  order by sections.id, e.category`
+	args := pgx.NamedArgs{
+		"year":    year,
+		"section": section,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetSectionExpenseSummary: %w", err)
+	}
+	defer rows.Close()
+
+	var summary []model.SectionExpenseSummary
+	for rows.Next() {
+		s := model.SectionExpenseSummary{Year: int32(year)}
+		err := rows.Scan(&s.Section.Id, &s.Section.Title, &s.Category, &s.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("convert to expense summary model error: %w", err)
+		}
+		summary = append(summary, s)
+	}
+	return summary, nil
+}
@@ -0,0 +1,191 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const feeColumns = `membership_fees.id, membership_fees.section, membership_fees.role, membership_fees.season,
+			  (membership_fees.amount * 100)::bigint, membership_fees.due_on`
+
+// arrearsQuery matches every section role with the fees of that section and role
+// that are already due and keeps the ones the person has not paid in full. Only
+// fees due since the member joined count; for members whose join date is unknown
+// only the latest fee due is charged.
+func arrearsQuery(condition string, order string) string {
+	return `select person_id, name, surname, patronymic, fee_id, section, role, season, due, due_on, paid
+			  from (
+			      select persons.id as person_id, name, surname, patronymic,
+			             membership_fees.id as fee_id, membership_fees.section, membership_fees.role,
+			             membership_fees.season, (membership_fees.amount * 100)::bigint as due, membership_fees.due_on,
+			             coalesce((select (sum(amount) * 100)::bigint
+			                       from membership_payments
+			                       where membership_payments.person = persons.id
+			                         and membership_payments.fee = membership_fees.id), 0) as paid
+			      from persons
+			      join persons_roles
+			      on persons_roles.person = persons.id
+			      join membership_fees
+			      on membership_fees.section = persons_roles.section and membership_fees.role = persons_roles.role
+			      where membership_fees.due_on <= @date::date
+			        and (membership_fees.due_on >= persons_roles.joined_on
+			             or persons_roles.joined_on is null
+			                and membership_fees.due_on = (select max(latest.due_on)
+			                                              from membership_fees as latest
+			                                              where latest.section = membership_fees.section
+			                                                and latest.role = membership_fees.role
+			                                                and latest.due_on <= @date::date))
+			        and ` + condition + `) as debts
+			  where paid < due
+			  order by ` + order
+}
+
+func CreateMembershipFee(pg *db.Postgres, ctx context.Context, fee model.MembershipFee) (int, error) {
+	query := `insert into membership_fees (section, role, season, amount, due_on)
+			  values (@section, @role, @season, @amount::numeric / 100, @dueOn)
+			  returning id`
+	args := pgx.NamedArgs{
+		"section": fee.Section,
+		"role":    fee.Role,
+		"season":  fee.Season,
+		"amount":  fee.Amount,
+		"dueOn":   fee.DueOn,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateMembershipFee: %w", err)
+	}
+	return id, nil
+}
+
+func DeleteMembershipFee(pg *db.Postgres, ctx context.Context, id int) error {
+	query := `delete from membership_fees where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove fee in DeleteMembershipFee: %w", err)
+	}
+	return nil
+}
+
+func GetMembershipFees(pg *db.Postgres, ctx context.Context, section pgtype.Int4) ([]model.MembershipFee, error) {
+	query := `select ` + feeColumns + `
+			  from membership_fees
+			  where @section::int is null or section = @section
+			  order by section, due_on, role`
+	args := pgx.NamedArgs{
+		"section": section,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetMembershipFees: %w", err)
+	}
+	defer rows.Close()
+
+	var fees []model.MembershipFee
+	for rows.Next() {
+		f := model.MembershipFee{}
+		err := rows.Scan(&f.Id, &f.Section, &f.Role, &f.Season, &f.Amount, &f.DueOn)
+		if err != nil {
+			return nil, fmt.Errorf("convert to membership fee model error: %w", err)
+		}
+		fees = append(fees, f)
+	}
+	return fees, nil
+}
+
+func CreateMembershipPayment(pg *db.Postgres, ctx context.Context, payment model.MembershipPayment) (int, error) {
+	query := `insert into membership_payments (person, fee, amount, paid_on, note)
+			  values (@person, @fee, @amount::numeric / 100, @paidOn, @note)
+			  returning id`
+	args := pgx.NamedArgs{
+		"person": payment.Person,
+		"fee":    payment.Fee,
+		"amount": payment.Amount,
+		"paidOn": payment.PaidOn,
+		"note":   payment.Note,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateMembershipPayment: %w", err)
+	}
+	return id, nil
+}
+
+func GetMembershipPayments(pg *db.Postgres, ctx context.Context, person int) ([]model.MembershipPayment, error) {
+	query := `select id, person, fee, (amount * 100)::bigint, paid_on, note
+			  from membership_payments
+			  where person = @person
+			  order by paid_on, id`
+	args := pgx.NamedArgs{
+		"person": person,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetMembershipPayments: %w", err)
+	}
+	defer rows.Close()
+
+	var payments []model.MembershipPayment
+	for rows.Next() {
+		p := model.MembershipPayment{}
+		err := rows.Scan(&p.Id, &p.Person, &p.Fee, &p.Amount, &p.PaidOn, &p.Note)
+		if err != nil {
+			return nil, fmt.Errorf("convert to membership payment model error: %w", err)
+		}
+		payments = append(payments, p)
+	}
+	return payments, nil
+}
+
+func rows2Arrears(rows pgx.Rows) ([]model.Arrear, error) {
+	var arrears []model.Arrear
+	for rows.Next() {
+		a := model.Arrear{}
+		err := rows.Scan(&a.Person.Id, &a.Person.Name, &a.Person.Surname, &a.Person.Patronymic,
+			&a.Fee.Id, &a.Fee.Section, &a.Fee.Role, &a.Fee.Season, &a.Fee.Amount, &a.Fee.DueOn, &a.Paid)
+		if err != nil {
+			return nil, fmt.Errorf("convert to arrear model error: %w", err)
+		}
+		arrears = append(arrears, a)
+	}
+	return arrears, nil
+}
+
+func GetArrears(pg *db.Postgres, ctx context.Context, date string, section pgtype.Int4) ([]model.Arrear, error) {
+	query := arrearsQuery(`(@section::int is null or persons_roles.section = @section)`, `surname, name, due_on`)
+	args := pgx.NamedArgs{
+		"date":    date,
+		"section": section,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetArrears: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2Arrears(rows)
+}
+
+func GetPersonArrears(pg *db.Postgres, ctx context.Context, person int, date string) ([]model.Arrear, error) {
+	query := arrearsQuery(`persons.id = @person`, `due_on`)
+	args := pgx.NamedArgs{
+		"date":   date,
+		"person": person,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetPersonArrears: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2Arrears(rows)
+}
@@ -0,0 +1,211 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func rows2FitnessTests(rows pgx.Rows) ([]model.FitnessTest, error) {
+	var tests []model.FitnessTest
+	for rows.Next() {
+		test := model.FitnessTest{}
+		err := rows.Scan(&test.Id, &test.Title, &test.Unit, &test.HigherIsBetter)
+		if err != nil {
+			return nil, fmt.Errorf("convert to fitness test model error: %w", err)
+		}
+		tests = append(tests, test)
+	}
+	return tests, nil
+}
+
+func CreateFitnessTest(pg *db.Postgres, ctx context.Context, test model.FitnessTest) (int, error) {
+	query := `insert into fitness_tests (title, unit, higher_is_better)
+			  values (@title, @unit, @higherIsBetter)
+			  returning id`
+	args := pgx.NamedArgs{
+		"title":          test.Title,
+		"unit":           test.Unit,
+		"higherIsBetter": test.HigherIsBetter,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateFitnessTest: %w", err)
+	}
+	return id, nil
+}
+
+func GetFitnessTest(pg *db.Postgres, ctx context.Context, id int) (*model.FitnessTest, error) {
+	query := `select id, title, unit, higher_is_better from fitness_tests where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	var test model.FitnessTest
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&test.Id, &test.Title, &test.Unit, &test.HigherIsBetter)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve test in GetFitnessTest: %w", err)
+	}
+	return &test, nil
+}
+
+func GetFitnessTests(pg *db.Postgres, ctx context.Context) ([]model.FitnessTest, error) {
+	query := `select id, title, unit, higher_is_better from fitness_tests order by title`
+	rows, err := pg.Db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetFitnessTests: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2FitnessTests(rows)
+}
+
+func DeleteFitnessTest(pg *db.Postgres, ctx context.Context, id int) error {
+	query := `delete from fitness_tests where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove test in DeleteFitnessTest: %w", err)
+	}
+	return nil
+}
+
+func AddFitnessResult(pg *db.Postgres, ctx context.Context, result model.FitnessResult) (int, error) {
+	query := `insert into fitness_results (test, person, value, taken_on, note)
+			  values (@test, @person, @value, @takenOn, @note)
+			  returning id`
+	args := pgx.NamedArgs{
+		"test":    result.Test,
+		"person":  result.Person,
+		"value":   result.Value,
+		"takenOn": result.TakenOn,
+		"note":    result.Note,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in AddFitnessResult: %w", err)
+	}
+	return id, nil
+}
+
+func DeleteFitnessResult(pg *db.Postgres, ctx context.Context, id int) error {
+	query := `delete from fitness_results where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove result in DeleteFitnessResult: %w", err)
+	}
+	return nil
+}
+
+func GetFitnessResults(pg *db.Postgres, ctx context.Context, person int, test pgtype.Int4) ([]model.FitnessResult, error) {
+	query := `select id, test, person, value, taken_on, note
+			  from fitness_results
+			  where person = @person and (@test::int is null or test = @test)
+			  order by test, taken_on, id`
+	args := pgx.NamedArgs{
+		"person": person,
+		"test":   test,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetFitnessResults: %w", err)
+	}
+	defer rows.Close()
+
+	var results []model.FitnessResult
+	for rows.Next() {
+		result := model.FitnessResult{}
+		err := rows.Scan(&result.Id, &result.Test, &result.Person, &result.Value, &result.TakenOn, &result.Note)
+		if err != nil {
+			return nil, fmt.Errorf("convert to fitness result model error: %w", err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// GetGroupFitnessRanking returns the best result of every group member for the
+// test between @from and @to, best first.
+func GetGroupFitnessRanking(pg *db.Postgres, ctx context.Context, group int, test model.FitnessTest, from string, to string) ([]model.FitnessRanking, error) {
+	order := "asc"
+	if test.HigherIsBetter {
+		order = "desc"
+	}
+	query := `select persons.id, persons.name, persons.surname, persons.patronymic, best.value, best.taken_on
+			  from (
+			      select distinct on (fitness_results.person) fitness_results.person, fitness_results.value, fitness_results.taken_on
+			      from fitness_results
+			      join groups_persons
+			      on groups_persons.person = fitness_results.person
+			      where groups_persons.group_id = @group and fitness_results.test = @test
+			        and fitness_results.taken_on between @from and @to
+			      order by fitness_results.person, fitness_results.value ` + order + `, fitness_results.taken_on
+			  ) as best
+			  join persons
+			  on persons.id = best.person
+			  order by best.value ` + order + `, best.taken_on, persons.surname`
+	args := pgx.NamedArgs{
+		"group": group,
+		"test":  test.Id,
+		"from":  from,
+		"to":    to,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetGroupFitnessRanking: %w", err)
+	}
+	defer rows.Close()
+
+	var ranking []model.FitnessRanking
+	for rows.Next() {
+		r := model.FitnessRanking{}
+		err := rows.Scan(&r.Person.Id, &r.Person.Name, &r.Person.Surname, &r.Person.Patronymic, &r.Value, &r.TakenOn)
+		if err != nil {
+			return nil, fmt.Errorf("convert to fitness ranking model error: %w", err)
+		}
+		ranking = append(ranking, r)
+	}
+	return ranking, nil
+}
+
+// GetTouristsByFitness selects tourists whose best result for the test taken since
+// the given date meets the threshold in the direction of the test.
+func GetTouristsByFitness(pg *db.Postgres, ctx context.Context, test int, value float64, since string) ([]model.Person, error) {
+	query := `select distinct persons.id, persons.name, persons.surname, persons.patronymic
+			  from persons
+			  join persons_roles
+			  on persons.id = persons_roles.person
+			  join fitness_results
+			  on fitness_results.person = persons.id
+			  join fitness_tests
+			  on fitness_tests.id = fitness_results.test
+			  where (role = 0 or role = 1) and fitness_tests.id = @test
+			    and fitness_results.taken_on >= @since::date
+			    and case when fitness_tests.higher_is_better then fitness_results.value >= @value
+			             else fitness_results.value <= @value end`
+	args := pgx.NamedArgs{
+		"test":  test,
+		"value": value,
+		"since": since,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetTouristsByFitness: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2Persons(rows)
+}
@@ -6,13 +6,17 @@ import (
 	"db_backend/model"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const groupColumns = `id, group_number, section, min_age, max_age, sex, min_qualification`
+
 func rows2Groups(rows pgx.Rows) ([]model.Group, error) {
 	var groups []model.Group
 	for rows.Next() {
 		group := model.Group{}
-		err := rows.Scan(&group.Id, &group.GroupNumber, &group.Section)
+		err := rows.Scan(&group.Id, &group.GroupNumber, &group.Section, &group.MinAge, &group.MaxAge, &group.Sex,
+			&group.MinQualification)
 		if err != nil {
 			return nil, fmt.Errorf("convert to group model error: %w", err)
 		}
@@ -35,29 +39,27 @@ func rows2Sections(rows pgx.Rows) ([]model.Section, error) {
 }
 
 func CreateGroup(pg *db.Postgres, ctx context.Context, group model.Group) (int, error) {
-	query := `INSERT INTO groups (group_number, section) VALUES (@number, @section)`
+	query := `INSERT INTO groups (group_number, section, min_age, max_age, sex, min_qualification)
+			  VALUES (@number, @section, @minAge, @maxAge, @sex, @minQualification)
+			  RETURNING id`
 	args := pgx.NamedArgs{
-		"number":  group.GroupNumber,
-		"section": group.Section,
-	}
-	_, err := pg.Db.Exec(ctx, query, args)
+		"number":           group.GroupNumber,
+		"section":          group.Section,
+		"minAge":           group.MinAge,
+		"maxAge":           group.MaxAge,
+		"sex":              group.Sex,
+		"minQualification": group.MinQualification,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("unable to insert row in CreateGroup: %w", err)
 	}
-	query = `select last_value from groups_id_seq`
-	rows, err := pg.Db.Query(ctx, query)
-	defer rows.Close()
-	var lastValue int
-	rows.Next()
-	err = rows.Scan(&lastValue)
-	if err != nil {
-		return 0, err
-	}
-	return lastValue, nil
+	return id, nil
 }
 
 func GetGroup(pg *db.Postgres, ctx context.Context, id int) (*model.Group, error) {
-	query := `SELECT * FROM groups where id = @id`
+	query := `SELECT ` + groupColumns + ` FROM groups where id = @id`
 	args := pgx.NamedArgs{
 		"id": id,
 	}
@@ -81,11 +83,18 @@ func GetGroup(pg *db.Postgres, ctx context.Context, id int) (*model.Group, error
 }
 
 func UpdateGroup(pg *db.Postgres, ctx context.Context, group model.Group) error {
-	query := `UPDATE groups SET group_number = @number, section = @section WHERE id = @id`
+	query := `UPDATE groups
+			  SET group_number = @number, section = @section, min_age = @minAge, max_age = @maxAge, sex = @sex,
+			      min_qualification = @minQualification
+			  WHERE id = @id`
 	args := pgx.NamedArgs{
-		"id":      group.Id,
-		"number":  group.GroupNumber,
-		"section": group.Section,
+		"id":               group.Id,
+		"number":           group.GroupNumber,
+		"section":          group.Section,
+		"minAge":           group.MinAge,
+		"maxAge":           group.MaxAge,
+		"sex":              group.Sex,
+		"minQualification": group.MinQualification,
 	}
 	_, err := pg.Db.Exec(ctx, query, args)
 	if err != nil {
@@ -157,7 +166,7 @@ func GetGroupMembers(pg *db.Postgres, ctx context.Context, group int) ([]int, er
 }
 
 func GetGroups(pg *db.Postgres, ctx context.Context) ([]model.Group, error) {
-	query := `SELECT * from groups`
+	query := `SELECT ` + groupColumns + ` from groups`
 	rows, err := pg.Db.Query(ctx, query)
 	defer rows.Close()
 	if err != nil {
@@ -174,24 +183,16 @@ func GetGroups(pg *db.Postgres, ctx context.Context) ([]model.Group, error) {
 }
 
 func CreateSection(pg *db.Postgres, ctx context.Context, section model.Section) (int, error) {
-	query := `INSERT INTO sections (title) VALUES (@title)`
+	query := `INSERT INTO sections (title) VALUES (@title) RETURNING id`
 	args := pgx.NamedArgs{
 		"title": section.Title,
 	}
-	_, err := pg.Db.Exec(ctx, query, args)
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("unable to insert row in CreateSection: %w", err)
 	}
-	query = `select last_value from sections_id_seq`
-	rows, err := pg.Db.Query(ctx, query)
-	defer rows.Close()
-	var lastValue int
-	rows.Next()
-	err = rows.Scan(&lastValue)
-	if err != nil {
-		return 0, err
-	}
-	return lastValue, nil
+	return id, nil
 }
 
 func GetSection(pg *db.Postgres, ctx context.Context, id int) (*model.Section, error) {
@@ -260,7 +261,7 @@ func GetAllSections(pg *db.Postgres, ctx context.Context) ([]model.Section, erro
 }
 
 func GetGroupsFromSections(pg *db.Postgres, ctx context.Context, section int) ([]model.Group, error) {
-	query := `SELECT * FROM groups where section = @section`
+	query := `SELECT ` + groupColumns + ` FROM groups where section = @section`
 	args := pgx.NamedArgs{
 		"section": section,
 	}
@@ -276,3 +277,75 @@ func GetGroupsFromSections(pg *db.Postgres, ctx context.Context, section int) ([
 	}
 	return groups, nil
 }
+
+// GetGroupCandidates loads the birth date (attribute 2), sex (attribute 1) and
+// qualification of the persons. Qualification is the highest difficulty of a
+// route they completed, 0 when none.
+func GetGroupCandidates(pg *db.Postgres, ctx context.Context, persons []int32) ([]model.GroupCandidate, error) {
+	query := `select persons.id, persons.name, persons.surname, persons.patronymic,
+			         birth.value, sex.value,
+			         coalesce((select max(routes.difficulty)::int
+			                   from persons_tours
+			                   join tours
+			                   on tours.id = persons_tours.tour
+			                   join routes
+			                   on routes.id = tours.route
+			                   where persons_tours.person = persons.id and persons_tours.outcome = 'completed'), 0)
+			  from persons
+			  left join persons_attrs_date as birth
+			  on birth.person = persons.id and birth.attr = 2
+			  left join persons_attrs_int as sex
+			  on sex.person = persons.id and sex.attr = 1
+			  where persons.id = any(@persons)
+			  order by persons.id`
+	args := pgx.NamedArgs{
+		"persons": persons,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetGroupCandidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []model.GroupCandidate
+	for rows.Next() {
+		candidate := model.GroupCandidate{}
+		err := rows.Scan(&candidate.Person.Id, &candidate.Person.Name, &candidate.Person.Surname,
+			&candidate.Person.Patronymic, &candidate.BirthDate, &candidate.Sex, &candidate.Qualification)
+		if err != nil {
+			return nil, fmt.Errorf("convert to group candidate model error: %w", err)
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// GetSectionMemberships lists group memberships of one section, or of all
+// sections when section is null.
+func GetSectionMemberships(pg *db.Postgres, ctx context.Context, section pgtype.Int4) ([]model.GroupMembership, error) {
+	query := `select distinct groups_persons.group_id, groups_persons.person
+			  from groups_persons
+			  join groups
+			  on groups.id = groups_persons.group_id
+			  where @section::int is null or groups.section = @section
+			  order by groups_persons.group_id, groups_persons.person`
+	args := pgx.NamedArgs{
+		"section": section,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetSectionMemberships: %w", err)
+	}
+	defer rows.Close()
+
+	var memberships []model.GroupMembership
+	for rows.Next() {
+		membership := model.GroupMembership{}
+		err := rows.Scan(&membership.Group, &membership.Person)
+		if err != nil {
+			return nil, fmt.Errorf("convert to group membership model error: %w", err)
+		}
+		memberships = append(memberships, membership)
+	}
+	return memberships, nil
+}
@@ -0,0 +1,194 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"db_backend/utils"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CanAccessHealth allows managers and instructors of the person's planned or
+// active tours, as well as the person themselves.
+func CanAccessHealth(pg *db.Postgres, ctx context.Context, viewer int, person int) (bool, error) {
+	query := `select @viewer::int = @person::int
+			      or exists (select 1 from persons_roles where person = @viewer and role = 3)
+			      or exists (select 1
+			                 from tours
+			                 join persons_tours
+			                 on persons_tours.tour = tours.id
+			                 where tours.instructor = @viewer and persons_tours.person = @person
+			                   and persons_tours.status = 'enrolled' and tours.status in ('planned', 'active'))`
+	args := pgx.NamedArgs{
+		"viewer": viewer,
+		"person": person,
+	}
+	var allowed bool
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&allowed)
+	if err != nil {
+		return false, fmt.Errorf("unable to do query CanAccessHealth: %w", err)
+	}
+	return allowed, nil
+}
+
+// CanEditHealth allows managers and instructors of the person's planned or active
+// tours to change a health record; unlike reading, nobody may edit their own.
+func CanEditHealth(pg *db.Postgres, ctx context.Context, viewer int, person int) (bool, error) {
+	query := `select @viewer::int <> @person::int
+			     and (exists (select 1 from persons_roles where person = @viewer and role = 3)
+			          or exists (select 1
+			                     from tours
+			                     join persons_tours
+			                     on persons_tours.tour = tours.id
+			                     join persons_roles
+			                     on persons_roles.person = tours.instructor and persons_roles.role = 2
+			                     where tours.instructor = @viewer and persons_tours.person = @person
+			                       and persons_tours.status = 'enrolled' and tours.status in ('planned', 'active')))`
+	args := pgx.NamedArgs{
+		"viewer": viewer,
+		"person": person,
+	}
+	var allowed bool
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&allowed)
+	if err != nil {
+		return false, fmt.Errorf("unable to do query CanEditHealth: %w", err)
+	}
+	return allowed, nil
+}
+
+func IsManager(pg *db.Postgres, ctx context.Context, person int) (bool, error) {
+	query := `select exists (select 1 from persons_roles where person = @person and role = 3)`
+	args := pgx.NamedArgs{
+		"person": person,
+	}
+	var manager bool
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&manager)
+	if err != nil {
+		return false, fmt.Errorf("unable to do query IsManager: %w", err)
+	}
+	return manager, nil
+}
+
+func GetHealthRecord(pg *db.Postgres, ctx context.Context, person int) (*model.HealthRecord, error) {
+	query := `select person, clearance_until, blood_type, allergies, medical_notes, updated_at, updated_by
+			  from persons_health
+			  where person = @person`
+	args := pgx.NamedArgs{
+		"person": person,
+	}
+	var record model.HealthRecord
+	var bloodType, allergies, notes []byte
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&record.Person, &record.ClearanceUntil, &bloodType, &allergies, &notes,
+		&record.UpdatedAt, &record.UpdatedBy)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve health record: %w", err)
+	}
+
+	record.BloodType, err = utils.Decrypt(bloodType, record.Person, "persons_health.blood_type")
+	if err != nil {
+		return nil, err
+	}
+	record.Allergies, err = utils.Decrypt(allergies, record.Person, "persons_health.allergies")
+	if err != nil {
+		return nil, err
+	}
+	record.MedicalNotes, err = utils.Decrypt(notes, record.Person, "persons_health.medical_notes")
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func SetHealthRecord(pg *db.Postgres, ctx context.Context, record model.HealthRecord) error {
+	bloodType, err := utils.Encrypt(record.BloodType, record.Person, "persons_health.blood_type")
+	if err != nil {
+		return err
+	}
+	allergies, err := utils.Encrypt(record.Allergies, record.Person, "persons_health.allergies")
+	if err != nil {
+		return err
+	}
+	notes, err := utils.Encrypt(record.MedicalNotes, record.Person, "persons_health.medical_notes")
+	if err != nil {
+		return err
+	}
+
+	query := `insert into persons_health (person, clearance_until, blood_type, allergies, medical_notes, updated_by)
+			  values (@person, @clearanceUntil, @bloodType, @allergies, @notes, @updatedBy)
+			  on conflict (person) do update
+			  set clearance_until = excluded.clearance_until, blood_type = excluded.blood_type,
+			      allergies = excluded.allergies, medical_notes = excluded.medical_notes,
+			      updated_at = now(), updated_by = excluded.updated_by`
+	args := pgx.NamedArgs{
+		"person":         record.Person,
+		"clearanceUntil": record.ClearanceUntil,
+		"bloodType":      bloodType,
+		"allergies":      allergies,
+		"notes":          notes,
+		"updatedBy":      record.UpdatedBy,
+	}
+	_, err = pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to insert row in SetHealthRecord: %w", err)
+	}
+	return nil
+}
+
+func DeleteHealthRecord(pg *db.Postgres, ctx context.Context, person int) error {
+	query := `delete from persons_health where person = @person`
+	args := pgx.NamedArgs{
+		"person": person,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove health record: %w", err)
+	}
+	return nil
+}
+
+// GetClearanceAlerts lists participants of planned tours starting up to @until whose
+// medical clearance is missing or lapses before the start. Only the tours of the
+// given instructor are checked when one is passed.
+func GetClearanceAlerts(pg *db.Postgres, ctx context.Context, until string, instructor pgtype.Int4) ([]model.ClearanceAlert, error) {
+	query := `select persons.id, persons.name, persons.surname, persons.patronymic,
+			         tours.id, tours.instructor, tours.start, persons_health.clearance_until
+			  from tours
+			  join persons_tours
+			  on persons_tours.tour = tours.id
+			  join persons
+			  on persons.id = persons_tours.person
+			  left join persons_health
+			  on persons_health.person = persons.id
+			  where tours.status = 'planned' and persons_tours.status = 'enrolled'
+			    and tours.start between current_date and @until
+			    and (@instructor::int is null or tours.instructor = @instructor)
+			    and (persons_health.clearance_until is null or persons_health.clearance_until < tours.start)
+			  order by tours.start, tours.id, persons.surname, persons.name`
+	args := pgx.NamedArgs{
+		"until":      until,
+		"instructor": instructor,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetClearanceAlerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []model.ClearanceAlert
+	for rows.Next() {
+		alert := model.ClearanceAlert{}
+		err := rows.Scan(&alert.Person.Id, &alert.Person.Name, &alert.Person.Surname, &alert.Person.Patronymic,
+			&alert.Tour, &alert.Instructor, &alert.Start, &alert.ClearanceUntil)
+		if err != nil {
+			return nil, fmt.Errorf("convert to clearance alert model error: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
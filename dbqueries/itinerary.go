@@ -0,0 +1,214 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetRoutePlaceList returns the places of a route in the order the route passes them.
+func GetRoutePlaceList(pg *db.Postgres, ctx context.Context, route int) ([]model.Place, error) {
+	query := `select places.id, places.title
+			  from places_routes
+			  join places
+			  on places.id = places_routes.place
+			  where places_routes.route = @route
+			  order by places_routes.position, places.id`
+	args := pgx.NamedArgs{
+		"route": route,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetRoutePlaceList: %w", err)
+	}
+	defer rows.Close()
+
+	var places []model.Place
+	for rows.Next() {
+		place := model.Place{}
+		err := rows.Scan(&place.Id, &place.Title)
+		if err != nil {
+			return nil, fmt.Errorf("convert to place model error: %w", err)
+		}
+		places = append(places, place)
+	}
+	return places, nil
+}
+
+// IsRouteOrdered tells whether the order of the places is known for the whole route.
+func IsRouteOrdered(pg *db.Postgres, ctx context.Context, route int) (bool, error) {
+	query := `select coalesce(bool_and(position is not null), false)
+			  from places_routes
+			  where route = @route`
+	args := pgx.NamedArgs{
+		"route": route,
+	}
+	var ordered bool
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&ordered)
+	if err != nil {
+		return false, fmt.Errorf("unable to do query IsRouteOrdered: %w", err)
+	}
+	return ordered, nil
+}
+
+// SetRoutePlacePosition puts a place of a route at position in the route order.
+func SetRoutePlacePosition(pg *db.Postgres, ctx context.Context, route int, place int, position int) error {
+	query := `update places_routes
+			  set position = @position
+			  where route = @route and place = @place`
+	args := pgx.NamedArgs{
+		"route":    route,
+		"place":    place,
+		"position": position,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to update row in SetRoutePlacePosition: %w", err)
+	}
+	return nil
+}
+
+func GetRouteLength(pg *db.Postgres, ctx context.Context, route int) (float64, error) {
+	query := `select coalesce(length_km, 0)::float8 from routes where id = @route`
+	args := pgx.NamedArgs{
+		"route": route,
+	}
+	var length float64
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&length)
+	if err != nil {
+		return 0, fmt.Errorf("unable to do query GetRouteLength: %w", err)
+	}
+	return length, nil
+}
+
+func DeleteTourStages(pg *db.Postgres, ctx context.Context, tour int) error {
+	query := `delete from tour_stages where tour = @tour`
+	args := pgx.NamedArgs{
+		"tour": tour,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove stages in DeleteTourStages: %w", err)
+	}
+	return nil
+}
+
+func AddTourStage(pg *db.Postgres, ctx context.Context, stage model.TourStage) (int, error) {
+	query := `insert into tour_stages (tour, day, start_place, end_place, distance_km, camp)
+			  values (@tour, @day, @startPlace, @endPlace, @distance, @camp)
+			  returning id`
+	args := pgx.NamedArgs{
+		"tour":       stage.Tour,
+		"day":        stage.Day,
+		"startPlace": stage.StartPlace.Id,
+		"endPlace":   stage.EndPlace.Id,
+		"distance":   stage.DistanceKm,
+		"camp":       stage.Camp,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in AddTourStage: %w", err)
+	}
+
+	query = `insert into tour_stage_checkpoints (stage, position, place, title, planned_time)
+			  values (@stage, @position, @place, @title, @plannedTime)`
+	for _, checkpoint := range stage.Checkpoints {
+		args = pgx.NamedArgs{
+			"stage":       id,
+			"position":    checkpoint.Position,
+			"place":       checkpoint.Place,
+			"title":       checkpoint.Title,
+			"plannedTime": checkpoint.PlannedTime,
+		}
+		_, err = pg.Db.Exec(ctx, query, args)
+		if err != nil {
+			return 0, fmt.Errorf("unable to insert checkpoint in AddTourStage: %w", err)
+		}
+	}
+	return id, nil
+}
+
+// GetTourStages returns the itinerary of a tour by day, each stage with its
+// checkpoints in order. A day of zero returns every stage.
+func GetTourStages(pg *db.Postgres, ctx context.Context, tour int, day int) ([]model.TourStage, error) {
+	query := `select tour_stages.id, tour_stages.tour, tour_stages.day,
+			         start_place.id, start_place.title, end_place.id, end_place.title,
+			         tour_stages.distance_km::float8, tour_stages.camp
+			  from tour_stages
+			  join places as start_place
+			  on start_place.id = tour_stages.start_place
+			  join places as end_place
+			  on end_place.id = tour_stages.end_place
+			  where tour_stages.tour = @tour and (@day = 0 or tour_stages.day = @day)
+			  order by tour_stages.day`
+	args := pgx.NamedArgs{
+		"tour": tour,
+		"day":  day,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetTourStages: %w", err)
+	}
+
+	var stages []model.TourStage
+	for rows.Next() {
+		stage := model.TourStage{}
+		err := rows.Scan(&stage.Id, &stage.Tour, &stage.Day, &stage.StartPlace.Id, &stage.StartPlace.Title,
+			&stage.EndPlace.Id, &stage.EndPlace.Title, &stage.DistanceKm, &stage.Camp)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("convert to tour stage model error: %w", err)
+		}
+		stages = append(stages, stage)
+	}
+	rows.Close()
+
+	query = `select id, stage, position, place, title, planned_time
+			  from tour_stage_checkpoints
+			  where stage = @stage
+			  order by position, planned_time`
+	for i := range stages {
+		args = pgx.NamedArgs{
+			"stage": stages[i].Id,
+		}
+		rows, err := pg.Db.Query(ctx, query, args)
+		if err != nil {
+			return nil, fmt.Errorf("unable to do query GetTourStages: %w", err)
+		}
+		for rows.Next() {
+			checkpoint := model.StageCheckpoint{}
+			err := rows.Scan(&checkpoint.Id, &checkpoint.Stage, &checkpoint.Position, &checkpoint.Place,
+				&checkpoint.Title, &checkpoint.PlannedTime)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("convert to stage checkpoint model error: %w", err)
+			}
+			stages[i].Checkpoints = append(stages[i].Checkpoints, checkpoint)
+		}
+		rows.Close()
+	}
+	return stages, nil
+}
+
+// GetToursOnDate returns the tours that are under way on the date, the same
+// window GetTouristsByTourTime uses for people.
+func GetToursOnDate(pg *db.Postgres, ctx context.Context, date string) ([]model.Tour, error) {
+	query := `select ` + tourColumns + `
+			  from tours
+			  where (@date::date - tours.start) < tours.duration_days and (@date::date - tours.start) >= 0
+			    and tours.status <> 'cancelled'
+			  order by tours.id`
+	args := pgx.NamedArgs{
+		"date": date,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetToursOnDate: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2Tours(rows)
+}
@@ -0,0 +1,238 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func rows2PayrollLines(rows pgx.Rows) ([]model.PayrollLine, error) {
+	var lines []model.PayrollLine
+	for rows.Next() {
+		line := model.PayrollLine{}
+		err := rows.Scan(&line.Trainer.Id, &line.Trainer.Name, &line.Trainer.Surname, &line.Trainer.Patronymic,
+			&line.WorkoutType, &line.Minutes, &line.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("convert to payroll line model error: %w", err)
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+func rows2PayPeriods(rows pgx.Rows) ([]model.PayPeriod, error) {
+	var periods []model.PayPeriod
+	for rows.Next() {
+		period := model.PayPeriod{}
+		err := rows.Scan(&period.Id, &period.Section, &period.Month, &period.ClosedAt, &period.Total)
+		if err != nil {
+			return nil, fmt.Errorf("convert to pay period model error: %w", err)
+		}
+		periods = append(periods, period)
+	}
+	return periods, nil
+}
+
+func CreatePayrollRate(pg *db.Postgres, ctx context.Context, rate model.PayrollRate) (int, error) {
+	query := `insert into payroll_rates (trainer, workout_type, hourly_rate)
+			  values (@trainer, @workoutType, @rate::numeric / 100)
+			  returning id`
+	args := pgx.NamedArgs{
+		"trainer":     rate.Trainer,
+		"workoutType": rate.WorkoutType,
+		"rate":        rate.HourlyRate,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreatePayrollRate: %w", err)
+	}
+	return id, nil
+}
+
+func DeletePayrollRate(pg *db.Postgres, ctx context.Context, id int) error {
+	query := `delete from payroll_rates where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove rate in DeletePayrollRate: %w", err)
+	}
+	return nil
+}
+
+func GetPayrollRates(pg *db.Postgres, ctx context.Context, trainer pgtype.Int4) ([]model.PayrollRate, error) {
+	query := `select id, trainer, workout_type, (hourly_rate * 100)::bigint
+			  from payroll_rates
+			  where @trainer::int is null or trainer = @trainer or trainer is null
+			  order by trainer nulls first, workout_type nulls first`
+	args := pgx.NamedArgs{
+		"trainer": trainer,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetPayrollRates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []model.PayrollRate
+	for rows.Next() {
+		rate := model.PayrollRate{}
+		err := rows.Scan(&rate.Id, &rate.Trainer, &rate.WorkoutType, &rate.HourlyRate)
+		if err != nil {
+			return nil, fmt.Errorf("convert to payroll rate model error: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// GetDeliveredHours sums the sessions held by the section's trainers for its groups
+// between @from and @to. A session counts as delivered when somebody was marked
+// present or late, or when attendance was not taken at all. The most specific
+// rate wins: trainer and type, then trainer, then type, then the default one.
+func GetDeliveredHours(pg *db.Postgres, ctx context.Context, section int, from string, to string) ([]model.PayrollLine, error) {
+	query := `select persons.id, persons.name, persons.surname, persons.patronymic, delivered.type, delivered.minutes,
+			         (select (pr.hourly_rate * 100)::bigint
+			          from payroll_rates as pr
+			          where (pr.trainer = delivered.trainer or pr.trainer is null)
+			            and (pr.workout_type = delivered.type or pr.workout_type is null)
+			          order by pr.trainer is null, pr.workout_type is null
+			          limit 1)
+			  from (
+			      select sessions.trainer, sessions.type,
+			             (extract(epoch from sum(sessions.finish_time - sessions.start_time)) / 60)::int as minutes
+			      from (
+			          select distinct workouts.id, wd.trainer, coalesce(wdat.value, '') as type,
+			                 workouts.start_time, workouts.finish_time
+			          from workouts
+			          join workout_descriptions as wd
+			          on wd.id = workouts.description
+			          left join workout_descrs_attrs_text as wdat
+			          on wdat.descr = wd.id
+			          join groups_workouts
+			          on groups_workouts.workout = wd.id
+			          join groups
+			          on groups.id = groups_workouts.group_id
+			          join persons_roles
+			          on persons_roles.person = wd.trainer and persons_roles.section = groups.section
+			          where groups.section = @section and persons_roles.role = 2
+			            and workouts.date between @from and @to and workouts.date <= current_date
+			            and (exists (select 1 from workout_attendance as wa
+			                         where wa.workout = workouts.id and wa.status in ('present', 'late'))
+			                 or not exists (select 1 from workout_attendance as wa where wa.workout = workouts.id))
+			      ) as sessions
+			      group by sessions.trainer, sessions.type
+			  ) as delivered
+			  join persons
+			  on persons.id = delivered.trainer
+			  order by persons.surname, persons.name, delivered.type`
+	args := pgx.NamedArgs{
+		"section": section,
+		"from":    from,
+		"to":      to,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetDeliveredHours: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2PayrollLines(rows)
+}
+
+func GetPayPeriod(pg *db.Postgres, ctx context.Context, section int, month string) (*model.PayPeriod, error) {
+	query := `select id, section, month, closed_at, (total * 100)::bigint
+			  from pay_periods
+			  where section = @section and month = @month`
+	args := pgx.NamedArgs{
+		"section": section,
+		"month":   month,
+	}
+	var period model.PayPeriod
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&period.Id, &period.Section, &period.Month, &period.ClosedAt, &period.Total)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetPayPeriod: %w", err)
+	}
+	return &period, nil
+}
+
+func GetPayPeriods(pg *db.Postgres, ctx context.Context, section pgtype.Int4) ([]model.PayPeriod, error) {
+	query := `select id, section, month, closed_at, (total * 100)::bigint
+			  from pay_periods
+			  where @section::int is null or section = @section
+			  order by month desc, section`
+	args := pgx.NamedArgs{
+		"section": section,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetPayPeriods: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2PayPeriods(rows)
+}
+
+func CreatePayPeriod(pg *db.Postgres, ctx context.Context, period model.PayPeriod) (int, error) {
+	query := `insert into pay_periods (section, month, total)
+			  values (@section, @month, @total::numeric / 100)
+			  returning id`
+	args := pgx.NamedArgs{
+		"section": period.Section,
+		"month":   period.Month,
+		"total":   period.Total,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreatePayPeriod: %w", err)
+	}
+	return id, nil
+}
+
+func AddPayrollEntry(pg *db.Postgres, ctx context.Context, period int, line model.PayrollLine) error {
+	query := `insert into payroll_entries (period, trainer, workout_type, minutes, rate, amount)
+			  values (@period, @trainer, @workoutType, @minutes, @rate::numeric / 100, @amount::numeric / 100)`
+	args := pgx.NamedArgs{
+		"period":      period,
+		"trainer":     line.Trainer.Id,
+		"workoutType": line.WorkoutType,
+		"minutes":     line.Minutes,
+		"rate":        line.Rate,
+		"amount":      line.Amount(),
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to insert row in AddPayrollEntry: %w", err)
+	}
+	return nil
+}
+
+func GetPayrollEntries(pg *db.Postgres, ctx context.Context, period int) ([]model.PayrollLine, error) {
+	query := `select persons.id, persons.name, persons.surname, persons.patronymic,
+			         payroll_entries.workout_type, payroll_entries.minutes, (payroll_entries.rate * 100)::bigint
+			  from payroll_entries
+			  join persons
+			  on persons.id = payroll_entries.trainer
+			  where payroll_entries.period = @period
+			  order by persons.surname, persons.name, payroll_entries.workout_type`
+	args := pgx.NamedArgs{
+		"period": period,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetPayrollEntries: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2PayrollLines(rows)
+}
@@ -4,25 +4,46 @@ import (
 	"context"
 	"db_backend/db"
 	"db_backend/model"
+	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"log"
 )
 
-func InsertPerson(pg *db.Postgres, ctx context.Context, person model.Person) error {
-	query := `INSERT INTO persons (name,surname,patronymic) VALUES (@name, @surname, @patronymic)`
+func InsertPerson(pg *db.Postgres, ctx context.Context, person model.Person) (int, error) {
+	query := `INSERT INTO persons (name,surname,patronymic) VALUES (@name, @surname, @patronymic) RETURNING id`
 	args := pgx.NamedArgs{
 		"name":       person.Name,
 		"surname":    person.Surname,
 		"patronymic": person.Patronymic,
 	}
-	_, err := pg.Db.Exec(ctx, query, args)
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
 	if err != nil {
-		return fmt.Errorf("unable to insert row: %w", err)
+		return 0, fmt.Errorf("unable to insert row: %w", err)
 	}
 
-	return nil
+	return id, nil
+}
+
+// FindPersonsByName returns the persons with exactly this full name, ignoring case.
+func FindPersonsByName(pg *db.Postgres, ctx context.Context, person model.Person) ([]model.Person, error) {
+	query := `SELECT id, name, surname, patronymic FROM persons
+			  WHERE lower(surname) = lower(@surname) AND lower(name) = lower(@name)
+			    AND lower(coalesce(patronymic, '')) = lower(@patronymic)`
+	args := pgx.NamedArgs{
+		"name":       person.Name,
+		"surname":    person.Surname,
+		"patronymic": person.Patronymic,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query FindPersonsByName: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2Persons(rows)
 }
 
 func GetPerson(pg *db.Postgres, ctx context.Context, id int) (*model.Person, error) {
@@ -132,20 +153,26 @@ func DeletePersonRole(pg *db.Postgres, ctx context.Context, person int, section
 	return nil
 }
 
+// UpdatePersonRole replaces the role of the person in the section. A member who
+// only changes role keeps the date they joined the section.
 func UpdatePersonRole(pg *db.Postgres, ctx context.Context, person int, section int, role int) error {
-	query := `DELETE FROM persons_roles WHERE person = @person AND section = @section`
+	query := `DELETE FROM persons_roles WHERE person = @person AND section = @section RETURNING joined_on`
 	args := pgx.NamedArgs{
 		"person":  person,
 		"section": section,
 		"role":    role,
 	}
-	_, err := pg.Db.Exec(ctx, query, args)
-	if err != nil {
+	var joinedOn pgtype.Date
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&joinedOn)
+	member := err == nil
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 		return fmt.Errorf("unable to remove old role of user in UpdatePersonRole: %w", err)
 	}
 
-	query = `INSERT INTO persons_roles (person, section, role) VALUES (@person, @section, @role)`
-
+	query = `INSERT INTO persons_roles (person, section, role, joined_on)
+			 VALUES (@person, @section, @role, CASE WHEN @member THEN @joinedOn::date ELSE current_date END)`
+	args["member"] = member
+	args["joinedOn"] = joinedOn
 	_, err = pg.Db.Exec(ctx, query, args)
 	if err != nil {
 		return fmt.Errorf("unable to insert row in UpdatePersonRole: %w", err)
@@ -977,8 +1004,8 @@ func GetTouristsByRouteType(pg *db.Postgres, ctx context.Context, routeTypeId in
 			  on persons.id = persons_tours.person
 			  join tours
 			  on persons_tours.tour = tours.id
-			  join routes as rt on rt.id = ts.route
-			  where rt.type=@typeId
+			  join routes as rt on rt.id = tours.route
+			  where rt.type=@typeId and persons_tours.outcome = 'completed'
 			  group by persons.id)
 			  where lvl >= @diff`
 	args := pgx.NamedArgs{
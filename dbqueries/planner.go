@@ -0,0 +1,331 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func GetPersonsByIds(pg *db.Postgres, ctx context.Context, ids []int32) ([]model.Person, error) {
+	query := `select id, name, surname, patronymic from persons where id = any(@ids) order by id`
+	args := pgx.NamedArgs{
+		"ids": ids,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetPersonsByIds: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2Persons(rows)
+}
+
+// GetRouteTypeLevelsOf is GetPersonRouteTypeLevels for several persons at once.
+func GetRouteTypeLevelsOf(pg *db.Postgres, ctx context.Context, persons []int32) ([]model.RouteTypeLevel, error) {
+	query := `select persons_tours.person, rt.type, coalesce(route_types.type, ''), max(rt.difficulty)::int,
+			         count(distinct rt.id)::int
+			  from persons_tours
+			  join tours
+			  on persons_tours.tour = tours.id
+			  join routes as rt on rt.id = tours.route
+			  left join route_types
+			  on route_types.id = rt.type
+			  where persons_tours.person = any(@persons) and persons_tours.outcome = 'completed'
+			  group by persons_tours.person, rt.type, route_types.type`
+	args := pgx.NamedArgs{
+		"persons": persons,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetRouteTypeLevelsOf: %w", err)
+	}
+	defer rows.Close()
+
+	var levels []model.RouteTypeLevel
+	for rows.Next() {
+		level := model.RouteTypeLevel{}
+		err := rows.Scan(&level.Person, &level.Type, &level.Title, &level.MaxDifficulty, &level.Completed)
+		if err != nil {
+			return nil, fmt.Errorf("convert to route type level model error: %w", err)
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// GetGroupMemberships lists the groups the persons belong to, limited to one
+// section unless section is null.
+func GetGroupMemberships(pg *db.Postgres, ctx context.Context, persons []int32, section pgtype.Int4) ([]model.GroupMembership, error) {
+	query := `select groups_persons.group_id, groups_persons.person
+			  from groups_persons
+			  join groups
+			  on groups.id = groups_persons.group_id
+			  where groups_persons.person = any(@persons)
+			    and (@section::int is null or groups.section = @section)
+			  order by groups_persons.group_id, groups_persons.person`
+	args := pgx.NamedArgs{
+		"persons": persons,
+		"section": section,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetGroupMemberships: %w", err)
+	}
+	defer rows.Close()
+
+	var memberships []model.GroupMembership
+	for rows.Next() {
+		membership := model.GroupMembership{}
+		err := rows.Scan(&membership.Group, &membership.Person)
+		if err != nil {
+			return nil, fmt.Errorf("convert to group membership model error: %w", err)
+		}
+		memberships = append(memberships, membership)
+	}
+	return memberships, nil
+}
+
+// GetBusyPersons returns who already leads or is enrolled in a planned or
+// active tour overlapping the days from..to.
+func GetBusyPersons(pg *db.Postgres, ctx context.Context, from string, to string) ([]int32, error) {
+	query := `select tours.instructor
+			  from tours
+			  where tours.status in ('planned', 'active')
+			    and tours.start < @to::date and tours.start + tours.duration_days > @from::date
+			  union
+			  select persons_tours.person
+			  from tours
+			  join persons_tours
+			  on persons_tours.tour = tours.id
+			  where tours.status in ('planned', 'active') and persons_tours.status = 'enrolled'
+			    and tours.start < @to::date and tours.start + tours.duration_days > @from::date`
+	args := pgx.NamedArgs{
+		"from": from,
+		"to":   to,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetBusyPersons: %w", err)
+	}
+	defer rows.Close()
+
+	var persons []int32
+	for rows.Next() {
+		var person int32
+		err := rows.Scan(&person)
+		if err != nil {
+			return nil, fmt.Errorf("unable to do query GetBusyPersons: %w", err)
+		}
+		persons = append(persons, person)
+	}
+	return persons, nil
+}
+
+func CreatePlanDraft(pg *db.Postgres, ctx context.Context, draft model.PlanDraft) (int, error) {
+	query := `insert into tour_plan_drafts (section, created_by, instructor_ratio, warnings)
+			  values (@section, @createdBy, @ratio, @warnings)
+			  returning id`
+	args := pgx.NamedArgs{
+		"section":   draft.Section,
+		"createdBy": draft.CreatedBy,
+		"ratio":     draft.InstructorRatio,
+		"warnings":  draft.Warnings,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreatePlanDraft: %w", err)
+	}
+
+	for _, tour := range draft.Tours {
+		query = `insert into tour_plan_draft_tours (draft, route, start, duration_days, instructor, min_participants, max_participants)
+				 values (@draft, @route, @start, @durationDays, @instructor, @minParticipants, @maxParticipants)
+				 returning id`
+		args = pgx.NamedArgs{
+			"draft":           id,
+			"route":           tour.Route,
+			"start":           tour.Start,
+			"durationDays":    tour.DurationDays,
+			"instructor":      tour.Instructor,
+			"minParticipants": tour.MinParticipants,
+			"maxParticipants": tour.MaxParticipants,
+		}
+		var draftTour int
+		err := pg.Db.QueryRow(ctx, query, args).Scan(&draftTour)
+		if err != nil {
+			return 0, fmt.Errorf("unable to insert row in CreatePlanDraft: %w", err)
+		}
+
+		query = `insert into tour_plan_draft_members (draft_tour, person)
+				 select @draftTour, unnest(@members::int[])`
+		args = pgx.NamedArgs{
+			"draftTour": draftTour,
+			"members":   tour.Members,
+		}
+		_, err = pg.Db.Exec(ctx, query, args)
+		if err != nil {
+			return 0, fmt.Errorf("unable to insert row in CreatePlanDraft: %w", err)
+		}
+	}
+
+	for _, unassigned := range draft.Unassigned {
+		query = `insert into tour_plan_draft_unassigned (draft, person, reason) values (@draft, @person, @reason)`
+		args = pgx.NamedArgs{
+			"draft":  id,
+			"person": unassigned.Person,
+			"reason": unassigned.Reason,
+		}
+		_, err = pg.Db.Exec(ctx, query, args)
+		if err != nil {
+			return 0, fmt.Errorf("unable to insert row in CreatePlanDraft: %w", err)
+		}
+	}
+	return id, nil
+}
+
+func getPlanDraft(pg *db.Postgres, ctx context.Context, query string, id int) (*model.PlanDraft, error) {
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	var draft model.PlanDraft
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&draft.Id, &draft.Section, &draft.CreatedBy, &draft.CreatedAt,
+		&draft.InstructorRatio, &draft.Status, &draft.AcceptedAt, &draft.Warnings)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve plan draft: %w", err)
+	}
+
+	query = `select tour_plan_draft_tours.id, draft, route, start, duration_days, instructor,
+			        min_participants, max_participants, tour,
+			        coalesce(array_agg(tour_plan_draft_members.person order by tour_plan_draft_members.person)
+			                 filter (where tour_plan_draft_members.person is not null), '{}')
+			 from tour_plan_draft_tours
+			 left join tour_plan_draft_members
+			 on tour_plan_draft_members.draft_tour = tour_plan_draft_tours.id
+			 where draft = @id
+			 group by tour_plan_draft_tours.id
+			 order by tour_plan_draft_tours.id`
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve plan draft tours: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		tour := model.PlanDraftTour{}
+		err := rows.Scan(&tour.Id, &tour.Draft, &tour.Route, &tour.Start, &tour.DurationDays, &tour.Instructor,
+			&tour.MinParticipants, &tour.MaxParticipants, &tour.Tour, &tour.Members)
+		if err != nil {
+			return nil, fmt.Errorf("convert to plan draft tour model error: %w", err)
+		}
+		draft.Tours = append(draft.Tours, tour)
+	}
+	rows.Close()
+
+	query = `select person, reason from tour_plan_draft_unassigned where draft = @id order by person`
+	rows, err = pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve plan draft unassigned: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		unassigned := model.PlanUnassigned{}
+		err := rows.Scan(&unassigned.Person, &unassigned.Reason)
+		if err != nil {
+			return nil, fmt.Errorf("convert to plan unassigned model error: %w", err)
+		}
+		draft.Unassigned = append(draft.Unassigned, unassigned)
+	}
+	return &draft, nil
+}
+
+const planDraftColumns = `id, section, created_by, created_at, instructor_ratio, status, accepted_at, warnings`
+
+func GetPlanDraft(pg *db.Postgres, ctx context.Context, id int) (*model.PlanDraft, error) {
+	query := `select ` + planDraftColumns + ` from tour_plan_drafts where id = @id`
+	return getPlanDraft(pg, ctx, query, id)
+}
+
+func GetPlanDraftForUpdate(pg *db.Postgres, ctx context.Context, id int) (*model.PlanDraft, error) {
+	query := `select ` + planDraftColumns + ` from tour_plan_drafts where id = @id for update`
+	return getPlanDraft(pg, ctx, query, id)
+}
+
+func SetPlanDraftStatus(pg *db.Postgres, ctx context.Context, id int, status string) error {
+	query := `update tour_plan_drafts
+			  set status = @status, accepted_at = case when @status::text = 'accepted' then now() end
+			  where id = @id`
+	args := pgx.NamedArgs{
+		"id":     id,
+		"status": status,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to update row in SetPlanDraftStatus: %w", err)
+	}
+	return nil
+}
+
+func SetPlanDraftTour(pg *db.Postgres, ctx context.Context, draftTour int, tour int) error {
+	query := `update tour_plan_draft_tours set tour = @tour where id = @id`
+	args := pgx.NamedArgs{
+		"id":   draftTour,
+		"tour": tour,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to update row in SetPlanDraftTour: %w", err)
+	}
+	return nil
+}
+
+// UnassignPlanDraftMember takes a person off a tour of a draft and records why.
+func UnassignPlanDraftMember(pg *db.Postgres, ctx context.Context, draft int, draftTour int, person int, reason string) error {
+	query := `delete from tour_plan_draft_members where draft_tour = @draftTour and person = @person`
+	args := pgx.NamedArgs{
+		"draftTour": draftTour,
+		"person":    person,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove member in UnassignPlanDraftMember: %w", err)
+	}
+
+	query = `insert into tour_plan_draft_unassigned (draft, person, reason) values (@draft, @person, @reason)
+			 on conflict (draft, person) do update set reason = excluded.reason`
+	args = pgx.NamedArgs{
+		"draft":  draft,
+		"person": person,
+		"reason": reason,
+	}
+	_, err = pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to insert row in UnassignPlanDraftMember: %w", err)
+	}
+	return nil
+}
+
+func CreateTour(pg *db.Postgres, ctx context.Context, tour model.Tour) (int, error) {
+	query := `insert into tours (route, instructor, start, duration_days, min_participants, max_participants)
+			  values (@route, @instructor, @start, @durationDays, @minParticipants, @maxParticipants)
+			  returning id`
+	args := pgx.NamedArgs{
+		"route":           tour.Route,
+		"instructor":      tour.Instructor,
+		"start":           tour.Start,
+		"durationDays":    tour.DurationDays,
+		"minParticipants": tour.MinParticipants,
+		"maxParticipants": tour.MaxParticipants,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateTour: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,262 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+func rows2TrainingPlans(rows pgx.Rows) ([]model.TrainingPlan, error) {
+	var plans []model.TrainingPlan
+	for rows.Next() {
+		plan := model.TrainingPlan{}
+		err := rows.Scan(&plan.Id, &plan.Group, &plan.Title, &plan.StartsOn, &plan.EndsOn)
+		if err != nil {
+			return nil, fmt.Errorf("convert to training plan model error: %w", err)
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+func CreateTrainingPlan(pg *db.Postgres, ctx context.Context, plan model.TrainingPlan) (int, error) {
+	query := `insert into training_plans (group_id, title, starts_on, ends_on)
+			  values (@group, @title, @startsOn, @endsOn)
+			  returning id`
+	args := pgx.NamedArgs{
+		"group":    plan.Group,
+		"title":    plan.Title,
+		"startsOn": plan.StartsOn,
+		"endsOn":   plan.EndsOn,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateTrainingPlan: %w", err)
+	}
+	return id, nil
+}
+
+func GetTrainingPlan(pg *db.Postgres, ctx context.Context, id int) (*model.TrainingPlan, error) {
+	query := `select id, group_id, title, starts_on, ends_on from training_plans where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	var plan model.TrainingPlan
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&plan.Id, &plan.Group, &plan.Title, &plan.StartsOn, &plan.EndsOn)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve plan in GetTrainingPlan: %w", err)
+	}
+	return &plan, nil
+}
+
+func GetTrainingPlans(pg *db.Postgres, ctx context.Context, group int) ([]model.TrainingPlan, error) {
+	query := `select id, group_id, title, starts_on, ends_on
+			  from training_plans
+			  where group_id = @group
+			  order by starts_on desc`
+	args := pgx.NamedArgs{
+		"group": group,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetTrainingPlans: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2TrainingPlans(rows)
+}
+
+func DeleteTrainingPlan(pg *db.Postgres, ctx context.Context, id int) error {
+	query := `delete from training_plans where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove plan in DeleteTrainingPlan: %w", err)
+	}
+	return nil
+}
+
+func CreateTrainingCycle(pg *db.Postgres, ctx context.Context, cycle model.TrainingCycle) (int, error) {
+	query := `insert into training_cycles (plan, parent, kind, title, starts_on, ends_on)
+			  values (@plan, @parent, @kind, @title, @startsOn, @endsOn)
+			  returning id`
+	args := pgx.NamedArgs{
+		"plan":     cycle.Plan,
+		"parent":   cycle.Parent,
+		"kind":     cycle.Kind,
+		"title":    cycle.Title,
+		"startsOn": cycle.StartsOn,
+		"endsOn":   cycle.EndsOn,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateTrainingCycle: %w", err)
+	}
+	return id, nil
+}
+
+func DeleteTrainingCycle(pg *db.Postgres, ctx context.Context, id int) error {
+	query := `delete from training_cycles where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove cycle in DeleteTrainingCycle: %w", err)
+	}
+	return nil
+}
+
+// GetTrainingCycles returns the cycles of a plan, mesocycles first, each group by start date.
+func GetTrainingCycles(pg *db.Postgres, ctx context.Context, plan int) ([]model.TrainingCycle, error) {
+	query := `select id, plan, parent, kind, title, starts_on, ends_on
+			  from training_cycles
+			  where plan = @plan
+			  order by kind, starts_on, id`
+	args := pgx.NamedArgs{
+		"plan": plan,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetTrainingCycles: %w", err)
+	}
+	defer rows.Close()
+
+	var cycles []model.TrainingCycle
+	for rows.Next() {
+		cycle := model.TrainingCycle{}
+		err := rows.Scan(&cycle.Id, &cycle.Plan, &cycle.Parent, &cycle.Kind, &cycle.Title, &cycle.StartsOn, &cycle.EndsOn)
+		if err != nil {
+			return nil, fmt.Errorf("convert to training cycle model error: %w", err)
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles, nil
+}
+
+func GetTrainingCycle(pg *db.Postgres, ctx context.Context, id int) (*model.TrainingCycle, error) {
+	query := `select id, plan, parent, kind, title, starts_on, ends_on from training_cycles where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	var cycle model.TrainingCycle
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&cycle.Id, &cycle.Plan, &cycle.Parent, &cycle.Kind, &cycle.Title,
+		&cycle.StartsOn, &cycle.EndsOn)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve cycle in GetTrainingCycle: %w", err)
+	}
+	return &cycle, nil
+}
+
+func SetCycleTargets(pg *db.Postgres, ctx context.Context, cycle int, targets []model.CycleTarget) error {
+	query := `delete from training_cycle_targets where cycle = @cycle`
+	args := pgx.NamedArgs{
+		"cycle": cycle,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to remove old targets in SetCycleTargets: %w", err)
+	}
+
+	query = `insert into training_cycle_targets (cycle, workout_type, minutes) values (@cycle, @workoutType, @minutes)`
+	for _, target := range targets {
+		args = pgx.NamedArgs{
+			"cycle":       cycle,
+			"workoutType": target.WorkoutType,
+			"minutes":     target.Minutes,
+		}
+		_, err = pg.Db.Exec(ctx, query, args)
+		if err != nil {
+			return fmt.Errorf("unable to insert row in SetCycleTargets: %w", err)
+		}
+	}
+	return nil
+}
+
+func GetPlanTargets(pg *db.Postgres, ctx context.Context, plan int) ([]model.CycleTarget, error) {
+	query := `select training_cycle_targets.cycle, training_cycle_targets.workout_type, training_cycle_targets.minutes
+			  from training_cycle_targets
+			  join training_cycles
+			  on training_cycles.id = training_cycle_targets.cycle
+			  where training_cycles.plan = @plan
+			  order by training_cycle_targets.cycle, training_cycle_targets.workout_type`
+	args := pgx.NamedArgs{
+		"plan": plan,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetPlanTargets: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []model.CycleTarget
+	for rows.Next() {
+		target := model.CycleTarget{}
+		err := rows.Scan(&target.Cycle, &target.WorkoutType, &target.Minutes)
+		if err != nil {
+			return nil, fmt.Errorf("convert to cycle target model error: %w", err)
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// GetPlanVolumes sums the group's workouts held up to @date per workout type for
+// every cycle of the plan and, under cycle zero, for the whole plan.
+func GetPlanVolumes(pg *db.Postgres, ctx context.Context, plan int, date string) ([]model.CycleVolume, error) {
+	query := `with periods as (
+			      select id as cycle, starts_on, ends_on from training_cycles where plan = @plan
+			      union all
+			      select 0, starts_on, ends_on from training_plans where id = @plan
+			  ), sessions as (
+			      select distinct workouts.id, coalesce(wdat.value, '') as type, workouts.date,
+			             workouts.finish_time - workouts.start_time as duration
+			      from training_plans
+			      join groups_workouts
+			      on groups_workouts.group_id = training_plans.group_id
+			      join workouts
+			      on workouts.description = groups_workouts.workout
+			      left join workout_descrs_attrs_text as wdat
+			      on wdat.descr = workouts.description
+			      where training_plans.id = @plan and workouts.date <= @date
+			  )
+			  select periods.cycle, sessions.type, (extract(epoch from sum(sessions.duration)) / 60)::int
+			  from periods
+			  join sessions
+			  on sessions.date between periods.starts_on and periods.ends_on
+			  group by periods.cycle, sessions.type
+			  order by periods.cycle, sessions.type`
+	args := pgx.NamedArgs{
+		"plan": plan,
+		"date": date,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetPlanVolumes: %w", err)
+	}
+	defer rows.Close()
+
+	var volumes []model.CycleVolume
+	for rows.Next() {
+		volume := model.CycleVolume{}
+		err := rows.Scan(&volume.Cycle, &volume.WorkoutType, &volume.Minutes)
+		if err != nil {
+			return nil, fmt.Errorf("convert to cycle volume model error: %w", err)
+		}
+		volumes = append(volumes, volume)
+	}
+	return volumes, nil
+}
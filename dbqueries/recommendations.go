@@ -0,0 +1,161 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+// completedRoute is the one definition of having completed the route of a tour,
+// shared by recommendations and reviews: the tour is completed and the
+// participant stayed enrolled to its end with the completed outcome. tours and
+// participants name the joined tours and persons_tours rows.
+func completedRoute(tours string, participants string) string {
+	return fmt.Sprintf(`%[1]s.status = 'completed' and %[2]s.status = 'enrolled' and %[2]s.outcome = 'completed'`,
+		tours, participants)
+}
+
+// GetPersonRouteTypeLevels returns, per route type, the highest difficulty the
+// person has completed.
+func GetPersonRouteTypeLevels(pg *db.Postgres, ctx context.Context, person int) ([]model.RouteTypeLevel, error) {
+	query := `select rt.type, coalesce(route_types.type, ''), max(rt.difficulty)::int, count(distinct rt.id)::int
+			  from persons_tours
+			  join tours
+			  on persons_tours.tour = tours.id
+			  join routes as rt on rt.id = tours.route
+			  left join route_types
+			  on route_types.id = rt.type
+			  where persons_tours.person = @person and ` + completedRoute("tours", "persons_tours") + `
+			  group by rt.type, route_types.type`
+	args := pgx.NamedArgs{
+		"person": person,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetPersonRouteTypeLevels: %w", err)
+	}
+	defer rows.Close()
+
+	var levels []model.RouteTypeLevel
+	for rows.Next() {
+		level := model.RouteTypeLevel{}
+		err := rows.Scan(&level.Type, &level.Title, &level.MaxDifficulty, &level.Completed)
+		if err != nil {
+			return nil, fmt.Errorf("convert to route type level model error: %w", err)
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+func GetPersonCompletedRoutes(pg *db.Postgres, ctx context.Context, person int) ([]model.RouteId, error) {
+	query := `select distinct tours.route
+			  from persons_tours
+			  join tours
+			  on persons_tours.tour = tours.id
+			  where persons_tours.person = @person and ` + completedRoute("tours", "persons_tours")
+	args := pgx.NamedArgs{
+		"person": person,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetPersonCompletedRoutes: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2RouteIds(rows)
+}
+
+// GetPersonVisitedPlaces returns the places on the routes the person completed.
+func GetPersonVisitedPlaces(pg *db.Postgres, ctx context.Context, person int) ([]model.Place, error) {
+	query := `select distinct places.id, places.title
+			  from persons_tours
+			  join tours
+			  on persons_tours.tour = tours.id
+			  join places_routes
+			  on places_routes.route = tours.route
+			  join places
+			  on places.id = places_routes.place
+			  where persons_tours.person = @person and ` + completedRoute("tours", "persons_tours")
+	args := pgx.NamedArgs{
+		"person": person,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetPersonVisitedPlaces: %w", err)
+	}
+	defer rows.Close()
+
+	var places []model.Place
+	for rows.Next() {
+		place := model.Place{}
+		err := rows.Scan(&place.Id, &place.Title)
+		if err != nil {
+			return nil, fmt.Errorf("convert to place model error: %w", err)
+		}
+		places = append(places, place)
+	}
+	return places, nil
+}
+
+// GetAllRoutePlaces returns the places of every route keyed by route id.
+func GetAllRoutePlaces(pg *db.Postgres, ctx context.Context) (map[int32][]model.Place, error) {
+	query := `select places_routes.route, places.id, places.title
+			  from places_routes
+			  join places
+			  on places.id = places_routes.place
+			  order by places_routes.route, places.id`
+	rows, err := pg.Db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetAllRoutePlaces: %w", err)
+	}
+	defer rows.Close()
+
+	places := map[int32][]model.Place{}
+	for rows.Next() {
+		var route int32
+		place := model.Place{}
+		err := rows.Scan(&route, &place.Id, &place.Title)
+		if err != nil {
+			return nil, fmt.Errorf("convert to place model error: %w", err)
+		}
+		places[route] = append(places[route], place)
+	}
+	return places, nil
+}
+
+// GetSimilarTouristRatings aggregates route reviews left by tourists who
+// completed at least one route the person completed too.
+func GetSimilarTouristRatings(pg *db.Postgres, ctx context.Context, person int) ([]model.RouteRating, error) {
+	query := `with similar as (
+			      select distinct others.person
+			      from persons_tours as mine
+			      join tours as my_tours
+			      on my_tours.id = mine.tour
+			      join tours as their_tours
+			      on their_tours.route = my_tours.route
+			      join persons_tours as others
+			      on others.tour = their_tours.id
+			      where mine.person = @person and ` + completedRoute("my_tours", "mine") + `
+			        and ` + completedRoute("their_tours", "others") + ` and others.person <> @person
+			  )
+			  select route_reviews.route, '', count(*)::int, avg(route_reviews.rating)::float8,
+			         avg(route_reviews.perceived_difficulty)::float8, routes.difficulty::int
+			  from route_reviews
+			  join routes
+			  on routes.id = route_reviews.route
+			  where route_reviews.person in (select person from similar)
+			  group by route_reviews.route, routes.difficulty`
+	args := pgx.NamedArgs{
+		"person": person,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetSimilarTouristRatings: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2RouteRatings(rows)
+}
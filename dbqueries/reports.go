@@ -0,0 +1,217 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+const reportColumns = `tour_reports.id, tour_reports.tour, tour_reports.author, tour_reports.edited_by,
+			  tour_reports.title, tour_reports.body,
+			  tour_reports.gps_track, tour_reports.track_km::float8, tour_reports.published,
+			  tour_reports.created_at, tour_reports.updated_at`
+
+func rows2Reports(rows pgx.Rows) ([]model.TourReport, error) {
+	var reports []model.TourReport
+	for rows.Next() {
+		report := model.TourReport{}
+		err := rows.Scan(&report.Id, &report.Tour, &report.Author, &report.EditedBy, &report.Title, &report.Body,
+			&report.GpsTrack, &report.TrackKm, &report.Published, &report.CreatedAt, &report.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("convert to tour report model error: %w", err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func GetTourReport(pg *db.Postgres, ctx context.Context, tour int) (*model.TourReport, error) {
+	query := `select ` + reportColumns + ` from tour_reports where tour = @tour`
+	args := pgx.NamedArgs{
+		"tour": tour,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetTourReport: %w", err)
+	}
+	defer rows.Close()
+
+	reports, err := rows2Reports(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, nil
+	}
+	return &reports[0], nil
+}
+
+// SaveTourReport creates the report of a tour or replaces its text and track.
+// The report keeps the author who wrote it first; when someone else saves it
+// later, report.Author is recorded as its last editor instead.
+func SaveTourReport(pg *db.Postgres, ctx context.Context, report model.TourReport) (int, error) {
+	query := `insert into tour_reports (tour, author, title, body, gps_track, track_km, published)
+			  values (@tour, @author, @title, @body, @gpsTrack, @trackKm, @published)
+			  on conflict (tour) do update
+			  set title = excluded.title, body = excluded.body,
+			      gps_track = excluded.gps_track, track_km = excluded.track_km,
+			      published = excluded.published, updated_at = now(),
+			      edited_by = nullif(excluded.author, tour_reports.author)
+			  returning id`
+	args := pgx.NamedArgs{
+		"tour":      report.Tour,
+		"author":    report.Author,
+		"title":     report.Title,
+		"body":      report.Body,
+		"gpsTrack":  report.GpsTrack,
+		"trackKm":   report.TrackKm,
+		"published": report.Published,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in SaveTourReport: %w", err)
+	}
+	return id, nil
+}
+
+func DeleteTourReport(pg *db.Postgres, ctx context.Context, tour int) error {
+	query := `delete from tour_reports where tour = @tour`
+	args := pgx.NamedArgs{
+		"tour": tour,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to delete row in DeleteTourReport: %w", err)
+	}
+	return nil
+}
+
+// GetPublishedRouteReports returns the published reports of all tours on the route,
+// the most recent tour first.
+func GetPublishedRouteReports(pg *db.Postgres, ctx context.Context, route int) ([]model.TourReport, error) {
+	query := `select ` + reportColumns + `
+			  from tour_reports
+			  join tours
+			  on tours.id = tour_reports.tour
+			  where tours.route = @route and tour_reports.published
+			  order by tours.start desc, tours.id desc`
+	args := pgx.NamedArgs{
+		"route": route,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetPublishedRouteReports: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2Reports(rows)
+}
+
+const attachmentColumns = `id, report, kind, filename, content_type, size, blob_key, thumb_key, uploaded_at`
+
+func rows2Attachments(rows pgx.Rows) ([]model.ReportAttachment, error) {
+	var attachments []model.ReportAttachment
+	for rows.Next() {
+		attachment := model.ReportAttachment{}
+		err := rows.Scan(&attachment.Id, &attachment.Report, &attachment.Kind, &attachment.Filename,
+			&attachment.ContentType, &attachment.Size, &attachment.BlobKey, &attachment.ThumbKey, &attachment.UploadedAt)
+		if err != nil {
+			return nil, fmt.Errorf("convert to report attachment model error: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+func AddReportAttachment(pg *db.Postgres, ctx context.Context, attachment model.ReportAttachment) (int, error) {
+	query := `insert into tour_report_attachments (report, kind, filename, content_type, size, blob_key, thumb_key)
+			  values (@report, @kind, @filename, @contentType, @size, @blobKey, @thumbKey)
+			  returning id`
+	args := pgx.NamedArgs{
+		"report":      attachment.Report,
+		"kind":        attachment.Kind,
+		"filename":    attachment.Filename,
+		"contentType": attachment.ContentType,
+		"size":        attachment.Size,
+		"blobKey":     attachment.BlobKey,
+		"thumbKey":    attachment.ThumbKey,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in AddReportAttachment: %w", err)
+	}
+	return id, nil
+}
+
+func GetReportAttachments(pg *db.Postgres, ctx context.Context, report int) ([]model.ReportAttachment, error) {
+	query := `select ` + attachmentColumns + `
+			  from tour_report_attachments
+			  where report = @report
+			  order by uploaded_at, id`
+	args := pgx.NamedArgs{
+		"report": report,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetReportAttachments: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2Attachments(rows)
+}
+
+func GetReportAttachment(pg *db.Postgres, ctx context.Context, id int) (*model.ReportAttachment, error) {
+	query := `select ` + attachmentColumns + ` from tour_report_attachments where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	var attachment model.ReportAttachment
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&attachment.Id, &attachment.Report, &attachment.Kind,
+		&attachment.Filename, &attachment.ContentType, &attachment.Size, &attachment.BlobKey,
+		&attachment.ThumbKey, &attachment.UploadedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetReportAttachment: %w", err)
+	}
+	return &attachment, nil
+}
+
+func GetReportById(pg *db.Postgres, ctx context.Context, id int) (*model.TourReport, error) {
+	query := `select ` + reportColumns + ` from tour_reports where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetReportById: %w", err)
+	}
+	defer rows.Close()
+
+	reports, err := rows2Reports(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, nil
+	}
+	return &reports[0], nil
+}
+
+func DeleteReportAttachment(pg *db.Postgres, ctx context.Context, id int) error {
+	query := `delete from tour_report_attachments where id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to delete row in DeleteReportAttachment: %w", err)
+	}
+	return nil
+}
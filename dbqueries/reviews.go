@@ -0,0 +1,240 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+const routeColumns = `routes.id, routes.type, route_types.type, routes.difficulty::int, routes.length_km::float8`
+
+func rows2Routes(rows pgx.Rows) ([]model.Route, error) {
+	var routes []model.Route
+	for rows.Next() {
+		route := model.Route{}
+		err := rows.Scan(&route.Id, &route.TypeId, &route.Type, &route.Difficulty, &route.LengthKm)
+		if err != nil {
+			return nil, fmt.Errorf("convert to route model error: %w", err)
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+func GetRoute(pg *db.Postgres, ctx context.Context, id int) (*model.Route, error) {
+	query := `select ` + routeColumns + `
+			  from routes
+			  left join route_types
+			  on route_types.id = routes.type
+			  where routes.id = @id`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetRoute: %w", err)
+	}
+	defer rows.Close()
+
+	routes, err := rows2Routes(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(routes) == 0 {
+		return nil, nil
+	}
+	return &routes[0], nil
+}
+
+func GetAllRoutes(pg *db.Postgres, ctx context.Context) ([]model.Route, error) {
+	query := `select ` + routeColumns + `
+			  from routes
+			  left join route_types
+			  on route_types.id = routes.type
+			  order by routes.id`
+	rows, err := pg.Db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetAllRoutes: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2Routes(rows)
+}
+
+// GetCompletedTourOnRoute returns the latest completed tour on the route the
+// person went through to the end, or nil when there is none.
+func GetCompletedTourOnRoute(pg *db.Postgres, ctx context.Context, person int, route int) (*model.Tour, error) {
+	query := `select ` + tourColumns + `
+			  from tours
+			  join persons_tours
+			  on persons_tours.tour = tours.id
+			  where tours.route = @route and persons_tours.person = @person
+			    and ` + completedRoute("tours", "persons_tours") + `
+			  order by tours.start desc, tours.id desc
+			  limit 1`
+	args := pgx.NamedArgs{
+		"person": person,
+		"route":  route,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetCompletedTourOnRoute: %w", err)
+	}
+	defer rows.Close()
+
+	tours, err := rows2Tours(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(tours) == 0 {
+		return nil, nil
+	}
+	return &tours[0], nil
+}
+
+func SaveRouteReview(pg *db.Postgres, ctx context.Context, review model.RouteReview) (int, error) {
+	query := `insert into route_reviews (route, person, tour, rating, perceived_difficulty, season, conditions, review)
+			  values (@route, @person, @tour, @rating, @perceived, @season, @conditions, @review)
+			  on conflict (route, person) do update
+			  set tour = excluded.tour, rating = excluded.rating, perceived_difficulty = excluded.perceived_difficulty,
+			      season = excluded.season, conditions = excluded.conditions, review = excluded.review,
+			      updated_at = now()
+			  returning id`
+	args := pgx.NamedArgs{
+		"route":      review.Route,
+		"person":     review.Person.Id,
+		"tour":       review.Tour,
+		"rating":     review.Rating,
+		"perceived":  review.PerceivedDifficulty,
+		"season":     review.Season,
+		"conditions": review.Conditions,
+		"review":     review.Review,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in SaveRouteReview: %w", err)
+	}
+	return id, nil
+}
+
+func DeleteRouteReview(pg *db.Postgres, ctx context.Context, route int, person int) error {
+	query := `delete from route_reviews where route = @route and person = @person`
+	args := pgx.NamedArgs{
+		"route":  route,
+		"person": person,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to delete row in DeleteRouteReview: %w", err)
+	}
+	return nil
+}
+
+func GetRouteReviews(pg *db.Postgres, ctx context.Context, route int) ([]model.RouteReview, error) {
+	query := `select route_reviews.id, route_reviews.route, persons.id, persons.name, persons.surname, persons.patronymic,
+			         route_reviews.tour, route_reviews.rating, route_reviews.perceived_difficulty, route_reviews.season,
+			         route_reviews.conditions, route_reviews.review, route_reviews.created_at, route_reviews.updated_at
+			  from route_reviews
+			  join persons
+			  on persons.id = route_reviews.person
+			  where route_reviews.route = @route
+			  order by route_reviews.updated_at desc, route_reviews.id desc`
+	args := pgx.NamedArgs{
+		"route": route,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetRouteReviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []model.RouteReview
+	for rows.Next() {
+		review := model.RouteReview{}
+		err := rows.Scan(&review.Id, &review.Route, &review.Person.Id, &review.Person.Name, &review.Person.Surname,
+			&review.Person.Patronymic, &review.Tour, &review.Rating, &review.PerceivedDifficulty, &review.Season,
+			&review.Conditions, &review.Review, &review.CreatedAt, &review.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("convert to route review model error: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, nil
+}
+
+func rows2RouteRatings(rows pgx.Rows) ([]model.RouteRating, error) {
+	var ratings []model.RouteRating
+	for rows.Next() {
+		rating := model.RouteRating{}
+		err := rows.Scan(&rating.Route, &rating.Season, &rating.Reviews, &rating.AvgRating, &rating.AvgPerceived,
+			&rating.OfficialDifficulty)
+		if err != nil {
+			return nil, fmt.Errorf("convert to route rating model error: %w", err)
+		}
+		ratings = append(ratings, rating)
+	}
+	return ratings, nil
+}
+
+// GetRouteRatings aggregates the reviews of every given route. Routes without
+// reviews are included with zero reviews and null averages.
+func GetRouteRatings(pg *db.Postgres, ctx context.Context, routes []int32) ([]model.RouteRating, error) {
+	query := `select routes.id, '', count(route_reviews.id)::int,
+			         avg(route_reviews.rating)::float8, avg(route_reviews.perceived_difficulty)::float8,
+			         routes.difficulty::int
+			  from routes
+			  left join route_reviews
+			  on route_reviews.route = routes.id
+			  where routes.id = any(@routes)
+			  group by routes.id`
+	args := pgx.NamedArgs{
+		"routes": routes,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetRouteRatings: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2RouteRatings(rows)
+}
+
+func GetRouteSeasonRatings(pg *db.Postgres, ctx context.Context, route int) ([]model.RouteRating, error) {
+	query := `select routes.id, route_reviews.season, count(*)::int,
+			         avg(route_reviews.rating)::float8, avg(route_reviews.perceived_difficulty)::float8,
+			         routes.difficulty::int
+			  from route_reviews
+			  join routes
+			  on routes.id = route_reviews.route
+			  where routes.id = @route
+			  group by routes.id, route_reviews.season
+			  order by array_position(array['winter', 'spring', 'summer', 'autumn'], route_reviews.season::text)`
+	args := pgx.NamedArgs{
+		"route": route,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetRouteSeasonRatings: %w", err)
+	}
+	defer rows.Close()
+
+	return rows2RouteRatings(rows)
+}
+
+func GetRouteTourCount(pg *db.Postgres, ctx context.Context, route int) (int, int, error) {
+	query := `select count(*)::int, count(*) filter (where status = 'completed')::int
+			  from tours
+			  where route = @route`
+	args := pgx.NamedArgs{
+		"route": route,
+	}
+	var total, completed int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&total, &completed)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to do query GetRouteTourCount: %w", err)
+	}
+	return total, completed, nil
+}
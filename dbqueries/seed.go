@@ -0,0 +1,165 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+// EnsureRole adds a role with a fixed id unless the id is already taken.
+func EnsureRole(pg *db.Postgres, ctx context.Context, role model.Role) error {
+	query := `insert into roles (id, role) values (@id, @role) on conflict (id) do nothing`
+	args := pgx.NamedArgs{
+		"id":   role.Id,
+		"role": role.Role,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to insert row in EnsureRole: %w", err)
+	}
+	return nil
+}
+
+// EnsureAttribute adds an attribute with a fixed id unless the id is already
+// taken, since queries refer to some attributes by id.
+func EnsureAttribute(pg *db.Postgres, ctx context.Context, attr model.Attribute) error {
+	query := `insert into attributes (id, attr, role, attr_type) values (@id, @attr, @role, @attr_type)
+			  on conflict (id) do nothing`
+	args := pgx.NamedArgs{
+		"id":        attr.Id,
+		"attr":      attr.Name,
+		"role":      attr.Role,
+		"attr_type": attr.Type,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to insert row in EnsureAttribute: %w", err)
+	}
+	return nil
+}
+
+func CreateRouteType(pg *db.Postgres, ctx context.Context, routeType string) (int, error) {
+	query := `insert into route_types (type) values (@type) returning id`
+	args := pgx.NamedArgs{
+		"type": routeType,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateRouteType: %w", err)
+	}
+	return id, nil
+}
+
+func CreatePlace(pg *db.Postgres, ctx context.Context, title string) (int, error) {
+	query := `insert into places (title) values (@title) returning id`
+	args := pgx.NamedArgs{
+		"title": title,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreatePlace: %w", err)
+	}
+	return id, nil
+}
+
+// CreateRoute adds a route passing the places in the given order.
+func CreateRoute(pg *db.Postgres, ctx context.Context, route model.Route, places []int32) (int, error) {
+	query := `insert into routes (type, difficulty, length_km) values (@type, @difficulty, @length) returning id`
+	args := pgx.NamedArgs{
+		"type":       route.TypeId,
+		"difficulty": route.Difficulty,
+		"length":     route.LengthKm,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateRoute: %w", err)
+	}
+
+	query = `insert into places_routes (place, route, position) values (@place, @route, @position)`
+	for i, place := range places {
+		args = pgx.NamedArgs{
+			"place":    place,
+			"route":    id,
+			"position": i + 1,
+		}
+		_, err = pg.Db.Exec(ctx, query, args)
+		if err != nil {
+			return 0, fmt.Errorf("unable to insert place in CreateRoute: %w", err)
+		}
+	}
+	return id, nil
+}
+
+// CreateWorkoutDescription adds a recurring workout of a trainer of the given
+// type and schedules it for the groups.
+func CreateWorkoutDescription(pg *db.Postgres, ctx context.Context, trainer int, workoutType string, groups []int) (int, error) {
+	query := `insert into workout_descriptions (trainer) values (@trainer) returning id`
+	args := pgx.NamedArgs{
+		"trainer": trainer,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateWorkoutDescription: %w", err)
+	}
+
+	query = `insert into workout_descrs_attrs_text (descr, value) values (@descr, @type)`
+	args = pgx.NamedArgs{
+		"descr": id,
+		"type":  workoutType,
+	}
+	_, err = pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert type in CreateWorkoutDescription: %w", err)
+	}
+
+	query = `insert into groups_workouts (group_id, workout) values (@group, @descr)`
+	for _, group := range groups {
+		args = pgx.NamedArgs{
+			"group": group,
+			"descr": id,
+		}
+		_, err = pg.Db.Exec(ctx, query, args)
+		if err != nil {
+			return 0, fmt.Errorf("unable to insert group in CreateWorkoutDescription: %w", err)
+		}
+	}
+	return id, nil
+}
+
+func CreateWorkoutSession(pg *db.Postgres, ctx context.Context, session model.WorkoutSession) (int, error) {
+	query := `insert into workouts (description, date, start_time, finish_time)
+			  values (@description, @date, @start, @finish)
+			  returning id`
+	args := pgx.NamedArgs{
+		"description": session.Description,
+		"date":        session.Date,
+		"start":       session.StartTime,
+		"finish":      session.FinishTime,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateWorkoutSession: %w", err)
+	}
+	return id, nil
+}
+
+func CreateChampionship(pg *db.Postgres, ctx context.Context, championship model.Championship) (int, error) {
+	query := `insert into championships (title, date) values (@title, @date) returning id`
+	args := pgx.NamedArgs{
+		"title": championship.Title,
+		"date":  championship.Date,
+	}
+	var id int
+	err := pg.Db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert row in CreateChampionship: %w", err)
+	}
+	return id, nil
+}
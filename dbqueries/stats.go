@@ -0,0 +1,201 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var statsViews = []string{
+	"stats_members",
+	"stats_new_members",
+	"stats_tours",
+	"stats_workouts",
+	"stats_championships",
+	"stats_demographics",
+}
+
+// RefreshStats recomputes the statistics summaries without blocking readers.
+func RefreshStats(pg *db.Postgres, ctx context.Context) error {
+	for _, view := range statsViews {
+		_, err := pg.Db.Exec(ctx, `refresh materialized view concurrently `+view)
+		if err != nil {
+			return fmt.Errorf("unable to refresh %s: %w", view, err)
+		}
+	}
+	_, err := pg.Db.Exec(ctx, `insert into stats_refreshes default values`)
+	if err != nil {
+		return fmt.Errorf("unable to insert row in RefreshStats: %w", err)
+	}
+	return nil
+}
+
+func GetStatsRefreshedAt(pg *db.Postgres, ctx context.Context) (pgtype.Timestamp, error) {
+	var refreshedAt pgtype.Timestamp
+	err := pg.Db.QueryRow(ctx, `select max(refreshed_at) from stats_refreshes`).Scan(&refreshedAt)
+	if err != nil {
+		return refreshedAt, fmt.Errorf("unable to do query GetStatsRefreshedAt: %w", err)
+	}
+	return refreshedAt, nil
+}
+
+func GetStatsMembers(pg *db.Postgres, ctx context.Context, section int) ([]model.MemberCount, error) {
+	query := `select role, members from stats_members where section = @section order by role`
+	args := pgx.NamedArgs{
+		"section": section,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetStatsMembers: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []model.MemberCount
+	for rows.Next() {
+		count := model.MemberCount{}
+		err := rows.Scan(&count.Role, &count.Members)
+		if err != nil {
+			return nil, fmt.Errorf("convert to member count model error: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
+
+func GetStatsNewMembers(pg *db.Postgres, ctx context.Context, section int, from string, to string) ([]model.NewMembers, error) {
+	query := `select month, members
+			  from stats_new_members
+			  where section = @section and month between date_trunc('month', @from::date) and @to::date
+			  order by month`
+	args := pgx.NamedArgs{
+		"section": section,
+		"from":    from,
+		"to":      to,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetStatsNewMembers: %w", err)
+	}
+	defer rows.Close()
+
+	var months []model.NewMembers
+	for rows.Next() {
+		month := model.NewMembers{}
+		err := rows.Scan(&month.Month, &month.Members)
+		if err != nil {
+			return nil, fmt.Errorf("convert to new members model error: %w", err)
+		}
+		months = append(months, month)
+	}
+	return months, nil
+}
+
+func GetStatsTours(pg *db.Postgres, ctx context.Context, section int, from string, to string) ([]model.TourStats, error) {
+	query := `select month, tours, km, participants
+			  from stats_tours
+			  where section = @section and month between date_trunc('month', @from::date) and @to::date
+			  order by month`
+	args := pgx.NamedArgs{
+		"section": section,
+		"from":    from,
+		"to":      to,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetStatsTours: %w", err)
+	}
+	defer rows.Close()
+
+	var months []model.TourStats
+	for rows.Next() {
+		month := model.TourStats{}
+		err := rows.Scan(&month.Month, &month.Tours, &month.Km, &month.Participants)
+		if err != nil {
+			return nil, fmt.Errorf("convert to tour stats model error: %w", err)
+		}
+		months = append(months, month)
+	}
+	return months, nil
+}
+
+func GetStatsWorkouts(pg *db.Postgres, ctx context.Context, section int, from string, to string) ([]model.WorkoutStats, error) {
+	query := `select month, workouts, coalesce(hours, 0)
+			  from stats_workouts
+			  where section = @section and month between date_trunc('month', @from::date) and @to::date
+			  order by month`
+	args := pgx.NamedArgs{
+		"section": section,
+		"from":    from,
+		"to":      to,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetStatsWorkouts: %w", err)
+	}
+	defer rows.Close()
+
+	var months []model.WorkoutStats
+	for rows.Next() {
+		month := model.WorkoutStats{}
+		err := rows.Scan(&month.Month, &month.Workouts, &month.Hours)
+		if err != nil {
+			return nil, fmt.Errorf("convert to workout stats model error: %w", err)
+		}
+		months = append(months, month)
+	}
+	return months, nil
+}
+
+func GetStatsChampionships(pg *db.Postgres, ctx context.Context, section int, from string, to string) ([]model.ChampionshipStats, error) {
+	query := `select month, championships, participants
+			  from stats_championships
+			  where section = @section and month between date_trunc('month', @from::date) and @to::date
+			  order by month`
+	args := pgx.NamedArgs{
+		"section": section,
+		"from":    from,
+		"to":      to,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetStatsChampionships: %w", err)
+	}
+	defer rows.Close()
+
+	var months []model.ChampionshipStats
+	for rows.Next() {
+		month := model.ChampionshipStats{}
+		err := rows.Scan(&month.Month, &month.Championships, &month.Participants)
+		if err != nil {
+			return nil, fmt.Errorf("convert to championship stats model error: %w", err)
+		}
+		months = append(months, month)
+	}
+	return months, nil
+}
+
+func GetStatsDemographics(pg *db.Postgres, ctx context.Context, section int) ([]model.DemographicCount, error) {
+	query := `select sex, age_group, members from stats_demographics where section = @section order by sex, age_group`
+	args := pgx.NamedArgs{
+		"section": section,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do query GetStatsDemographics: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []model.DemographicCount
+	for rows.Next() {
+		count := model.DemographicCount{}
+		err := rows.Scan(&count.Sex, &count.AgeGroup, &count.Members)
+		if err != nil {
+			return nil, fmt.Errorf("convert to demographic count model error: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
@@ -0,0 +1,62 @@
+package dbqueries
+
+import (
+	"context"
+	"db_backend/db"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func UpdateTourStatus(pg *db.Postgres, ctx context.Context, tour int, status string, startedOn pgtype.Date, finishedOn pgtype.Date) error {
+	query := `update tours
+			  set status = @status,
+			      started_on = coalesce(@startedOn, started_on),
+			      finished_on = coalesce(@finishedOn, finished_on)
+			  where id = @id`
+	args := pgx.NamedArgs{
+		"id":         tour,
+		"status":     status,
+		"startedOn":  startedOn,
+		"finishedOn": finishedOn,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to update in UpdateTourStatus: %w", err)
+	}
+	return nil
+}
+
+// CompleteRemainingParticipants marks every participant of the tour who has
+// no outcome yet as having completed it.
+func CompleteRemainingParticipants(pg *db.Postgres, ctx context.Context, tour int, date pgtype.Date) (int, error) {
+	query := `update persons_tours
+			  set outcome = 'completed', outcome_date = @date
+			  where tour = @tour and status = 'enrolled' and outcome is null`
+	args := pgx.NamedArgs{
+		"tour": tour,
+		"date": date,
+	}
+	tag, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return 0, fmt.Errorf("unable to update rows in CompleteRemainingParticipants: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func SetParticipantOutcome(pg *db.Postgres, ctx context.Context, tour int, person int, outcome pgtype.Text, date pgtype.Date) error {
+	query := `update persons_tours
+			  set outcome = @outcome, outcome_date = @date
+			  where tour = @tour and person = @person`
+	args := pgx.NamedArgs{
+		"tour":    tour,
+		"person":  person,
+		"outcome": outcome,
+		"date":    date,
+	}
+	_, err := pg.Db.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("unable to update row in SetParticipantOutcome: %w", err)
+	}
+	return nil
+}
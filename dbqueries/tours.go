@@ -31,7 +31,7 @@ func GetTouristsByToursCount(pg *db.Postgres, ctx context.Context, cntTours int)
 			  on persons.id = persons_tours.person
 			  join tours
 			  on persons_tours.tour = tours.id
-			  where extract(days from (now() - tours.start)) > 0
+			  where persons_tours.outcome = 'completed'
 			  group by persons.id) as cnttbl
 			  on persons.id = cnttbl.id
 			  where cnttbl.count >=@cntTours`
@@ -56,7 +56,7 @@ func GetTouristsByTour(pg *db.Postgres, ctx context.Context, tour int) ([]model.
 	query := `select distinct persons.id, name, surname, patronymic
 			  from persons
 			  join persons_tours on persons.id = persons_tours.person
-			  where tour = @tour`
+			  where tour = @tour and persons_tours.status = 'enrolled'`
 	args := pgx.NamedArgs{
 		"tour": tour,
 	}
@@ -84,7 +84,7 @@ func GetTouristsByTourTime(pg *db.Postgres, ctx context.Context, date string) ([
 			  on persons.id = persons_tours.person
 			  join tours
 			  on persons_tours.tour = tours.id
-			  where (@date::date - tours.start) < tours.duration_days and (@date::date - tours.start) >= 0 
+			  where (@date::date - tours.start) < tours.duration_days and (@date::date - tours.start) >= 0 and persons_tours.status = 'enrolled'
 			  group by persons.id) as cnttbl
 			  on persons.id = cnttbl.id`
 	args := pgx.NamedArgs{
@@ -114,7 +114,7 @@ func GetTouristsByTourRoute(pg *db.Postgres, ctx context.Context, route int) ([]
 			  on persons.id = persons_tours.person
 			  join tours
 			  on persons_tours.tour = tours.id
-			  where tours.route = @route
+			  where tours.route = @route and persons_tours.status = 'enrolled'
 			  group by persons.id) as cnttbl
 			  on persons.id = cnttbl.id`
 	args := pgx.NamedArgs{
@@ -146,7 +146,7 @@ func GetTouristsByTourPlace(pg *db.Postgres, ctx context.Context, placeId int) (
 			  on persons_tours.tour = tours.id
 			  join places_routes 
 			  on places_routes.route = tours.route
-			  where places_routes.place = @place
+			  where places_routes.place = @place and persons_tours.status = 'enrolled'
 			  group by persons.id) as cnttbl
 			  on persons.id = cnttbl.id`
 	args := pgx.NamedArgs{
@@ -191,7 +191,7 @@ func GetRoutesBySection(pg *db.Postgres, ctx context.Context, section int) ([]mo
 			  join tours
 			  on persons_tours.tour = tours.id
 			  join persons_roles on persons.id = persons_roles.person
-			  where (role = 0 or role = 1) and section = @section`
+			  where (role = 0 or role = 1) and section = @section and persons_tours.status = 'enrolled'`
 	args := pgx.NamedArgs{
 		"section": section,
 	}
@@ -216,7 +216,7 @@ func GetRoutesByTime(pg *db.Postgres, ctx context.Context, fromDate string, toDa
 			  on persons.id = persons_tours.person
 			  join tours
 			  on persons_tours.tour = tours.id
-			  where  (((@toDate::date - tours.start ) >= tours.duration_days) and (((@fromDate::date - tours.start) <= tours.duration_days) or (((@toDate::date - tours.start ) >= 0) and ((@fromDate::date - tours.start) <= 0))))`
+			  where  (((@toDate::date - tours.start ) >= tours.duration_days) and (((@fromDate::date - tours.start) <= tours.duration_days) or (((@toDate::date - tours.start ) >= 0) and ((@fromDate::date - tours.start) <= 0)))) and persons_tours.status = 'enrolled'`
 	args := pgx.NamedArgs{
 		"fromDate": fromDate,
 		"toDate":   toDate,
@@ -242,7 +242,7 @@ func GetRoutesByInstructor(pg *db.Postgres, ctx context.Context, instructor int)
 			  on persons.id = persons_tours.person
 			  join tours
 			  on persons_tours.tour = tours.id
-			  where tours.instructor = @instructor`
+			  where tours.instructor = @instructor and persons_tours.status = 'enrolled'`
 
 	args := pgx.NamedArgs{
 		"instructor": instructor,
@@ -271,6 +271,7 @@ func GetRoutesByCntGroups(pg *db.Postgres, ctx context.Context, cntGroups int) (
 			  on persons.id = persons_tours.person
 			  join tours
 			  on persons_tours.tour = tours.id
+			  where persons_tours.status = 'enrolled'
 			  group by tours.route) as cnttbl
 			  on cnttbl.route = id
 			  where cnttbl.cnt >= @cntGroups`
@@ -418,7 +419,7 @@ func GetInstructorsByCategory(pg *db.Postgres, ctx context.Context, routeType in
 			  on persons.id = persons_roles.person
 			  join persons_tours 
 			  on persons.id = persons_tours.person join tours as ts on ts.id = persons_tours.tour join routes as rt on rt.id = ts.route
-			  where tours.instructor = persons.id  and rt.type= @routeType
+			  where tours.instructor = persons.id  and rt.type= @routeType and persons_tours.outcome = 'completed'
 			  group by persons.id) 
 			  where category >= @difficulty`
 
@@ -542,7 +543,7 @@ func GetTouristsWithTrainerInstructor(pg *db.Postgres, ctx context.Context) ([]m
 			  on groups_workouts.group_id = groups_persons.group_id
 			  join workout_descriptions
 			  on workout_descriptions.id = groups_workouts.workout
-			  where workout_descriptions.trainer = tours.instructor`
+			  where workout_descriptions.trainer = tours.instructor and persons_tours.status = 'enrolled'`
 
 	rows, err := pg.Db.Query(ctx, query)
 	defer rows.Close()
@@ -572,6 +573,7 @@ func GetTouristsCompletedAll(pg *db.Postgres, ctx context.Context) ([]model.Pers
 			  on tours.id = persons_tours.tour
 			  join routes
 			  on routes.id = tours.route
+			  where persons_tours.outcome = 'completed'
 			  group by persons.id) as cnttbl 
 			  on persons.id = cnttbl.id 
 			  where cnttbl.cnt = (select count(*) from routes)`
@@ -591,7 +593,7 @@ func GetTouristsCompletedAll(pg *db.Postgres, ctx context.Context) ([]model.Pers
 }
 
 func GetTouristsCompletedRoute(pg *db.Postgres, ctx context.Context, routeId int) ([]model.Person, error) {
-	query := `select distinct persons.id                        
+	query := `select distinct persons.id, name, surname, patronymic
 			  from persons
 			  join persons_tours
 			  on persons_tours.person = persons.id 
@@ -599,7 +601,7 @@ func GetTouristsCompletedRoute(pg *db.Postgres, ctx context.Context, routeId int
 			  on tours.id = persons_tours.tour
 			  join routes
 			  on routes.id = tours.route
-			  where tours.route = @routeId`
+			  where tours.route = @routeId and persons_tours.outcome = 'completed'`
 
 	args := pgx.NamedArgs{
 		"routeId": routeId,
@@ -6,6 +6,7 @@ import (
 	"db_backend/model"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func rows2Strain(rows pgx.Rows) ([]model.Strain, error) {
@@ -50,3 +51,73 @@ func GetStrainForTrainer(pg *db.Postgres, ctx context.Context, trainer int, from
 
 	return strain, nil
 }
+
+var strainGroupings = map[string][2]string{
+	"trainer": {`wd.trainer`, `trainers.surname || ' ' || trainers.name`},
+	"group":   {`coalesce(groups.id, 0)`, `coalesce(groups.group_number::text, '')`},
+	"section": {`coalesce(sections.id, 0)`, `coalesce(sections.title, '')`},
+	"type":    {`0`, `coalesce(wdat.value, '')`},
+}
+
+func IsStrainGrouping(groupBy string) bool {
+	_, ok := strainGroupings[groupBy]
+	return ok
+}
+
+// GetStrainBuckets sums workout durations per grouping key and per week or month.
+// A workout scheduled for several groups is counted once for every group but only
+// once for its trainer, section or type.
+func GetStrainBuckets(pg *db.Postgres, ctx context.Context, groupBy string, bucket string, fromDate string, toDate string,
+	section pgtype.Int4, trainer pgtype.Int4) ([]model.StrainBucket, error) {
+	grouping, ok := strainGroupings[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown strain grouping %q", groupBy)
+	}
+	query := `select key, label, bucket, sum(duration)
+			  from (
+			      select distinct workouts.id, ` + grouping[0] + ` as key, ` + grouping[1] + ` as label,
+			             date_trunc(@bucket::text, workouts.date::timestamp)::date as bucket,
+			             workouts.finish_time - workouts.start_time as duration
+			      from workouts
+			      join workout_descriptions as wd
+			      on wd.id = workouts.description
+			      join persons as trainers
+			      on trainers.id = wd.trainer
+			      left join workout_descrs_attrs_text as wdat
+			      on wdat.descr = wd.id
+			      left join groups_workouts
+			      on groups_workouts.workout = wd.id
+			      left join groups
+			      on groups.id = groups_workouts.group_id
+			      left join sections
+			      on sections.id = groups.section
+			      where workouts.date between @fromDate and @toDate
+			        and (@section::int is null or groups.section = @section)
+			        and (@trainer::int is null or wd.trainer = @trainer)
+			  ) as sessions
+			  group by key, label, bucket
+			  order by label, key, bucket`
+	args := pgx.NamedArgs{
+		"bucket":   bucket,
+		"fromDate": fromDate,
+		"toDate":   toDate,
+		"section":  section,
+		"trainer":  trainer,
+	}
+	rows, err := pg.Db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query GetStrainBuckets: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []model.StrainBucket
+	for rows.Next() {
+		b := model.StrainBucket{}
+		err := rows.Scan(&b.Key, &b.Label, &b.Bucket, &b.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("convert to strain bucket model error: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, nil
+}
@@ -0,0 +1,12 @@
+package dto
+
+type AuthTokenRequest struct {
+	Person int32 `json:"person"`
+	Days   int   `json:"days"`
+}
+
+type AuthToken struct {
+	Person    int32  `json:"person"`
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "encoding/json"
+
+type ArchiveHeader struct {
+	Format     string   `json:"format"`
+	Version    int      `json:"version"`
+	CreatedAt  string   `json:"created_at"`
+	Migrations []string `json:"migrations"`
+}
+
+type ArchiveTable struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Rows    int      `json:"rows"`
+}
+
+// ArchiveLine is one line of an archive: the header first, then every table
+// followed by its rows.
+type ArchiveLine struct {
+	Archive *ArchiveHeader  `json:"archive,omitempty"`
+	Table   *ArchiveTable   `json:"table,omitempty"`
+	Row     json.RawMessage `json:"row,omitempty"`
+}
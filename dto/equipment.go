@@ -0,0 +1,51 @@
+package dto
+
+type Equipment struct {
+	Id        int32  `json:"id"`
+	Title     string `json:"title"`
+	Category  string `json:"category"`
+	Quantity  int32  `json:"quantity"`
+	Condition string `json:"condition"`
+	Section   *int32 `json:"section"`
+	Notes     string `json:"notes"`
+}
+
+type EquipmentCheckoutRequest struct {
+	Equipment int32  `json:"equipment"`
+	Quantity  int32  `json:"quantity"`
+	Tour      *int32 `json:"tour"`
+	Person    *int32 `json:"person"`
+	DueOn     string `json:"due_on"`
+}
+
+type EquipmentCheckinRequest struct {
+	Checkout  int32  `json:"checkout"`
+	Condition string `json:"condition"`
+	Report    string `json:"report"`
+	Date      string `json:"date"`
+}
+
+type EquipmentCheckout struct {
+	Id           int32  `json:"id"`
+	Equipment    int32  `json:"equipment"`
+	Quantity     int32  `json:"quantity"`
+	Tour         *int32 `json:"tour"`
+	Person       *int32 `json:"person"`
+	CheckedOutOn string `json:"checked_out_on"`
+	DueOn        string `json:"due_on,omitempty"`
+	CheckedInOn  string `json:"checked_in_on,omitempty"`
+	ConditionIn  string `json:"condition_in,omitempty"`
+	Report       string `json:"report,omitempty"`
+}
+
+type EquipmentAvailability struct {
+	Equipment Equipment `json:"equipment"`
+	Allocated int32     `json:"allocated"`
+	Available int32     `json:"available"`
+}
+
+type EquipmentAvailabilityResponse struct {
+	From  string                  `json:"from"`
+	To    string                  `json:"to"`
+	Items []EquipmentAvailability `json:"items"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+type ExpenseShareRequest struct {
+	Person int32   `json:"person"`
+	Weight float64 `json:"weight"`
+}
+
+type TourExpenseRequest struct {
+	Tour        int32                 `json:"tour"`
+	Payer       int32                 `json:"payer"`
+	Category    string                `json:"category"`
+	Amount      float64               `json:"amount"`
+	Description string                `json:"description"`
+	Date        string                `json:"date"`
+	Shares      []ExpenseShareRequest `json:"shares"`
+	Excluded    []int32               `json:"excluded"`
+}
+
+type ExpenseShare struct {
+	Person int32   `json:"person"`
+	Weight float64 `json:"weight"`
+	Amount float64 `json:"amount"`
+}
+
+type TourExpense struct {
+	Id          int32          `json:"id"`
+	Tour        int32          `json:"tour"`
+	Payer       int32          `json:"payer"`
+	Category    string         `json:"category"`
+	Amount      float64        `json:"amount"`
+	Description string         `json:"description"`
+	Date        string         `json:"date"`
+	Shares      []ExpenseShare `json:"shares"`
+}
+
+type PersonBalance struct {
+	Person PersonResponse `json:"person"`
+	Paid   float64        `json:"paid"`
+	Owed   float64        `json:"owed"`
+	Net    float64        `json:"net"`
+}
+
+type Transfer struct {
+	From   int32   `json:"from"`
+	To     int32   `json:"to"`
+	Amount float64 `json:"amount"`
+}
+
+type SettlementResponse struct {
+	Tour      int32           `json:"tour"`
+	Total     float64         `json:"total"`
+	Balances  []PersonBalance `json:"balances"`
+	Transfers []Transfer      `json:"transfers"`
+}
+
+type SectionExpenseSummary struct {
+	Section  Section `json:"section"`
+	Year     int32   `json:"year"`
+	Category string  `json:"category"`
+	Amount   float64 `json:"amount"`
+}
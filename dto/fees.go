@@ -0,0 +1,32 @@
+package dto
+
+type MembershipFee struct {
+	Id      int32   `json:"id"`
+	Section int32   `json:"section"`
+	Role    int32   `json:"role"`
+	Season  string  `json:"season"`
+	Amount  float64 `json:"amount"`
+	DueOn   string  `json:"due_on"`
+}
+
+type MembershipPayment struct {
+	Id     int32   `json:"id"`
+	Person int32   `json:"person"`
+	Fee    int32   `json:"fee"`
+	Amount float64 `json:"amount"`
+	PaidOn string  `json:"paid_on"`
+	Note   string  `json:"note"`
+}
+
+type Arrear struct {
+	Person      PersonResponse `json:"person"`
+	Fee         MembershipFee  `json:"fee"`
+	Paid        float64        `json:"paid"`
+	Outstanding float64        `json:"outstanding"`
+}
+
+type ArrearsResponse struct {
+	Date    string   `json:"date"`
+	Total   float64  `json:"total"`
+	Arrears []Arrear `json:"arrears"`
+}
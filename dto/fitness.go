@@ -0,0 +1,38 @@
+package dto
+
+type FitnessTest struct {
+	Id             int32  `json:"id"`
+	Title          string `json:"title"`
+	Unit           string `json:"unit"`
+	HigherIsBetter bool   `json:"higher_is_better"`
+}
+
+type FitnessResult struct {
+	Id      int32   `json:"id"`
+	Test    int32   `json:"test"`
+	Person  int32   `json:"person"`
+	Value   float64 `json:"value"`
+	TakenOn string  `json:"taken_on"`
+	Note    string  `json:"note"`
+}
+
+type PersonalBest struct {
+	Test    FitnessTest     `json:"test"`
+	Best    FitnessResult   `json:"best"`
+	History []FitnessResult `json:"history"`
+}
+
+type FitnessRanking struct {
+	Place   int32          `json:"place"`
+	Person  PersonResponse `json:"person"`
+	Value   float64        `json:"value"`
+	TakenOn string         `json:"taken_on"`
+}
+
+type FitnessRankingResponse struct {
+	Group    int32            `json:"group"`
+	Test     FitnessTest      `json:"test"`
+	FromDate string           `json:"from_date"`
+	ToDate   string           `json:"to_date"`
+	Ranking  []FitnessRanking `json:"ranking"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+type HealthRecord struct {
+	Person         int32  `json:"person"`
+	ClearanceUntil string `json:"clearance_until"`
+	BloodType      string `json:"blood_type"`
+	Allergies      string `json:"allergies"`
+	MedicalNotes   string `json:"medical_notes"`
+	UpdatedAt      string `json:"updated_at"`
+	UpdatedBy      *int32 `json:"updated_by"`
+}
+
+type ClearanceAlert struct {
+	Person         PersonResponse `json:"person"`
+	Tour           int32          `json:"tour"`
+	Instructor     int32          `json:"instructor"`
+	Start          string         `json:"start"`
+	ClearanceUntil string         `json:"clearance_until"`
+	Reason         string         `json:"reason"`
+}
+
+type EmergencyContact struct {
+	Id       int32  `json:"id"`
+	Person   int32  `json:"person"`
+	Name     string `json:"name"`
+	Phone    string `json:"phone"`
+	Relation string `json:"relation"`
+	Priority int32  `json:"priority"`
+}
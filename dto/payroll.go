@@ -0,0 +1,40 @@
+package dto
+
+type PayrollRate struct {
+	Id          int32   `json:"id"`
+	Trainer     *int32  `json:"trainer"`
+	WorkoutType *string `json:"workout_type"`
+	HourlyRate  float64 `json:"hourly_rate"`
+}
+
+type PayrollLine struct {
+	WorkoutType string   `json:"workout_type"`
+	Hours       float64  `json:"hours"`
+	Rate        *float64 `json:"rate"`
+	Amount      float64  `json:"amount"`
+}
+
+type TrainerPayroll struct {
+	Trainer PersonResponse `json:"trainer"`
+	Hours   float64        `json:"hours"`
+	Amount  float64        `json:"amount"`
+	Unrated bool           `json:"unrated"`
+	Lines   []PayrollLine  `json:"lines"`
+}
+
+type PayrollReport struct {
+	Section  int32            `json:"section"`
+	Month    string           `json:"month"`
+	Closed   bool             `json:"closed"`
+	ClosedAt string           `json:"closed_at,omitempty"`
+	Total    float64          `json:"total"`
+	Trainers []TrainerPayroll `json:"trainers"`
+}
+
+type PayPeriod struct {
+	Id       int32   `json:"id"`
+	Section  int32   `json:"section"`
+	Month    string  `json:"month"`
+	ClosedAt string  `json:"closed_at"`
+	Total    float64 `json:"total"`
+}
@@ -55,9 +55,13 @@ type PersonDateAttribute struct {
 }
 
 type Group struct {
-	Id          int32 `json:"id"`
-	GroupNumber int32 `json:"group_number"`
-	Section     int32 `json:"section"`
+	Id               int32  `json:"id"`
+	GroupNumber      int32  `json:"group_number"`
+	Section          int32  `json:"section"`
+	MinAge           *int32 `json:"min_age"`
+	MaxAge           *int32 `json:"max_age"`
+	Sex              *int32 `json:"sex"`
+	MinQualification *int32 `json:"min_qualification"`
 }
 
 type Section struct {
@@ -69,3 +73,48 @@ type Role struct {
 	Id   int32  `json:"id"`
 	Role string `json:"role"`
 }
+
+type GroupFit struct {
+	Group   Group    `json:"group"`
+	Members int      `json:"members"`
+	Fits    bool     `json:"fits"`
+	Reasons []string `json:"reasons"`
+}
+
+type GroupSuggestion struct {
+	Person        PersonResponse `json:"person"`
+	Age           *int           `json:"age"`
+	Sex           *int32         `json:"sex"`
+	Qualification int32          `json:"qualification"`
+	Suggested     *int32         `json:"suggested"`
+	Groups        []GroupFit     `json:"groups"`
+}
+
+type GroupMisfit struct {
+	Person       PersonResponse `json:"person"`
+	Group        Group          `json:"group"`
+	Age          *int           `json:"age"`
+	Reasons      []string       `json:"reasons"`
+	Alternatives []int32        `json:"alternatives"`
+}
+
+type GroupRebalanceReport struct {
+	Date    string        `json:"date"`
+	Section *int32        `json:"section"`
+	Misfits []GroupMisfit `json:"misfits"`
+}
+
+type ImportProblem struct {
+	Row    int    `json:"row"`
+	Column string `json:"column,omitempty"`
+	Error  string `json:"error"`
+}
+
+type ImportReport struct {
+	DryRun   bool            `json:"dry_run"`
+	Rows     int             `json:"rows"`
+	Imported int             `json:"imported"`
+	Persons  []int32         `json:"persons"`
+	Errors   []ImportProblem `json:"errors"`
+	Warnings []ImportProblem `json:"warnings"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+type CycleTarget struct {
+	WorkoutType string  `json:"workout_type"`
+	Hours       float64 `json:"hours"`
+}
+
+type TrainingPlan struct {
+	Id       int32  `json:"id"`
+	Group    int32  `json:"group"`
+	Title    string `json:"title"`
+	StartsOn string `json:"starts_on"`
+	EndsOn   string `json:"ends_on"`
+}
+
+type TrainingCycle struct {
+	Id       int32           `json:"id"`
+	Plan     int32           `json:"plan"`
+	Parent   *int32          `json:"parent"`
+	Kind     string          `json:"kind"`
+	Title    string          `json:"title"`
+	StartsOn string          `json:"starts_on"`
+	EndsOn   string          `json:"ends_on"`
+	Targets  []CycleTarget   `json:"targets"`
+	Cycles   []TrainingCycle `json:"cycles,omitempty"`
+}
+
+type TrainingPlanResponse struct {
+	TrainingPlan
+	Cycles  []TrainingCycle `json:"cycles"`
+	Orphans []TrainingCycle `json:"orphans,omitempty"`
+}
+
+type CycleTargetsRequest struct {
+	Cycle   int32         `json:"cycle"`
+	Targets []CycleTarget `json:"targets"`
+}
+
+type PlanComparisonLine struct {
+	WorkoutType string  `json:"workout_type"`
+	Planned     float64 `json:"planned"`
+	Expected    float64 `json:"expected"`
+	Actual      float64 `json:"actual"`
+	Deviation   float64 `json:"deviation"`
+	Status      string  `json:"status"`
+}
+
+type CycleComparison struct {
+	Cycle    int32                `json:"cycle"`
+	Parent   *int32               `json:"parent"`
+	Kind     string               `json:"kind"`
+	Title    string               `json:"title"`
+	StartsOn string               `json:"starts_on"`
+	EndsOn   string               `json:"ends_on"`
+	Progress float64              `json:"progress"`
+	Lines    []PlanComparisonLine `json:"lines"`
+}
+
+type PlanComparisonResponse struct {
+	Plan      TrainingPlan         `json:"plan"`
+	Date      string               `json:"date"`
+	Tolerance float64              `json:"tolerance"`
+	Progress  float64              `json:"progress"`
+	Lines     []PlanComparisonLine `json:"lines"`
+	Cycles    []CycleComparison    `json:"cycles"`
+}
@@ -0,0 +1,54 @@
+package dto
+
+type TourReportRequest struct {
+	Tour      int32  `json:"tour"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	GpsTrack  string `json:"gps_track"`
+	Published bool   `json:"published"`
+}
+
+type ReportAttachment struct {
+	Id           int32  `json:"id"`
+	Kind         string `json:"kind"`
+	Filename     string `json:"filename"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Url          string `json:"url"`
+	ThumbnailUrl string `json:"thumbnail_url,omitempty"`
+	UploadedAt   string `json:"uploaded_at"`
+}
+
+type TourReport struct {
+	Id          int32              `json:"id"`
+	Tour        int32              `json:"tour"`
+	Author      PersonResponse     `json:"author"`
+	EditedBy    *PersonResponse    `json:"edited_by,omitempty"`
+	Title       string             `json:"title"`
+	Body        string             `json:"body"`
+	GpsTrack    string             `json:"gps_track,omitempty"`
+	TrackKm     *float64           `json:"track_km"`
+	Published   bool               `json:"published"`
+	CreatedAt   string             `json:"created_at"`
+	UpdatedAt   string             `json:"updated_at"`
+	Attachments []ReportAttachment `json:"attachments"`
+}
+
+type RouteReportEntry struct {
+	Tour       int32      `json:"tour"`
+	Start      string     `json:"start"`
+	End        string     `json:"end"`
+	Instructor string     `json:"instructor"`
+	Report     TourReport `json:"report"`
+}
+
+type RouteReports struct {
+	Route      int32              `json:"route"`
+	Type       string             `json:"type"`
+	Difficulty string             `json:"difficulty"`
+	LengthKm   string             `json:"length_km"`
+	Places     []string           `json:"places"`
+	Reports    []RouteReportEntry `json:"reports"`
+	Photos     int                `json:"photos"`
+	TrackKm    float64            `json:"track_km"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+type SeedSummary struct {
+	Seed          uint64 `json:"seed"`
+	Scale         int    `json:"scale"`
+	Sections      int    `json:"sections"`
+	Groups        int    `json:"groups"`
+	Persons       int    `json:"persons"`
+	Places        int    `json:"places"`
+	Routes        int    `json:"routes"`
+	Tours         int    `json:"tours"`
+	Participants  int    `json:"participants"`
+	Workouts      int    `json:"workouts"`
+	Attendance    int    `json:"attendance"`
+	Championships int    `json:"championships"`
+	Registrations int    `json:"registrations"`
+}
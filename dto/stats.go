@@ -0,0 +1,61 @@
+package dto
+
+type MemberCount struct {
+	Role     int32  `json:"role"`
+	RoleName string `json:"role_name"`
+	Members  int32  `json:"members"`
+}
+
+type NewMembers struct {
+	Month   string `json:"month"`
+	Members int32  `json:"members"`
+}
+
+type TourStats struct {
+	Month        string  `json:"month"`
+	Tours        int32   `json:"tours"`
+	Km           float64 `json:"km"`
+	Participants int32   `json:"participants"`
+}
+
+type WorkoutStats struct {
+	Month    string  `json:"month"`
+	Workouts int32   `json:"workouts"`
+	Hours    float64 `json:"hours"`
+}
+
+type ChampionshipStats struct {
+	Month         string `json:"month"`
+	Championships int32  `json:"championships"`
+	Participants  int32  `json:"participants"`
+}
+
+type DistributionEntry struct {
+	Value   string `json:"value"`
+	Members int32  `json:"members"`
+}
+
+type StatsTotals struct {
+	Members               int32   `json:"members"`
+	NewMembers            int32   `json:"new_members"`
+	Tours                 int32   `json:"tours"`
+	Km                    float64 `json:"km"`
+	WorkoutHours          float64 `json:"workout_hours"`
+	ChampionshipEntries   int32   `json:"championship_entries"`
+	ChampionshipsAttended int32   `json:"championships_attended"`
+}
+
+type StatsOverview struct {
+	DateFrom      string              `json:"date_from"`
+	DateTo        string              `json:"date_to"`
+	Section       *int32              `json:"section"`
+	RefreshedAt   string              `json:"refreshed_at"`
+	Totals        StatsTotals         `json:"totals"`
+	Members       []MemberCount       `json:"members"`
+	NewMembers    []NewMembers        `json:"new_members"`
+	Tours         []TourStats         `json:"tours"`
+	Workouts      []WorkoutStats      `json:"workouts"`
+	Championships []ChampionshipStats `json:"championships"`
+	Age           []DistributionEntry `json:"age"`
+	Sex           []DistributionEntry `json:"sex"`
+}
@@ -14,3 +14,228 @@ type RouteType struct {
 	Id   int    `json:"id"`
 	Type string `json:"type"`
 }
+
+type TourResponse struct {
+	Id               int32  `json:"id"`
+	Route            int32  `json:"route"`
+	Instructor       int32  `json:"instructor"`
+	Start            string `json:"start"`
+	DurationDays     int32  `json:"duration_days"`
+	MinParticipants  *int32 `json:"min_participants"`
+	MaxParticipants  *int32 `json:"max_participants"`
+	EnrollmentClosed bool   `json:"enrollment_closed"`
+	AtRisk           bool   `json:"at_risk"`
+	Enrolled         int32  `json:"enrolled"`
+	Status           string `json:"status"`
+	StartedOn        string `json:"started_on,omitempty"`
+	FinishedOn       string `json:"finished_on,omitempty"`
+}
+
+type TourParticipantResponse struct {
+	Person      PersonResponse `json:"person"`
+	Status      string         `json:"status"`
+	RequestedAt string         `json:"requested_at"`
+	Position    int32          `json:"position,omitempty"`
+	Outcome     string         `json:"outcome,omitempty"`
+	OutcomeDate string         `json:"outcome_date,omitempty"`
+}
+
+type TourParticipantsResponse struct {
+	Tour         int32                     `json:"tour"`
+	Participants []TourParticipantResponse `json:"participants"`
+	Waitlist     []TourParticipantResponse `json:"waitlist"`
+}
+
+type EnrollmentResponse struct {
+	Tour     int32  `json:"tour"`
+	Person   int32  `json:"person"`
+	Status   string `json:"status"`
+	Position int32  `json:"position,omitempty"`
+}
+
+type CancellationResponse struct {
+	Tour     int32   `json:"tour"`
+	Person   int32   `json:"person"`
+	Promoted []int32 `json:"promoted"`
+	AtRisk   bool    `json:"at_risk"`
+}
+
+type TourStatusResponse struct {
+	Tour      TourResponse `json:"tour"`
+	Completed int32        `json:"completed_participants"`
+}
+
+type ParticipantOutcomeResponse struct {
+	Tour        int32  `json:"tour"`
+	Person      int32  `json:"person"`
+	Outcome     string `json:"outcome"`
+	OutcomeDate string `json:"outcome_date"`
+}
+
+type TourCheckin struct {
+	Id         int32  `json:"id"`
+	Tour       int32  `json:"tour"`
+	Kind       string `json:"kind"`
+	ReportedAt string `json:"reported_at"`
+	ReportedBy *int32 `json:"reported_by"`
+	Location   string `json:"location"`
+	Note       string `json:"note"`
+}
+
+type OverdueTour struct {
+	Tour         TourResponse     `json:"tour"`
+	Due          string           `json:"due"`
+	LastCheckin  string           `json:"last_checkin,omitempty"`
+	LastLocation string           `json:"last_location,omitempty"`
+	Participants []PersonResponse `json:"participants"`
+	Places       []string         `json:"places"`
+}
+
+type StageCheckpoint struct {
+	Place       *int32 `json:"place"`
+	Title       string `json:"title"`
+	PlannedTime string `json:"planned_time"`
+}
+
+type TourStage struct {
+	Day             int32             `json:"day"`
+	Date            string            `json:"date"`
+	StartPlace      int32             `json:"start_place"`
+	StartPlaceTitle string            `json:"start_place_title"`
+	EndPlace        int32             `json:"end_place"`
+	EndPlaceTitle   string            `json:"end_place_title"`
+	DistanceKm      float64           `json:"distance_km"`
+	Camp            string            `json:"camp"`
+	Checkpoints     []StageCheckpoint `json:"checkpoints"`
+}
+
+// RoutePlaceOrder lists every place of a route in the order the route passes them.
+type RoutePlaceOrder struct {
+	Route  int32   `json:"route"`
+	Places []int32 `json:"places"`
+}
+
+type ItineraryRequest struct {
+	Tour   int32       `json:"tour"`
+	Stages []TourStage `json:"stages"`
+}
+
+type ItineraryResponse struct {
+	Tour     int32       `json:"tour"`
+	Stages   []TourStage `json:"stages"`
+	Errors   []string    `json:"errors"`
+	Warnings []string    `json:"warnings"`
+}
+
+type GroupPosition struct {
+	Tour         int32            `json:"tour"`
+	Route        int32            `json:"route"`
+	Day          int32            `json:"day"`
+	Stage        *TourStage       `json:"stage"`
+	Participants []PersonResponse `json:"participants"`
+}
+
+type RouteReviewRequest struct {
+	Route               int32  `json:"route"`
+	Rating              int32  `json:"rating"`
+	PerceivedDifficulty int32  `json:"perceived_difficulty"`
+	Conditions          string `json:"conditions"`
+	Review              string `json:"review"`
+}
+
+type RouteReview struct {
+	Id                  int32          `json:"id"`
+	Person              PersonResponse `json:"person"`
+	Tour                int32          `json:"tour"`
+	Rating              int32          `json:"rating"`
+	PerceivedDifficulty int32          `json:"perceived_difficulty"`
+	Season              string         `json:"season"`
+	Conditions          string         `json:"conditions"`
+	Review              string         `json:"review"`
+	UpdatedAt           string         `json:"updated_at"`
+}
+
+type RouteRating struct {
+	Season                 string   `json:"season,omitempty"`
+	Reviews                int32    `json:"reviews"`
+	AvgRating              *float64 `json:"avg_rating"`
+	AvgPerceivedDifficulty *float64 `json:"avg_perceived_difficulty"`
+	DifficultyGap          *float64 `json:"difficulty_gap"`
+	DifficultyFeel         string   `json:"difficulty_feel"`
+}
+
+type RouteResponse struct {
+	Id             int32         `json:"id"`
+	Type           string        `json:"type"`
+	Difficulty     *int32        `json:"difficulty"`
+	LengthKm       *float64      `json:"length_km"`
+	Places         []string      `json:"places"`
+	Tours          int           `json:"tours"`
+	CompletedTours int           `json:"completed_tours"`
+	Rating         RouteRating   `json:"rating"`
+	Seasons        []RouteRating `json:"seasons"`
+	Reviews        []RouteReview `json:"reviews"`
+}
+
+type RouteRecommendation struct {
+	Route          int32    `json:"route"`
+	Type           string   `json:"type"`
+	Difficulty     int32    `json:"difficulty"`
+	LengthKm       *float64 `json:"length_km"`
+	Places         []string `json:"places"`
+	Score          float64  `json:"score"`
+	SimilarRating  *float64 `json:"similar_rating"`
+	SimilarReviews int32    `json:"similar_reviews"`
+	Reasons        []string `json:"reasons"`
+}
+
+type RecommendedRoutes struct {
+	Person PersonResponse        `json:"person"`
+	Routes []RouteRecommendation `json:"routes"`
+}
+
+type PlannerRoute struct {
+	Route           int32  `json:"route"`
+	Start           string `json:"start"`
+	DurationDays    int32  `json:"duration_days"`
+	MinParticipants *int32 `json:"min_participants"`
+	MaxParticipants int32  `json:"max_participants"`
+}
+
+type PlannerRequest struct {
+	Section         *int32         `json:"section"`
+	Tourists        []int32        `json:"tourists"`
+	Instructors     []int32        `json:"instructors"`
+	InstructorRatio int32          `json:"instructor_ratio"`
+	Routes          []PlannerRoute `json:"routes"`
+}
+
+type PlanDraftTour struct {
+	Route           int32            `json:"route"`
+	RouteType       string           `json:"route_type"`
+	Difficulty      *int32           `json:"difficulty"`
+	Start           string           `json:"start"`
+	DurationDays    int32            `json:"duration_days"`
+	Instructor      *PersonResponse  `json:"instructor"`
+	MinParticipants *int32           `json:"min_participants"`
+	MaxParticipants int32            `json:"max_participants"`
+	Members         []PersonResponse `json:"members"`
+	Tour            *int32           `json:"tour"`
+}
+
+type PlanUnassigned struct {
+	Person PersonResponse `json:"person"`
+	Reason string         `json:"reason"`
+}
+
+type PlanDraft struct {
+	Id              int32            `json:"id"`
+	Section         *int32           `json:"section"`
+	Status          string           `json:"status"`
+	CreatedAt       string           `json:"created_at"`
+	AcceptedAt      string           `json:"accepted_at,omitempty"`
+	InstructorRatio int32            `json:"instructor_ratio"`
+	Tours           []PlanDraftTour  `json:"tours"`
+	Unassigned      []PlanUnassigned `json:"unassigned"`
+	Warnings        []string         `json:"warnings"`
+}
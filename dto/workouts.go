@@ -2,7 +2,31 @@ package dto
 
 type StrainResponse struct {
 	Strain   string `json:"type"`
-	Duration string `json:"duration"`
+	Duration any    `json:"duration"`
+}
+
+type StrainBucket struct {
+	Bucket   string `json:"bucket"`
+	Duration any    `json:"duration"`
+}
+
+type StrainReportEntry struct {
+	Key           int32          `json:"key"`
+	Label         string         `json:"label"`
+	Total         any            `json:"total"`
+	Previous      any            `json:"previous"`
+	ChangePercent *float64       `json:"change_percent"`
+	Buckets       []StrainBucket `json:"buckets"`
+}
+
+type StrainReportResponse struct {
+	GroupBy      string              `json:"group_by"`
+	Bucket       string              `json:"bucket"`
+	FromDate     string              `json:"from_date"`
+	ToDate       string              `json:"to_date"`
+	PreviousFrom string              `json:"previous_from"`
+	PreviousTo   string              `json:"previous_to"`
+	Entries      []StrainReportEntry `json:"entries"`
 }
 
 type StrainListResponse struct {
@@ -10,3 +34,64 @@ type StrainListResponse struct {
 	Total      int32            `json:"total"`
 	StrainList []StrainResponse `json:"strainList"`
 }
+
+type AttendanceMark struct {
+	Person int32  `json:"person"`
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+type AttendanceRequest struct {
+	Workout int32            `json:"workout"`
+	Group   int32            `json:"group"`
+	Status  string           `json:"status"`
+	Marks   []AttendanceMark `json:"marks"`
+}
+
+type Attendance struct {
+	Person   int32  `json:"person"`
+	Status   string `json:"status"`
+	MarkedBy *int32 `json:"marked_by"`
+	MarkedAt string `json:"marked_at"`
+	Note     string `json:"note"`
+}
+
+type WorkoutAttendanceResponse struct {
+	Workout    int32        `json:"workout"`
+	Date       string       `json:"date"`
+	Trainer    int32        `json:"trainer"`
+	Attendance []Attendance `json:"attendance"`
+	Unmarked   []int32      `json:"unmarked"`
+}
+
+type AttendanceStats struct {
+	Person   PersonResponse `json:"person"`
+	Present  int32          `json:"present"`
+	Absent   int32          `json:"absent"`
+	Excused  int32          `json:"excused"`
+	Late     int32          `json:"late"`
+	Unmarked int32          `json:"unmarked"`
+	Rate     float64        `json:"rate"`
+}
+
+type GroupAttendanceResponse struct {
+	Group    int32             `json:"group"`
+	FromDate string            `json:"from_date"`
+	ToDate   string            `json:"to_date"`
+	Rate     float64           `json:"rate"`
+	Members  []AttendanceStats `json:"members"`
+}
+
+type LowAttendance struct {
+	Group       int32           `json:"group"`
+	GroupNumber int32           `json:"group_number"`
+	Stats       AttendanceStats `json:"stats"`
+}
+
+type LowAttendanceResponse struct {
+	Section   int32           `json:"section"`
+	FromDate  string          `json:"from_date"`
+	ToDate    string          `json:"to_date"`
+	Threshold float64         `json:"threshold"`
+	Persons   []LowAttendance `json:"persons"`
+}
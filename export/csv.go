@@ -0,0 +1,25 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes a header row followed by rows. A UTF-8 byte order mark is
+// written first so that spreadsheet programs detect the encoding of Cyrillic text.
+func WriteCSV(w io.Writer, header []string, rows [][]string) error {
+	_, err := w.Write([]byte("\xef\xbb\xbf"))
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	err = writer.Write(header)
+	if err != nil {
+		return err
+	}
+	err = writer.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
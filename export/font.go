@@ -0,0 +1,365 @@
+package export
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/binary"
+	"fmt"
+	"slices"
+)
+
+//go:embed fonts/DejaVuSans.ttf
+var dejaVuSans []byte
+
+//go:embed fonts/DejaVuSans-Bold.ttf
+var dejaVuSansBold []byte
+
+// The fonts every PDF is set in. They cover Latin and Cyrillic together with
+// the usual typographic punctuation, so text is printed as it is.
+var (
+	regularFont = mustParseFont("DejaVuSans", dejaVuSans)
+	boldFont    = mustParseFont("DejaVuSans-Bold", dejaVuSansBold)
+)
+
+// font is a TrueType font read far enough to measure text and to embed the
+// glyphs a document uses.
+type font struct {
+	name       string
+	tables     map[string][]byte
+	unitsPerEm int
+	bbox       [4]int
+	ascent     int
+	descent    int
+	capHeight  int
+	glyphs     map[rune]uint16
+	advances   []uint16
+	loca       []uint32
+}
+
+func mustParseFont(name string, data []byte) *font {
+	f, err := parseFont(name, data)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func parseFont(name string, data []byte) (*font, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("font %s is truncated", name)
+	}
+	f := &font{name: name, tables: map[string][]byte{}}
+	count := int(binary.BigEndian.Uint16(data[4:]))
+	for i := range count {
+		record := 12 + 16*i
+		if record+16 > len(data) {
+			return nil, fmt.Errorf("font %s is truncated", name)
+		}
+		tag := string(data[record : record+4])
+		offset := int(binary.BigEndian.Uint32(data[record+8:]))
+		length := int(binary.BigEndian.Uint32(data[record+12:]))
+		if offset+length > len(data) {
+			return nil, fmt.Errorf("font %s table %s is truncated", name, tag)
+		}
+		f.tables[tag] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "maxp", "hmtx", "loca", "glyf"} {
+		if f.tables[tag] == nil {
+			return nil, fmt.Errorf("font %s has no %s table", name, tag)
+		}
+	}
+
+	head := f.tables["head"]
+	f.unitsPerEm = int(binary.BigEndian.Uint16(head[18:]))
+	for i := range f.bbox {
+		f.bbox[i] = int(int16(binary.BigEndian.Uint16(head[36+2*i:])))
+	}
+	longLoca := binary.BigEndian.Uint16(head[50:]) == 1
+
+	hhea := f.tables["hhea"]
+	f.ascent = int(int16(binary.BigEndian.Uint16(hhea[4:])))
+	f.descent = int(int16(binary.BigEndian.Uint16(hhea[6:])))
+	f.capHeight = f.ascent
+	if os2 := f.tables["OS/2"]; len(os2) >= 90 && binary.BigEndian.Uint16(os2) >= 2 {
+		f.capHeight = int(int16(binary.BigEndian.Uint16(os2[88:])))
+	}
+
+	numGlyphs := int(binary.BigEndian.Uint16(f.tables["maxp"][4:]))
+	metrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	hmtx := f.tables["hmtx"]
+	if metrics == 0 || len(hmtx) < 4*metrics {
+		return nil, fmt.Errorf("font %s has a broken hmtx table", name)
+	}
+	f.advances = make([]uint16, numGlyphs)
+	for i := range f.advances {
+		f.advances[i] = binary.BigEndian.Uint16(hmtx[4*min(i, metrics-1):])
+	}
+
+	loca := f.tables["loca"]
+	f.loca = make([]uint32, numGlyphs+1)
+	for i := range f.loca {
+		if longLoca {
+			if len(loca) < 4*(i+1) {
+				return nil, fmt.Errorf("font %s has a broken loca table", name)
+			}
+			f.loca[i] = binary.BigEndian.Uint32(loca[4*i:])
+		} else {
+			if len(loca) < 2*(i+1) {
+				return nil, fmt.Errorf("font %s has a broken loca table", name)
+			}
+			f.loca[i] = 2 * uint32(binary.BigEndian.Uint16(loca[2*i:]))
+		}
+	}
+
+	// A subset has no character map, its glyphs are addressed by number.
+	f.glyphs = map[rune]uint16{}
+	if cmap := f.tables["cmap"]; cmap != nil {
+		glyphs, err := readCmap(cmap)
+		if err != nil {
+			return nil, fmt.Errorf("font %s: %w", name, err)
+		}
+		f.glyphs = glyphs
+	}
+	return f, nil
+}
+
+// readCmap reads the Unicode character map, preferring the full repertoire
+// subtable (format 12) over the basic plane one (format 4).
+func readCmap(cmap []byte) (map[rune]uint16, error) {
+	var basic, full []byte
+	count := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := range count {
+		record := cmap[4+8*i:]
+		platform := binary.BigEndian.Uint16(record)
+		encoding := binary.BigEndian.Uint16(record[2:])
+		table := cmap[binary.BigEndian.Uint32(record[4:]):]
+		switch format := binary.BigEndian.Uint16(table); {
+		case format == 12 && (platform == 0 || platform == 3 && encoding == 10):
+			full = table
+		case format == 4 && (platform == 0 || platform == 3 && encoding == 1):
+			basic = table
+		}
+	}
+
+	glyphs := map[rune]uint16{}
+	switch {
+	case full != nil:
+		groups := int(binary.BigEndian.Uint32(full[12:]))
+		for i := range groups {
+			group := full[16+12*i:]
+			start := binary.BigEndian.Uint32(group)
+			end := binary.BigEndian.Uint32(group[4:])
+			glyph := binary.BigEndian.Uint32(group[8:])
+			for r := start; r <= end; r++ {
+				glyphs[rune(r)] = uint16(glyph + r - start)
+			}
+		}
+	case basic != nil:
+		segments := int(binary.BigEndian.Uint16(basic[6:])) / 2
+		ends := basic[14:]
+		starts := ends[2*segments+2:]
+		deltas := starts[2*segments:]
+		ranges := deltas[2*segments:]
+		for i := range segments {
+			start := binary.BigEndian.Uint16(starts[2*i:])
+			end := binary.BigEndian.Uint16(ends[2*i:])
+			delta := binary.BigEndian.Uint16(deltas[2*i:])
+			rangeOffset := int(binary.BigEndian.Uint16(ranges[2*i:]))
+			for r := int(start); r <= int(end) && r != 0xFFFF; r++ {
+				glyph := uint16(r) + delta
+				if rangeOffset != 0 {
+					at := 2*i + rangeOffset + 2*(r-int(start))
+					glyph = binary.BigEndian.Uint16(ranges[at:])
+					if glyph != 0 {
+						glyph += delta
+					}
+				}
+				if glyph != 0 {
+					glyphs[rune(r)] = glyph
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("no unicode character map")
+	}
+	return glyphs, nil
+}
+
+// glyph returns the glyph of r, the missing glyph 0 when the font has none.
+func (f *font) glyph(r rune) uint16 {
+	return f.glyphs[r]
+}
+
+// advance returns the width of a glyph in thousandths of the font size.
+func (f *font) advance(glyph uint16) float64 {
+	return float64(f.advances[glyph]) * 1000 / float64(f.unitsPerEm)
+}
+
+// width returns the width of text set at size points.
+func (f *font) width(text string, size float64) float64 {
+	total := 0.0
+	for _, r := range text {
+		total += f.advance(f.glyph(r))
+	}
+	return total * size / 1000
+}
+
+// scale turns font units into thousandths of the font size.
+func (f *font) scale(units int) int {
+	return units * 1000 / f.unitsPerEm
+}
+
+// glyphData returns the outline of a glyph, empty for blank glyphs.
+func (f *font) glyphData(glyph uint16) []byte {
+	glyf := f.tables["glyf"]
+	start, end := f.loca[glyph], f.loca[glyph+1]
+	if start >= end || int(end) > len(glyf) {
+		return nil
+	}
+	return glyf[start:end]
+}
+
+// components returns the glyphs a composite glyph is built from.
+func (f *font) components(glyph uint16) []uint16 {
+	data := f.glyphData(glyph)
+	if len(data) < 10 || int16(binary.BigEndian.Uint16(data)) >= 0 {
+		return nil
+	}
+	const (
+		argsAreWords  = 0x0001
+		haveScale     = 0x0008
+		moreComponent = 0x0020
+		haveXYScale   = 0x0040
+		haveTwoByTwo  = 0x0080
+	)
+	var result []uint16
+	for at := 10; at+4 <= len(data); {
+		flags := binary.BigEndian.Uint16(data[at:])
+		result = append(result, binary.BigEndian.Uint16(data[at+2:]))
+		at += 4
+		if flags&argsAreWords != 0 {
+			at += 4
+		} else {
+			at += 2
+		}
+		switch {
+		case flags&haveScale != 0:
+			at += 2
+		case flags&haveXYScale != 0:
+			at += 4
+		case flags&haveTwoByTwo != 0:
+			at += 8
+		}
+		if flags&moreComponent == 0 {
+			break
+		}
+	}
+	return result
+}
+
+// subset returns the font with the outlines of every glyph but the used ones,
+// the ones they are composed of and the missing glyph removed. Glyph numbers
+// stay the same, so the text of a page can address glyphs by their number.
+func (f *font) subset(used map[uint16]rune) []byte {
+	keep := map[uint16]bool{}
+	pending := []uint16{0}
+	for glyph := range used {
+		pending = append(pending, glyph)
+	}
+	for len(pending) > 0 {
+		glyph := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if keep[glyph] {
+			continue
+		}
+		keep[glyph] = true
+		for _, component := range f.components(glyph) {
+			if !keep[component] && int(component) < len(f.advances) {
+				pending = append(pending, component)
+			}
+		}
+	}
+
+	var glyf bytes.Buffer
+	loca := make([]byte, 4*len(f.loca))
+	for glyph := range len(f.loca) - 1 {
+		binary.BigEndian.PutUint32(loca[4*glyph:], uint32(glyf.Len()))
+		if keep[uint16(glyph)] {
+			glyf.Write(f.glyphData(uint16(glyph)))
+			for glyf.Len()%4 != 0 {
+				glyf.WriteByte(0)
+			}
+		}
+	}
+	binary.BigEndian.PutUint32(loca[4*(len(f.loca)-1):], uint32(glyf.Len()))
+
+	head := slices.Clone(f.tables["head"])
+	binary.BigEndian.PutUint32(head[8:], 0)
+	binary.BigEndian.PutUint16(head[50:], 1)
+
+	tables := map[string][]byte{
+		"glyf": glyf.Bytes(),
+		"loca": loca,
+		"head": head,
+		"hhea": f.tables["hhea"],
+		"hmtx": f.tables["hmtx"],
+		"maxp": f.tables["maxp"],
+	}
+	for _, tag := range []string{"cvt ", "fpgm", "prep"} {
+		if f.tables[tag] != nil {
+			tables[tag] = f.tables[tag]
+		}
+	}
+	return writeFont(tables)
+}
+
+// writeFont assembles TrueType tables into a font file.
+func writeFont(tables map[string][]byte) []byte {
+	var tags []string
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+
+	searchRange, selector := 1, 0
+	for searchRange*2 <= len(tags) {
+		searchRange *= 2
+		selector++
+	}
+	var out bytes.Buffer
+	binary.Write(&out, binary.BigEndian, []uint16{
+		1, 0, uint16(len(tags)), uint16(16 * searchRange), uint16(selector), uint16(16 * (len(tags) - searchRange)),
+	})
+
+	offset := 12 + 16*len(tags)
+	headAt := 0
+	for _, tag := range tags {
+		data := tables[tag]
+		if tag == "head" {
+			headAt = offset
+		}
+		out.WriteString(tag)
+		binary.Write(&out, binary.BigEndian, []uint32{checksum(data), uint32(offset), uint32(len(data))})
+		offset += (len(data) + 3) &^ 3
+	}
+	for _, tag := range tags {
+		out.Write(tables[tag])
+		for out.Len()%4 != 0 {
+			out.WriteByte(0)
+		}
+	}
+
+	result := out.Bytes()
+	binary.BigEndian.PutUint32(result[headAt+8:], 0xB1B0AFBA-checksum(result))
+	return result
+}
+
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
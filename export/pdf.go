@@ -0,0 +1,363 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 40.0
+)
+
+type pdfLine struct {
+	x    float64
+	y    float64
+	size float64
+	bold bool
+	text string
+}
+
+func lineFont(bold bool) *font {
+	if bold {
+		return boldFont
+	}
+	return regularFont
+}
+
+// PDF is a minimal A4 text document. It is set in an embedded DejaVu Sans, so
+// Cyrillic and any other text the font covers is printed as it is.
+type PDF struct {
+	pages [][]pdfLine
+	y     float64
+}
+
+func NewPDF() *PDF {
+	return &PDF{pages: [][]pdfLine{{}}, y: pageHeight - margin}
+}
+
+// Text adds a paragraph, wrapping it to the page width and starting a new page when needed.
+func (p *PDF) Text(size float64, bold bool, text string) {
+	p.TextAt(0, size, bold, text)
+}
+
+// TextAt is Text with the paragraph indented by indent points.
+func (p *PDF) TextAt(indent float64, size float64, bold bool, text string) {
+	for _, line := range wrap(text, lineFont(bold), size, pageWidth-2*margin-indent) {
+		p.advance(size)
+		p.add(pdfLine{x: margin + indent, y: p.y, size: size, bold: bold, text: line})
+	}
+}
+
+// advance moves down one line of the given size, starting a new page when the
+// line does not fit. It tells whether a page was started.
+func (p *PDF) advance(size float64) bool {
+	started := false
+	if p.y-size < margin {
+		p.pages = append(p.pages, []pdfLine{})
+		p.y = pageHeight - margin
+		started = true
+	}
+	p.y -= size * 1.3
+	return started
+}
+
+func (p *PDF) add(line pdfLine) {
+	last := len(p.pages) - 1
+	p.pages[last] = append(p.pages[last], line)
+}
+
+// Table adds a table whose bold header is repeated on every page. Columns are
+// as wide as their longest value, when the page is too narrow the widest ones
+// are shrunk and cells that do not fit are cut.
+func (p *PDF) Table(size float64, header []string, rows [][]string) {
+	padding := size
+	widths := make([]float64, len(header))
+	for i, cell := range header {
+		widths[i] = boldFont.width(cell, size)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = max(widths[i], regularFont.width(cell, size))
+			}
+		}
+	}
+
+	total := 0.0
+	for i := range widths {
+		widths[i] += padding
+		total += widths[i]
+	}
+	available := pageWidth - 2*margin
+	if total > available {
+		shrinkColumns(widths, available, 4*padding)
+	}
+
+	line := func(cells []string, bold bool) {
+		x := margin
+		for i, width := range widths {
+			if i < len(cells) {
+				text := cut(cells[i], lineFont(bold), size, width-padding/2)
+				p.add(pdfLine{x: x, y: p.y, size: size, bold: bold, text: text})
+			}
+			x += width
+		}
+	}
+
+	p.advance(size)
+	line(header, true)
+	for _, row := range rows {
+		if p.advance(size) {
+			line(header, true)
+			p.advance(size)
+		}
+		line(row, false)
+	}
+}
+
+// shrinkColumns fits widths into available points. Columns narrower than an
+// even share of the remaining space keep their width, the others split it but
+// get no narrower than least.
+func shrinkColumns(widths []float64, available float64, least float64) {
+	fixed := make([]bool, len(widths))
+	open := len(widths)
+	for changed := true; changed && open > 0; {
+		changed = false
+		share := available / float64(open)
+		for i, width := range widths {
+			if !fixed[i] && width <= share {
+				fixed[i] = true
+				available -= width
+				open--
+				changed = true
+			}
+		}
+	}
+	for i := range widths {
+		if !fixed[i] {
+			widths[i] = max(available/float64(open), least)
+		}
+	}
+}
+
+// cut shortens text to width points, marking the cut with an ellipsis.
+func cut(text string, f *font, size float64, width float64) string {
+	if f.width(text, size) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && f.width(string(runes)+"…", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	if len(runes) == 0 {
+		return ""
+	}
+	return string(runes) + "…"
+}
+
+func (p *PDF) Gap(height float64) {
+	p.y -= height
+}
+
+// wrap breaks text into lines no wider than width points. A word longer than
+// a line is put on a line of its own.
+func wrap(text string, f *font, size float64, width float64) []string {
+	space := f.width(" ", size)
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		lineWidth := 0.0
+		for _, word := range strings.Fields(paragraph) {
+			wordWidth := f.width(word, size)
+			if line != "" && lineWidth+space+wordWidth > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+				lineWidth += space
+			} else {
+				lineWidth = 0
+			}
+			line += word
+			lineWidth += wordWidth
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// encode writes text as the hex string of its glyph numbers, recording the
+// glyphs in used together with the characters they stand for.
+func encode(w *bytes.Buffer, f *font, text string, used map[uint16]rune) {
+	w.WriteByte('<')
+	for _, r := range text {
+		glyph := f.glyph(r)
+		if _, ok := used[glyph]; !ok && glyph != 0 {
+			used[glyph] = r
+		}
+		fmt.Fprintf(w, "%04X", glyph)
+	}
+	w.WriteByte('>')
+}
+
+// fontObjects returns the bodies of the five objects embedding the used glyphs
+// of f, numbered from first: the Type0 font, its CIDFontType2 descendant, the
+// font descriptor, the font file and the ToUnicode map.
+func fontObjects(f *font, used map[uint16]rune, first int) ([]string, error) {
+	glyphs := make([]uint16, 0, len(used))
+	for glyph := range used {
+		glyphs = append(glyphs, glyph)
+	}
+	slices.Sort(glyphs)
+
+	tag := subsetTag(glyphs)
+	name := tag + "+" + f.name
+
+	var widths strings.Builder
+	for i, glyph := range glyphs {
+		if i == 0 || glyphs[i-1] != glyph-1 {
+			if i > 0 {
+				widths.WriteString("] ")
+			}
+			fmt.Fprintf(&widths, "%d [", glyph)
+		} else {
+			widths.WriteByte(' ')
+		}
+		fmt.Fprintf(&widths, "%.0f", f.advance(glyph))
+	}
+	if len(glyphs) > 0 {
+		widths.WriteString("]")
+	}
+
+	var file bytes.Buffer
+	program := f.subset(used)
+	zw := zlib.NewWriter(&file)
+	if _, err := zw.Write(program); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	var cmap bytes.Buffer
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for start := 0; start < len(glyphs); start += 100 {
+		chunk := glyphs[start:min(start+100, len(glyphs))]
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", len(chunk))
+		for _, glyph := range chunk {
+			fmt.Fprintf(&cmap, "<%04X> <", glyph)
+			for _, unit := range utf16.Encode([]rune{used[glyph]}) {
+				fmt.Fprintf(&cmap, "%04X", unit)
+			}
+			cmap.WriteString(">\n")
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+
+	return []string{
+		fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H "+
+			"/DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", name, first+1, first+4),
+		fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+			"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+			"/FontDescriptor %d 0 R /CIDToGIDMap /Identity /DW %.0f /W [%s] >>",
+			name, first+2, f.advance(0), widths.String()),
+		fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] "+
+			"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+			name, f.scale(f.bbox[0]), f.scale(f.bbox[1]), f.scale(f.bbox[2]), f.scale(f.bbox[3]),
+			f.scale(f.ascent), f.scale(f.descent), f.scale(f.capHeight), first+3),
+		fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+			file.Len(), len(program), file.String()),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", cmap.Len(), cmap.String()),
+	}, nil
+}
+
+// subsetTag names a font subset after the glyphs in it, six capital letters as
+// PDF asks for.
+func subsetTag(glyphs []uint16) string {
+	hash := fnv.New32a()
+	for _, glyph := range glyphs {
+		binary.Write(hash, binary.BigEndian, glyph)
+	}
+	sum := hash.Sum32()
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = byte('A' + sum%26)
+		sum /= 26
+	}
+	return string(tag)
+}
+
+// WriteTo renders the document. Object 1 is the catalog, 2 the page tree,
+// 3 to 7 the regular font and 8 to 12 the bold one, followed by a page and a
+// content stream for every page.
+func (p *PDF) WriteTo(w io.Writer) (int64, error) {
+	const firstPage = 13
+	used := map[*font]map[uint16]rune{regularFont: {}, boldFont: {}}
+	contents := make([]bytes.Buffer, len(p.pages))
+	for i, page := range p.pages {
+		for _, line := range page {
+			name, f := "F1", regularFont
+			if line.bold {
+				name, f = "F2", boldFont
+			}
+			fmt.Fprintf(&contents[i], "BT /%s %.1f Tf %.1f %.1f Td ", name, line.size, line.x, line.y)
+			encode(&contents[i], f, line.text, used[f])
+			contents[i].WriteString(" Tj ET\n")
+		}
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	var kids []string
+	for i := range p.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPage+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	for _, f := range []*font{regularFont, boldFont} {
+		bodies, err := fontObjects(f, used[f], len(offsets)+1)
+		if err != nil {
+			return 0, err
+		}
+		for _, body := range bodies {
+			object(body)
+		}
+	}
+
+	for i, content := range contents {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 8 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
@@ -0,0 +1,73 @@
+package export
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPDFKeepsCyrillic(t *testing.T) {
+	table := Table{
+		Title:   "tourists",
+		Columns: []string{"surname", "name"},
+		Rows:    [][]string{{"Щербакова", "Алёна"}, {"Éluard", "«Поль» — 5°"}},
+	}
+	data, _, err := table.Render(FormatPDF, "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range "ТуристыФамилияЩербаковаАлёнаÉ«»—°" {
+		glyph := regularFont.glyph(r)
+		if glyph == 0 {
+			t.Fatalf("the font has no glyph for %q", r)
+		}
+	}
+	var unicode []string
+	for _, r := range "ЩербаковаАлёна«»—°" {
+		unicode = append(unicode, strings.ToUpper(strconv.FormatInt(int64(r), 16)))
+	}
+	for _, code := range unicode {
+		if !regexp.MustCompile(`<[0-9A-F]{4}> <0*` + code + `>`).Match(data) {
+			t.Errorf("the ToUnicode map has no U+%s", code)
+		}
+	}
+
+	xref := bytes.LastIndex(data, []byte("\nxref\n")) + 1
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(data[xref:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if !bytes.HasPrefix(data[offset:], []byte(strconv.Itoa(i+1)+" 0 obj")) {
+			t.Errorf("xref entry %d points at %q", i+1, data[offset:offset+10])
+		}
+	}
+}
+
+func TestFontSubsetKeepsUsedGlyphs(t *testing.T) {
+	used := map[uint16]rune{}
+	for _, r := range "Ёлка й" {
+		used[regularFont.glyph(r)] = r
+	}
+	subset, err := parseFont("subset", regularFont.subset(used))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checksum(regularFont.subset(used)) != 0xB1B0AFBA {
+		t.Error("the subset has a wrong checksum adjustment")
+	}
+	for glyph := range used {
+		if !bytes.Equal(subset.glyphData(glyph), regularFont.glyphData(glyph)) {
+			t.Errorf("glyph %d changed in the subset", glyph)
+		}
+		for _, component := range regularFont.components(glyph) {
+			if !bytes.Equal(subset.glyphData(component), regularFont.glyphData(component)) {
+				t.Errorf("component %d of glyph %d changed in the subset", component, glyph)
+			}
+		}
+	}
+	if data := subset.glyphData(regularFont.glyph('Ж')); data != nil {
+		t.Error("an unused glyph was kept in the subset")
+	}
+}
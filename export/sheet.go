@@ -0,0 +1,220 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ReadTable reads the rows of a spreadsheet, telling XLSX from CSV by the zip
+// signature at the start of data.
+func ReadTable(data []byte) ([][]string, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return ReadXLSX(data)
+	}
+	return ReadCSV(data)
+}
+
+// ReadCSV reads comma or semicolon separated values, the latter being what
+// spreadsheet programs write in Russian locales. A byte order mark is skipped.
+func ReadCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read csv: %w", err)
+	}
+	return rows, nil
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxWorkbookSheets struct {
+	Sheets []struct {
+		Id string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	text := t.Text
+	for _, run := range t.Runs {
+		text += run.Text
+	}
+	return text
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// Limits on what ReadXLSX accepts, so that a small archive cannot inflate into
+// huge XML or make the reader pad rows and cells up to far away references.
+const (
+	maxXLSXPartSize  = 64 << 20
+	maxXLSXColumns   = 16384
+	maxXLSXBlankRows = 10000
+	maxXLSXCells     = 1 << 21
+)
+
+func readZipXML(archive *zip.Reader, name string, v any) (bool, error) {
+	file, err := archive.Open(name)
+	if err != nil {
+		return false, nil
+	}
+	defer file.Close()
+	limited := &io.LimitedReader{R: file, N: maxXLSXPartSize}
+	err = xml.NewDecoder(limited).Decode(v)
+	if limited.N == 0 {
+		return true, fmt.Errorf("%s is larger than %d bytes", name, maxXLSXPartSize)
+	}
+	if err != nil {
+		return true, fmt.Errorf("unable to read %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// columnIndex turns the letters of a cell reference such as "AB12" into a zero
+// based column index, or -1 when the reference has no letters or is beyond the
+// last column of a sheet.
+func columnIndex(ref string) int {
+	index := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+		if index > maxXLSXColumns {
+			return -1
+		}
+	}
+	return index - 1
+}
+
+// ReadXLSX reads the cells of the first sheet of a workbook as text. Numbers
+// are returned the way they are stored, so dates come as serial day numbers.
+func ReadXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to open xlsx: %w", err)
+	}
+
+	sheetPath := "xl/worksheets/sheet1.xml"
+	var workbook xlsxWorkbookSheets
+	var rels xlsxRelationships
+	found, err := readZipXML(archive, "xl/workbook.xml", &workbook)
+	if err != nil {
+		return nil, err
+	}
+	if found && len(workbook.Sheets) > 0 {
+		_, err = readZipXML(archive, "xl/_rels/workbook.xml.rels", &rels)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range rels.Relationships {
+			if rel.Id == workbook.Sheets[0].Id {
+				if strings.HasPrefix(rel.Target, "/") {
+					sheetPath = strings.TrimPrefix(rel.Target, "/")
+				} else {
+					sheetPath = path.Join("xl", rel.Target)
+				}
+			}
+		}
+	}
+
+	var shared xlsxSharedStrings
+	_, err = readZipXML(archive, "xl/sharedStrings.xml", &shared)
+	if err != nil {
+		return nil, err
+	}
+
+	var sheet xlsxSheet
+	found, err = readZipXML(archive, sheetPath, &sheet)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("xlsx has no sheet %s", sheetPath)
+	}
+
+	var rows [][]string
+	total := 0
+	for _, row := range sheet.Rows {
+		if row.Number > len(sheet.Rows)+maxXLSXBlankRows {
+			return nil, fmt.Errorf("xlsx row %d is far beyond the %d rows of the sheet", row.Number, len(sheet.Rows))
+		}
+		for row.Number > 0 && len(rows) < row.Number-1 {
+			rows = append(rows, nil)
+		}
+		var cells []string
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				column = columnIndex(cell.Ref)
+			}
+			if column < 0 || column >= maxXLSXColumns {
+				return nil, fmt.Errorf("xlsx cell reference %q is not a valid column", cell.Ref)
+			}
+			if total+column >= maxXLSXCells {
+				return nil, fmt.Errorf("xlsx sheet has more than %d cells", maxXLSXCells)
+			}
+			for len(cells) < column {
+				cells = append(cells, "")
+			}
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(shared.Items) {
+					return nil, fmt.Errorf("xlsx cell %s refers to a missing shared string", cell.Ref)
+				}
+				value = shared.Items[index].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			}
+			if column < len(cells) {
+				cells[column] = value
+			} else {
+				cells = append(cells, value)
+			}
+		}
+		total += len(cells)
+		rows = append(rows, cells)
+	}
+	return rows, nil
+}
@@ -0,0 +1,160 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+// ContentTypes maps every format a table can be rendered to onto its media type.
+var ContentTypes = map[string]string{
+	FormatJSON: "application/json",
+	FormatCSV:  "text/csv; charset=utf-8",
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	FormatPDF:  "application/pdf",
+}
+
+// Table is a filter result ready to be rendered. Title and Columns are keys of
+// the labels dictionary and get localized on Render, Subtitle is printed as is.
+type Table struct {
+	Title    string
+	Subtitle string
+	Columns  []string
+	Rows     [][]string
+}
+
+var labels = map[string]map[string]string{
+	"ru": {
+		"tourists":      "Туристы",
+		"trainers":      "Тренеры",
+		"managers":      "Руководители секций",
+		"instructors":   "Инструкторы",
+		"routes":        "Маршруты",
+		"strain":        "Нагрузка тренера",
+		"championships": "Соревнования",
+		"id":            "№",
+		"surname":       "Фамилия",
+		"name":          "Имя",
+		"patronymic":    "Отчество",
+		"full_name":     "ФИО",
+		"title":         "Название",
+		"date":          "Дата",
+		"type":          "Тип",
+		"difficulty":    "Категория сложности",
+		"length_km":     "Протяжённость, км",
+		"places":        "Пункты маршрута",
+		"rating":        "Оценка",
+		"reviews":       "Отзывов",
+		"workout_type":  "Вид занятий",
+		"duration":      "Продолжительность",
+		"section":       "Секция",
+		"role":          "Роль",
+		"group":         "Группа",
+	},
+	"en": {
+		"tourists":      "Tourists",
+		"trainers":      "Trainers",
+		"managers":      "Section managers",
+		"instructors":   "Instructors",
+		"routes":        "Routes",
+		"strain":        "Trainer workload",
+		"championships": "Championships",
+		"id":            "ID",
+		"surname":       "Surname",
+		"name":          "Name",
+		"patronymic":    "Patronymic",
+		"full_name":     "Full name",
+		"title":         "Title",
+		"date":          "Date",
+		"type":          "Type",
+		"difficulty":    "Difficulty",
+		"length_km":     "Length, km",
+		"places":        "Places",
+		"rating":        "Rating",
+		"reviews":       "Reviews",
+		"workout_type":  "Workout type",
+		"duration":      "Duration",
+		"section":       "Section",
+		"role":          "Role",
+		"group":         "Group",
+	},
+}
+
+// DefaultLanguage is used when none of the requested languages is known.
+const DefaultLanguage = "ru"
+
+// Language picks the first known language of a comma separated list such as
+// an Accept-Language header, ignoring regions and weights.
+func Language(requested string) string {
+	for _, tag := range strings.Split(requested, ",") {
+		tag, _, _ = strings.Cut(tag, ";")
+		tag, _, _ = strings.Cut(strings.TrimSpace(tag), "-")
+		tag = strings.ToLower(tag)
+		if _, ok := labels[tag]; ok {
+			return tag
+		}
+	}
+	return DefaultLanguage
+}
+
+func label(lang string, key string) string {
+	if text, ok := labels[lang][key]; ok {
+		return text
+	}
+	return key
+}
+
+// ColumnKey finds the key of a column header given either as the key itself
+// or as its label in any language.
+func ColumnKey(header string) (string, bool) {
+	header = strings.ToLower(strings.TrimSpace(header))
+	for _, dictionary := range labels {
+		for key, text := range dictionary {
+			if header == key || header == strings.ToLower(text) {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Render writes the table in format with labels in lang and returns the
+// document together with its content type.
+func (t Table) Render(format string, lang string) ([]byte, string, error) {
+	title := label(lang, t.Title)
+	header := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		header[i] = label(lang, column)
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatCSV:
+		err = WriteCSV(&buf, header, t.Rows)
+	case FormatXLSX:
+		err = WriteXLSX(&buf, title, header, t.Rows)
+	case FormatPDF:
+		doc := NewPDF()
+		doc.Text(14, true, title)
+		if t.Subtitle != "" {
+			doc.Text(10, false, t.Subtitle)
+		}
+		doc.Gap(8)
+		doc.Table(9, header, t.Rows)
+		_, err = doc.WriteTo(&buf)
+	default:
+		return nil, "", fmt.Errorf("unknown export format %q", format)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), ContentTypes[format], nil
+}
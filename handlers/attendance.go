@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"db_backend/dto"
+	"db_backend/services"
+	"db_backend/utils"
+	"encoding/json"
+	"net/http"
+)
+
+func MarkAttendance(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	var req dto.AttendanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err := services.MarkAttendance(viewer, req)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func MarkGroupAttendance(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	var req dto.AttendanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err := services.MarkGroupAttendance(viewer, req)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func GetWorkoutAttendance(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	workout := r.FormValue("workout")
+
+	data, err := services.GetWorkoutAttendance(workout)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetPersonAttendance(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	person := r.FormValue("person")
+	fromDate := r.FormValue("from_date")
+	toDate := r.FormValue("to_date")
+
+	data, err := services.GetPersonAttendance(person, fromDate, toDate)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetGroupAttendance(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	group := r.FormValue("group")
+	fromDate := r.FormValue("from_date")
+	toDate := r.FormValue("to_date")
+
+	data, err := services.GetGroupAttendance(group, fromDate, toDate)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetLowAttendance(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	section := r.FormValue("section")
+	fromDate := r.FormValue("from_date")
+	toDate := r.FormValue("to_date")
+	threshold := r.FormValue("threshold")
+	minSessions := r.FormValue("min_sessions")
+
+	data, err := services.GetLowAttendance(section, fromDate, toDate, threshold, minSessions)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
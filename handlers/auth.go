@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"db_backend/dto"
+	"db_backend/services"
+	"db_backend/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type viewerKey struct{}
+
+func bearerToken(r *http.Request) (string, bool) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return strings.TrimSpace(token), found
+}
+
+// Authenticate resolves the bearer token of a request to the person it was
+// issued to. Requests without a token pass through anonymously and are refused
+// by the endpoints that need to know the requester.
+func Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token, found := bearerToken(r)
+		if !found || token == "" {
+			utils.RespondWithError(w, http.StatusUnauthorized, "expected a bearer token")
+			return
+		}
+		person, err := services.Authenticate(token)
+		if err != nil {
+			utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), viewerKey{}, person)))
+	})
+}
+
+// requestViewer returns the id of the authenticated person, or "" for anonymous requests.
+func requestViewer(r *http.Request) string {
+	person, ok := r.Context().Value(viewerKey{}).(int)
+	if !ok {
+		return ""
+	}
+	return strconv.Itoa(person)
+}
+
+func IssueToken(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req dto.AuthTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	data, err := services.IssueTokenFor(requestViewer(r), req)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func RevokeToken(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	token, found := bearerToken(r)
+	if !found {
+		utils.RespondWithError(w, http.StatusUnauthorized, "expected a bearer token")
+		return
+	}
+	err := services.RevokeToken(token)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
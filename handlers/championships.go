@@ -1,19 +1,50 @@
 package handlers
 
 import (
+	"db_backend/export"
 	"db_backend/services"
 	"db_backend/utils"
 	"net/http"
 )
 
-func FindChampionships(w http.ResponseWriter, r *http.Request) {
+func (c *Club) FindChampionships(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	section := r.FormValue("section")
 
-	data, err := services.GetChampionshipsWithCondition(section)
+	data, err := c.club.GetChampionshipsWithCondition(section)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if format := exportFormat(r); format != export.FormatJSON {
+		respondWithTable(w, r, format, "championships", services.ChampionshipsTable(data))
+		return
+	}
 	utils.RespondWithJSON(w, http.StatusOK, data)
 }
+
+func (c *Club) RegisterForChampionship(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	championship := r.FormValue("championship")
+	person := r.FormValue("person")
+
+	err := c.club.RegisterForChampionship(championship, person)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func (c *Club) UnregisterFromChampionship(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	championship := r.FormValue("championship")
+	person := r.FormValue("person")
+
+	err := c.club.UnregisterFromChampionship(championship, person)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"db_backend/dto"
+	"db_backend/services"
+	"db_backend/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+func AddTourCheckin(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	var req dto.TourCheckin
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := services.AddTourCheckin(viewer, req)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
+}
+
+func GetTourCheckins(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+
+	data, err := services.GetTourCheckins(tour)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetOverdueTours(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	data, err := services.GetOverdueTours()
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
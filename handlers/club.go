@@ -0,0 +1,12 @@
+package handlers
+
+import "db_backend/services"
+
+// Club holds the handlers answering from the club repositories.
+type Club struct {
+	club *services.Club
+}
+
+func NewClub(club *services.Club) *Club {
+	return &Club{club: club}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"db_backend/services"
+	"db_backend/utils"
+	"net/http"
+)
+
+func SetTourCapacity(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+	minParticipants := r.FormValue("min")
+	maxParticipants := r.FormValue("max")
+
+	data, err := services.SetTourCapacity(tour, minParticipants, maxParticipants)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func (c *Club) EnrollTourist(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+	person := r.FormValue("person")
+
+	data, err := c.club.EnrollTourist(tour, person)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func CancelTourEnrollment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+	person := r.FormValue("person")
+
+	data, err := services.CancelTourEnrollment(tour, person)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetTourParticipants(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+
+	data, err := services.GetTourParticipants(tour)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func CloseTourEnrollment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+
+	data, err := services.SetTourEnrollmentClosed(tour, true)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func OpenTourEnrollment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+
+	data, err := services.SetTourEnrollmentClosed(tour, false)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetToursAtRisk(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	data, err := services.GetToursAtRisk()
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"db_backend/dto"
+	"db_backend/services"
+	"db_backend/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+func CreateEquipment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req dto.Equipment
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := services.CreateEquipment(req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
+}
+
+func GetEquipment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	id := r.FormValue("id")
+	item, err := services.GetEquipment(id)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, item)
+}
+
+func UpdateEquipment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req dto.Equipment
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err := services.UpdateEquipment(req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func DeleteEquipment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	id := r.FormValue("id")
+	err := services.DeleteEquipment(id)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func GetEquipmentList(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	section := r.FormValue("section")
+	category := r.FormValue("category")
+
+	items, err := services.GetEquipmentList(section, category)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, items)
+}
+
+func CheckoutEquipment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req dto.EquipmentCheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := services.CheckoutEquipment(req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
+}
+
+func CheckinEquipment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req dto.EquipmentCheckinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err := services.CheckinEquipment(req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func GetEquipmentCheckouts(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+	person := r.FormValue("person")
+	open := r.FormValue("open")
+
+	data, err := services.GetEquipmentCheckouts(tour, person, open)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetEquipmentAvailability(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+	fromDate := r.FormValue("from_date")
+	toDate := r.FormValue("to_date")
+
+	data, err := services.GetEquipmentAvailability(tour, fromDate, toDate)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
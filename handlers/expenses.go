@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"db_backend/dto"
+	"db_backend/services"
+	"db_backend/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+func AddTourExpense(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req dto.TourExpenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := services.AddTourExpense(req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
+}
+
+func DeleteTourExpense(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	id := r.FormValue("id")
+	err := services.DeleteTourExpense(id)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func GetTourExpenses(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+
+	data, err := services.GetTourExpenses(tour)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetTourSettlement(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+
+	data, err := services.GetTourSettlement(tour)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetSectionExpenseSummary(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	year := r.FormValue("year")
+	section := r.FormValue("section")
+
+	data, err := services.GetSectionExpenseSummary(year, section)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
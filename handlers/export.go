@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"db_backend/export"
+	"db_backend/utils"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// exportFormat picks the format of a filter result: the export parameter if it
+// names a known format, otherwise the first known media type of the Accept
+// header. JSON is the default. It is not called format, which some endpoints
+// already use for their own options.
+func exportFormat(r *http.Request) string {
+	format := strings.ToLower(r.FormValue("export"))
+	if _, ok := export.ContentTypes[format]; ok {
+		return format
+	}
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		for format, contentType := range export.ContentTypes {
+			known, _, _ := mime.ParseMediaType(contentType)
+			if mediaType == known {
+				return format
+			}
+		}
+	}
+	return export.FormatJSON
+}
+
+// exportLanguage reads the language of column headers from the lang parameter
+// or the Accept-Language header.
+func exportLanguage(r *http.Request) string {
+	lang := r.FormValue("lang")
+	if lang == "" {
+		lang = r.Header.Get("Accept-Language")
+	}
+	return export.Language(lang)
+}
+
+func respondWithTable(w http.ResponseWriter, r *http.Request, format string, name string, table export.Table) {
+	data, contentType, err := table.Render(format, exportLanguage(r))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.%s\"", name, format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"db_backend/dto"
+	"db_backend/services"
+	"db_backend/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+func CreateMembershipFee(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req dto.MembershipFee
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := services.CreateMembershipFee(req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
+}
+
+func DeleteMembershipFee(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	id := r.FormValue("id")
+	err := services.DeleteMembershipFee(id)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func GetMembershipFees(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	section := r.FormValue("section")
+
+	data, err := services.GetMembershipFees(section)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func AddMembershipPayment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req dto.MembershipPayment
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := services.AddMembershipPayment(req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
+}
+
+func GetMembershipPayments(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	person := r.FormValue("person")
+
+	data, err := services.GetMembershipPayments(person)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetArrears(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	section := r.FormValue("section")
+	date := r.FormValue("date")
+
+	data, err := services.GetArrears(section, date)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"db_backend/dto"
+	"db_backend/services"
+	"db_backend/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+func CreateFitnessTest(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req dto.FitnessTest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := services.CreateFitnessTest(req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
+}
+
+func GetFitnessTests(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	data, err := services.GetFitnessTests()
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func DeleteFitnessTest(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	id := r.FormValue("id")
+	err := services.DeleteFitnessTest(id)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func AddFitnessResult(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req dto.FitnessResult
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := services.AddFitnessResult(req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
+}
+
+func DeleteFitnessResult(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	id := r.FormValue("id")
+	err := services.DeleteFitnessResult(id)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func GetFitnessResults(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	person := r.FormValue("person")
+	test := r.FormValue("test")
+
+	data, err := services.GetFitnessResults(person, test)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetPersonalBests(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	person := r.FormValue("person")
+	test := r.FormValue("test")
+
+	data, err := services.GetPersonalBests(person, test)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetFitnessRanking(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	group := r.FormValue("group")
+	test := r.FormValue("test")
+	fromDate := r.FormValue("from_date")
+	toDate := r.FormValue("to_date")
+
+	data, err := services.GetFitnessRanking(group, test, fromDate, toDate)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
@@ -2,15 +2,17 @@ package handlers
 
 import (
 	"db_backend/dto"
-	"db_backend/services"
 	"db_backend/utils"
 	"encoding/json"
+	"io"
 	"log"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
 )
 
-func CreateGroup(w http.ResponseWriter, r *http.Request) {
+func (c *Club) CreateGroup(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	var req dto.Group
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -18,7 +20,7 @@ func CreateGroup(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	id, err := services.CreateGroup(req)
+	id, err := c.club.CreateGroup(req)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -26,34 +28,39 @@ func CreateGroup(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
 }
 
-func GetGroup(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetGroup(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	id := r.FormValue("id")
-	group, err := services.GetGroup(id)
+	group, err := c.club.GetGroup(id)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	var groupResponse = dto.Group{}
-
 	if group == nil {
 		utils.RespondWithJSON(w, http.StatusOK, nil)
 		return
 	}
-	groupResponse.Id = group.Id
-	groupResponse.GroupNumber = group.GroupNumber
-	groupResponse.Section = group.Section
-	utils.RespondWithJSON(w, http.StatusOK, groupResponse)
+	utils.RespondWithJSON(w, http.StatusOK, group)
 }
 
-func UpdateGroup(w http.ResponseWriter, r *http.Request) {
+func (c *Club) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	var group dto.Group
-	if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+	if err := json.Unmarshal(body, &group); err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	err := services.UpdateGroup(group)
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err = c.club.UpdateGroup(group, slices.Collect(maps.Keys(fields)))
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -61,10 +68,10 @@ func UpdateGroup(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func DeleteGroup(w http.ResponseWriter, r *http.Request) {
+func (c *Club) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	id := r.FormValue("id")
-	err := services.DeleteGroup(id)
+	err := c.club.DeleteGroup(id)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -72,10 +79,10 @@ func DeleteGroup(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func GetGroupMembers(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetGroupMembers(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	id := r.FormValue("id")
-	members, err := services.GetGroupMembers(id)
+	members, err := c.club.GetGroupMembers(id)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -83,12 +90,12 @@ func GetGroupMembers(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, members)
 }
 
-func AddGroupMember(w http.ResponseWriter, r *http.Request) {
+func (c *Club) AddGroupMember(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	person := r.FormValue("person")
 	group := r.FormValue("group")
 
-	err := services.AddGroupMember(group, person)
+	err := c.club.AddGroupMember(group, person)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -96,11 +103,11 @@ func AddGroupMember(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func RemoveGroupMember(w http.ResponseWriter, r *http.Request) {
+func (c *Club) RemoveGroupMember(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	person := r.FormValue("person")
 	group := r.FormValue("group")
-	err := services.RemoveGroupMember(person, group)
+	err := c.club.RemoveGroupMember(person, group)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -108,9 +115,9 @@ func RemoveGroupMember(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func GetAllGroups(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetAllGroups(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
-	groups, err := services.GetAllGroups()
+	groups, err := c.club.GetAllGroups()
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -118,14 +125,14 @@ func GetAllGroups(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, groups)
 }
 
-func CreateSection(w http.ResponseWriter, r *http.Request) {
+func (c *Club) CreateSection(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	var req dto.Section
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	id, err := services.CreateSection(req)
+	id, err := c.club.CreateSection(req)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -133,10 +140,10 @@ func CreateSection(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
 }
 
-func GetSection(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetSection(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	id := r.FormValue("id")
-	section, err := services.GetSection(id)
+	section, err := c.club.GetSection(id)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -144,14 +151,14 @@ func GetSection(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, section)
 }
 
-func UpdateSection(w http.ResponseWriter, r *http.Request) {
+func (c *Club) UpdateSection(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	var section dto.Section
 	if err := json.NewDecoder(r.Body).Decode(&section); err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	err := services.UpdateSection(section)
+	err := c.club.UpdateSection(section)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -159,10 +166,10 @@ func UpdateSection(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func DeleteSection(w http.ResponseWriter, r *http.Request) {
+func (c *Club) DeleteSection(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	id := r.FormValue("id")
-	err := services.DeleteSection(id)
+	err := c.club.DeleteSection(id)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -170,9 +177,9 @@ func DeleteSection(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func GetAllSections(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetAllSections(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
-	sections, err := services.GetAllSections()
+	sections, err := c.club.GetAllSections()
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -180,13 +187,40 @@ func GetAllSections(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, sections)
 }
 
-func GetGroupsFromSections(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetGroupsFromSections(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	id := r.FormValue("id")
-	groups, err := services.GetGroupFromSection(id)
+	groups, err := c.club.GetGroupFromSection(id)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	utils.RespondWithJSON(w, http.StatusOK, groups)
 }
+
+func (c *Club) SuggestGroup(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	person := r.FormValue("person")
+	section := r.FormValue("section")
+	date := r.FormValue("date")
+
+	data, err := c.club.SuggestGroup(person, section, date)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func (c *Club) GetGroupRebalanceReport(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	section := r.FormValue("section")
+	date := r.FormValue("date")
+
+	data, err := c.club.GetGroupRebalanceReport(section, date)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"db_backend/blobstore"
+	"db_backend/dto"
+	"db_backend/services"
+	"db_backend/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+func healthErrorStatus(err error) int {
+	if errors.Is(err, services.ErrUnauthorized) {
+		return http.StatusUnauthorized
+	}
+	if errors.Is(err, services.ErrForbidden) {
+		return http.StatusForbidden
+	}
+	if errors.Is(err, blobstore.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+func GetHealthRecord(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	person := r.FormValue("person")
+
+	data, err := services.GetHealthRecord(viewer, person)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func SetHealthRecord(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	var req dto.HealthRecord
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err := services.SetHealthRecord(viewer, req)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func DeleteHealthRecord(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	person := r.FormValue("person")
+
+	err := services.DeleteHealthRecord(viewer, person)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func GetClearanceAlerts(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	days := r.FormValue("days")
+
+	data, err := services.GetClearanceAlerts(viewer, days)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func AddEmergencyContact(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	var req dto.EmergencyContact
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := services.AddEmergencyContact(viewer, req)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
+}
+
+func DeleteEmergencyContact(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	person := r.FormValue("person")
+	id := r.FormValue("id")
+
+	err := services.DeleteEmergencyContact(viewer, person, id)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func GetEmergencyContacts(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	person := r.FormValue("person")
+
+	data, err := services.GetEmergencyContacts(viewer, person)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetTourRoster(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	tour := mux.Vars(r)["id"]
+	format := r.FormValue("format")
+
+	data, contentType, err := services.GetTourRoster(viewer, tour, format)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	extension := "pdf"
+	if format == "csv" {
+		extension = "csv"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"tour-%s-roster.%s\"", tour, extension))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
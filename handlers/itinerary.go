@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"db_backend/dto"
+	"db_backend/services"
+	"db_backend/utils"
+	"encoding/json"
+	"net/http"
+)
+
+func GetTourItinerary(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+
+	data, err := services.GetTourItinerary(tour)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func SetTourItinerary(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req dto.ItineraryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	data, err := services.SetTourItinerary(req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func ValidateTourItinerary(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req dto.ItineraryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	data, err := services.ValidateTourItinerary(req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetGroupPositions(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	date := r.FormValue("date")
+	tour := r.FormValue("tour")
+
+	data, err := services.GetGroupPositions(date, tour)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func SetRoutePlaceOrder(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	var req dto.RoutePlaceOrder
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err := services.SetRoutePlaceOrder(viewer, req)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"result": "success"})
+}
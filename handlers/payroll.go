@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"db_backend/dto"
+	"db_backend/services"
+	"db_backend/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+func CreatePayrollRate(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var req dto.PayrollRate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := services.CreatePayrollRate(req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
+}
+
+func DeletePayrollRate(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	id := r.FormValue("id")
+	err := services.DeletePayrollRate(id)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+func GetPayrollRates(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	trainer := r.FormValue("trainer")
+
+	data, err := services.GetPayrollRates(trainer)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetPayrollReport(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	section := r.FormValue("section")
+	month := r.FormValue("month")
+
+	data, err := services.GetPayrollReport(section, month)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func ClosePayPeriod(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	section := r.FormValue("section")
+	month := r.FormValue("month")
+
+	id, err := services.ClosePayPeriod(section, month)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
+}
+
+func GetPayPeriods(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	section := r.FormValue("section")
+
+	data, err := services.GetPayPeriods(section)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
@@ -2,14 +2,17 @@ package handlers
 
 import (
 	"db_backend/dto"
+	"db_backend/export"
 	"db_backend/services"
 	"db_backend/utils"
 	"encoding/json"
+	"io"
 	"log"
 	"net/http"
+	"strings"
 )
 
-func CreatePerson(w http.ResponseWriter, r *http.Request) {
+func (c *Club) CreatePerson(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	var req dto.PersonCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -18,7 +21,7 @@ func CreatePerson(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := services.CreatePerson(req)
+	err := c.club.CreatePerson(req)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
@@ -26,12 +29,12 @@ func CreatePerson(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func GetPersonRole(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetPersonRole(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	person := r.FormValue("person")
 	section := r.FormValue("section")
 
-	role, err := services.GetPersonRole(person, section)
+	role, err := c.club.GetPersonRole(person, section)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -40,12 +43,12 @@ func GetPersonRole(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, role)
 }
 
-func SetPersonRole(w http.ResponseWriter, r *http.Request) {
+func (c *Club) SetPersonRole(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	person := r.FormValue("person")
 	section := r.FormValue("section")
 	role := r.FormValue("role")
-	err := services.SetPersonRole(person, section, role)
+	err := c.club.SetPersonRole(person, section, role)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -53,12 +56,12 @@ func SetPersonRole(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func DeletePersonRole(w http.ResponseWriter, r *http.Request) {
+func (c *Club) DeletePersonRole(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	person := r.FormValue("person")
 	section := r.FormValue("section")
 
-	err := services.DeletePersonRole(person, section)
+	err := c.club.DeletePersonRole(person, section)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -66,14 +69,14 @@ func DeletePersonRole(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func CreatePersonAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) CreatePersonAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	var req dto.PersonAttribute
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Println("Error decoding person create request:", err)
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 	}
-	err := services.CreatePersonAttribute(req)
+	err := c.club.CreatePersonAttribute(req)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -81,11 +84,11 @@ func CreatePersonAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func GetPersonAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetPersonAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	id := r.FormValue("id")
 
-	attr, err := services.GetPersonAttribute(id)
+	attr, err := c.club.GetPersonAttribute(id)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -93,14 +96,14 @@ func GetPersonAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, attr)
 }
 
-func SetPersonAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) SetPersonAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	var req dto.PersonAttribute
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Println("Error decoding person create request:", err)
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 	}
-	err := services.SetPersonAttribute(req)
+	err := c.club.SetPersonAttribute(req)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -108,10 +111,10 @@ func SetPersonAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func DeletePersonAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) DeletePersonAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	attr := r.FormValue("id")
-	err := services.DeletePersonAttribute(attr)
+	err := c.club.DeletePersonAttribute(attr)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -119,22 +122,29 @@ func DeletePersonAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func FindTourists(w http.ResponseWriter, r *http.Request) {
+func (c *Club) FindTourists(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	section := r.FormValue("section")
 	group := r.FormValue("group")
 	sex := r.FormValue("sex")
 	birthYear := r.FormValue("birth_year")
 	age := r.FormValue("age")
-	data, err := services.GetTouristsWithCondition(section, group, sex, birthYear, age)
+	fitnessTest := r.FormValue("fitness_test")
+	fitnessValue := r.FormValue("fitness_value")
+	fitnessMonths := r.FormValue("fitness_months")
+	data, err := c.club.GetTouristsWithCondition(section, group, sex, birthYear, age, fitnessTest, fitnessValue, fitnessMonths)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if format := exportFormat(r); format != export.FormatJSON {
+		respondWithTable(w, r, format, "tourists", services.PersonsTable("tourists", data))
+		return
+	}
 	utils.RespondWithJSON(w, http.StatusOK, data)
 }
 
-func FindTrainers(w http.ResponseWriter, r *http.Request) {
+func (c *Club) FindTrainers(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	section := r.FormValue("section")
 	sex := r.FormValue("sex")
@@ -142,15 +152,19 @@ func FindTrainers(w http.ResponseWriter, r *http.Request) {
 	salary := r.FormValue("salary")
 	specialization := r.FormValue("specialization")
 
-	data, err := services.GetTrainersWithCondition(section, sex, age, salary, specialization)
+	data, err := c.club.GetTrainersWithCondition(section, sex, age, salary, specialization)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if format := exportFormat(r); format != export.FormatJSON {
+		respondWithTable(w, r, format, "trainers", services.PersonsTable("trainers", data))
+		return
+	}
 	utils.RespondWithJSON(w, http.StatusOK, data)
 }
 
-func FindManagers(w http.ResponseWriter, r *http.Request) {
+func (c *Club) FindManagers(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	birthYear := r.FormValue("birth_year")
 	beginYear := r.FormValue("begin_year")
@@ -158,20 +172,24 @@ func FindManagers(w http.ResponseWriter, r *http.Request) {
 	salary := r.FormValue("salary")
 	sex := r.FormValue("sex")
 
-	data, err := services.GetManagersWithCondition(salary, birthYear, age, beginYear, sex)
+	data, err := c.club.GetManagersWithCondition(salary, birthYear, age, beginYear, sex)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if format := exportFormat(r); format != export.FormatJSON {
+		respondWithTable(w, r, format, "managers", services.PersonsTable("managers", data))
+		return
+	}
 	utils.RespondWithJSON(w, http.StatusOK, data)
 }
 
-func FindTrainersByWorkouts(w http.ResponseWriter, r *http.Request) {
+func (c *Club) FindTrainersByWorkouts(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	group := r.FormValue("group")
 	from := r.FormValue("from_date")
 	to := r.FormValue("to_date")
-	data, err := services.GetTrainersByWorkout(group, from, to)
+	data, err := c.club.GetTrainersByWorkout(group, from, to)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
@@ -179,9 +197,9 @@ func FindTrainersByWorkouts(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, data)
 }
 
-func GetAllRoles(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetAllRoles(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
-	roles, err := services.GetAllRoles()
+	roles, err := c.club.GetAllRoles()
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -189,9 +207,9 @@ func GetAllRoles(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, roles)
 }
 
-func GetAllPersonAttributes(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetAllPersonAttributes(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
-	attributes, err := services.GetAllAttributes()
+	attributes, err := c.club.GetAllAttributes()
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -199,12 +217,12 @@ func GetAllPersonAttributes(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, attributes)
 }
 
-func GetPersonIntAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetPersonIntAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	person := r.FormValue("person")
 	attribute := r.FormValue("attribute")
 
-	val, err := services.GetPersonIntAttribute(person, attribute)
+	val, err := c.club.GetPersonIntAttribute(person, attribute)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -212,12 +230,12 @@ func GetPersonIntAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, val)
 }
 
-func GetPersonFloatAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetPersonFloatAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	person := r.FormValue("person")
 	attribute := r.FormValue("attribute")
 
-	val, err := services.GetPersonFloatAttribute(person, attribute)
+	val, err := c.club.GetPersonFloatAttribute(person, attribute)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -225,12 +243,12 @@ func GetPersonFloatAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, val)
 }
 
-func GetPersonStringAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetPersonStringAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	person := r.FormValue("person")
 	attribute := r.FormValue("attribute")
 
-	val, err := services.GetPersonStringAttribute(person, attribute)
+	val, err := c.club.GetPersonStringAttribute(person, attribute)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -238,12 +256,12 @@ func GetPersonStringAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, val)
 }
 
-func GetPersonDateAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetPersonDateAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	person := r.FormValue("person")
 	attribute := r.FormValue("attribute")
 
-	val, err := services.GetPersonDateAttribute(person, attribute)
+	val, err := c.club.GetPersonDateAttribute(person, attribute)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -251,14 +269,14 @@ func GetPersonDateAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, val)
 }
 
-func SetPersonIntAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) SetPersonIntAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	var attr dto.PersonIntAttribute
 	if err := json.NewDecoder(r.Body).Decode(&attr); err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	err := services.SetPersonIntAttribute(attr)
+	err := c.club.SetPersonIntAttribute(attr)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -266,14 +284,14 @@ func SetPersonIntAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func SetPersonFloatAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) SetPersonFloatAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	var attr dto.PersonFloatAttribute
 	if err := json.NewDecoder(r.Body).Decode(&attr); err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	err := services.SetPersonFloatAttribute(attr)
+	err := c.club.SetPersonFloatAttribute(attr)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -281,14 +299,14 @@ func SetPersonFloatAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func SetPersonStringAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) SetPersonStringAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	var attr dto.PersonStringAttribute
 	if err := json.NewDecoder(r.Body).Decode(&attr); err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	err := services.SetPersonStringAttribute(attr)
+	err := c.club.SetPersonStringAttribute(attr)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -296,14 +314,14 @@ func SetPersonStringAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func SetPersonDateAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) SetPersonDateAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	var attr dto.PersonDateAttribute
 	if err := json.NewDecoder(r.Body).Decode(&attr); err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	err := services.SetPersonDateAttribute(attr)
+	err := c.club.SetPersonDateAttribute(attr)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -311,12 +329,12 @@ func SetPersonDateAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func DeletePersonIntAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) DeletePersonIntAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	person := r.FormValue("person")
 	attribute := r.FormValue("attribute")
 
-	err := services.DeletePersonIntAttribute(person, attribute)
+	err := c.club.DeletePersonIntAttribute(person, attribute)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -324,12 +342,12 @@ func DeletePersonIntAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func DeletePersonFloatAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) DeletePersonFloatAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	person := r.FormValue("person")
 	attribute := r.FormValue("attribute")
 
-	err := services.DeletePersonFloatAttribute(person, attribute)
+	err := c.club.DeletePersonFloatAttribute(person, attribute)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -337,12 +355,12 @@ func DeletePersonFloatAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func DeletePersonStringAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) DeletePersonStringAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	person := r.FormValue("person")
 	attribute := r.FormValue("attribute")
 
-	err := services.DeletePersonStringAttribute(person, attribute)
+	err := c.club.DeletePersonStringAttribute(person, attribute)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -350,15 +368,51 @@ func DeletePersonStringAttribute(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
-func DeletePersonDateAttribute(w http.ResponseWriter, r *http.Request) {
+func (c *Club) DeletePersonDateAttribute(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	person := r.FormValue("person")
 	attribute := r.FormValue("attribute")
 
-	err := services.DeletePersonDateAttribute(person, attribute)
+	err := c.club.DeletePersonDateAttribute(person, attribute)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
+
+// ImportPersons takes a CSV or XLSX spreadsheet either as the "file" field of
+// a multipart form or as the request body.
+func ImportPersons(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	query := r.URL.Query()
+	dryRun := query.Has("dry_run") && query.Get("dry_run") != "false" && query.Get("dry_run") != "0"
+	r.Body = http.MaxBytesReader(w, r.Body, services.MaxImportSize)
+
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := services.ImportPersons(data, dryRun)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(report.Errors) > 0 && !report.DryRun {
+		utils.RespondWithJSON(w, http.StatusUnprocessableEntity, report)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"db_backend/dto"
+	"db_backend/services"
+	"db_backend/utils"
+	"encoding/json"
+	"net/http"
+)
+
+func (c *Club) PlanTourGroups(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	var req dto.PlannerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	data, err := c.club.PlanTourGroups(viewer, req)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func GetPlanDraft(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	draft := r.FormValue("draft")
+
+	data, err := services.GetPlanDraft(draft)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func (c *Club) AcceptPlanDraft(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	draft := r.FormValue("draft")
+
+	data, err := c.club.AcceptPlanDraft(draft)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func DiscardPlanDraft(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	draft := r.FormValue("draft")
+
+	err := services.DiscardPlanDraft(draft)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"result": "success"})
+}
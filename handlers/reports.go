@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"db_backend/dto"
+	"db_backend/services"
+	"db_backend/utils"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+func GetTourReport(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	tour := r.FormValue("tour")
+
+	data, err := services.GetTourReport(viewer, tour)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func SaveTourReport(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	var req dto.TourReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	id, err := services.SaveTourReport(viewer, req)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
+}
+
+func DeleteTourReport(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	tour := r.FormValue("tour")
+
+	err := services.DeleteTourReport(viewer, tour)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"result": "success"})
+}
+
+func AddReportAttachment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	r.Body = http.MaxBytesReader(w, r.Body, services.MaxAttachmentSize+1<<20)
+	if err := r.ParseMultipartForm(8 << 20); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tour := r.FormValue("tour")
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer file.Close()
+
+	id, err := services.AddReportAttachment(viewer, tour, header.Filename, file)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"id": strconv.Itoa(id)})
+}
+
+func DeleteReportAttachment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	id := r.FormValue("id")
+
+	err := services.DeleteReportAttachment(viewer, id)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"result": "success"})
+}
+
+func serveReportAttachment(w http.ResponseWriter, r *http.Request, thumbnail bool) {
+	defer r.Body.Close()
+	viewer := requestViewer(r)
+	id := mux.Vars(r)["id"]
+
+	content, attachment, err := services.OpenReportAttachment(viewer, id, thumbnail)
+	if err != nil {
+		utils.RespondWithError(w, healthErrorStatus(err), err.Error())
+		return
+	}
+	defer content.Close()
+
+	contentType, disposition := attachment.ContentType, "inline"
+	if !services.InlineImageTypes[contentType] {
+		contentType, disposition = "application/octet-stream", "attachment"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, content)
+}
+
+func GetReportAttachment(w http.ResponseWriter, r *http.Request) {
+	serveReportAttachment(w, r, false)
+}
+
+func GetReportAttachmentThumbnail(w http.ResponseWriter, r *http.Request) {
+	serveReportAttachment(w, r, true)
+}
+
+func GetRouteReports(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	route := mux.Vars(r)["id"]
+
+	data, err := services.GetRouteReports(route)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"db_backend/services"
+	"db_backend/utils"
+	"net/http"
+)
+
+func GetStatsOverview(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	section := r.FormValue("section")
+	dateFrom := r.FormValue("date_from")
+	dateTo := r.FormValue("date_to")
+
+	data, err := services.GetStatsOverview(section, dateFrom, dateTo)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func RefreshStats(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	err := services.RefreshStats()
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"result": "success"})
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"db_backend/services"
+	"db_backend/utils"
+	"net/http"
+)
+
+func GetTour(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	id := r.FormValue("id")
+
+	data, err := services.GetTour(id)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func ChangeTourStatus(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+	status := r.FormValue("status")
+	date := r.FormValue("date")
+
+	data, err := services.ChangeTourStatus(tour, status, date)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func SetParticipantOutcome(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	tour := r.FormValue("tour")
+	person := r.FormValue("person")
+	outcome := r.FormValue("outcome")
+	date := r.FormValue("date")
+
+	data, err := services.SetParticipantOutcome(tour, person, outcome, date)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
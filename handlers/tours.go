@@ -2,13 +2,14 @@ package handlers
 
 import (
 	"db_backend/dto"
+	"db_backend/export"
 	"db_backend/services"
 	"db_backend/utils"
 	"encoding/json"
 	"net/http"
 )
 
-func FindTouristsByTour(w http.ResponseWriter, r *http.Request) {
+func (c *Club) FindTouristsByTour(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	section := r.FormValue("section")
 	group := r.FormValue("group")
@@ -18,7 +19,7 @@ func FindTouristsByTour(w http.ResponseWriter, r *http.Request) {
 	routeId := r.FormValue("route_id")
 	placeId := r.FormValue("place_id")
 
-	data, err := services.GetTouristsByTour(section, group, cntTours, tourId, tourTime, routeId, placeId)
+	data, err := c.club.GetTouristsByTour(section, group, cntTours, tourId, tourTime, routeId, placeId)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
@@ -26,29 +27,39 @@ func FindTouristsByTour(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, data)
 }
 
-func FindRoutes(w http.ResponseWriter, r *http.Request) {
+func (c *Club) FindRoutes(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	section := r.FormValue("section")
 	dateFrom := r.FormValue("date_from")
 	dateTo := r.FormValue("date_to")
 	instructor := r.FormValue("instructor")
 	groupCnt := r.FormValue("group_cnt")
+	sortBy := r.FormValue("sort")
 
-	data, err := services.GetRoutesWithConditions(section, dateFrom, dateTo, instructor, groupCnt)
+	data, err := c.club.GetRoutesWithConditions(section, dateFrom, dateTo, instructor, groupCnt, sortBy)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if format := exportFormat(r); format != export.FormatJSON {
+		table, err := services.RoutesTable(data)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		respondWithTable(w, r, format, "routes", *table)
+		return
+	}
 	utils.RespondWithJSON(w, http.StatusOK, data)
 }
 
-func FindRoutesWithGeo(w http.ResponseWriter, r *http.Request) {
+func (c *Club) FindRoutesWithGeo(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	place := r.FormValue("place")
 	length := r.FormValue("length")
 	difficulty := r.FormValue("difficulty")
 
-	data, err := services.GetRoutesWithGeoCond(place, length, difficulty)
+	data, err := c.club.GetRoutesWithGeoCond(place, length, difficulty)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
@@ -56,7 +67,7 @@ func FindRoutesWithGeo(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, data)
 }
 
-func FindInstructors(w http.ResponseWriter, r *http.Request) {
+func (c *Club) FindInstructors(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	role := r.FormValue("role")
 	routeType := r.FormValue("type")
@@ -65,20 +76,24 @@ func FindInstructors(w http.ResponseWriter, r *http.Request) {
 	tourId := r.FormValue("tour_id")
 	placeId := r.FormValue("place_id")
 
-	data, err := services.GetInstructorsWithCondition(role, routeType, routeDifficulty, cntTours, tourId, placeId)
+	data, err := c.club.GetInstructorsWithCondition(role, routeType, routeDifficulty, cntTours, tourId, placeId)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if format := exportFormat(r); format != export.FormatJSON {
+		respondWithTable(w, r, format, "instructors", services.PersonsTable("instructors", data))
+		return
+	}
 	utils.RespondWithJSON(w, http.StatusOK, data)
 }
 
-func FindTouristsWithTrainerInstructor(w http.ResponseWriter, r *http.Request) {
+func (c *Club) FindTouristsWithTrainerInstructor(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	section := r.FormValue("section")
 	group := r.FormValue("group")
 
-	data, err := services.GetTouristsWithTrainerInstructor(section, group)
+	data, err := c.club.GetTouristsWithTrainerInstructor(section, group)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
@@ -86,12 +101,12 @@ func FindTouristsWithTrainerInstructor(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, data)
 }
 
-func FindTouristsCompletedAll(w http.ResponseWriter, r *http.Request) {
+func (c *Club) FindTouristsCompletedAll(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	section := r.FormValue("section")
 	group := r.FormValue("group")
 
-	data, err := services.GetTouristsCompletedALl(section, group)
+	data, err := c.club.GetTouristsCompletedALl(section, group)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
@@ -99,7 +114,7 @@ func FindTouristsCompletedAll(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, data)
 }
 
-func FindTouristsCompletedRoutes(w http.ResponseWriter, r *http.Request) {
+func (c *Club) FindTouristsCompletedRoutes(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	section := r.FormValue("section")
 	group := r.FormValue("group")
@@ -111,7 +126,7 @@ func FindTouristsCompletedRoutes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data, err := services.GetTouristsCompletedRoutes(section, group, requestBody)
+	data, err := c.club.GetTouristsCompletedRoutes(section, group, requestBody)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
@@ -119,10 +134,10 @@ func FindTouristsCompletedRoutes(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, data)
 }
 
-func GetAllRouteTypes(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetAllRouteTypes(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
-	data, err := services.GetAllRouteTypes()
+	data, err := c.club.GetAllRouteTypes()
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -130,14 +145,14 @@ func GetAllRouteTypes(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, data)
 }
 
-func GetTouristsByTour(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetTouristsByTour(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	routeType := r.FormValue("type_id")
 
 	difficulty := r.FormValue("difficulty")
 
-	data, err := services.GetSuitablePersonsByRoute(routeType, difficulty)
+	data, err := c.club.GetSuitablePersonsByRoute(routeType, difficulty)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
@@ -1,18 +1,48 @@
 package handlers
 
 import (
+	"db_backend/export"
 	"db_backend/services"
 	"db_backend/utils"
 	"net/http"
 )
 
-func GetStrain(w http.ResponseWriter, r *http.Request) {
+func (c *Club) GetStrain(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	trainer := r.FormValue("trainer")
 	fromDate := r.FormValue("from_date")
 	toDate := r.FormValue("to_date")
+	format := r.FormValue("format")
+	output := exportFormat(r)
 
-	data, err := services.GetStrainForTrainer(trainer, fromDate, toDate)
+	data, err := c.club.GetStrainForTrainer(trainer, fromDate, toDate, format)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if output != export.FormatJSON {
+		table, err := services.StrainTable(trainer, fromDate, toDate, data)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		respondWithTable(w, r, output, "strain", *table)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, data)
+}
+
+func (c *Club) GetStrainReport(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	groupBy := r.FormValue("group_by")
+	bucket := r.FormValue("bucket")
+	fromDate := r.FormValue("from_date")
+	toDate := r.FormValue("to_date")
+	format := r.FormValue("format")
+	section := r.FormValue("section")
+	trainer := r.FormValue("trainer")
+
+	data, err := c.club.GetStrainReport(groupBy, bucket, fromDate, toDate, format, section, trainer)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
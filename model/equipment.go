@@ -0,0 +1,50 @@
+package model
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+// Equipment conditions from best to worst; written_off is only used in check-in
+// reports and removes the checked-in units from the inventory.
+var EquipmentConditions = []string{"new", "good", "worn", "damaged"}
+
+const EquipmentWrittenOff = "written_off"
+
+type Equipment struct {
+	Id        int32
+	Title     string
+	Category  string
+	Quantity  int32
+	Condition string
+	Section   pgtype.Int4
+	Notes     string
+}
+
+type EquipmentCheckout struct {
+	Id           int32
+	Equipment    int32
+	Quantity     int32
+	Tour         pgtype.Int4
+	Person       pgtype.Int4
+	CheckedOutOn pgtype.Date
+	DueOn        pgtype.Date
+	CheckedInOn  pgtype.Date
+	ConditionIn  pgtype.Text
+	Report       pgtype.Text
+}
+
+type EquipmentAvailability struct {
+	Equipment Equipment
+	Allocated int32
+}
+
+func (a *EquipmentAvailability) Available() int32 {
+	return a.Equipment.Quantity - a.Allocated
+}
+
+func EquipmentConditionRank(condition string) int {
+	for i, c := range EquipmentConditions {
+		if c == condition {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,40 @@
+package model
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+var ExpenseCategories = []string{"transport", "food", "permits", "gear_rental", "other"}
+
+// Money amounts are kept in kopecks to split them without rounding drift.
+type TourExpense struct {
+	Id          int32
+	Tour        int32
+	Payer       int32
+	Category    string
+	Amount      int64
+	Description string
+	SpentOn     pgtype.Date
+}
+
+type ExpenseShare struct {
+	Expense int32
+	Person  int32
+	Weight  float64
+	Amount  int64
+}
+
+type PersonBalance struct {
+	Person Person
+	Paid   int64
+	Owed   int64
+}
+
+func (b *PersonBalance) Net() int64 {
+	return b.Paid - b.Owed
+}
+
+type SectionExpenseSummary struct {
+	Section  Section
+	Year     int32
+	Category string
+	Amount   int64
+}
@@ -0,0 +1,31 @@
+package model
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type MembershipFee struct {
+	Id      int32
+	Section int32
+	Role    int32
+	Season  string
+	Amount  int64
+	DueOn   pgtype.Date
+}
+
+type MembershipPayment struct {
+	Id     int32
+	Person int32
+	Fee    int32
+	Amount int64
+	PaidOn pgtype.Date
+	Note   string
+}
+
+type Arrear struct {
+	Person Person
+	Fee    MembershipFee
+	Paid   int64
+}
+
+func (a *Arrear) Outstanding() int64 {
+	return a.Fee.Amount - a.Paid
+}
@@ -0,0 +1,33 @@
+package model
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type FitnessTest struct {
+	Id             int32
+	Title          string
+	Unit           string
+	HigherIsBetter bool
+}
+
+// Better reports whether a beats b for this test.
+func (t *FitnessTest) Better(a float64, b float64) bool {
+	if t.HigherIsBetter {
+		return a > b
+	}
+	return a < b
+}
+
+type FitnessResult struct {
+	Id      int32
+	Test    int32
+	Person  int32
+	Value   float64
+	TakenOn pgtype.Date
+	Note    string
+}
+
+type FitnessRanking struct {
+	Person  Person
+	Value   float64
+	TakenOn pgtype.Date
+}
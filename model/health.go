@@ -0,0 +1,52 @@
+package model
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+var BloodTypes = map[string]bool{
+	"O+": true, "O-": true, "A+": true, "A-": true,
+	"B+": true, "B-": true, "AB+": true, "AB-": true,
+}
+
+// HealthRecord holds decrypted values; only ClearanceUntil is stored in the clear.
+type HealthRecord struct {
+	Person         int32
+	ClearanceUntil pgtype.Date
+	BloodType      string
+	Allergies      string
+	MedicalNotes   string
+	UpdatedAt      pgtype.Timestamp
+	UpdatedBy      pgtype.Int4
+}
+
+type ClearanceAlert struct {
+	Person         Person
+	Tour           int32
+	Instructor     int32
+	Start          pgtype.Date
+	ClearanceUntil pgtype.Date
+}
+
+type EmergencyContact struct {
+	Id       int32
+	Person   int32
+	Name     string
+	Phone    string
+	Relation string
+	Priority int32
+}
+
+type RosterEntry struct {
+	Person   Person
+	Health   *HealthRecord
+	Contacts []EmergencyContact
+}
+
+type Roster struct {
+	Tour         Tour
+	RouteType    string
+	Difficulty   string
+	LengthKm     string
+	Instructor   Person
+	Places       []string
+	Participants []RosterEntry
+}
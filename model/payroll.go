@@ -0,0 +1,33 @@
+package model
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type PayrollRate struct {
+	Id          int32
+	Trainer     pgtype.Int4
+	WorkoutType pgtype.Text
+	HourlyRate  int64
+}
+
+type PayrollLine struct {
+	Trainer     Person
+	WorkoutType string
+	Minutes     int32
+	Rate        pgtype.Int8
+}
+
+// Amount is the pay for the delivered minutes in kopecks, zero when no rate applies.
+func (l *PayrollLine) Amount() int64 {
+	if !l.Rate.Valid {
+		return 0
+	}
+	return (l.Rate.Int64*int64(l.Minutes) + 30) / 60
+}
+
+type PayPeriod struct {
+	Id       int32
+	Section  int32
+	Month    pgtype.Date
+	ClosedAt pgtype.Timestamp
+	Total    int64
+}
@@ -1,6 +1,10 @@
 package model
 
-import "github.com/jackc/pgx/v5/pgtype"
+import (
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"time"
+)
 
 type Person struct {
 	Id         int32
@@ -16,6 +20,15 @@ type Attribute struct {
 	Role pgtype.Int4
 }
 
+// Values of attributes.attr_type, telling which persons_attrs_* table holds
+// the values of an attribute.
+const (
+	AttrTypeInt    = 1
+	AttrTypeFloat  = 2
+	AttrTypeString = 3
+	AttrTypeDate   = 4
+)
+
 type PersonStringAttribute struct {
 	AttributeId int
 	PersonId    int
@@ -41,9 +54,58 @@ type PersonDateAttribute struct {
 }
 
 type Group struct {
-	Id          int32
-	GroupNumber int32
-	Section     int32
+	Id               int32
+	GroupNumber      int32
+	Section          int32
+	MinAge           pgtype.Int4
+	MaxAge           pgtype.Int4
+	Sex              pgtype.Int4
+	MinQualification pgtype.Int4
+}
+
+// GroupCandidate is what the group criteria are checked against: the birth
+// date and sex attributes of a person and the highest difficulty they completed.
+type GroupCandidate struct {
+	Person        Person
+	BirthDate     pgtype.Date
+	Sex           pgtype.Int4
+	Qualification int32
+}
+
+// AgeOn returns the age in full years on the given day.
+func (c *GroupCandidate) AgeOn(day time.Time) int {
+	birth := c.BirthDate.Time
+	age := day.Year() - birth.Year()
+	if day.Month() < birth.Month() || day.Month() == birth.Month() && day.Day() < birth.Day() {
+		age--
+	}
+	return age
+}
+
+// Misfits lists why the candidate does not meet the criteria of the group on
+// the given day; an empty list means they fit.
+func (g *Group) Misfits(c GroupCandidate, day time.Time) []string {
+	reasons := []string{}
+	if g.MinAge.Valid || g.MaxAge.Valid {
+		if !c.BirthDate.Valid {
+			reasons = append(reasons, "birth date is unknown")
+		} else {
+			age := c.AgeOn(day)
+			if g.MinAge.Valid && age < int(g.MinAge.Int32) {
+				reasons = append(reasons, fmt.Sprintf("age %d is below %d", age, g.MinAge.Int32))
+			}
+			if g.MaxAge.Valid && age > int(g.MaxAge.Int32) {
+				reasons = append(reasons, fmt.Sprintf("age %d is above %d", age, g.MaxAge.Int32))
+			}
+		}
+	}
+	if g.Sex.Valid && (!c.Sex.Valid || c.Sex.Int32 != g.Sex.Int32) {
+		reasons = append(reasons, "sex does not match")
+	}
+	if g.MinQualification.Valid && c.Qualification < g.MinQualification.Int32 {
+		reasons = append(reasons, fmt.Sprintf("qualification %d is below %d", c.Qualification, g.MinQualification.Int32))
+	}
+	return reasons
 }
 
 type Section struct {
@@ -0,0 +1,40 @@
+package model
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+const (
+	CycleMeso  = "meso"
+	CycleMicro = "micro"
+)
+
+type TrainingPlan struct {
+	Id       int32
+	Group    int32
+	Title    string
+	StartsOn pgtype.Date
+	EndsOn   pgtype.Date
+}
+
+type TrainingCycle struct {
+	Id       int32
+	Plan     int32
+	Parent   pgtype.Int4
+	Kind     string
+	Title    string
+	StartsOn pgtype.Date
+	EndsOn   pgtype.Date
+}
+
+type CycleTarget struct {
+	Cycle       int32
+	WorkoutType string
+	Minutes     int32
+}
+
+// CycleVolume is the number of minutes of a workout type delivered within a cycle;
+// Cycle is zero for the plan as a whole.
+type CycleVolume struct {
+	Cycle       int32
+	WorkoutType string
+	Minutes     int32
+}
@@ -0,0 +1,45 @@
+package model
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+const (
+	DraftOpen      = "draft"
+	DraftAccepted  = "accepted"
+	DraftDiscarded = "discarded"
+)
+
+type PlanDraftTour struct {
+	Id              int32
+	Draft           int32
+	Route           int32
+	Start           pgtype.Date
+	DurationDays    int32
+	Instructor      pgtype.Int4
+	MinParticipants pgtype.Int4
+	MaxParticipants int32
+	Tour            pgtype.Int4
+	Members         []int32
+}
+
+type PlanUnassigned struct {
+	Person int32
+	Reason string
+}
+
+type PlanDraft struct {
+	Id              int32
+	Section         pgtype.Int4
+	CreatedBy       pgtype.Int4
+	CreatedAt       pgtype.Timestamp
+	InstructorRatio int32
+	Status          string
+	AcceptedAt      pgtype.Timestamp
+	Warnings        []string
+	Tours           []PlanDraftTour
+	Unassigned      []PlanUnassigned
+}
+
+type GroupMembership struct {
+	Group  int32
+	Person int32
+}
@@ -0,0 +1,34 @@
+package model
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+const (
+	AttachmentPhoto    = "photo"
+	AttachmentDocument = "document"
+)
+
+type TourReport struct {
+	Id        int32
+	Tour      int32
+	Author    int32
+	EditedBy  pgtype.Int4
+	Title     string
+	Body      string
+	GpsTrack  pgtype.Text
+	TrackKm   pgtype.Float8
+	Published bool
+	CreatedAt pgtype.Timestamp
+	UpdatedAt pgtype.Timestamp
+}
+
+type ReportAttachment struct {
+	Id          int32
+	Report      int32
+	Kind        string
+	Filename    string
+	ContentType string
+	Size        int64
+	BlobKey     string
+	ThumbKey    pgtype.Text
+	UploadedAt  pgtype.Timestamp
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+	"time"
+)
+
+type RouteReview struct {
+	Id                  int32
+	Route               int32
+	Person              Person
+	Tour                int32
+	Rating              int32
+	PerceivedDifficulty int32
+	Season              string
+	Conditions          string
+	Review              string
+	CreatedAt           pgtype.Timestamp
+	UpdatedAt           pgtype.Timestamp
+}
+
+// Season returns the season a date falls into.
+func Season(date time.Time) string {
+	switch date.Month() {
+	case time.December, time.January, time.February:
+		return "winter"
+	case time.March, time.April, time.May:
+		return "spring"
+	case time.June, time.July, time.August:
+		return "summer"
+	}
+	return "autumn"
+}
+
+type RouteRating struct {
+	Route              int32
+	Season             string
+	Reviews            int32
+	AvgRating          pgtype.Float8
+	AvgPerceived       pgtype.Float8
+	OfficialDifficulty pgtype.Int4
+}
+
+// DifficultyGap is how much harder than its official difficulty the route felt
+// to those who reviewed it.
+func (r *RouteRating) DifficultyGap() pgtype.Float8 {
+	if !r.AvgPerceived.Valid || !r.OfficialDifficulty.Valid {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: r.AvgPerceived.Float64 - float64(r.OfficialDifficulty.Int32), Valid: true}
+}
+
+// DifficultyFeel sums up DifficultyGap: "harder" or "easier" when reviewers
+// disagree with the official difficulty by half a category or more.
+func (r *RouteRating) DifficultyFeel() string {
+	gap := r.DifficultyGap()
+	switch {
+	case !gap.Valid:
+		return ""
+	case gap.Float64 >= 0.5:
+		return "harder"
+	case gap.Float64 <= -0.5:
+		return "easier"
+	}
+	return "as rated"
+}
@@ -0,0 +1,41 @@
+package model
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+// AllSections is the section id the statistics summaries use for club totals.
+const AllSections = 0
+
+type MemberCount struct {
+	Role    int32
+	Members int32
+}
+
+type NewMembers struct {
+	Month   pgtype.Date
+	Members int32
+}
+
+type TourStats struct {
+	Month        pgtype.Date
+	Tours        int32
+	Km           float64
+	Participants int32
+}
+
+type WorkoutStats struct {
+	Month    pgtype.Date
+	Workouts int32
+	Hours    float64
+}
+
+type ChampionshipStats struct {
+	Month         pgtype.Date
+	Championships int32
+	Participants  int32
+}
+
+type DemographicCount struct {
+	Sex      int32
+	AgeGroup string
+	Members  int32
+}
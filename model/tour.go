@@ -1,5 +1,7 @@
 package model
 
+import "github.com/jackc/pgx/v5/pgtype"
+
 type RouteId struct {
 	Id int32
 }
@@ -8,3 +10,133 @@ type RouteType struct {
 	Id   int
 	Type string
 }
+
+type Route struct {
+	Id         int32
+	TypeId     pgtype.Int4
+	Type       pgtype.Text
+	Difficulty pgtype.Int4
+	LengthKm   pgtype.Float8
+}
+
+const (
+	TourPlanned   = "planned"
+	TourActive    = "active"
+	TourCompleted = "completed"
+	TourCancelled = "cancelled"
+)
+
+// TourTransitions lists the statuses a tour may move to from each status.
+var TourTransitions = map[string][]string{
+	TourPlanned: {TourActive, TourCancelled},
+	TourActive:  {TourCompleted, TourCancelled},
+}
+
+const (
+	OutcomeCompleted = "completed"
+	OutcomeWithdrew  = "withdrew"
+	OutcomeEvacuated = "evacuated"
+)
+
+const (
+	EnrollmentEnrolled   = "enrolled"
+	EnrollmentWaitlisted = "waitlisted"
+	EnrollmentCancelled  = "cancelled"
+)
+
+type Tour struct {
+	Id               int32
+	Route            int32
+	Instructor       int32
+	Start            pgtype.Date
+	DurationDays     int32
+	MinParticipants  pgtype.Int4
+	MaxParticipants  pgtype.Int4
+	EnrollmentClosed bool
+	AtRisk           bool
+	Status           string
+	StartedOn        pgtype.Date
+	FinishedOn       pgtype.Date
+}
+
+func (t *Tour) GetStartAsString() string {
+	return t.Start.Time.Format("2006-01-02")
+}
+
+// GetEndAsString returns the last day of the tour.
+func (t *Tour) GetEndAsString() string {
+	return t.Start.Time.AddDate(0, 0, int(t.DurationDays)-1).Format("2006-01-02")
+}
+
+func (t *Tour) CanMoveTo(status string) bool {
+	for _, next := range TourTransitions[t.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+type TourParticipant struct {
+	Person      Person
+	Status      string
+	RequestedAt pgtype.Timestamp
+	Outcome     pgtype.Text
+	OutcomeDate pgtype.Date
+}
+
+const (
+	CheckinDeparture = "departure"
+	CheckinRoute     = "checkin"
+	CheckinReturn    = "return"
+)
+
+type TourCheckin struct {
+	Id         int32
+	Tour       int32
+	Kind       string
+	ReportedAt pgtype.Timestamp
+	ReportedBy pgtype.Int4
+	Location   string
+	Note       string
+}
+
+type RouteTypeLevel struct {
+	Person        int32
+	Type          int32
+	Title         string
+	MaxDifficulty int32
+	Completed     int32
+}
+
+type OverdueTour struct {
+	Tour        Tour
+	Due         pgtype.Timestamp
+	LastCheckin pgtype.Timestamp
+	Location    pgtype.Text
+}
+
+type Place struct {
+	Id    int32
+	Title string
+}
+
+type StageCheckpoint struct {
+	Id          int32
+	Stage       int32
+	Position    int32
+	Place       pgtype.Int4
+	Title       string
+	PlannedTime pgtype.Time
+}
+
+type TourStage struct {
+	Id          int32
+	Tour        int32
+	Day         int32
+	StartPlace  Place
+	EndPlace    Place
+	DistanceKm  float64
+	Camp        string
+	Checkpoints []StageCheckpoint
+}
@@ -7,17 +7,116 @@ import (
 
 type Strain struct {
 	Type     string
-	Duration pgtype.Time
+	Duration pgtype.Interval
 }
 
-func (c *Strain) GetTimeAsString() string {
-	us := c.Duration.Microseconds
+type StrainBucket struct {
+	Key      int32
+	Label    string
+	Bucket   pgtype.Date
+	Duration pgtype.Interval
+}
+
+// IntervalMicroseconds flattens an interval, counting a month as 30 days as
+// postgres does when justifying intervals.
+func IntervalMicroseconds(interval pgtype.Interval) int64 {
+	return interval.Microseconds + (int64(interval.Days)+int64(interval.Months)*30)*24*3600*1_000_000
+}
+
+func FormatHMS(us int64) string {
+	seconds := us / 1_000_000
+	hours := seconds / 3600
+	seconds %= 3600
+	minutes := seconds / 60
+	seconds %= 60
+
+	return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+}
 
+func FormatISO8601(us int64) string {
 	seconds := us / 1_000_000
+	if seconds == 0 {
+		return "PT0S"
+	}
 	hours := seconds / 3600
 	seconds %= 3600
 	minutes := seconds / 60
 	seconds %= 60
 
-	return fmt.Sprintf("%02d:%02d", hours, minutes)
+	result := "PT"
+	if hours > 0 {
+		result += fmt.Sprintf("%dH", hours)
+	}
+	if minutes > 0 {
+		result += fmt.Sprintf("%dM", minutes)
+	}
+	if seconds > 0 {
+		result += fmt.Sprintf("%dS", seconds)
+	}
+	return result
+}
+
+func (c *Strain) GetTimeAsString() string {
+	return FormatHMS(IntervalMicroseconds(c.Duration))
+}
+
+func (c *Strain) GetMinutes() int64 {
+	return IntervalMicroseconds(c.Duration) / 60_000_000
+}
+
+const (
+	AttendancePresent = "present"
+	AttendanceAbsent  = "absent"
+	AttendanceExcused = "excused"
+	AttendanceLate    = "late"
+)
+
+var AttendanceStatuses = map[string]bool{
+	AttendancePresent: true,
+	AttendanceAbsent:  true,
+	AttendanceExcused: true,
+	AttendanceLate:    true,
+}
+
+type WorkoutSession struct {
+	Id          int32
+	Description int32
+	Trainer     int32
+	Date        pgtype.Date
+	StartTime   pgtype.Time
+	FinishTime  pgtype.Time
+}
+
+type Attendance struct {
+	Workout  int32
+	Person   int32
+	Status   string
+	MarkedBy pgtype.Int4
+	MarkedAt pgtype.Timestamp
+	Note     string
+}
+
+type AttendanceStats struct {
+	Person  Person
+	Present int32
+	Absent  int32
+	Excused int32
+	Late    int32
+	// Unmarked counts past sessions the person was expected at without a mark.
+	Unmarked int32
+}
+
+// Expected is the number of sessions counted against the person: excused
+// absences are not, sessions nobody marked them at are, as absences.
+func (s *AttendanceStats) Expected() int32 {
+	return s.Present + s.Late + s.Absent + s.Unmarked
+}
+
+// Rate is the share of attended sessions among the expected ones.
+func (s *AttendanceStats) Rate() float64 {
+	expected := s.Expected()
+	if expected == 0 {
+		return 0
+	}
+	return float64(s.Present+s.Late) / float64(expected)
 }
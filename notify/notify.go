@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+)
+
+// Notifier delivers alerts to the people on duty.
+type Notifier interface {
+	Notify(ctx context.Context, subject string, body string) error
+}
+
+// New builds a notifier from its target: "log" writes to logger, an http(s) URL
+// posts a JSON webhook and smtp://host:port?from=a@b&to=c@d,e@f sends mail
+// through an SMTP server without authentication.
+func New(target string, logger *log.Logger) (Notifier, error) {
+	if target == "" || target == "log" {
+		return &LogNotifier{Logger: logger}, nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notifier target: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return NewWebhookNotifier(target), nil
+	case "smtp":
+		from := u.Query().Get("from")
+		to := strings.Split(u.Query().Get("to"), ",")
+		if from == "" || u.Query().Get("to") == "" {
+			return nil, fmt.Errorf("smtp notifier needs from and to addresses")
+		}
+		return &SMTPNotifier{Addr: u.Host, From: from, To: to}, nil
+	}
+	return nil, fmt.Errorf("unknown notifier %q", target)
+}
+
+type LogNotifier struct {
+	Logger *log.Logger
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, subject string, body string) error {
+	n.Logger.Printf("ALERT %s\n%s", subject, body)
+	return nil
+}
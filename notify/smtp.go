@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+)
+
+type SMTPNotifier struct {
+	Addr string
+	From string
+	To   []string
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, subject string, body string) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	err := smtp.SendMail(n.Addr, nil, n.From, n.To, []byte(msg.String()))
+	if err != nil {
+		return fmt.Errorf("unable to send mail: %w", err)
+	}
+	return nil
+}
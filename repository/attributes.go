@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/model"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Attributes covers roles, the attribute definitions and the typed attribute
+// values of persons.
+type Attributes interface {
+	GetAllRoles(ctx context.Context) ([]model.Role, error)
+	GetAllAttributes(ctx context.Context) ([]model.Attribute, error)
+	GetAttribute(ctx context.Context, id int) (*model.Attribute, error)
+	CreateAttribute(ctx context.Context, attr model.Attribute) error
+	UpdateAttribute(ctx context.Context, attr model.Attribute) error
+	DeleteAttribute(ctx context.Context, id int) error
+
+	GetPersonIntAttribute(ctx context.Context, person int, attr int) (*int, error)
+	SetPersonIntAttribute(ctx context.Context, trio model.PersonIntAttribute) error
+	UpdatePersonIntAttribute(ctx context.Context, trio model.PersonIntAttribute) error
+	DeletePersonIntAttribute(ctx context.Context, person int, attr int) error
+
+	GetPersonFloatAttribute(ctx context.Context, person int, attr int) (*float64, error)
+	SetPersonFloatAttribute(ctx context.Context, trio model.PersonFloatAttribute) error
+	UpdatePersonFloatAttribute(ctx context.Context, trio model.PersonFloatAttribute) error
+	DeletePersonFloatAttribute(ctx context.Context, person int, attr int) error
+
+	GetPersonStringAttribute(ctx context.Context, person int, attr int) (*string, error)
+	SetPersonStringAttribute(ctx context.Context, trio model.PersonStringAttribute) error
+	UpdatePersonStringAttribute(ctx context.Context, trio model.PersonStringAttribute) error
+	DeletePersonStringAttribute(ctx context.Context, person int, attr int) error
+
+	GetPersonDateAttribute(ctx context.Context, person int, attr int) (*pgtype.Date, error)
+	SetPersonDateAttribute(ctx context.Context, trio model.PersonDateAttribute) error
+	UpdatePersonDateAttribute(ctx context.Context, trio model.PersonDateAttribute) error
+	DeletePersonDateAttribute(ctx context.Context, person int, attr int) error
+}
+
+type postgresAttributes struct {
+	pg *db.Postgres
+}
+
+func (r *postgresAttributes) GetAllRoles(ctx context.Context) ([]model.Role, error) {
+	return dbqueries.GetAllRoles(r.pg, ctx)
+}
+
+func (r *postgresAttributes) GetAllAttributes(ctx context.Context) ([]model.Attribute, error) {
+	return dbqueries.GetAllAttributes(r.pg, ctx)
+}
+
+func (r *postgresAttributes) GetAttribute(ctx context.Context, id int) (*model.Attribute, error) {
+	return dbqueries.GetAttribute(r.pg, ctx, id)
+}
+
+func (r *postgresAttributes) CreateAttribute(ctx context.Context, attr model.Attribute) error {
+	return dbqueries.CreateAttribute(r.pg, ctx, attr)
+}
+
+func (r *postgresAttributes) UpdateAttribute(ctx context.Context, attr model.Attribute) error {
+	return dbqueries.UpdateAttribute(r.pg, ctx, attr)
+}
+
+func (r *postgresAttributes) DeleteAttribute(ctx context.Context, id int) error {
+	return dbqueries.DeleteAttribute(r.pg, ctx, id)
+}
+
+func (r *postgresAttributes) GetPersonIntAttribute(ctx context.Context, person int, attr int) (*int, error) {
+	return dbqueries.GetPersonIntAttribute(r.pg, ctx, person, attr)
+}
+
+func (r *postgresAttributes) SetPersonIntAttribute(ctx context.Context, trio model.PersonIntAttribute) error {
+	return dbqueries.SetPersonIntAttribute(r.pg, ctx, trio)
+}
+
+func (r *postgresAttributes) UpdatePersonIntAttribute(ctx context.Context, trio model.PersonIntAttribute) error {
+	return dbqueries.UpdatePersonIntAttribute(r.pg, ctx, trio)
+}
+
+func (r *postgresAttributes) DeletePersonIntAttribute(ctx context.Context, person int, attr int) error {
+	return dbqueries.DeletePersonIntAttribute(r.pg, ctx, person, attr)
+}
+
+func (r *postgresAttributes) GetPersonFloatAttribute(ctx context.Context, person int, attr int) (*float64, error) {
+	return dbqueries.GetPersonFloatAttribute(r.pg, ctx, person, attr)
+}
+
+func (r *postgresAttributes) SetPersonFloatAttribute(ctx context.Context, trio model.PersonFloatAttribute) error {
+	return dbqueries.SetPersonFloatAttribute(r.pg, ctx, trio)
+}
+
+func (r *postgresAttributes) UpdatePersonFloatAttribute(ctx context.Context, trio model.PersonFloatAttribute) error {
+	return dbqueries.UpdatePersonFloatAttribute(r.pg, ctx, trio)
+}
+
+func (r *postgresAttributes) DeletePersonFloatAttribute(ctx context.Context, person int, attr int) error {
+	return dbqueries.DeletePersonFloatAttribute(r.pg, ctx, person, attr)
+}
+
+func (r *postgresAttributes) GetPersonStringAttribute(ctx context.Context, person int, attr int) (*string, error) {
+	return dbqueries.GetPersonStringAttribute(r.pg, ctx, person, attr)
+}
+
+func (r *postgresAttributes) SetPersonStringAttribute(ctx context.Context, trio model.PersonStringAttribute) error {
+	return dbqueries.SetPersonStringAttribute(r.pg, ctx, trio)
+}
+
+func (r *postgresAttributes) UpdatePersonStringAttribute(ctx context.Context, trio model.PersonStringAttribute) error {
+	return dbqueries.UpdatePersonStringAttribute(r.pg, ctx, trio)
+}
+
+func (r *postgresAttributes) DeletePersonStringAttribute(ctx context.Context, person int, attr int) error {
+	return dbqueries.DeletePersonStringAttribute(r.pg, ctx, person, attr)
+}
+
+func (r *postgresAttributes) GetPersonDateAttribute(ctx context.Context, person int, attr int) (*pgtype.Date, error) {
+	return dbqueries.GetPersonDateAttribute(r.pg, ctx, person, attr)
+}
+
+func (r *postgresAttributes) SetPersonDateAttribute(ctx context.Context, trio model.PersonDateAttribute) error {
+	return dbqueries.SetPersonDateAttribute(r.pg, ctx, trio)
+}
+
+func (r *postgresAttributes) UpdatePersonDateAttribute(ctx context.Context, trio model.PersonDateAttribute) error {
+	return dbqueries.UpdatePersonDateAttribute(r.pg, ctx, trio)
+}
+
+func (r *postgresAttributes) DeletePersonDateAttribute(ctx context.Context, person int, attr int) error {
+	return dbqueries.DeletePersonDateAttribute(r.pg, ctx, person, attr)
+}
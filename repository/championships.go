@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/model"
+)
+
+// Championships covers championships and the registrations for them.
+type Championships interface {
+	GetAllChampionships(ctx context.Context) ([]model.Championship, error)
+	GetAllChampionshipsBySection(ctx context.Context, section int) ([]model.Championship, error)
+	GetChampionship(ctx context.Context, id int) (*model.Championship, error)
+	RegisterForChampionship(ctx context.Context, championship int, person int) error
+	UnregisterFromChampionship(ctx context.Context, championship int, person int) error
+}
+
+type postgresChampionships struct {
+	pg *db.Postgres
+}
+
+func (r *postgresChampionships) GetAllChampionships(ctx context.Context) ([]model.Championship, error) {
+	return dbqueries.GetAllChampionships(r.pg, ctx)
+}
+
+func (r *postgresChampionships) GetAllChampionshipsBySection(ctx context.Context, section int) ([]model.Championship, error) {
+	return dbqueries.GetAllChampionshipsBySection(r.pg, ctx, section)
+}
+
+func (r *postgresChampionships) GetChampionship(ctx context.Context, id int) (*model.Championship, error) {
+	return dbqueries.GetChampionship(r.pg, ctx, id)
+}
+
+func (r *postgresChampionships) RegisterForChampionship(ctx context.Context, championship int, person int) error {
+	return dbqueries.RegisterForChampionship(r.pg, ctx, championship, person)
+}
+
+func (r *postgresChampionships) UnregisterFromChampionship(ctx context.Context, championship int, person int) error {
+	return dbqueries.UnregisterFromChampionship(r.pg, ctx, championship, person)
+}
@@ -0,0 +1,320 @@
+package repository
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/model"
+	"github.com/jackc/pgx/v5/pgtype"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+// The contract below is run against every backend. Postgres joins in when
+// TEST_DATABASE_URL names a database; migrations are applied to it and every
+// case runs in a transaction that is rolled back.
+const testDatabaseEnv = "TEST_DATABASE_URL"
+
+// contractCase gets repositories with nothing of its own in them yet. The
+// Postgres database may hold other rows, so cases only look at what they made.
+type contractCase struct {
+	name string
+	run  func(t *testing.T, ctx context.Context, repos *Repositories)
+}
+
+func TestContract(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		t.Parallel()
+		for _, c := range contractCases {
+			t.Run(c.name, func(t *testing.T) {
+				t.Parallel()
+				c.run(t, t.Context(), NewMemory().Repositories())
+			})
+		}
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		dsn := os.Getenv(testDatabaseEnv)
+		if dsn == "" {
+			t.Skipf("%s is not set", testDatabaseEnv)
+		}
+		db.ConnString = dsn
+		pg, err := db.NewPG(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := pg.Migrate(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range contractCases {
+			t.Run(c.name, func(t *testing.T) {
+				tx, err := pg.Db.Begin(t.Context())
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() {
+					_ = tx.Rollback(context.Background())
+				})
+				c.run(t, t.Context(), NewPostgres(&db.Postgres{Db: tx}))
+			})
+		}
+	})
+}
+
+// contractClub is a section with a group, a tourist in the group, an athlete
+// outside of it and a trainer.
+type contractClub struct {
+	section, group            int
+	tourist, athlete, trainer int
+}
+
+func makeContractClub(t *testing.T, ctx context.Context, repos *Repositories) contractClub {
+	t.Helper()
+	must := func(id int, err error) int {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	check := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var club contractClub
+	club.section = must(repos.Groups.CreateSection(ctx, model.Section{Title: "Контрактная секция"}))
+	club.group = must(repos.Groups.CreateGroup(ctx, model.Group{GroupNumber: 901, Section: int32(club.section)}))
+
+	person := func(name string, role int, sex int, birth string) int {
+		t.Helper()
+		id := must(repos.Persons.InsertPerson(ctx, model.Person{Name: name, Surname: "Контрактов", Patronymic: "Тестович"}))
+		check(repos.Persons.UpdatePersonRole(ctx, id, club.section, role))
+		check(repos.Attributes.SetPersonIntAttribute(ctx, model.PersonIntAttribute{PersonId: id, AttributeId: attrSex, Value: sex}))
+		born, err := time.Parse("2006-01-02", birth)
+		check(err)
+		check(repos.Attributes.SetPersonDateAttribute(ctx, model.PersonDateAttribute{
+			PersonId: id, AttributeId: attrBirthDate, Value: pgtype.Date{Time: born, Valid: true},
+		}))
+		return id
+	}
+	club.tourist = person("Анна", 0, 2, "2001-04-12")
+	club.athlete = person("Борис", 1, 1, "1998-11-03")
+	club.trainer = person("Глеб", 2, 1, "1985-02-20")
+	check(repos.Groups.AddGroupMember(ctx, club.tourist, club.group))
+	return club
+}
+
+// personIds returns the ids of persons that are among only, sorted.
+func personIds(t *testing.T, persons []model.Person, err error, only ...int) []int {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := []int{}
+	for _, person := range persons {
+		if slices.Contains(only, int(person.Id)) {
+			result = append(result, int(person.Id))
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+var contractCases = []contractCase{
+	{"inserted person reads back", func(t *testing.T, ctx context.Context, repos *Repositories) {
+		club := makeContractClub(t, ctx, repos)
+		person, err := repos.Persons.GetPerson(ctx, club.tourist)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if person == nil || person.Name != "Анна" || person.Surname != "Контрактов" {
+			t.Errorf("got %+v, want Анна Контрактова", person)
+		}
+	}},
+
+	{"roles are kept per section", func(t *testing.T, ctx context.Context, repos *Repositories) {
+		club := makeContractClub(t, ctx, repos)
+		role, err := repos.Persons.GetPersonRole(ctx, club.athlete, club.section)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !role.Valid || role.Int32 != 1 {
+			t.Errorf("got role %+v, want 1", role)
+		}
+
+		if err := repos.Persons.UpdatePersonRole(ctx, club.athlete, club.section, 0); err != nil {
+			t.Fatal(err)
+		}
+		role, err = repos.Persons.GetPersonRole(ctx, club.athlete, club.section)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !role.Valid || role.Int32 != 0 {
+			t.Errorf("after update: got role %+v, want 0", role)
+		}
+	}},
+
+	{"tourists by section and group", func(t *testing.T, ctx context.Context, repos *Repositories) {
+		club := makeContractClub(t, ctx, repos)
+		all := []int{club.tourist, club.athlete, club.trainer}
+
+		persons, err := repos.Persons.GetTouristsBySection(ctx, club.section)
+		got := personIds(t, persons, err, all...)
+		if want := []int{club.tourist, club.athlete}; !slices.Equal(got, want) {
+			t.Errorf("by section: got %v, want %v", got, want)
+		}
+
+		persons, err = repos.Persons.GetTouristsByGroup(ctx, club.group)
+		got = personIds(t, persons, err, all...)
+		if want := []int{club.tourist}; !slices.Equal(got, want) {
+			t.Errorf("by group: got %v, want %v", got, want)
+		}
+
+		persons, err = repos.Persons.GetTouristsBySex(ctx, 2)
+		got = personIds(t, persons, err, all...)
+		if want := []int{club.tourist}; !slices.Equal(got, want) {
+			t.Errorf("by sex: got %v, want %v", got, want)
+		}
+
+		persons, err = repos.Persons.GetTouristsByBirthYear(ctx, 1998)
+		got = personIds(t, persons, err, all...)
+		if want := []int{club.athlete}; !slices.Equal(got, want) {
+			t.Errorf("by birth year: got %v, want %v", got, want)
+		}
+	}},
+
+	{"trainers by section", func(t *testing.T, ctx context.Context, repos *Repositories) {
+		club := makeContractClub(t, ctx, repos)
+		persons, err := repos.Persons.GetTrainersBySection(ctx, club.section)
+		got := personIds(t, persons, err, club.tourist, club.athlete, club.trainer)
+		if want := []int{club.trainer}; !slices.Equal(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}},
+
+	{"person attributes are set, updated and deleted", func(t *testing.T, ctx context.Context, repos *Repositories) {
+		club := makeContractClub(t, ctx, repos)
+		trio := model.PersonIntAttribute{PersonId: club.trainer, AttributeId: attrTrainerSalary, Value: 50000}
+		if err := repos.Attributes.SetPersonIntAttribute(ctx, trio); err != nil {
+			t.Fatal(err)
+		}
+		trio.Value = 65000
+		if err := repos.Attributes.UpdatePersonIntAttribute(ctx, trio); err != nil {
+			t.Fatal(err)
+		}
+		value, err := repos.Attributes.GetPersonIntAttribute(ctx, club.trainer, attrTrainerSalary)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value == nil || *value != 65000 {
+			t.Errorf("got salary %v, want 65000", value)
+		}
+
+		persons, err := repos.Persons.GetTrainersBySalary(ctx, 65000)
+		got := personIds(t, persons, err, club.trainer)
+		if want := []int{club.trainer}; !slices.Equal(got, want) {
+			t.Errorf("trainers by salary: got %v, want %v", got, want)
+		}
+
+		if err := repos.Attributes.DeletePersonIntAttribute(ctx, club.trainer, attrTrainerSalary); err != nil {
+			t.Fatal(err)
+		}
+		value, err = repos.Attributes.GetPersonIntAttribute(ctx, club.trainer, attrTrainerSalary)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != nil {
+			t.Errorf("got salary %d after delete, want none", *value)
+		}
+	}},
+
+	{"group criteria and members", func(t *testing.T, ctx context.Context, repos *Repositories) {
+		club := makeContractClub(t, ctx, repos)
+		group, err := repos.Groups.GetGroup(ctx, club.group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if group == nil {
+			t.Fatalf("group %d not found", club.group)
+		}
+		group.MinAge = pgtype.Int4{Int32: 18, Valid: true}
+		group.Sex = pgtype.Int4{Int32: 2, Valid: true}
+		if err := repos.Groups.UpdateGroup(ctx, *group); err != nil {
+			t.Fatal(err)
+		}
+		group, err = repos.Groups.GetGroup(ctx, club.group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if group.MinAge.Int32 != 18 || group.Sex.Int32 != 2 || group.MaxAge.Valid || group.MinQualification.Valid {
+			t.Errorf("got %+v, want min age 18 and sex 2 only", group)
+		}
+
+		members, err := repos.Groups.GetGroupMembers(ctx, club.group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []int{club.tourist}; !slices.Equal(members, want) {
+			t.Errorf("got members %v, want %v", members, want)
+		}
+		if err := repos.Groups.RemoveGroupMember(ctx, club.tourist, club.group); err != nil {
+			t.Fatal(err)
+		}
+		members, err = repos.Groups.GetGroupMembers(ctx, club.group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(members) != 0 {
+			t.Errorf("got members %v after removal, want none", members)
+		}
+	}},
+
+	{"section memberships and group candidates", func(t *testing.T, ctx context.Context, repos *Repositories) {
+		club := makeContractClub(t, ctx, repos)
+		memberships, err := repos.Groups.GetSectionMemberships(ctx, pgtype.Int4{Int32: int32(club.section), Valid: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []model.GroupMembership{{Group: int32(club.group), Person: int32(club.tourist)}}
+		if !slices.Equal(memberships, want) {
+			t.Errorf("got memberships %v, want %v", memberships, want)
+		}
+
+		candidates, err := repos.Groups.GetGroupCandidates(ctx, []int32{int32(club.tourist)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(candidates) != 1 {
+			t.Fatalf("got %d candidates, want 1", len(candidates))
+		}
+		candidate := candidates[0]
+		if int(candidate.Person.Id) != club.tourist || candidate.Sex.Int32 != 2 || candidate.Qualification != 0 ||
+			candidate.BirthDate.Time.Format("2006-01-02") != "2001-04-12" {
+			t.Errorf("got %+v, want Анна born 2001-04-12 without qualification", candidate)
+		}
+	}},
+
+	{"nobody completed all routes without completing one", func(t *testing.T, ctx context.Context, repos *Repositories) {
+		club := makeContractClub(t, ctx, repos)
+		persons, err := repos.Tours.GetTouristsCompletedAll(ctx)
+		got := personIds(t, persons, err, club.tourist, club.athlete, club.trainer)
+		if len(got) != 0 {
+			t.Errorf("got %v, want nobody", got)
+		}
+	}},
+
+	{"no arrears without fees", func(t *testing.T, ctx context.Context, repos *Repositories) {
+		club := makeContractClub(t, ctx, repos)
+		arrears, err := repos.Fees.GetPersonArrears(ctx, club.tourist, "2100-01-01")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(arrears) != 0 {
+			t.Errorf("got %d arrears, want none", len(arrears))
+		}
+	}},
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/model"
+)
+
+// Fees covers what members owe on their membership fees.
+type Fees interface {
+	GetPersonArrears(ctx context.Context, person int, date string) ([]model.Arrear, error)
+}
+
+type postgresFees struct {
+	pg *db.Postgres
+}
+
+func (r *postgresFees) GetPersonArrears(ctx context.Context, person int, date string) ([]model.Arrear, error) {
+	return dbqueries.GetPersonArrears(r.pg, ctx, person, date)
+}
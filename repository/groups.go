@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/model"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Groups covers sections, their groups and group membership.
+type Groups interface {
+	CreateGroup(ctx context.Context, group model.Group) (int, error)
+	GetGroup(ctx context.Context, id int) (*model.Group, error)
+	UpdateGroup(ctx context.Context, group model.Group) error
+	DeleteGroup(ctx context.Context, id int) error
+	GetGroups(ctx context.Context) ([]model.Group, error)
+	GetGroupsFromSections(ctx context.Context, section int) ([]model.Group, error)
+	AddGroupMember(ctx context.Context, person int, group int) error
+	RemoveGroupMember(ctx context.Context, person int, group int) error
+	GetGroupMembers(ctx context.Context, group int) ([]int, error)
+	GetSectionMemberships(ctx context.Context, section pgtype.Int4) ([]model.GroupMembership, error)
+	GetGroupCandidates(ctx context.Context, persons []int32) ([]model.GroupCandidate, error)
+
+	CreateSection(ctx context.Context, section model.Section) (int, error)
+	GetSection(ctx context.Context, id int) (*model.Section, error)
+	UpdateSection(ctx context.Context, section model.Section) error
+	DeleteSection(ctx context.Context, id int) error
+	GetAllSections(ctx context.Context) ([]model.Section, error)
+}
+
+type postgresGroups struct {
+	pg *db.Postgres
+}
+
+func (r *postgresGroups) CreateGroup(ctx context.Context, group model.Group) (int, error) {
+	return dbqueries.CreateGroup(r.pg, ctx, group)
+}
+
+func (r *postgresGroups) GetGroup(ctx context.Context, id int) (*model.Group, error) {
+	return dbqueries.GetGroup(r.pg, ctx, id)
+}
+
+func (r *postgresGroups) UpdateGroup(ctx context.Context, group model.Group) error {
+	return dbqueries.UpdateGroup(r.pg, ctx, group)
+}
+
+func (r *postgresGroups) DeleteGroup(ctx context.Context, id int) error {
+	return dbqueries.DeleteGroup(r.pg, ctx, id)
+}
+
+func (r *postgresGroups) GetGroups(ctx context.Context) ([]model.Group, error) {
+	return dbqueries.GetGroups(r.pg, ctx)
+}
+
+func (r *postgresGroups) GetGroupsFromSections(ctx context.Context, section int) ([]model.Group, error) {
+	return dbqueries.GetGroupsFromSections(r.pg, ctx, section)
+}
+
+func (r *postgresGroups) AddGroupMember(ctx context.Context, person int, group int) error {
+	return dbqueries.AddGroupMember(r.pg, ctx, person, group)
+}
+
+func (r *postgresGroups) RemoveGroupMember(ctx context.Context, person int, group int) error {
+	return dbqueries.RemoveGroupMember(r.pg, ctx, person, group)
+}
+
+func (r *postgresGroups) GetGroupMembers(ctx context.Context, group int) ([]int, error) {
+	return dbqueries.GetGroupMembers(r.pg, ctx, group)
+}
+
+func (r *postgresGroups) GetSectionMemberships(ctx context.Context, section pgtype.Int4) ([]model.GroupMembership, error) {
+	return dbqueries.GetSectionMemberships(r.pg, ctx, section)
+}
+
+func (r *postgresGroups) GetGroupCandidates(ctx context.Context, persons []int32) ([]model.GroupCandidate, error) {
+	return dbqueries.GetGroupCandidates(r.pg, ctx, persons)
+}
+
+func (r *postgresGroups) CreateSection(ctx context.Context, section model.Section) (int, error) {
+	return dbqueries.CreateSection(r.pg, ctx, section)
+}
+
+func (r *postgresGroups) GetSection(ctx context.Context, id int) (*model.Section, error) {
+	return dbqueries.GetSection(r.pg, ctx, id)
+}
+
+func (r *postgresGroups) UpdateSection(ctx context.Context, section model.Section) error {
+	return dbqueries.UpdateSection(r.pg, ctx, section)
+}
+
+func (r *postgresGroups) DeleteSection(ctx context.Context, id int) error {
+	return dbqueries.DeleteSection(r.pg, ctx, id)
+}
+
+func (r *postgresGroups) GetAllSections(ctx context.Context) ([]model.Section, error) {
+	return dbqueries.GetAllSections(r.pg, ctx)
+}
@@ -0,0 +1,354 @@
+package repository
+
+import (
+	"db_backend/model"
+	"github.com/jackc/pgx/v5/pgtype"
+	"sort"
+	"sync"
+	"time"
+)
+
+type personAttr struct {
+	person int
+	attr   int
+}
+
+type membership struct {
+	person  int
+	section int
+	role    int
+}
+
+type groupMember struct {
+	group  int
+	person int
+}
+
+type placeRoute struct {
+	place int
+	route int
+}
+
+type participant struct {
+	tour    int
+	person  int
+	status  string
+	outcome string
+}
+
+type workoutDescription struct {
+	trainer     int
+	workoutType string
+	groups      []int
+}
+
+type fitnessResult struct {
+	person  int
+	test    int
+	value   float64
+	takenOn time.Time
+}
+
+type routeReview struct {
+	route     int
+	rating    int
+	perceived int
+}
+
+type registration struct {
+	championship int
+	person       int
+}
+
+// Memory keeps the whole club in maps and answers the same questions as the
+// Postgres queries, so services can be tested without a database. Foreign keys
+// are not checked. The Add methods fill in what the repositories cannot write.
+type Memory struct {
+	// Now is the current time for ages and past championships; time.Now when nil.
+	Now func() time.Time
+
+	mu     sync.Mutex
+	lastId int
+
+	persons     map[int]model.Person
+	memberships []membership
+	roles       map[int]model.Role
+	attributes  map[int]model.Attribute
+	intAttrs    map[personAttr]int
+	floatAttrs  map[personAttr]float64
+	stringAttrs map[personAttr]string
+	dateAttrs   map[personAttr]pgtype.Date
+
+	sections     map[int]model.Section
+	groups       map[int]model.Group
+	groupMembers []groupMember
+
+	routeTypes   map[int]model.RouteType
+	places       map[int]string
+	routes       map[int]model.Route
+	placesRoutes []placeRoute
+	reviews      []routeReview
+	tours        map[int]model.Tour
+	participants []participant
+
+	descriptions map[int]workoutDescription
+	workouts     map[int]model.WorkoutSession
+
+	fitnessTests   map[int]model.FitnessTest
+	fitnessResults []fitnessResult
+
+	championships map[int]model.Championship
+	registrations []registration
+
+	arrears []model.Arrear
+}
+
+var (
+	_ Persons       = (*Memory)(nil)
+	_ Attributes    = (*Memory)(nil)
+	_ Groups        = (*Memory)(nil)
+	_ Tours         = (*Memory)(nil)
+	_ Routes        = (*Memory)(nil)
+	_ Workouts      = (*Memory)(nil)
+	_ Championships = (*Memory)(nil)
+	_ Fees          = (*Memory)(nil)
+)
+
+func NewMemory() *Memory {
+	return &Memory{
+		persons:       make(map[int]model.Person),
+		roles:         make(map[int]model.Role),
+		attributes:    make(map[int]model.Attribute),
+		intAttrs:      make(map[personAttr]int),
+		floatAttrs:    make(map[personAttr]float64),
+		stringAttrs:   make(map[personAttr]string),
+		dateAttrs:     make(map[personAttr]pgtype.Date),
+		sections:      make(map[int]model.Section),
+		groups:        make(map[int]model.Group),
+		routeTypes:    make(map[int]model.RouteType),
+		places:        make(map[int]string),
+		routes:        make(map[int]model.Route),
+		tours:         make(map[int]model.Tour),
+		descriptions:  make(map[int]workoutDescription),
+		workouts:      make(map[int]model.WorkoutSession),
+		fitnessTests:  make(map[int]model.FitnessTest),
+		championships: make(map[int]model.Championship),
+	}
+}
+
+// Repositories returns m behind every repository interface.
+func (m *Memory) Repositories() *Repositories {
+	return &Repositories{
+		Persons:       m,
+		Attributes:    m,
+		Groups:        m,
+		Tours:         m,
+		Routes:        m,
+		Workouts:      m,
+		Championships: m,
+		Fees:          m,
+	}
+}
+
+// nextId hands out ids from one counter for all tables, which keeps ids of
+// different kinds from being mixed up unnoticed in tests.
+func (m *Memory) nextId() int {
+	m.lastId++
+	return m.lastId
+}
+
+func (m *Memory) today() time.Time {
+	now := time.Now()
+	if m.Now != nil {
+		now = m.Now()
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func sortedKeys[V any](values map[int]V) []int {
+	keys := make([]int, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// personsWhere returns the persons matching keep ordered by id.
+func (m *Memory) personsWhere(keep func(id int) bool) []model.Person {
+	var result []model.Person
+	for _, id := range sortedKeys(m.persons) {
+		if keep(id) {
+			result = append(result, m.persons[id])
+		}
+	}
+	return result
+}
+
+// routesWhere returns the ids of the routes matching keep in order.
+func (m *Memory) routesWhere(keep func(id int) bool) []model.RouteId {
+	var result []model.RouteId
+	for _, id := range sortedKeys(m.routes) {
+		if keep(id) {
+			result = append(result, model.RouteId{Id: int32(id)})
+		}
+	}
+	return result
+}
+
+func (m *Memory) hasRole(person int, keep func(role int) bool) bool {
+	for _, member := range m.memberships {
+		if member.person == person && keep(member.role) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTourist(role int) bool {
+	return role == 0 || role == 1
+}
+
+func anyRole(int) bool {
+	return true
+}
+
+func (m *Memory) instructs(person int) bool {
+	for _, tour := range m.tours {
+		if int(tour.Instructor) == person {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Memory) routeHasPlace(route int, place int) bool {
+	for _, pr := range m.placesRoutes {
+		if pr.route == route && pr.place == place {
+			return true
+		}
+	}
+	return false
+}
+
+// ageOn is the age in full years as postgres age() counts it.
+func ageOn(birth time.Time, day time.Time) int {
+	candidate := model.GroupCandidate{BirthDate: pgtype.Date{Time: birth, Valid: true}}
+	return candidate.AgeOn(day)
+}
+
+func parseDate(value string) (time.Time, error) {
+	return time.Parse("2006-01-02", value)
+}
+
+// daysBetween is to - from in whole days, as subtracting dates in postgres.
+func daysBetween(from time.Time, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func (m *Memory) AddRole(role model.Role) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.roles[int(role.Id)] = role
+}
+
+func (m *Memory) AddRouteType(routeType string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextId()
+	m.routeTypes[id] = model.RouteType{Id: id, Type: routeType}
+	return id
+}
+
+func (m *Memory) AddPlace(title string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextId()
+	m.places[id] = title
+	return id
+}
+
+func (m *Memory) AddRoute(route model.Route, places ...int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextId()
+	route.Id = int32(id)
+	m.routes[id] = route
+	for _, place := range places {
+		m.placesRoutes = append(m.placesRoutes, placeRoute{place: place, route: id})
+	}
+	return id
+}
+
+func (m *Memory) AddRouteReview(route int, rating int, perceived int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reviews = append(m.reviews, routeReview{route: route, rating: rating, perceived: perceived})
+}
+
+func (m *Memory) AddTour(tour model.Tour) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextId()
+	tour.Id = int32(id)
+	m.tours[id] = tour
+	return id
+}
+
+// AddParticipant puts a person on a tour with an enrollment status and an
+// outcome, empty while the tour is not over.
+func (m *Memory) AddParticipant(tour int, person int, status string, outcome string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.participants = append(m.participants, participant{tour: tour, person: person, status: status, outcome: outcome})
+}
+
+// AddWorkoutDescription adds a recurring workout of a trainer for the groups;
+// an empty type leaves the workout without one.
+func (m *Memory) AddWorkoutDescription(trainer int, workoutType string, groups ...int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextId()
+	m.descriptions[id] = workoutDescription{trainer: trainer, workoutType: workoutType, groups: groups}
+	return id
+}
+
+func (m *Memory) AddWorkout(session model.WorkoutSession) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextId()
+	session.Id = int32(id)
+	m.workouts[id] = session
+	return id
+}
+
+func (m *Memory) AddFitnessTest(test model.FitnessTest) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextId()
+	test.Id = int32(id)
+	m.fitnessTests[id] = test
+	return id
+}
+
+func (m *Memory) AddFitnessResult(result model.FitnessResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fitnessResults = append(m.fitnessResults, fitnessResult{
+		person:  int(result.Person),
+		test:    int(result.Test),
+		value:   result.Value,
+		takenOn: result.TakenOn.Time,
+	})
+}
+
+func (m *Memory) AddChampionship(championship model.Championship) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextId()
+	championship.Id = int32(id)
+	m.championships[id] = championship
+	return id
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (m *Memory) GetAllRoles(ctx context.Context) ([]model.Role, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var roles []model.Role
+	for _, id := range sortedKeys(m.roles) {
+		roles = append(roles, m.roles[id])
+	}
+	return roles, nil
+}
+
+func (m *Memory) GetAllAttributes(ctx context.Context) ([]model.Attribute, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var attrs []model.Attribute
+	for _, id := range sortedKeys(m.attributes) {
+		attrs = append(attrs, m.attributes[id])
+	}
+	return attrs, nil
+}
+
+func (m *Memory) GetAttribute(ctx context.Context, id int) (*model.Attribute, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	attr, ok := m.attributes[id]
+	if !ok {
+		return nil, nil
+	}
+	return &attr, nil
+}
+
+// CreateAttribute ignores the id of attr and assigns a new one, like the
+// insert it stands for.
+func (m *Memory) CreateAttribute(ctx context.Context, attr model.Attribute) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	attr.Id = int32(m.nextId())
+	m.attributes[int(attr.Id)] = attr
+	return nil
+}
+
+func (m *Memory) UpdateAttribute(ctx context.Context, attr model.Attribute) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.attributes[int(attr.Id)]; ok {
+		m.attributes[int(attr.Id)] = attr
+	}
+	return nil
+}
+
+func (m *Memory) DeleteAttribute(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.attributes, id)
+	return nil
+}
+
+// The typed values follow the tables behind them: setting a value that is
+// already there fails on the primary key, updating a missing one does nothing.
+
+func getValue[V any](m *Memory, values map[personAttr]V, person int, attr int) *V {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := values[personAttr{person, attr}]
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+func setValue[V any](m *Memory, values map[personAttr]V, person int, attr int, value V) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := personAttr{person, attr}
+	if _, ok := values[key]; ok {
+		return fmt.Errorf("attribute %d of person %d is already set", attr, person)
+	}
+	values[key] = value
+	return nil
+}
+
+func updateValue[V any](m *Memory, values map[personAttr]V, person int, attr int, value V) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := personAttr{person, attr}
+	if _, ok := values[key]; ok {
+		values[key] = value
+	}
+	return nil
+}
+
+func deleteValue[V any](m *Memory, values map[personAttr]V, person int, attr int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(values, personAttr{person, attr})
+	return nil
+}
+
+func (m *Memory) GetPersonIntAttribute(ctx context.Context, person int, attr int) (*int, error) {
+	return getValue(m, m.intAttrs, person, attr), nil
+}
+
+func (m *Memory) SetPersonIntAttribute(ctx context.Context, trio model.PersonIntAttribute) error {
+	return setValue(m, m.intAttrs, trio.PersonId, trio.AttributeId, trio.Value)
+}
+
+func (m *Memory) UpdatePersonIntAttribute(ctx context.Context, trio model.PersonIntAttribute) error {
+	return updateValue(m, m.intAttrs, trio.PersonId, trio.AttributeId, trio.Value)
+}
+
+func (m *Memory) DeletePersonIntAttribute(ctx context.Context, person int, attr int) error {
+	return deleteValue(m, m.intAttrs, person, attr)
+}
+
+func (m *Memory) GetPersonFloatAttribute(ctx context.Context, person int, attr int) (*float64, error) {
+	return getValue(m, m.floatAttrs, person, attr), nil
+}
+
+func (m *Memory) SetPersonFloatAttribute(ctx context.Context, trio model.PersonFloatAttribute) error {
+	return setValue(m, m.floatAttrs, trio.PersonId, trio.AttributeId, trio.Value)
+}
+
+func (m *Memory) UpdatePersonFloatAttribute(ctx context.Context, trio model.PersonFloatAttribute) error {
+	return updateValue(m, m.floatAttrs, trio.PersonId, trio.AttributeId, trio.Value)
+}
+
+func (m *Memory) DeletePersonFloatAttribute(ctx context.Context, person int, attr int) error {
+	return deleteValue(m, m.floatAttrs, person, attr)
+}
+
+func (m *Memory) GetPersonStringAttribute(ctx context.Context, person int, attr int) (*string, error) {
+	return getValue(m, m.stringAttrs, person, attr), nil
+}
+
+func (m *Memory) SetPersonStringAttribute(ctx context.Context, trio model.PersonStringAttribute) error {
+	return setValue(m, m.stringAttrs, trio.PersonId, trio.AttributeId, trio.Value)
+}
+
+func (m *Memory) UpdatePersonStringAttribute(ctx context.Context, trio model.PersonStringAttribute) error {
+	return updateValue(m, m.stringAttrs, trio.PersonId, trio.AttributeId, trio.Value)
+}
+
+func (m *Memory) DeletePersonStringAttribute(ctx context.Context, person int, attr int) error {
+	return deleteValue(m, m.stringAttrs, person, attr)
+}
+
+func (m *Memory) GetPersonDateAttribute(ctx context.Context, person int, attr int) (*pgtype.Date, error) {
+	return getValue(m, m.dateAttrs, person, attr), nil
+}
+
+func (m *Memory) SetPersonDateAttribute(ctx context.Context, trio model.PersonDateAttribute) error {
+	return setValue(m, m.dateAttrs, trio.PersonId, trio.AttributeId, trio.Value)
+}
+
+func (m *Memory) UpdatePersonDateAttribute(ctx context.Context, trio model.PersonDateAttribute) error {
+	return updateValue(m, m.dateAttrs, trio.PersonId, trio.AttributeId, trio.Value)
+}
+
+func (m *Memory) DeletePersonDateAttribute(ctx context.Context, person int, attr int) error {
+	return deleteValue(m, m.dateAttrs, person, attr)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"db_backend/model"
+	"slices"
+)
+
+// pastChampionships returns the championships already held with an athlete
+// registered for them, athletes being kept to those matching athlete.
+func (m *Memory) pastChampionships(athlete func(member membership) bool) []model.Championship {
+	today := m.today()
+	var championships []model.Championship
+	for _, id := range sortedKeys(m.championships) {
+		championship := m.championships[id]
+		if !championship.Date.Valid || !championship.Date.Time.Before(today) {
+			continue
+		}
+		if slices.ContainsFunc(m.registrations, func(r registration) bool {
+			return r.championship == id && slices.ContainsFunc(m.memberships, func(member membership) bool {
+				return member.person == r.person && member.role == 1 && athlete(member)
+			})
+		}) {
+			championships = append(championships, championship)
+		}
+	}
+	return championships
+}
+
+func (m *Memory) GetAllChampionships(ctx context.Context) ([]model.Championship, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pastChampionships(func(membership) bool {
+		return true
+	}), nil
+}
+
+func (m *Memory) GetAllChampionshipsBySection(ctx context.Context, section int) ([]model.Championship, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pastChampionships(func(member membership) bool {
+		return member.section == section
+	}), nil
+}
+
+func (m *Memory) GetChampionship(ctx context.Context, id int) (*model.Championship, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	championship, ok := m.championships[id]
+	if !ok {
+		return nil, nil
+	}
+	return &championship, nil
+}
+
+func (m *Memory) RegisterForChampionship(ctx context.Context, championship int, person int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := registration{championship: championship, person: person}
+	if !slices.Contains(m.registrations, r) {
+		m.registrations = append(m.registrations, r)
+	}
+	return nil
+}
+
+func (m *Memory) UnregisterFromChampionship(ctx context.Context, championship int, person int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.registrations = slices.DeleteFunc(m.registrations, func(r registration) bool {
+		return r == registration{championship: championship, person: person}
+	})
+	return nil
+}
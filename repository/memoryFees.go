@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"db_backend/model"
+)
+
+// AddArrear records that a person owes on a fee; Memory does not work arrears
+// out from the fee schedule and payments.
+func (m *Memory) AddArrear(arrear model.Arrear) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.arrears = append(m.arrears, arrear)
+}
+
+func (m *Memory) GetPersonArrears(ctx context.Context, person int, date string) ([]model.Arrear, error) {
+	day, err := parseDate(date)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var arrears []model.Arrear
+	for _, arrear := range m.arrears {
+		if int(arrear.Person.Id) == person && !arrear.Fee.DueOn.Time.After(day) && arrear.Outstanding() > 0 {
+			arrears = append(arrears, arrear)
+		}
+	}
+	return arrears, nil
+}
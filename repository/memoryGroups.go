@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+)
+
+func (m *Memory) CreateGroup(ctx context.Context, group model.Group) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextId()
+	group.Id = int32(id)
+	m.groups[id] = group
+	return id, nil
+}
+
+func (m *Memory) GetGroup(ctx context.Context, id int) (*model.Group, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	group, ok := m.groups[id]
+	if !ok {
+		return nil, nil
+	}
+	return &group, nil
+}
+
+func (m *Memory) UpdateGroup(ctx context.Context, group model.Group) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.groups[int(group.Id)]; ok {
+		m.groups[int(group.Id)] = group
+	}
+	return nil
+}
+
+// DeleteGroup drops the memberships of the group along with it.
+func (m *Memory) DeleteGroup(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.groups, id)
+	m.groupMembers = slices.DeleteFunc(m.groupMembers, func(member groupMember) bool {
+		return member.group == id
+	})
+	return nil
+}
+
+func (m *Memory) groupsWhere(keep func(group model.Group) bool) []model.Group {
+	var groups []model.Group
+	for _, id := range sortedKeys(m.groups) {
+		if keep(m.groups[id]) {
+			groups = append(groups, m.groups[id])
+		}
+	}
+	return groups
+}
+
+func (m *Memory) GetGroups(ctx context.Context) ([]model.Group, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.groupsWhere(func(model.Group) bool {
+		return true
+	}), nil
+}
+
+func (m *Memory) GetGroupsFromSections(ctx context.Context, section int) ([]model.Group, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.groupsWhere(func(group model.Group) bool {
+		return int(group.Section) == section
+	}), nil
+}
+
+func (m *Memory) AddGroupMember(ctx context.Context, person int, group int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	member := groupMember{group: group, person: person}
+	if slices.Contains(m.groupMembers, member) {
+		return fmt.Errorf("person %d is already in group %d", person, group)
+	}
+	m.groupMembers = append(m.groupMembers, member)
+	return nil
+}
+
+func (m *Memory) RemoveGroupMember(ctx context.Context, person int, group int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.groupMembers = slices.DeleteFunc(m.groupMembers, func(member groupMember) bool {
+		return member.group == group && member.person == person
+	})
+	return nil
+}
+
+func (m *Memory) GetGroupMembers(ctx context.Context, group int) ([]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var members []int
+	for _, member := range m.groupMembers {
+		if member.group == group {
+			members = append(members, member.person)
+		}
+	}
+	slices.Sort(members)
+	return members, nil
+}
+
+// GetSectionMemberships lists the memberships of the groups of a section, of
+// every group when section is null, ordered by group and person.
+func (m *Memory) GetSectionMemberships(ctx context.Context, section pgtype.Int4) ([]model.GroupMembership, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var memberships []model.GroupMembership
+	for _, member := range m.groupMembers {
+		group, ok := m.groups[member.group]
+		if !ok || section.Valid && group.Section != section.Int32 {
+			continue
+		}
+		memberships = append(memberships, model.GroupMembership{Group: int32(member.group), Person: int32(member.person)})
+	}
+	slices.SortFunc(memberships, func(a, b model.GroupMembership) int {
+		if a.Group != b.Group {
+			return int(a.Group - b.Group)
+		}
+		return int(a.Person - b.Person)
+	})
+	return memberships, nil
+}
+
+// GetGroupCandidates takes the qualification of a person to be the highest
+// difficulty among the routes they completed, 0 without any.
+func (m *Memory) GetGroupCandidates(ctx context.Context, persons []int32) ([]model.GroupCandidate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var candidates []model.GroupCandidate
+	for _, person := range m.personsWhere(func(id int) bool {
+		return slices.Contains(persons, int32(id))
+	}) {
+		candidate := model.GroupCandidate{Person: person}
+		candidate.BirthDate = m.dateAttrs[personAttr{person: int(person.Id), attr: attrBirthDate}]
+		if sex, ok := m.intAttrs[personAttr{person: int(person.Id), attr: attrSex}]; ok {
+			candidate.Sex = pgtype.Int4{Int32: int32(sex), Valid: true}
+		}
+		for _, p := range m.participants {
+			tour, ok := m.tours[p.tour]
+			if !ok || p.person != int(person.Id) || !completed(p) {
+				continue
+			}
+			if route := m.routes[int(tour.Route)]; route.Difficulty.Valid {
+				candidate.Qualification = max(candidate.Qualification, route.Difficulty.Int32)
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+func (m *Memory) CreateSection(ctx context.Context, section model.Section) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextId()
+	section.Id = int32(id)
+	m.sections[id] = section
+	return id, nil
+}
+
+func (m *Memory) GetSection(ctx context.Context, id int) (*model.Section, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	section, ok := m.sections[id]
+	if !ok {
+		return nil, nil
+	}
+	return &section, nil
+}
+
+func (m *Memory) UpdateSection(ctx context.Context, section model.Section) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.sections[int(section.Id)]; ok {
+		m.sections[int(section.Id)] = section
+	}
+	return nil
+}
+
+func (m *Memory) DeleteSection(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sections, id)
+	return nil
+}
+
+func (m *Memory) GetAllSections(ctx context.Context) ([]model.Section, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var sections []model.Section
+	for _, id := range sortedKeys(m.sections) {
+		sections = append(sections, m.sections[id])
+	}
+	return sections, nil
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"db_backend/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+)
+
+// Attribute ids the person filters refer to, as in dbqueries.
+const (
+	attrSex            = 1
+	attrBirthDate      = 2
+	attrTrainerSalary  = 3
+	attrSpecialization = 4
+	attrManagerSince   = 5
+	attrManagerSalary  = 6
+)
+
+func isTrainer(role int) bool {
+	return role == 2
+}
+
+func isManager(role int) bool {
+	return role == 3
+}
+
+func (m *Memory) InsertPerson(ctx context.Context, person model.Person) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextId()
+	person.Id = int32(id)
+	m.persons[id] = person
+	return id, nil
+}
+
+func (m *Memory) GetPerson(ctx context.Context, id int) (*model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	person, ok := m.persons[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return &person, nil
+}
+
+func (m *Memory) GetPersonRole(ctx context.Context, person int, section int) (pgtype.Int4, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, member := range m.memberships {
+		if member.person == person && member.section == section {
+			return pgtype.Int4{Int32: int32(member.role), Valid: true}, nil
+		}
+	}
+	return pgtype.Int4{}, pgx.ErrNoRows
+}
+
+func (m *Memory) UpdatePersonRole(ctx context.Context, person int, section int, role int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, member := range m.memberships {
+		if member.person == person && member.section == section {
+			m.memberships[i].role = role
+			return nil
+		}
+	}
+	m.memberships = append(m.memberships, membership{person: person, section: section, role: role})
+	return nil
+}
+
+func (m *Memory) DeletePersonRole(ctx context.Context, person int, section int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.memberships = slices.DeleteFunc(m.memberships, func(member membership) bool {
+		return member.person == person && member.section == section
+	})
+	return nil
+}
+
+func (m *Memory) withRole(role func(int) bool) []model.Person {
+	return m.personsWhere(func(id int) bool {
+		return m.hasRole(id, role)
+	})
+}
+
+func (m *Memory) withRoleInSection(role func(int) bool, section int) []model.Person {
+	return m.personsWhere(func(id int) bool {
+		return slices.ContainsFunc(m.memberships, func(member membership) bool {
+			return member.person == id && member.section == section && role(member.role)
+		})
+	})
+}
+
+func (m *Memory) withIntAttr(role func(int) bool, attr int, value int) []model.Person {
+	return m.personsWhere(func(id int) bool {
+		stored, ok := m.intAttrs[personAttr{id, attr}]
+		return ok && stored == value && m.hasRole(id, role)
+	})
+}
+
+func (m *Memory) withDateAttr(role func(int) bool, attr int, keep func(date pgtype.Date) bool) []model.Person {
+	return m.personsWhere(func(id int) bool {
+		stored, ok := m.dateAttrs[personAttr{id, attr}]
+		return ok && stored.Valid && keep(stored) && m.hasRole(id, role)
+	})
+}
+
+func (m *Memory) inYear(role func(int) bool, attr int, year int) []model.Person {
+	return m.withDateAttr(role, attr, func(date pgtype.Date) bool {
+		return date.Time.Year() == year
+	})
+}
+
+func (m *Memory) agedOn(role func(int) bool, age int) []model.Person {
+	today := m.today()
+	return m.withDateAttr(role, attrBirthDate, func(date pgtype.Date) bool {
+		return ageOn(date.Time, today) == age
+	})
+}
+
+func (m *Memory) GetAllTourists(ctx context.Context) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.withRole(isTourist), nil
+}
+
+func (m *Memory) GetTouristsBySection(ctx context.Context, section int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.withRoleInSection(isTourist, section), nil
+}
+
+// GetTouristsByGroup returns every member of the group whatever their role,
+// like the query it stands for.
+func (m *Memory) GetTouristsByGroup(ctx context.Context, group int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.personsWhere(func(id int) bool {
+		return slices.Contains(m.groupMembers, groupMember{group: group, person: id})
+	}), nil
+}
+
+func (m *Memory) GetTouristsBySex(ctx context.Context, sex int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.withIntAttr(isTourist, attrSex, sex), nil
+}
+
+func (m *Memory) GetTouristsByBirthYear(ctx context.Context, year int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inYear(isTourist, attrBirthDate, year), nil
+}
+
+func (m *Memory) GetTouristsByAge(ctx context.Context, age int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.agedOn(isTourist, age), nil
+}
+
+func (m *Memory) GetTouristsByFitness(ctx context.Context, test int, value float64, since string) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	from, err := parseDate(since)
+	if err != nil {
+		return nil, err
+	}
+	definition, ok := m.fitnessTests[test]
+	if !ok {
+		return nil, nil
+	}
+	return m.personsWhere(func(id int) bool {
+		if !m.hasRole(id, isTourist) {
+			return false
+		}
+		for _, result := range m.fitnessResults {
+			if result.person != id || result.test != test || result.takenOn.Before(from) {
+				continue
+			}
+			if definition.HigherIsBetter && result.value >= value || !definition.HigherIsBetter && result.value <= value {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func (m *Memory) GetAllTrainers(ctx context.Context) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.withRole(isTrainer), nil
+}
+
+func (m *Memory) GetTrainersBySection(ctx context.Context, section int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.withRoleInSection(isTrainer, section), nil
+}
+
+func (m *Memory) GetTrainersBySex(ctx context.Context, sex int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.withIntAttr(isTrainer, attrSex, sex), nil
+}
+
+func (m *Memory) GetTrainersByAge(ctx context.Context, age int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.agedOn(isTrainer, age), nil
+}
+
+func (m *Memory) GetTrainersBySalary(ctx context.Context, salary int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.withIntAttr(isTrainer, attrTrainerSalary, salary), nil
+}
+
+func (m *Memory) GetTrainersBySpecialization(ctx context.Context, specialization string) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.personsWhere(func(id int) bool {
+		stored, ok := m.stringAttrs[personAttr{id, attrSpecialization}]
+		return ok && stored == specialization && m.hasRole(id, isTrainer)
+	}), nil
+}
+
+// GetTrainersByWorkout returns the trainers holding workouts between the
+// dates for a group with the given number in any section.
+func (m *Memory) GetTrainersByWorkout(ctx context.Context, groupNum int, fromDate string, toDate string) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	from, err := parseDate(fromDate)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseDate(toDate)
+	if err != nil {
+		return nil, err
+	}
+	trainers := make(map[int]bool)
+	for _, workout := range m.workouts {
+		if workout.Date.Time.Before(from) || workout.Date.Time.After(to) {
+			continue
+		}
+		description, ok := m.descriptions[int(workout.Description)]
+		if !ok {
+			continue
+		}
+		for _, group := range description.groups {
+			if g, ok := m.groups[group]; ok && int(g.GroupNumber) == groupNum {
+				trainers[description.trainer] = true
+			}
+		}
+	}
+	return m.personsWhere(func(id int) bool {
+		return trainers[id]
+	}), nil
+}
+
+func (m *Memory) GetAllManagers(ctx context.Context) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.withRole(isManager), nil
+}
+
+func (m *Memory) GetManagersBySalary(ctx context.Context, salary int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.withIntAttr(isManager, attrManagerSalary, salary), nil
+}
+
+func (m *Memory) GetManagersBySex(ctx context.Context, sex int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.withIntAttr(isManager, attrSex, sex), nil
+}
+
+func (m *Memory) GetManagersByBirthYear(ctx context.Context, year int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inYear(isManager, attrBirthDate, year), nil
+}
+
+func (m *Memory) GetManagersByAge(ctx context.Context, age int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.agedOn(isManager, age), nil
+}
+
+func (m *Memory) GetManagersByBeginYear(ctx context.Context, year int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inYear(isManager, attrManagerSince, year), nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"db_backend/model"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+)
+
+// enrolledTours returns the tours with an enrolled participant matching keep.
+func (m *Memory) enrolledTours(keep func(p participant, tour model.Tour) bool) []model.Tour {
+	seen := make(map[int]bool)
+	var tours []model.Tour
+	for _, p := range m.participants {
+		tour, ok := m.tours[p.tour]
+		if !ok || !enrolled(p) || seen[p.tour] || !keep(p, tour) {
+			continue
+		}
+		seen[p.tour] = true
+		tours = append(tours, tour)
+	}
+	return tours
+}
+
+// routesOf returns the routes of the tours in order.
+func (m *Memory) routesOf(tours []model.Tour) []model.RouteId {
+	return m.routesWhere(func(id int) bool {
+		return slices.ContainsFunc(tours, func(tour model.Tour) bool {
+			return int(tour.Route) == id
+		})
+	})
+}
+
+func (m *Memory) GetAllRouteIds(ctx context.Context) ([]model.RouteId, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.routesWhere(func(int) bool {
+		return true
+	}), nil
+}
+
+func (m *Memory) GetRoutesBySection(ctx context.Context, section int) ([]model.RouteId, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.routesOf(m.enrolledTours(func(p participant, tour model.Tour) bool {
+		return slices.ContainsFunc(m.memberships, func(member membership) bool {
+			return member.person == p.person && member.section == section && isTourist(member.role)
+		})
+	})), nil
+}
+
+// GetRoutesByTime keeps the condition of the query it stands for as is.
+func (m *Memory) GetRoutesByTime(ctx context.Context, fromDate string, toDate string) ([]model.RouteId, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	from, err := parseDate(fromDate)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseDate(toDate)
+	if err != nil {
+		return nil, err
+	}
+	return m.routesOf(m.enrolledTours(func(p participant, tour model.Tour) bool {
+		if !tour.Start.Valid {
+			return false
+		}
+		sinceFrom := daysBetween(tour.Start.Time, from)
+		sinceTo := daysBetween(tour.Start.Time, to)
+		duration := int(tour.DurationDays)
+		return sinceTo >= duration && (sinceFrom <= duration || sinceTo >= 0 && sinceFrom <= 0)
+	})), nil
+}
+
+func (m *Memory) GetRoutesByInstructor(ctx context.Context, instructor int) ([]model.RouteId, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.routesOf(m.enrolledTours(func(p participant, tour model.Tour) bool {
+		return int(tour.Instructor) == instructor
+	})), nil
+}
+
+// GetRoutesByCntGroups counts the tours of each route someone is enrolled in.
+func (m *Memory) GetRoutesByCntGroups(ctx context.Context, cntGroups int) ([]model.RouteId, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts := make(map[int]int)
+	for _, tour := range m.enrolledTours(func(participant, model.Tour) bool {
+		return true
+	}) {
+		counts[int(tour.Route)]++
+	}
+	return m.routesWhere(func(id int) bool {
+		return counts[id] > 0 && counts[id] >= cntGroups
+	}), nil
+}
+
+func (m *Memory) GetRoutesByPlace(ctx context.Context, place int) ([]model.RouteId, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.routesWhere(func(id int) bool {
+		return m.routeHasPlace(id, place)
+	}), nil
+}
+
+func (m *Memory) GetRoutesByLength(ctx context.Context, length int) ([]model.RouteId, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.routesWhere(func(id int) bool {
+		route := m.routes[id]
+		return route.LengthKm.Valid && route.LengthKm.Float64 >= float64(length)
+	}), nil
+}
+
+func (m *Memory) GetRoutesByDifficulty(ctx context.Context, difficulty int) ([]model.RouteId, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.routesWhere(func(id int) bool {
+		route := m.routes[id]
+		return route.Difficulty.Valid && int(route.Difficulty.Int32) >= difficulty
+	}), nil
+}
+
+func (m *Memory) GetAllRouteTypes(ctx context.Context) ([]model.RouteType, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var types []model.RouteType
+	for _, id := range sortedKeys(m.routeTypes) {
+		types = append(types, m.routeTypes[id])
+	}
+	return types, nil
+}
+
+// GetRouteRatings averages all reviews of each route regardless of season.
+func (m *Memory) GetRouteRatings(ctx context.Context, routes []int32) ([]model.RouteRating, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ratings []model.RouteRating
+	for _, id := range sortedKeys(m.routes) {
+		if !slices.Contains(routes, int32(id)) {
+			continue
+		}
+		rating := model.RouteRating{Route: int32(id), OfficialDifficulty: m.routes[id].Difficulty}
+		var ratingSum, perceivedSum int
+		for _, review := range m.reviews {
+			if review.route == id {
+				rating.Reviews++
+				ratingSum += review.rating
+				perceivedSum += review.perceived
+			}
+		}
+		if rating.Reviews > 0 {
+			rating.AvgRating = pgtype.Float8{Float64: float64(ratingSum) / float64(rating.Reviews), Valid: true}
+			rating.AvgPerceived = pgtype.Float8{Float64: float64(perceivedSum) / float64(rating.Reviews), Valid: true}
+		}
+		ratings = append(ratings, rating)
+	}
+	return ratings, nil
+}
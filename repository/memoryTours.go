@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"db_backend/model"
+	"slices"
+)
+
+// participatesIn tells whether the person has a participation on a tour
+// matching keep.
+func (m *Memory) participatesIn(person int, keep func(p participant, tour model.Tour) bool) bool {
+	for _, p := range m.participants {
+		if p.person != person {
+			continue
+		}
+		if tour, ok := m.tours[p.tour]; ok && keep(p, tour) {
+			return true
+		}
+	}
+	return false
+}
+
+func enrolled(p participant) bool {
+	return p.status == model.EnrollmentEnrolled
+}
+
+func completed(p participant) bool {
+	return p.outcome == model.OutcomeCompleted
+}
+
+func (m *Memory) GetTouristsByToursCount(ctx context.Context, cntTours int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.personsWhere(func(id int) bool {
+		count := 0
+		for _, p := range m.participants {
+			if p.person == id && completed(p) {
+				count++
+			}
+		}
+		return count > 0 && count >= cntTours
+	}), nil
+}
+
+func (m *Memory) GetTouristsByTour(ctx context.Context, tour int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.personsWhere(func(id int) bool {
+		return m.participatesIn(id, func(p participant, t model.Tour) bool {
+			return enrolled(p) && p.tour == tour
+		})
+	}), nil
+}
+
+// GetTouristsByTourTime returns those enrolled in a tour going on at the date.
+func (m *Memory) GetTouristsByTourTime(ctx context.Context, date string) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	day, err := parseDate(date)
+	if err != nil {
+		return nil, err
+	}
+	return m.personsWhere(func(id int) bool {
+		return m.participatesIn(id, func(p participant, t model.Tour) bool {
+			since := daysBetween(t.Start.Time, day)
+			return enrolled(p) && t.Start.Valid && since >= 0 && since < int(t.DurationDays)
+		})
+	}), nil
+}
+
+func (m *Memory) GetTouristsByTourRoute(ctx context.Context, route int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.personsWhere(func(id int) bool {
+		return m.participatesIn(id, func(p participant, t model.Tour) bool {
+			return enrolled(p) && int(t.Route) == route
+		})
+	}), nil
+}
+
+func (m *Memory) GetTouristsByTourPlace(ctx context.Context, place int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.personsWhere(func(id int) bool {
+		return m.participatesIn(id, func(p participant, t model.Tour) bool {
+			return enrolled(p) && m.routeHasPlace(int(t.Route), place)
+		})
+	}), nil
+}
+
+// GetTouristsWithTrainerInstructor returns those enrolled in a tour led by a
+// trainer who holds workouts for one of their groups.
+func (m *Memory) GetTouristsWithTrainerInstructor(ctx context.Context) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.personsWhere(func(id int) bool {
+		if !m.hasRole(id, anyRole) {
+			return false
+		}
+		return m.participatesIn(id, func(p participant, t model.Tour) bool {
+			return enrolled(p) && m.trainsMember(int(t.Instructor), id)
+		})
+	}), nil
+}
+
+func (m *Memory) trainsMember(trainer int, person int) bool {
+	for _, description := range m.descriptions {
+		if description.trainer != trainer {
+			continue
+		}
+		for _, group := range description.groups {
+			if slices.Contains(m.groupMembers, groupMember{group: group, person: person}) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetTouristsCompletedAll returns those who completed every route, which takes
+// at least one.
+func (m *Memory) GetTouristsCompletedAll(ctx context.Context) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.personsWhere(func(id int) bool {
+		if !m.hasRole(id, anyRole) {
+			return false
+		}
+		routes := make(map[int32]bool)
+		for _, p := range m.participants {
+			if tour, ok := m.tours[p.tour]; ok && p.person == id && completed(p) {
+				routes[tour.Route] = true
+			}
+		}
+		return len(routes) > 0 && len(routes) == len(m.routes)
+	}), nil
+}
+
+func (m *Memory) GetTouristsCompletedRoute(ctx context.Context, route int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.personsWhere(func(id int) bool {
+		return m.participatesIn(id, func(p participant, t model.Tour) bool {
+			return completed(p) && int(t.Route) == route
+		})
+	}), nil
+}
+
+// instructorsWhere returns the persons leading a tour matching keep.
+func (m *Memory) instructorsWhere(keep func(tour model.Tour) bool) []model.Person {
+	return m.personsWhere(func(id int) bool {
+		for _, tour := range m.tours {
+			if int(tour.Instructor) == id && keep(tour) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (m *Memory) GetAllInstructors(ctx context.Context) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.instructorsWhere(func(model.Tour) bool {
+		return true
+	}), nil
+}
+
+func (m *Memory) GetInstructorsByRole(ctx context.Context, role int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.personsWhere(func(id int) bool {
+		return m.instructs(id) && m.hasRole(id, func(r int) bool {
+			return r == role
+		})
+	}), nil
+}
+
+// GetInstructorsByCategory returns the instructors who completed a route of
+// the type at least as difficult as asked.
+func (m *Memory) GetInstructorsByCategory(ctx context.Context, routeType int, difficulty int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.personsWhere(func(id int) bool {
+		if !m.instructs(id) || !m.hasRole(id, anyRole) {
+			return false
+		}
+		return m.participatesIn(id, func(p participant, t model.Tour) bool {
+			route, ok := m.routes[int(t.Route)]
+			return ok && completed(p) && route.TypeId.Valid && int(route.TypeId.Int32) == routeType &&
+				route.Difficulty.Valid && int(route.Difficulty.Int32) >= difficulty
+		})
+	}), nil
+}
+
+func (m *Memory) GetInstructorsByCntTours(ctx context.Context, cntTours int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.personsWhere(func(id int) bool {
+		count := 0
+		for _, tour := range m.tours {
+			if int(tour.Instructor) == id {
+				count++
+			}
+		}
+		return count > 0 && count >= cntTours
+	}), nil
+}
+
+func (m *Memory) GetInstructorsByTour(ctx context.Context, route int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.instructorsWhere(func(tour model.Tour) bool {
+		return int(tour.Route) == route
+	}), nil
+}
+
+func (m *Memory) GetInstructorsByPlace(ctx context.Context, place int) ([]model.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.instructorsWhere(func(tour model.Tour) bool {
+		return m.routeHasPlace(int(tour.Route), place)
+	}), nil
+}
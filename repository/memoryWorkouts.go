@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+	"strconv"
+	"time"
+)
+
+func duration(session model.WorkoutSession) int64 {
+	return session.FinishTime.Microseconds - session.StartTime.Microseconds
+}
+
+// workoutsBetween returns the sessions dated between the dates inclusive.
+func (m *Memory) workoutsBetween(fromDate string, toDate string) ([]model.WorkoutSession, error) {
+	from, err := parseDate(fromDate)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseDate(toDate)
+	if err != nil {
+		return nil, err
+	}
+	var sessions []model.WorkoutSession
+	for _, id := range sortedKeys(m.workouts) {
+		session := m.workouts[id]
+		if session.Date.Valid && !session.Date.Time.Before(from) && !session.Date.Time.After(to) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *Memory) GetStrainForTrainer(ctx context.Context, trainer int, fromDate string, toDate string) ([]model.Strain, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sessions, err := m.workoutsBetween(fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := m.persons[trainer]; !ok {
+		return nil, nil
+	}
+	sums := make(map[string]int64)
+	for _, session := range sessions {
+		description, ok := m.descriptions[int(session.Description)]
+		if !ok || description.trainer != trainer || description.workoutType == "" {
+			continue
+		}
+		sums[description.workoutType] += duration(session)
+	}
+	var strain []model.Strain
+	for workoutType, sum := range sums {
+		strain = append(strain, model.Strain{Type: workoutType, Duration: pgtype.Interval{Microseconds: sum, Valid: true}})
+	}
+	slices.SortFunc(strain, func(a, b model.Strain) int {
+		return cmp.Compare(a.Type, b.Type)
+	})
+	return strain, nil
+}
+
+func truncate(date time.Time, bucket string) (time.Time, error) {
+	switch bucket {
+	case "week":
+		return date.AddDate(0, 0, -(int(date.Weekday())+6)%7), nil
+	case "month":
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown strain bucket %q", bucket)
+}
+
+type strainKey struct {
+	key    int32
+	label  string
+	bucket time.Time
+}
+
+// GetStrainBuckets counts a session once per distinct key it falls under, the
+// way the query deduplicates its rows before summing.
+func (m *Memory) GetStrainBuckets(ctx context.Context, groupBy string, bucket string, fromDate string, toDate string,
+	section pgtype.Int4, trainer pgtype.Int4) ([]model.StrainBucket, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sessions, err := m.workoutsBetween(fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	sums := make(map[strainKey]int64)
+	for _, session := range sessions {
+		description, ok := m.descriptions[int(session.Description)]
+		if !ok {
+			continue
+		}
+		person, ok := m.persons[description.trainer]
+		if !ok || trainer.Valid && description.trainer != int(trainer.Int32) {
+			continue
+		}
+		day, err := truncate(session.Date.Time, bucket)
+		if err != nil {
+			return nil, err
+		}
+		// a description without groups still joins once with empty group columns
+		groups := []*model.Group{nil}
+		if len(description.groups) > 0 {
+			groups = nil
+			for _, id := range description.groups {
+				if group, ok := m.groups[id]; ok {
+					groups = append(groups, &group)
+				} else {
+					groups = append(groups, nil)
+				}
+			}
+		}
+		seen := make(map[strainKey]bool)
+		for _, group := range groups {
+			if section.Valid && (group == nil || group.Section != section.Int32) {
+				continue
+			}
+			key := strainKey{bucket: day}
+			switch groupBy {
+			case "trainer":
+				key.key, key.label = int32(description.trainer), person.Surname+" "+person.Name
+			case "group":
+				if group != nil {
+					key.key, key.label = group.Id, strconv.Itoa(int(group.GroupNumber))
+				}
+			case "section":
+				if group != nil {
+					if s, ok := m.sections[int(group.Section)]; ok {
+						key.key, key.label = s.Id, s.Title
+					}
+				}
+			case "type":
+				key.label = description.workoutType
+			default:
+				return nil, fmt.Errorf("unknown strain grouping %q", groupBy)
+			}
+			if !seen[key] {
+				seen[key] = true
+				sums[key] += duration(session)
+			}
+		}
+	}
+	var buckets []model.StrainBucket
+	for key, sum := range sums {
+		buckets = append(buckets, model.StrainBucket{
+			Key:      key.key,
+			Label:    key.label,
+			Bucket:   pgtype.Date{Time: key.bucket, Valid: true},
+			Duration: pgtype.Interval{Microseconds: sum, Valid: true},
+		})
+	}
+	slices.SortFunc(buckets, func(a, b model.StrainBucket) int {
+		return cmp.Or(cmp.Compare(a.Label, b.Label), cmp.Compare(a.Key, b.Key), a.Bucket.Time.Compare(b.Bucket.Time))
+	})
+	return buckets, nil
+}
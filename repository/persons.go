@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/model"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Persons covers persons, their roles in sections and the filters over
+// tourists, trainers and managers.
+type Persons interface {
+	InsertPerson(ctx context.Context, person model.Person) (int, error)
+	GetPerson(ctx context.Context, id int) (*model.Person, error)
+	GetPersonRole(ctx context.Context, person int, section int) (pgtype.Int4, error)
+	UpdatePersonRole(ctx context.Context, person int, section int, role int) error
+	DeletePersonRole(ctx context.Context, person int, section int) error
+
+	GetAllTourists(ctx context.Context) ([]model.Person, error)
+	GetTouristsBySection(ctx context.Context, section int) ([]model.Person, error)
+	GetTouristsByGroup(ctx context.Context, group int) ([]model.Person, error)
+	GetTouristsBySex(ctx context.Context, sex int) ([]model.Person, error)
+	GetTouristsByBirthYear(ctx context.Context, year int) ([]model.Person, error)
+	GetTouristsByAge(ctx context.Context, age int) ([]model.Person, error)
+	GetTouristsByFitness(ctx context.Context, test int, value float64, since string) ([]model.Person, error)
+
+	GetAllTrainers(ctx context.Context) ([]model.Person, error)
+	GetTrainersBySection(ctx context.Context, section int) ([]model.Person, error)
+	GetTrainersBySex(ctx context.Context, sex int) ([]model.Person, error)
+	GetTrainersByAge(ctx context.Context, age int) ([]model.Person, error)
+	GetTrainersBySalary(ctx context.Context, salary int) ([]model.Person, error)
+	GetTrainersBySpecialization(ctx context.Context, specialization string) ([]model.Person, error)
+	GetTrainersByWorkout(ctx context.Context, groupNum int, fromDate string, toDate string) ([]model.Person, error)
+
+	GetAllManagers(ctx context.Context) ([]model.Person, error)
+	GetManagersBySalary(ctx context.Context, salary int) ([]model.Person, error)
+	GetManagersBySex(ctx context.Context, sex int) ([]model.Person, error)
+	GetManagersByBirthYear(ctx context.Context, year int) ([]model.Person, error)
+	GetManagersByAge(ctx context.Context, age int) ([]model.Person, error)
+	GetManagersByBeginYear(ctx context.Context, year int) ([]model.Person, error)
+}
+
+type postgresPersons struct {
+	pg *db.Postgres
+}
+
+func (r *postgresPersons) InsertPerson(ctx context.Context, person model.Person) (int, error) {
+	return dbqueries.InsertPerson(r.pg, ctx, person)
+}
+
+func (r *postgresPersons) GetPerson(ctx context.Context, id int) (*model.Person, error) {
+	return dbqueries.GetPerson(r.pg, ctx, id)
+}
+
+func (r *postgresPersons) GetPersonRole(ctx context.Context, person int, section int) (pgtype.Int4, error) {
+	return dbqueries.GetPersonRole(r.pg, ctx, person, section)
+}
+
+func (r *postgresPersons) UpdatePersonRole(ctx context.Context, person int, section int, role int) error {
+	return dbqueries.UpdatePersonRole(r.pg, ctx, person, section, role)
+}
+
+func (r *postgresPersons) DeletePersonRole(ctx context.Context, person int, section int) error {
+	return dbqueries.DeletePersonRole(r.pg, ctx, person, section)
+}
+
+func (r *postgresPersons) GetAllTourists(ctx context.Context) ([]model.Person, error) {
+	return dbqueries.GetAllTourists(r.pg, ctx)
+}
+
+func (r *postgresPersons) GetTouristsBySection(ctx context.Context, section int) ([]model.Person, error) {
+	return dbqueries.GetTouristsBySection(r.pg, ctx, section)
+}
+
+func (r *postgresPersons) GetTouristsByGroup(ctx context.Context, group int) ([]model.Person, error) {
+	return dbqueries.GetTouristsByGroup(r.pg, ctx, group)
+}
+
+func (r *postgresPersons) GetTouristsBySex(ctx context.Context, sex int) ([]model.Person, error) {
+	return dbqueries.GetTouristsBySex(r.pg, ctx, sex)
+}
+
+func (r *postgresPersons) GetTouristsByBirthYear(ctx context.Context, year int) ([]model.Person, error) {
+	return dbqueries.GetTouristsByBirthYear(r.pg, ctx, year)
+}
+
+func (r *postgresPersons) GetTouristsByAge(ctx context.Context, age int) ([]model.Person, error) {
+	return dbqueries.GetTouristsByAge(r.pg, ctx, age)
+}
+
+func (r *postgresPersons) GetTouristsByFitness(ctx context.Context, test int, value float64, since string) ([]model.Person, error) {
+	return dbqueries.GetTouristsByFitness(r.pg, ctx, test, value, since)
+}
+
+func (r *postgresPersons) GetAllTrainers(ctx context.Context) ([]model.Person, error) {
+	return dbqueries.GetAllTrainers(r.pg, ctx)
+}
+
+func (r *postgresPersons) GetTrainersBySection(ctx context.Context, section int) ([]model.Person, error) {
+	return dbqueries.GetTrainersBySection(r.pg, ctx, section)
+}
+
+func (r *postgresPersons) GetTrainersBySex(ctx context.Context, sex int) ([]model.Person, error) {
+	return dbqueries.GetTrainersBySex(r.pg, ctx, sex)
+}
+
+func (r *postgresPersons) GetTrainersByAge(ctx context.Context, age int) ([]model.Person, error) {
+	return dbqueries.GetTrainersByAge(r.pg, ctx, age)
+}
+
+func (r *postgresPersons) GetTrainersBySalary(ctx context.Context, salary int) ([]model.Person, error) {
+	return dbqueries.GetTrainersBySalary(r.pg, ctx, salary)
+}
+
+func (r *postgresPersons) GetTrainersBySpecialization(ctx context.Context, specialization string) ([]model.Person, error) {
+	return dbqueries.GetTrainersBySpecialization(r.pg, ctx, specialization)
+}
+
+func (r *postgresPersons) GetTrainersByWorkout(ctx context.Context, groupNum int, fromDate string, toDate string) ([]model.Person, error) {
+	return dbqueries.GetTrainersByWorkout(r.pg, ctx, groupNum, fromDate, toDate)
+}
+
+func (r *postgresPersons) GetAllManagers(ctx context.Context) ([]model.Person, error) {
+	return dbqueries.GetAllManagers(r.pg, ctx)
+}
+
+func (r *postgresPersons) GetManagersBySalary(ctx context.Context, salary int) ([]model.Person, error) {
+	return dbqueries.GetManagersBySalary(r.pg, ctx, salary)
+}
+
+func (r *postgresPersons) GetManagersBySex(ctx context.Context, sex int) ([]model.Person, error) {
+	return dbqueries.GetManagersBySex(r.pg, ctx, sex)
+}
+
+func (r *postgresPersons) GetManagersByBirthYear(ctx context.Context, year int) ([]model.Person, error) {
+	return dbqueries.GetManagersByBirthYear(r.pg, ctx, year)
+}
+
+func (r *postgresPersons) GetManagersByAge(ctx context.Context, age int) ([]model.Person, error) {
+	return dbqueries.GetManagersByAge(r.pg, ctx, age)
+}
+
+func (r *postgresPersons) GetManagersByBeginYear(ctx context.Context, year int) ([]model.Person, error) {
+	return dbqueries.GetManagersByBeginYear(r.pg, ctx, year)
+}
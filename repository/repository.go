@@ -0,0 +1,30 @@
+package repository
+
+import "db_backend/db"
+
+// Repositories bundles the stores the services read and write through, one
+// per aggregate.
+type Repositories struct {
+	Persons       Persons
+	Attributes    Attributes
+	Groups        Groups
+	Tours         Tours
+	Routes        Routes
+	Workouts      Workouts
+	Championships Championships
+	Fees          Fees
+}
+
+// NewPostgres returns repositories running the dbqueries against pg.
+func NewPostgres(pg *db.Postgres) *Repositories {
+	return &Repositories{
+		Persons:       &postgresPersons{pg},
+		Attributes:    &postgresAttributes{pg},
+		Groups:        &postgresGroups{pg},
+		Tours:         &postgresTours{pg},
+		Routes:        &postgresRoutes{pg},
+		Workouts:      &postgresWorkouts{pg},
+		Championships: &postgresChampionships{pg},
+		Fees:          &postgresFees{pg},
+	}
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/model"
+)
+
+// Routes covers routes, their types and ratings and the filters over them.
+type Routes interface {
+	GetAllRouteIds(ctx context.Context) ([]model.RouteId, error)
+	GetRoutesBySection(ctx context.Context, section int) ([]model.RouteId, error)
+	GetRoutesByTime(ctx context.Context, fromDate string, toDate string) ([]model.RouteId, error)
+	GetRoutesByInstructor(ctx context.Context, instructor int) ([]model.RouteId, error)
+	GetRoutesByCntGroups(ctx context.Context, cntGroups int) ([]model.RouteId, error)
+	GetRoutesByPlace(ctx context.Context, place int) ([]model.RouteId, error)
+	GetRoutesByLength(ctx context.Context, length int) ([]model.RouteId, error)
+	GetRoutesByDifficulty(ctx context.Context, difficulty int) ([]model.RouteId, error)
+	GetAllRouteTypes(ctx context.Context) ([]model.RouteType, error)
+	GetRouteRatings(ctx context.Context, routes []int32) ([]model.RouteRating, error)
+}
+
+type postgresRoutes struct {
+	pg *db.Postgres
+}
+
+func (r *postgresRoutes) GetAllRouteIds(ctx context.Context) ([]model.RouteId, error) {
+	return dbqueries.GetAllRouteIds(r.pg, ctx)
+}
+
+func (r *postgresRoutes) GetRoutesBySection(ctx context.Context, section int) ([]model.RouteId, error) {
+	return dbqueries.GetRoutesBySection(r.pg, ctx, section)
+}
+
+func (r *postgresRoutes) GetRoutesByTime(ctx context.Context, fromDate string, toDate string) ([]model.RouteId, error) {
+	return dbqueries.GetRoutesByTime(r.pg, ctx, fromDate, toDate)
+}
+
+func (r *postgresRoutes) GetRoutesByInstructor(ctx context.Context, instructor int) ([]model.RouteId, error) {
+	return dbqueries.GetRoutesByInstructor(r.pg, ctx, instructor)
+}
+
+func (r *postgresRoutes) GetRoutesByCntGroups(ctx context.Context, cntGroups int) ([]model.RouteId, error) {
+	return dbqueries.GetRoutesByCntGroups(r.pg, ctx, cntGroups)
+}
+
+func (r *postgresRoutes) GetRoutesByPlace(ctx context.Context, place int) ([]model.RouteId, error) {
+	return dbqueries.GetRoutesByPlace(r.pg, ctx, place)
+}
+
+func (r *postgresRoutes) GetRoutesByLength(ctx context.Context, length int) ([]model.RouteId, error) {
+	return dbqueries.GetRoutesByLength(r.pg, ctx, length)
+}
+
+func (r *postgresRoutes) GetRoutesByDifficulty(ctx context.Context, difficulty int) ([]model.RouteId, error) {
+	return dbqueries.GetRoutesByDifficulty(r.pg, ctx, difficulty)
+}
+
+func (r *postgresRoutes) GetAllRouteTypes(ctx context.Context) ([]model.RouteType, error) {
+	return dbqueries.GetAllRouteTypes(r.pg, ctx)
+}
+
+func (r *postgresRoutes) GetRouteRatings(ctx context.Context, routes []int32) ([]model.RouteRating, error) {
+	return dbqueries.GetRouteRatings(r.pg, ctx, routes)
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/model"
+)
+
+// Tours covers the filters over tour participants and instructors.
+type Tours interface {
+	GetTouristsByToursCount(ctx context.Context, cntTours int) ([]model.Person, error)
+	GetTouristsByTour(ctx context.Context, tour int) ([]model.Person, error)
+	GetTouristsByTourTime(ctx context.Context, date string) ([]model.Person, error)
+	GetTouristsByTourRoute(ctx context.Context, route int) ([]model.Person, error)
+	GetTouristsByTourPlace(ctx context.Context, place int) ([]model.Person, error)
+	GetTouristsWithTrainerInstructor(ctx context.Context) ([]model.Person, error)
+	GetTouristsCompletedAll(ctx context.Context) ([]model.Person, error)
+	GetTouristsCompletedRoute(ctx context.Context, route int) ([]model.Person, error)
+
+	GetAllInstructors(ctx context.Context) ([]model.Person, error)
+	GetInstructorsByRole(ctx context.Context, role int) ([]model.Person, error)
+	GetInstructorsByCategory(ctx context.Context, routeType int, difficulty int) ([]model.Person, error)
+	GetInstructorsByCntTours(ctx context.Context, cntTours int) ([]model.Person, error)
+	GetInstructorsByTour(ctx context.Context, route int) ([]model.Person, error)
+	GetInstructorsByPlace(ctx context.Context, place int) ([]model.Person, error)
+}
+
+type postgresTours struct {
+	pg *db.Postgres
+}
+
+func (r *postgresTours) GetTouristsByToursCount(ctx context.Context, cntTours int) ([]model.Person, error) {
+	return dbqueries.GetTouristsByToursCount(r.pg, ctx, cntTours)
+}
+
+func (r *postgresTours) GetTouristsByTour(ctx context.Context, tour int) ([]model.Person, error) {
+	return dbqueries.GetTouristsByTour(r.pg, ctx, tour)
+}
+
+func (r *postgresTours) GetTouristsByTourTime(ctx context.Context, date string) ([]model.Person, error) {
+	return dbqueries.GetTouristsByTourTime(r.pg, ctx, date)
+}
+
+func (r *postgresTours) GetTouristsByTourRoute(ctx context.Context, route int) ([]model.Person, error) {
+	return dbqueries.GetTouristsByTourRoute(r.pg, ctx, route)
+}
+
+func (r *postgresTours) GetTouristsByTourPlace(ctx context.Context, place int) ([]model.Person, error) {
+	return dbqueries.GetTouristsByTourPlace(r.pg, ctx, place)
+}
+
+func (r *postgresTours) GetTouristsWithTrainerInstructor(ctx context.Context) ([]model.Person, error) {
+	return dbqueries.GetTouristsWithTrainerInstructor(r.pg, ctx)
+}
+
+func (r *postgresTours) GetTouristsCompletedAll(ctx context.Context) ([]model.Person, error) {
+	return dbqueries.GetTouristsCompletedAll(r.pg, ctx)
+}
+
+func (r *postgresTours) GetTouristsCompletedRoute(ctx context.Context, route int) ([]model.Person, error) {
+	return dbqueries.GetTouristsCompletedRoute(r.pg, ctx, route)
+}
+
+func (r *postgresTours) GetAllInstructors(ctx context.Context) ([]model.Person, error) {
+	return dbqueries.GetAllInstructors(r.pg, ctx)
+}
+
+func (r *postgresTours) GetInstructorsByRole(ctx context.Context, role int) ([]model.Person, error) {
+	return dbqueries.GetInstructorsByRole(r.pg, ctx, role)
+}
+
+func (r *postgresTours) GetInstructorsByCategory(ctx context.Context, routeType int, difficulty int) ([]model.Person, error) {
+	return dbqueries.GetInstructorsByCategory(r.pg, ctx, routeType, difficulty)
+}
+
+func (r *postgresTours) GetInstructorsByCntTours(ctx context.Context, cntTours int) ([]model.Person, error) {
+	return dbqueries.GetInstructorsByCntTours(r.pg, ctx, cntTours)
+}
+
+func (r *postgresTours) GetInstructorsByTour(ctx context.Context, route int) ([]model.Person, error) {
+	return dbqueries.GetInstructorsByTour(r.pg, ctx, route)
+}
+
+func (r *postgresTours) GetInstructorsByPlace(ctx context.Context, place int) ([]model.Person, error) {
+	return dbqueries.GetInstructorsByPlace(r.pg, ctx, place)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/model"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Workouts covers the training load of workouts.
+type Workouts interface {
+	GetStrainForTrainer(ctx context.Context, trainer int, fromDate string, toDate string) ([]model.Strain, error)
+	GetStrainBuckets(ctx context.Context, groupBy string, bucket string, fromDate string, toDate string,
+		section pgtype.Int4, trainer pgtype.Int4) ([]model.StrainBucket, error)
+}
+
+type postgresWorkouts struct {
+	pg *db.Postgres
+}
+
+func (r *postgresWorkouts) GetStrainForTrainer(ctx context.Context, trainer int, fromDate string, toDate string) ([]model.Strain, error) {
+	return dbqueries.GetStrainForTrainer(r.pg, ctx, trainer, fromDate, toDate)
+}
+
+func (r *postgresWorkouts) GetStrainBuckets(ctx context.Context, groupBy string, bucket string, fromDate string, toDate string,
+	section pgtype.Int4, trainer pgtype.Int4) ([]model.StrainBucket, error) {
+	return dbqueries.GetStrainBuckets(r.pg, ctx, groupBy, bucket, fromDate, toDate, section, trainer)
+}
@@ -2,23 +2,19 @@ package services
 
 import (
 	"context"
-	"db_backend/db"
-	"db_backend/dbqueries"
 	"db_backend/dto"
+	"fmt"
+	"strconv"
+	"time"
 )
 
-func GetChampionshipsWithCondition(section string) (*dto.ChampionshipsListResponse, error) {
-	pg, err := db.NewPG(context.Background())
+func (c *Club) GetChampionshipsWithCondition(section string) (*dto.ChampionshipsListResponse, error) {
+	result, err := c.repos.Championships.GetAllChampionships(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := dbqueries.GetAllChampionships(pg, context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	result, err = checkParameter(pg, section, dbqueries.GetAllChampionshipsBySection, result)
+	result, err = checkParameter(section, c.repos.Championships.GetAllChampionshipsBySection, result)
 	if err != nil {
 		return nil, err
 	}
@@ -39,3 +35,45 @@ func GetChampionshipsWithCondition(section string) (*dto.ChampionshipsListRespon
 
 	return &response, nil
 }
+
+func (c *Club) RegisterForChampionship(championship string, person string) error {
+	championshipInt, err := strconv.Atoi(championship)
+	if err != nil {
+		return err
+	}
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return err
+	}
+
+	championshipModel, err := c.repos.Championships.GetChampionship(context.Background(), championshipInt)
+	if err != nil {
+		return err
+	}
+	if championshipModel == nil {
+		return fmt.Errorf("championship %d not found", championshipInt)
+	}
+	if championshipModel.Date.Time.Before(time.Now().Truncate(24 * time.Hour)) {
+		return fmt.Errorf("championship %d has already taken place", championshipInt)
+	}
+
+	err = c.checkDues(context.Background(), personInt)
+	if err != nil {
+		return err
+	}
+
+	return c.repos.Championships.RegisterForChampionship(context.Background(), championshipInt, personInt)
+}
+
+func (c *Club) UnregisterFromChampionship(championship string, person string) error {
+	championshipInt, err := strconv.Atoi(championship)
+	if err != nil {
+		return err
+	}
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return err
+	}
+
+	return c.repos.Championships.UnregisterFromChampionship(context.Background(), championshipInt, personInt)
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"db_backend/dto"
+	"db_backend/model"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+	"testing"
+	"time"
+)
+
+func championshipIds(t *testing.T, response *dto.ChampionshipsListResponse, err error) []int32 {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := []int32{}
+	for _, championship := range response.Championships {
+		result = append(result, championship.Id)
+	}
+	slices.Sort(result)
+	return result
+}
+
+func TestGetChampionshipsWithCondition(t *testing.T) {
+	t.Parallel()
+	c := useMemory(t)
+	m := c.memory
+	cup := m.AddChampionship(model.Championship{Title: "Кубок города", Date: date("2025-08-10")})
+	open := m.AddChampionship(model.Championship{Title: "Открытое первенство", Date: date("2025-08-20")})
+	autumn := m.AddChampionship(model.Championship{Title: "Осенний кубок", Date: date("2025-10-01")})
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(m.RegisterForChampionship(t.Context(), cup, c.boris))
+	must(m.RegisterForChampionship(t.Context(), open, c.anna))
+	must(m.RegisterForChampionship(t.Context(), autumn, c.boris))
+
+	response, err := c.GetChampionshipsWithCondition("")
+	got := championshipIds(t, response, err)
+	if want := ids(cup); !slices.Equal(got, want) {
+		t.Errorf("got %v, want only the past one with an athlete %v", got, want)
+	}
+
+	response, err = c.GetChampionshipsWithCondition(itoa(c.sectionB))
+	got = championshipIds(t, response, err)
+	if want := ids(); !slices.Equal(got, want) {
+		t.Errorf("section B: got %v, want %v", got, want)
+	}
+
+	must(c.UnregisterFromChampionship(itoa(cup), itoa(c.boris)))
+	response, err = c.GetChampionshipsWithCondition("")
+	got = championshipIds(t, response, err)
+	if want := ids(); !slices.Equal(got, want) {
+		t.Errorf("after unregistering: got %v, want %v", got, want)
+	}
+}
+
+func TestRegisterForChampionship(t *testing.T) {
+	t.Parallel()
+	c := useMemory(t)
+	m := c.memory
+	today := time.Now().Truncate(24 * time.Hour)
+	held := m.AddChampionship(model.Championship{Title: "Летний кубок", Date: pgtype.Date{Time: today.AddDate(0, 0, -7), Valid: true}})
+	coming := m.AddChampionship(model.Championship{Title: "Зимний кубок", Date: pgtype.Date{Time: today.AddDate(0, 0, 7), Valid: true}})
+
+	if err := c.RegisterForChampionship(itoa(held), itoa(c.boris)); err == nil {
+		t.Error("registered for a championship that has already taken place")
+	}
+
+	c.blockDebtors = true
+	m.AddArrear(model.Arrear{
+		Person: model.Person{Id: int32(c.anna)},
+		Fee:    model.MembershipFee{Amount: 150000, DueOn: pgtype.Date{Time: today.AddDate(0, -1, 0), Valid: true}},
+	})
+	if err := c.RegisterForChampionship(itoa(coming), itoa(c.anna)); err == nil {
+		t.Error("registered a person with unpaid fees while debtors are blocked")
+	}
+	if err := c.RegisterForChampionship(itoa(coming), itoa(c.boris)); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Now = func() time.Time {
+		return today.AddDate(0, 0, 8)
+	}
+	response, err := c.GetChampionshipsWithCondition("")
+	got := championshipIds(t, response, err)
+	if want := ids(coming); !slices.Equal(got, want) {
+		t.Errorf("got %v, want the one boris registered for %v", got, want)
+	}
+}
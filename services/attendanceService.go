@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+func person2Response(person model.Person) dto.PersonResponse {
+	var jsonPerson dto.PersonResponse
+	jsonPerson.Id = person.Id
+	jsonPerson.Name = person.Name
+	jsonPerson.Surname = person.Surname
+	jsonPerson.Patronymic = person.Patronymic
+	return jsonPerson
+}
+
+func attendanceStats2Dto(stats model.AttendanceStats) dto.AttendanceStats {
+	var jsonStats dto.AttendanceStats
+	jsonStats.Person = person2Response(stats.Person)
+	jsonStats.Present = stats.Present
+	jsonStats.Absent = stats.Absent
+	jsonStats.Excused = stats.Excused
+	jsonStats.Late = stats.Late
+	jsonStats.Unmarked = stats.Unmarked
+	jsonStats.Rate = stats.Rate()
+	return jsonStats
+}
+
+func dateRange(fromDate string, toDate string) (string, string) {
+	if fromDate == "" {
+		fromDate = "0001-01-01"
+	}
+	if toDate == "" {
+		toDate = "2999-01-01"
+	}
+	return fromDate, toDate
+}
+
+// checkSessionTrainer checks that the workout exists and that the marker, the
+// person the request is authenticated as, is its trainer.
+func checkSessionTrainer(pg *db.Postgres, ctx context.Context, workout int32, trainer int32) (*model.WorkoutSession, error) {
+	session, err := dbqueries.GetWorkoutSession(pg, ctx, int(workout))
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, fmt.Errorf("workout %d not found", workout)
+	}
+	if session.Trainer != trainer {
+		return nil, fmt.Errorf("%w: person %d is not the trainer of workout %d", ErrForbidden, trainer, workout)
+	}
+	return session, nil
+}
+
+func markAll(pg *db.Postgres, ctx context.Context, workout int32, trainer int32, attendees []int32, marks []dto.AttendanceMark) error {
+	for _, mark := range marks {
+		if !model.AttendanceStatuses[mark.Status] {
+			return fmt.Errorf("unknown attendance status %q", mark.Status)
+		}
+		if !slices.Contains(attendees, mark.Person) {
+			return fmt.Errorf("person %d is not in a group scheduled for workout %d", mark.Person, workout)
+		}
+
+		var attendance model.Attendance
+		attendance.Workout = workout
+		attendance.Person = mark.Person
+		attendance.Status = mark.Status
+		attendance.MarkedBy.Int32 = trainer
+		attendance.MarkedBy.Valid = true
+		attendance.Note = mark.Note
+		err := dbqueries.MarkAttendance(pg, ctx, attendance)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func MarkAttendance(viewer string, req dto.AttendanceRequest) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return err
+	}
+	trainer := int32(viewerInt)
+
+	if len(req.Marks) == 0 {
+		return errors.New("no attendance marks given")
+	}
+
+	return pg.InTx(context.Background(), func(tx *db.Postgres) error {
+		ctx := context.Background()
+		_, err := checkSessionTrainer(tx, ctx, req.Workout, trainer)
+		if err != nil {
+			return err
+		}
+		attendees, err := dbqueries.GetWorkoutAttendees(tx, ctx, int(req.Workout), 0)
+		if err != nil {
+			return err
+		}
+		return markAll(tx, ctx, req.Workout, trainer, attendees, req.Marks)
+	})
+}
+
+// MarkGroupAttendance gives every member of the group the same status, with the
+// individual marks of the request taking precedence.
+func MarkGroupAttendance(viewer string, req dto.AttendanceRequest) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return err
+	}
+	trainer := int32(viewerInt)
+
+	if req.Status == "" {
+		req.Status = model.AttendancePresent
+	}
+
+	return pg.InTx(context.Background(), func(tx *db.Postgres) error {
+		ctx := context.Background()
+		_, err := checkSessionTrainer(tx, ctx, req.Workout, trainer)
+		if err != nil {
+			return err
+		}
+		scheduled, err := dbqueries.IsGroupScheduled(tx, ctx, int(req.Workout), int(req.Group))
+		if err != nil {
+			return err
+		}
+		if !scheduled {
+			return fmt.Errorf("group %d is not scheduled for workout %d", req.Group, req.Workout)
+		}
+		attendees, err := dbqueries.GetWorkoutAttendees(tx, ctx, int(req.Workout), int(req.Group))
+		if err != nil {
+			return err
+		}
+
+		overridden := make(map[int32]bool)
+		for _, mark := range req.Marks {
+			overridden[mark.Person] = true
+		}
+		marks := req.Marks
+		for _, person := range attendees {
+			if !overridden[person] {
+				marks = append(marks, dto.AttendanceMark{Person: person, Status: req.Status})
+			}
+		}
+		return markAll(tx, ctx, req.Workout, trainer, attendees, marks)
+	})
+}
+
+func GetWorkoutAttendance(workout string) (*dto.WorkoutAttendanceResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	workoutInt, err := strconv.Atoi(workout)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := dbqueries.GetWorkoutSession(pg, context.Background(), workoutInt)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, fmt.Errorf("workout %d not found", workoutInt)
+	}
+	marks, err := dbqueries.GetWorkoutAttendance(pg, context.Background(), workoutInt)
+	if err != nil {
+		return nil, err
+	}
+	attendees, err := dbqueries.GetWorkoutAttendees(pg, context.Background(), workoutInt, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.WorkoutAttendanceResponse
+	response.Workout = session.Id
+	response.Date = session.Date.Time.Format("2006-01-02")
+	response.Trainer = session.Trainer
+
+	marked := make(map[int32]bool)
+	for _, mark := range marks {
+		var jsonMark dto.Attendance
+		jsonMark.Person = mark.Person
+		jsonMark.Status = mark.Status
+		if mark.MarkedBy.Valid {
+			jsonMark.MarkedBy = &mark.MarkedBy.Int32
+		}
+		jsonMark.MarkedAt = mark.MarkedAt.Time.Format("2006-01-02 15:04:05")
+		jsonMark.Note = mark.Note
+		response.Attendance = append(response.Attendance, jsonMark)
+		marked[mark.Person] = true
+	}
+	for _, person := range attendees {
+		if !marked[person] {
+			response.Unmarked = append(response.Unmarked, person)
+		}
+	}
+	return &response, nil
+}
+
+func GetPersonAttendance(person string, fromDate string, toDate string) (*dto.AttendanceStats, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return nil, err
+	}
+	fromDate, toDate = dateRange(fromDate, toDate)
+
+	stats, err := dbqueries.GetPersonAttendanceStats(pg, context.Background(), personInt, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		personModel, err := dbqueries.GetPerson(pg, context.Background(), personInt)
+		if err != nil {
+			return nil, err
+		}
+		stats = &model.AttendanceStats{Person: *personModel}
+	}
+
+	response := attendanceStats2Dto(*stats)
+	return &response, nil
+}
+
+func GetGroupAttendance(group string, fromDate string, toDate string) (*dto.GroupAttendanceResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	groupInt, err := strconv.Atoi(group)
+	if err != nil {
+		return nil, err
+	}
+	fromDate, toDate = dateRange(fromDate, toDate)
+
+	stats, err := dbqueries.GetGroupAttendanceStats(pg, context.Background(), groupInt, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.GroupAttendanceResponse
+	response.Group = int32(groupInt)
+	response.FromDate = fromDate
+	response.ToDate = toDate
+
+	var total model.AttendanceStats
+	for _, s := range stats {
+		response.Members = append(response.Members, attendanceStats2Dto(s))
+		total.Present += s.Present
+		total.Absent += s.Absent
+		total.Late += s.Late
+		total.Unmarked += s.Unmarked
+	}
+	response.Rate = total.Rate()
+	return &response, nil
+}
+
+// GetLowAttendance lists members of the section's groups whose attendance rate
+// is below threshold, skipping those with fewer than minSessions expected sessions.
+// Sessions a member was never marked at count as missed.
+func GetLowAttendance(section string, fromDate string, toDate string, threshold string, minSessions string) (*dto.LowAttendanceResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	sectionInt, err := strconv.Atoi(section)
+	if err != nil {
+		return nil, err
+	}
+	fromDate, toDate = dateRange(fromDate, toDate)
+
+	thresholdFloat := 0.5
+	if threshold != "" {
+		thresholdFloat, err = strconv.ParseFloat(threshold, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+	minSessionsInt := 3
+	if minSessions != "" {
+		minSessionsInt, err = strconv.Atoi(minSessions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	groups, err := dbqueries.GetGroupsFromSections(pg, context.Background(), sectionInt)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.LowAttendanceResponse
+	response.Section = int32(sectionInt)
+	response.FromDate = fromDate
+	response.ToDate = toDate
+	response.Threshold = thresholdFloat
+
+	for _, group := range groups {
+		stats, err := dbqueries.GetGroupAttendanceStats(pg, context.Background(), int(group.Id), fromDate, toDate)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range stats {
+			if int(s.Expected()) < minSessionsInt || s.Rate() >= thresholdFloat {
+				continue
+			}
+			var low dto.LowAttendance
+			low.Group = group.Id
+			low.GroupNumber = group.GroupNumber
+			low.Stats = attendanceStats2Dto(s)
+			response.Persons = append(response.Persons, low)
+		}
+	}
+	return &response, nil
+}
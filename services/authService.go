@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var ErrUnauthorized = errors.New("not authenticated")
+
+// TokenDays is how long an issued token stays valid unless the request asks for less.
+var TokenDays = 30
+
+// parseViewer reads the id of the authenticated person the handler passes on;
+// it is empty when the request carried no token.
+func parseViewer(viewer string) (int, error) {
+	if viewer == "" {
+		return 0, fmt.Errorf("%w: requester is not identified", ErrUnauthorized)
+	}
+	viewerInt, err := strconv.Atoi(viewer)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+	return viewerInt, nil
+}
+
+func hashToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
+
+// IssueToken creates a bearer token for person. Only its hash is stored, so the
+// token cannot be shown again after it is returned here.
+func IssueToken(person int, days int) (*dto.AuthToken, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if days <= 0 || days > TokenDays {
+		days = TokenDays
+	}
+
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	expiresAt := time.Now().AddDate(0, 0, days)
+
+	err = dbqueries.CreateAuthToken(pg, context.Background(), hashToken(token), person, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AuthToken{
+		Person:    int32(person),
+		Token:     token,
+		ExpiresAt: expiresAt.Format("2006-01-02 15:04:05"),
+	}, nil
+}
+
+// IssueTokenFor lets a manager issue a token for another member of the club.
+func IssueTokenFor(viewer string, req dto.AuthTokenRequest) (*dto.AuthToken, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := dbqueries.IsManager(pg, context.Background(), viewerInt)
+	if err != nil {
+		return nil, err
+	}
+	if !manager {
+		return nil, fmt.Errorf("%w: only managers issue tokens", ErrForbidden)
+	}
+	if req.Person <= 0 {
+		return nil, errors.New("person is required")
+	}
+	return IssueToken(int(req.Person), req.Days)
+}
+
+// Authenticate returns the id of the person a bearer token was issued to.
+func Authenticate(token string) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	person, err := dbqueries.GetAuthTokenPerson(pg, context.Background(), hashToken(token))
+	if err != nil {
+		return 0, err
+	}
+	if !person.Valid {
+		return 0, fmt.Errorf("%w: token is unknown or expired", ErrUnauthorized)
+	}
+	return int(person.Int32), nil
+}
+
+func RevokeToken(token string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+	return dbqueries.DeleteAuthToken(pg, context.Background(), hashToken(token))
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+const (
+	ArchiveFormat  = "db_backend-archive"
+	ArchiveVersion = 1
+)
+
+// restoreBatch is how many rows of a table are inserted with one statement.
+const restoreBatch = 500
+
+// ExportArchive writes every data table of the database as NDJSON: a header
+// with the format version and the applied migrations, then each table followed
+// by its rows, parents before the tables referring to them. Operational tables
+// such as the login sessions are left out. All tables are read from the same
+// snapshot.
+func ExportArchive(w io.Writer) ([]dto.ArchiveTable, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	out := bufio.NewWriter(w)
+	encoder := json.NewEncoder(out)
+
+	var tables []dto.ArchiveTable
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		err := dbqueries.UseSnapshot(tx, ctx)
+		if err != nil {
+			return err
+		}
+
+		migrations, err := dbqueries.GetAppliedMigrations(tx, ctx)
+		if err != nil {
+			return err
+		}
+		header := dto.ArchiveHeader{
+			Format:     ArchiveFormat,
+			Version:    ArchiveVersion,
+			CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+			Migrations: migrations,
+		}
+		err = encoder.Encode(dto.ArchiveLine{Archive: &header})
+		if err != nil {
+			return err
+		}
+
+		names, err := dbqueries.GetDataTables(tx, ctx)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			var table dto.ArchiveTable
+			table.Name = name
+			table.Columns, err = dbqueries.GetTableColumns(tx, ctx, name)
+			if err != nil {
+				return err
+			}
+			table.Rows, err = dbqueries.CountTableRows(tx, ctx, name)
+			if err != nil {
+				return err
+			}
+			err = encoder.Encode(dto.ArchiveLine{Table: &table})
+			if err != nil {
+				return err
+			}
+
+			err = dbqueries.ExportTableRows(tx, ctx, name, func(row []byte) error {
+				out.WriteString(`{"row":`)
+				out.Write(row)
+				_, err := out.WriteString("}\n")
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			tables = append(tables, table)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tables, out.Flush()
+}
+
+type archiveReader struct {
+	in   *bufio.Reader
+	line int
+}
+
+// next returns the following line of the archive, or nil at its end. Lines
+// are read whole since rows such as GPS tracks can be long.
+func (r *archiveReader) next() (*dto.ArchiveLine, error) {
+	data, err := r.in.ReadBytes('\n')
+	if err == io.EOF && len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	r.line++
+	var line dto.ArchiveLine
+	err = json.Unmarshal(data, &line)
+	if err != nil {
+		return nil, fmt.Errorf("archive line %d: %w", r.line, err)
+	}
+	return &line, nil
+}
+
+// checkArchiveTable makes sure a table of the archive can be restored: it has
+// to exist with all the archived columns and be empty.
+func checkArchiveTable(pg *db.Postgres, ctx context.Context, table dto.ArchiveTable, known []string) error {
+	if !slices.Contains(known, table.Name) {
+		return fmt.Errorf("table %s of the archive does not exist in the database", table.Name)
+	}
+	columns, err := dbqueries.GetTableColumns(pg, ctx, table.Name)
+	if err != nil {
+		return err
+	}
+	for _, column := range table.Columns {
+		if !slices.Contains(columns, column) {
+			return fmt.Errorf("column %s.%s of the archive does not exist in the database", table.Name, column)
+		}
+	}
+	count, err := dbqueries.CountTableRows(pg, ctx, table.Name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("restore needs an empty database, table %s has %d rows", table.Name, count)
+	}
+	return nil
+}
+
+// RestoreArchive loads an archive written by ExportArchive into an empty
+// database in one transaction. The database must have at least the migrations
+// of the archive applied; columns added by later migrations get their defaults.
+// Sequences are moved past the restored ids afterwards.
+func RestoreArchive(r io.Reader) ([]dto.ArchiveTable, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	reader := archiveReader{in: bufio.NewReaderSize(r, 1<<20)}
+
+	first, err := reader.next()
+	if err != nil {
+		return nil, err
+	}
+	if first == nil || first.Archive == nil || first.Archive.Format != ArchiveFormat {
+		return nil, errors.New("not a club data archive")
+	}
+	if first.Archive.Version > ArchiveVersion {
+		return nil, fmt.Errorf("archive version %d is newer than the supported version %d", first.Archive.Version, ArchiveVersion)
+	}
+
+	applied, err := dbqueries.GetAppliedMigrations(pg, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, migration := range first.Archive.Migrations {
+		if !slices.Contains(applied, migration) {
+			return nil, fmt.Errorf("the database lacks migration %s of the archive, migrate it first", migration)
+		}
+	}
+	known, err := dbqueries.GetDataTables(pg, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []dto.ArchiveTable
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		var table *dto.ArchiveTable
+		var batch bytes.Buffer
+		var pending, restored int
+		skipping := false
+
+		flush := func() error {
+			if pending == 0 {
+				return nil
+			}
+			batch.WriteByte(']')
+			err := dbqueries.ImportTableRows(tx, ctx, table.Name, table.Columns, batch.Bytes())
+			batch.Reset()
+			pending = 0
+			return err
+		}
+		finish := func() error {
+			if table == nil {
+				return nil
+			}
+			err := flush()
+			if err != nil {
+				return err
+			}
+			if restored != table.Rows {
+				return fmt.Errorf("table %s: archive announces %d rows but holds %d", table.Name, table.Rows, restored)
+			}
+			tables = append(tables, *table)
+			return dbqueries.ResetSequences(tx, ctx, table.Name)
+		}
+
+		for {
+			line, err := reader.next()
+			if err != nil {
+				return err
+			}
+			if line == nil {
+				return finish()
+			}
+			switch {
+			case line.Table != nil:
+				err = finish()
+				if err != nil {
+					return err
+				}
+				// Archives written before sessions were left out may hold them.
+				table, skipping = nil, slices.Contains(dbqueries.OperationalTables, line.Table.Name)
+				if skipping {
+					continue
+				}
+				err = checkArchiveTable(tx, ctx, *line.Table, known)
+				if err != nil {
+					return err
+				}
+				table = line.Table
+				restored = 0
+			case line.Row != nil && skipping:
+			case line.Row != nil:
+				if table == nil {
+					return fmt.Errorf("archive line %d: row before any table", reader.line)
+				}
+				if pending == 0 {
+					batch.WriteByte('[')
+				} else {
+					batch.WriteByte(',')
+				}
+				batch.Write(line.Row)
+				pending++
+				restored++
+				if pending == restoreBatch {
+					err = flush()
+					if err != nil {
+						return err
+					}
+				}
+			default:
+				return fmt.Errorf("archive line %d: expected a table or a row", reader.line)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
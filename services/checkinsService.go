@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"db_backend/notify"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OverdueGrace is how long after the end of its last day a tour may stay out
+// without a return check-out before it counts as overdue.
+var OverdueGrace = 12 * time.Hour
+
+// AddTourCheckin records the departure, a check-in on the route or the return of
+// a tour. Only the instructor of the tour reports them.
+func AddTourCheckin(viewer string, checkin dto.TourCheckin) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return -1, err
+	}
+	if !slices.Contains([]string{model.CheckinDeparture, model.CheckinRoute, model.CheckinReturn}, checkin.Kind) {
+		return -1, fmt.Errorf("unknown check-in kind %q", checkin.Kind)
+	}
+
+	var id int
+	err = pg.InTx(context.Background(), func(tx *db.Postgres) error {
+		ctx := context.Background()
+		tour, err := dbqueries.GetTourForUpdate(tx, ctx, int(checkin.Tour))
+		if err != nil {
+			return err
+		}
+		if tour == nil {
+			return fmt.Errorf("tour %d not found", checkin.Tour)
+		}
+		if tour.Instructor != int32(viewerInt) {
+			return fmt.Errorf("%w: only the instructor reports check-ins of tour %d", ErrForbidden, tour.Id)
+		}
+		if tour.Status != model.TourPlanned && tour.Status != model.TourActive {
+			return fmt.Errorf("tour %d is %s", tour.Id, tour.Status)
+		}
+
+		checkins, err := dbqueries.GetTourCheckins(tx, ctx, int(tour.Id))
+		if err != nil {
+			return err
+		}
+		departed, returned := false, false
+		for _, c := range checkins {
+			departed = departed || c.Kind == model.CheckinDeparture
+			returned = returned || c.Kind == model.CheckinReturn
+		}
+		switch {
+		case returned:
+			return fmt.Errorf("tour %d has already returned", tour.Id)
+		case checkin.Kind == model.CheckinDeparture && departed:
+			return fmt.Errorf("tour %d has already departed", tour.Id)
+		case checkin.Kind != model.CheckinDeparture && !departed:
+			return fmt.Errorf("tour %d has not departed yet", tour.Id)
+		}
+
+		var checkinModel model.TourCheckin
+		checkinModel.Tour = tour.Id
+		checkinModel.Kind = checkin.Kind
+		checkinModel.ReportedBy.Int32 = int32(viewerInt)
+		checkinModel.ReportedBy.Valid = true
+		checkinModel.Location = checkin.Location
+		checkinModel.Note = checkin.Note
+		id, err = dbqueries.AddTourCheckin(tx, ctx, checkinModel)
+		return err
+	})
+	if err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
+func GetTourCheckins(tour string) ([]dto.TourCheckin, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return nil, err
+	}
+
+	checkins, err := dbqueries.GetTourCheckins(pg, context.Background(), tourInt)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []dto.TourCheckin
+	for _, checkin := range checkins {
+		var jsonCheckin dto.TourCheckin
+		jsonCheckin.Id = checkin.Id
+		jsonCheckin.Tour = checkin.Tour
+		jsonCheckin.Kind = checkin.Kind
+		jsonCheckin.ReportedAt = checkin.ReportedAt.Time.Format("2006-01-02 15:04:05")
+		if checkin.ReportedBy.Valid {
+			jsonCheckin.ReportedBy = &checkin.ReportedBy.Int32
+		}
+		jsonCheckin.Location = checkin.Location
+		jsonCheckin.Note = checkin.Note
+		response = append(response, jsonCheckin)
+	}
+	return response, nil
+}
+
+func overdue2Dto(pg *db.Postgres, ctx context.Context, overdue model.OverdueTour) (dto.OverdueTour, error) {
+	var jsonOverdue dto.OverdueTour
+	jsonOverdue.Tour = tour2Response(overdue.Tour, 0)
+	jsonOverdue.Due = overdue.Due.Time.Format("2006-01-02 15:04")
+	if overdue.LastCheckin.Valid {
+		jsonOverdue.LastCheckin = overdue.LastCheckin.Time.Format("2006-01-02 15:04")
+	}
+	jsonOverdue.LastLocation = overdue.Location.String
+
+	participants, err := dbqueries.GetTourParticipants(pg, ctx, int(overdue.Tour.Id))
+	if err != nil {
+		return jsonOverdue, err
+	}
+	for _, participant := range participants {
+		if participant.Status == model.EnrollmentEnrolled {
+			jsonOverdue.Participants = append(jsonOverdue.Participants, person2Response(participant.Person))
+		}
+	}
+	jsonOverdue.Tour.Enrolled = int32(len(jsonOverdue.Participants))
+
+	jsonOverdue.Places, err = dbqueries.GetRoutePlaces(pg, ctx, int(overdue.Tour.Route))
+	if err != nil {
+		return jsonOverdue, err
+	}
+	return jsonOverdue, nil
+}
+
+func GetOverdueTours() ([]dto.OverdueTour, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	overdue, err := dbqueries.GetOverdueTours(pg, context.Background(), int(OverdueGrace.Seconds()), false)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []dto.OverdueTour
+	for _, o := range overdue {
+		jsonOverdue, err := overdue2Dto(pg, context.Background(), o)
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, jsonOverdue)
+	}
+	return response, nil
+}
+
+func overdueMessage(overdue dto.OverdueTour) (string, string) {
+	subject := fmt.Sprintf("Tour %d is overdue", overdue.Tour.Id)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Tour %d on route %d (instructor %d) was due back by %s and has not checked out.\n",
+		overdue.Tour.Id, overdue.Tour.Route, overdue.Tour.Instructor, overdue.Due)
+	if overdue.LastCheckin != "" {
+		fmt.Fprintf(&body, "Last check-in: %s %s\n", overdue.LastCheckin, overdue.LastLocation)
+	}
+	fmt.Fprintf(&body, "Route: %s\n", strings.Join(overdue.Places, " - "))
+	fmt.Fprintf(&body, "Participants (%d):\n", len(overdue.Participants))
+	for _, person := range overdue.Participants {
+		fmt.Fprintf(&body, "  %d %s %s %s\n", person.Id, person.Surname, person.Name, person.Patronymic)
+	}
+	return subject, body.String()
+}
+
+// AlertOverdueTours notifies about every overdue tour once; a tour whose alert
+// could not be delivered is logged, skipped and retried on the next run.
+func AlertOverdueTours(ctx context.Context, notifier notify.Notifier, logger *log.Logger) (int, error) {
+	pg, err := db.NewPG(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	overdue, err := dbqueries.GetOverdueTours(pg, ctx, int(OverdueGrace.Seconds()), true)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, o := range overdue {
+		jsonOverdue, err := overdue2Dto(pg, ctx, o)
+		if err != nil {
+			logger.Printf("overdue watcher: tour %d: %v", o.Tour.Id, err)
+			continue
+		}
+		subject, body := overdueMessage(jsonOverdue)
+		err = notifier.Notify(ctx, subject, body)
+		if err != nil {
+			logger.Printf("overdue watcher: tour %d: %v", o.Tour.Id, err)
+			continue
+		}
+		err = dbqueries.MarkOverdueAlerted(pg, ctx, int(o.Tour.Id))
+		if err != nil {
+			logger.Printf("overdue watcher: tour %d: %v", o.Tour.Id, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// WatchOverdueTours runs AlertOverdueTours every interval until ctx is done.
+func WatchOverdueTours(ctx context.Context, notifier notify.Notifier, interval time.Duration, logger *log.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		sent, err := AlertOverdueTours(ctx, notifier, logger)
+		if err != nil {
+			logger.Printf("overdue watcher: %v", err)
+		} else if sent > 0 {
+			logger.Printf("overdue watcher: %d alerts sent", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
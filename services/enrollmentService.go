@@ -0,0 +1,389 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"math"
+	"strconv"
+)
+
+func parseOptionalInt4(value string) (pgtype.Int4, error) {
+	if value == "" {
+		return pgtype.Int4{}, nil
+	}
+	valueInt, err := strconv.Atoi(value)
+	if err != nil {
+		return pgtype.Int4{}, err
+	}
+	return pgtype.Int4{Int32: int32(valueInt), Valid: true}, nil
+}
+
+func tour2Response(tour model.Tour, enrolled int) dto.TourResponse {
+	var jsonTour dto.TourResponse
+	jsonTour.Id = tour.Id
+	jsonTour.Route = tour.Route
+	jsonTour.Instructor = tour.Instructor
+	jsonTour.Start = tour.GetStartAsString()
+	jsonTour.DurationDays = tour.DurationDays
+	if tour.MinParticipants.Valid {
+		jsonTour.MinParticipants = &tour.MinParticipants.Int32
+	}
+	if tour.MaxParticipants.Valid {
+		jsonTour.MaxParticipants = &tour.MaxParticipants.Int32
+	}
+	jsonTour.EnrollmentClosed = tour.EnrollmentClosed
+	jsonTour.AtRisk = tour.AtRisk
+	jsonTour.Enrolled = int32(enrolled)
+	jsonTour.Status = tour.Status
+	if tour.StartedOn.Valid {
+		jsonTour.StartedOn = tour.StartedOn.Time.Format("2006-01-02")
+	}
+	if tour.FinishedOn.Valid {
+		jsonTour.FinishedOn = tour.FinishedOn.Time.Format("2006-01-02")
+	}
+	return jsonTour
+}
+
+func lockTour(tx *db.Postgres, ctx context.Context, tour int) (*model.Tour, error) {
+	tourModel, err := dbqueries.GetTourForUpdate(tx, ctx, tour)
+	if err != nil {
+		return nil, err
+	}
+	if tourModel == nil {
+		return nil, fmt.Errorf("tour %d not found", tour)
+	}
+	return tourModel, nil
+}
+
+// fillFromWaitlist promotes waitlisted people while the tour has free places
+// and recalculates the at-risk flag of a tour with closed enrollment.
+func fillFromWaitlist(tx *db.Postgres, ctx context.Context, tour *model.Tour) ([]int, int, error) {
+	enrolled, err := dbqueries.CountTourParticipants(tx, ctx, int(tour.Id), model.EnrollmentEnrolled)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var promoted []int
+	free := math.MaxInt32
+	if tour.MaxParticipants.Valid {
+		free = int(tour.MaxParticipants.Int32) - enrolled
+	}
+	if free > 0 {
+		promoted, err = dbqueries.PromoteWaitlisted(tx, ctx, int(tour.Id), free)
+		if err != nil {
+			return nil, 0, err
+		}
+		enrolled += len(promoted)
+	}
+
+	if tour.EnrollmentClosed {
+		atRisk := tour.MinParticipants.Valid && enrolled < int(tour.MinParticipants.Int32)
+		if atRisk != tour.AtRisk {
+			err = dbqueries.SetTourAtRisk(tx, ctx, int(tour.Id), atRisk)
+			if err != nil {
+				return nil, 0, err
+			}
+			tour.AtRisk = atRisk
+		}
+	}
+	return promoted, enrolled, nil
+}
+
+func SetTourCapacity(tour string, minParticipants string, maxParticipants string) (*dto.TourResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return nil, err
+	}
+	minReady, err := parseOptionalInt4(minParticipants)
+	if err != nil {
+		return nil, err
+	}
+	maxReady, err := parseOptionalInt4(maxParticipants)
+	if err != nil {
+		return nil, err
+	}
+	if minReady.Valid && maxReady.Valid && minReady.Int32 > maxReady.Int32 {
+		return nil, errors.New("min_participants must not exceed max_participants")
+	}
+
+	var response dto.TourResponse
+	ctx := context.Background()
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		tourModel, err := lockTour(tx, ctx, tourInt)
+		if err != nil {
+			return err
+		}
+
+		err = dbqueries.UpdateTourCapacity(tx, ctx, tourInt, minReady, maxReady)
+		if err != nil {
+			return err
+		}
+		tourModel.MinParticipants = minReady
+		tourModel.MaxParticipants = maxReady
+
+		_, enrolled, err := fillFromWaitlist(tx, ctx, tourModel)
+		if err != nil {
+			return err
+		}
+		response = tour2Response(*tourModel, enrolled)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Club) EnrollTourist(tour string, person string) (*dto.EnrollmentResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return nil, err
+	}
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.EnrollmentResponse
+	ctx := context.Background()
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		tourModel, err := lockTour(tx, ctx, tourInt)
+		if err != nil {
+			return err
+		}
+		if tourModel.EnrollmentClosed {
+			return fmt.Errorf("enrollment for tour %d is closed", tourInt)
+		}
+		if tourModel.Status != model.TourPlanned {
+			return fmt.Errorf("tour %d is %s", tourInt, tourModel.Status)
+		}
+		err = c.checkDues(ctx, personInt)
+		if err != nil {
+			return err
+		}
+
+		current, err := dbqueries.GetEnrollmentStatus(tx, ctx, tourInt, personInt)
+		if err != nil {
+			return err
+		}
+		if current != nil && *current != model.EnrollmentCancelled {
+			return fmt.Errorf("person %d is already %s for tour %d", personInt, *current, tourInt)
+		}
+
+		enrolled, err := dbqueries.CountTourParticipants(tx, ctx, tourInt, model.EnrollmentEnrolled)
+		if err != nil {
+			return err
+		}
+
+		status := model.EnrollmentEnrolled
+		if tourModel.MaxParticipants.Valid && enrolled >= int(tourModel.MaxParticipants.Int32) {
+			status = model.EnrollmentWaitlisted
+		}
+
+		err = dbqueries.EnrollPerson(tx, ctx, tourInt, personInt, status)
+		if err != nil {
+			return err
+		}
+
+		response.Tour = int32(tourInt)
+		response.Person = int32(personInt)
+		response.Status = status
+		if status == model.EnrollmentWaitlisted {
+			position, err := dbqueries.GetWaitlistPosition(tx, ctx, tourInt, personInt)
+			if err != nil {
+				return err
+			}
+			response.Position = int32(position)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func CancelTourEnrollment(tour string, person string) (*dto.CancellationResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return nil, err
+	}
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.CancellationResponse
+	ctx := context.Background()
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		tourModel, err := lockTour(tx, ctx, tourInt)
+		if err != nil {
+			return err
+		}
+
+		current, err := dbqueries.GetEnrollmentStatus(tx, ctx, tourInt, personInt)
+		if err != nil {
+			return err
+		}
+		if current == nil || *current == model.EnrollmentCancelled {
+			return fmt.Errorf("person %d is not enrolled in tour %d", personInt, tourInt)
+		}
+
+		err = dbqueries.CancelEnrollment(tx, ctx, tourInt, personInt)
+		if err != nil {
+			return err
+		}
+
+		response.Tour = int32(tourInt)
+		response.Person = int32(personInt)
+
+		if *current == model.EnrollmentEnrolled {
+			promoted, _, err := fillFromWaitlist(tx, ctx, tourModel)
+			if err != nil {
+				return err
+			}
+			for _, p := range promoted {
+				response.Promoted = append(response.Promoted, int32(p))
+			}
+		}
+		response.AtRisk = tourModel.AtRisk
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func GetTourParticipants(tour string) (*dto.TourParticipantsResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := dbqueries.GetTourParticipants(pg, context.Background(), tourInt)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.TourParticipantsResponse
+	response.Tour = int32(tourInt)
+	response.Participants = []dto.TourParticipantResponse{}
+	response.Waitlist = []dto.TourParticipantResponse{}
+
+	for _, participant := range participants {
+		var jsonParticipant dto.TourParticipantResponse
+		jsonParticipant.Person.Id = participant.Person.Id
+		jsonParticipant.Person.Name = participant.Person.Name
+		jsonParticipant.Person.Surname = participant.Person.Surname
+		jsonParticipant.Person.Patronymic = participant.Person.Patronymic
+		jsonParticipant.Status = participant.Status
+		jsonParticipant.RequestedAt = participant.RequestedAt.Time.Format("2006-01-02 15:04:05")
+		jsonParticipant.Outcome = participant.Outcome.String
+		if participant.OutcomeDate.Valid {
+			jsonParticipant.OutcomeDate = participant.OutcomeDate.Time.Format("2006-01-02")
+		}
+
+		if participant.Status == model.EnrollmentWaitlisted {
+			jsonParticipant.Position = int32(len(response.Waitlist) + 1)
+			response.Waitlist = append(response.Waitlist, jsonParticipant)
+		} else {
+			response.Participants = append(response.Participants, jsonParticipant)
+		}
+	}
+	return &response, nil
+}
+
+func SetTourEnrollmentClosed(tour string, closed bool) (*dto.TourResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.TourResponse
+	ctx := context.Background()
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		tourModel, err := lockTour(tx, ctx, tourInt)
+		if err != nil {
+			return err
+		}
+
+		err = dbqueries.SetTourEnrollmentClosed(tx, ctx, tourInt, closed)
+		if err != nil {
+			return err
+		}
+		tourModel.EnrollmentClosed = closed
+
+		if !closed && tourModel.AtRisk {
+			err = dbqueries.SetTourAtRisk(tx, ctx, tourInt, false)
+			if err != nil {
+				return err
+			}
+			tourModel.AtRisk = false
+		}
+
+		_, enrolled, err := fillFromWaitlist(tx, ctx, tourModel)
+		if err != nil {
+			return err
+		}
+		response = tour2Response(*tourModel, enrolled)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func GetToursAtRisk() ([]dto.TourResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tours, err := dbqueries.GetToursAtRisk(pg, context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	var response []dto.TourResponse
+	for _, tour := range tours {
+		enrolled, err := dbqueries.CountTourParticipants(pg, context.Background(), int(tour.Id), model.EnrollmentEnrolled)
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, tour2Response(tour, enrolled))
+	}
+	return response, nil
+}
@@ -0,0 +1,408 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"strconv"
+	"time"
+)
+
+func equipment2Dto(item model.Equipment) dto.Equipment {
+	var jsonItem dto.Equipment
+	jsonItem.Id = item.Id
+	jsonItem.Title = item.Title
+	jsonItem.Category = item.Category
+	jsonItem.Quantity = item.Quantity
+	jsonItem.Condition = item.Condition
+	if item.Section.Valid {
+		jsonItem.Section = &item.Section.Int32
+	}
+	jsonItem.Notes = item.Notes
+	return jsonItem
+}
+
+func dto2Equipment(item dto.Equipment) (model.Equipment, error) {
+	var itemModel model.Equipment
+	if item.Title == "" || item.Category == "" {
+		return itemModel, errors.New("equipment title and category are required")
+	}
+	if item.Quantity < 0 {
+		return itemModel, errors.New("equipment quantity must not be negative")
+	}
+	if item.Condition == "" {
+		item.Condition = "good"
+	}
+	if model.EquipmentConditionRank(item.Condition) < 0 {
+		return itemModel, fmt.Errorf("unknown equipment condition %q", item.Condition)
+	}
+
+	itemModel.Id = item.Id
+	itemModel.Title = item.Title
+	itemModel.Category = item.Category
+	itemModel.Quantity = item.Quantity
+	itemModel.Condition = item.Condition
+	if item.Section != nil {
+		itemModel.Section = pgtype.Int4{Int32: *item.Section, Valid: true}
+	}
+	itemModel.Notes = item.Notes
+	return itemModel, nil
+}
+
+func checkout2Dto(checkout model.EquipmentCheckout) dto.EquipmentCheckout {
+	var jsonCheckout dto.EquipmentCheckout
+	jsonCheckout.Id = checkout.Id
+	jsonCheckout.Equipment = checkout.Equipment
+	jsonCheckout.Quantity = checkout.Quantity
+	if checkout.Tour.Valid {
+		jsonCheckout.Tour = &checkout.Tour.Int32
+	}
+	if checkout.Person.Valid {
+		jsonCheckout.Person = &checkout.Person.Int32
+	}
+	jsonCheckout.CheckedOutOn = checkout.CheckedOutOn.Time.Format("2006-01-02")
+	if checkout.DueOn.Valid {
+		jsonCheckout.DueOn = checkout.DueOn.Time.Format("2006-01-02")
+	}
+	if checkout.CheckedInOn.Valid {
+		jsonCheckout.CheckedInOn = checkout.CheckedInOn.Time.Format("2006-01-02")
+	}
+	jsonCheckout.ConditionIn = checkout.ConditionIn.String
+	jsonCheckout.Report = checkout.Report.String
+	return jsonCheckout
+}
+
+// tourWindow returns the [from, to) dates of a tour in the format the queries expect.
+func tourWindow(tour model.Tour) (string, string) {
+	from := tour.Start.Time
+	to := from.AddDate(0, 0, int(tour.DurationDays))
+	return from.Format("2006-01-02"), to.Format("2006-01-02")
+}
+
+func CreateEquipment(item dto.Equipment) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	itemModel, err := dto2Equipment(item)
+	if err != nil {
+		return -1, err
+	}
+
+	return dbqueries.CreateEquipment(pg, context.Background(), itemModel)
+}
+
+func GetEquipment(id string) (*dto.Equipment, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := dbqueries.GetEquipment(pg, context.Background(), idInt)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, nil
+	}
+
+	jsonItem := equipment2Dto(*item)
+	return &jsonItem, nil
+}
+
+func UpdateEquipment(item dto.Equipment) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	itemModel, err := dto2Equipment(item)
+	if err != nil {
+		return err
+	}
+
+	return dbqueries.UpdateEquipment(pg, context.Background(), itemModel)
+}
+
+func DeleteEquipment(id string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+
+	return dbqueries.DeleteEquipment(pg, context.Background(), idInt)
+}
+
+func GetEquipmentList(section string, category string) ([]dto.Equipment, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	sectionReady, err := parseOptionalInt4(section)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := dbqueries.GetEquipmentList(pg, context.Background(), sectionReady, category)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.Equipment
+	for _, item := range items {
+		result = append(result, equipment2Dto(item))
+	}
+	return result, nil
+}
+
+func CheckoutEquipment(req dto.EquipmentCheckoutRequest) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	if (req.Tour == nil) == (req.Person == nil) {
+		return -1, errors.New("equipment is checked out either to a tour or to a person")
+	}
+	if req.Quantity <= 0 {
+		return -1, errors.New("checkout quantity must be positive")
+	}
+
+	var checkout model.EquipmentCheckout
+	checkout.Equipment = req.Equipment
+	checkout.Quantity = req.Quantity
+	checkout.CheckedOutOn, err = parseDateOrToday("")
+	if err != nil {
+		return -1, err
+	}
+	if req.DueOn != "" {
+		err = checkout.DueOn.Scan(req.DueOn)
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	var id int
+	ctx := context.Background()
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		item, err := dbqueries.GetEquipmentForUpdate(tx, ctx, int(req.Equipment))
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return fmt.Errorf("equipment %d not found", req.Equipment)
+		}
+
+		var fromDate, toDate string
+		if req.Tour != nil {
+			tour, err := dbqueries.GetTour(tx, ctx, int(*req.Tour))
+			if err != nil {
+				return err
+			}
+			if tour == nil {
+				return fmt.Errorf("tour %d not found", *req.Tour)
+			}
+			if tour.Status != model.TourPlanned && tour.Status != model.TourActive {
+				return fmt.Errorf("tour %d is %s", tour.Id, tour.Status)
+			}
+			checkout.Tour = pgtype.Int4{Int32: tour.Id, Valid: true}
+			fromDate, toDate = tourWindow(*tour)
+			if checkout.CheckedOutOn.Time.Format("2006-01-02") < fromDate {
+				fromDate = checkout.CheckedOutOn.Time.Format("2006-01-02")
+			}
+		} else {
+			checkout.Person = pgtype.Int4{Int32: *req.Person, Valid: true}
+			fromDate = checkout.CheckedOutOn.Time.Format("2006-01-02")
+			toDate = "2999-01-01"
+			if checkout.DueOn.Valid {
+				toDate = checkout.DueOn.Time.AddDate(0, 0, 1).Format("2006-01-02")
+			}
+		}
+
+		availability, err := dbqueries.GetEquipmentAvailability(tx, ctx, fromDate, toDate, pgtype.Int4{Int32: item.Id, Valid: true})
+		if err != nil {
+			return err
+		}
+		if len(availability) == 0 || availability[0].Available() < req.Quantity {
+			available := int32(0)
+			if len(availability) > 0 {
+				available = availability[0].Available()
+			}
+			return fmt.Errorf("only %d of %q available from %s to %s", available, item.Title, fromDate, toDate)
+		}
+
+		id, err = dbqueries.CreateEquipmentCheckout(tx, ctx, checkout)
+		return err
+	})
+	if err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
+func CheckinEquipment(req dto.EquipmentCheckinRequest) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	date, err := parseDateOrToday(req.Date)
+	if err != nil {
+		return err
+	}
+
+	var condition pgtype.Text
+	if req.Condition != "" {
+		if req.Condition != model.EquipmentWrittenOff && model.EquipmentConditionRank(req.Condition) < 0 {
+			return fmt.Errorf("unknown equipment condition %q", req.Condition)
+		}
+		condition = pgtype.Text{String: req.Condition, Valid: true}
+	}
+	var report pgtype.Text
+	if req.Report != "" {
+		report = pgtype.Text{String: req.Report, Valid: true}
+	}
+
+	ctx := context.Background()
+	return pg.InTx(ctx, func(tx *db.Postgres) error {
+		checkout, err := dbqueries.GetEquipmentCheckoutForUpdate(tx, ctx, int(req.Checkout))
+		if err != nil {
+			return err
+		}
+		if checkout == nil {
+			return fmt.Errorf("checkout %d not found", req.Checkout)
+		}
+		if checkout.CheckedInOn.Valid {
+			return fmt.Errorf("checkout %d is already checked in", req.Checkout)
+		}
+		if date.Time.Before(checkout.CheckedOutOn.Time) {
+			return errors.New("check-in date is before the checkout date")
+		}
+
+		err = dbqueries.CheckinEquipment(tx, ctx, int(checkout.Id), date, condition, report)
+		if err != nil {
+			return err
+		}
+
+		if !condition.Valid {
+			return nil
+		}
+
+		item, err := dbqueries.GetEquipmentForUpdate(tx, ctx, int(checkout.Equipment))
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return nil
+		}
+
+		if condition.String == model.EquipmentWrittenOff {
+			item.Quantity -= checkout.Quantity
+			if item.Quantity < 0 {
+				item.Quantity = 0
+			}
+		} else if model.EquipmentConditionRank(condition.String) > model.EquipmentConditionRank(item.Condition) {
+			item.Condition = condition.String
+		} else {
+			return nil
+		}
+		return dbqueries.UpdateEquipment(tx, ctx, *item)
+	})
+}
+
+func GetEquipmentCheckouts(tour string, person string, open string) ([]dto.EquipmentCheckout, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tourReady, err := parseOptionalInt4(tour)
+	if err != nil {
+		return nil, err
+	}
+	personReady, err := parseOptionalInt4(person)
+	if err != nil {
+		return nil, err
+	}
+	openOnly := false
+	if open != "" {
+		openOnly, err = strconv.ParseBool(open)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	checkouts, err := dbqueries.GetEquipmentCheckouts(pg, context.Background(), tourReady, personReady, openOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.EquipmentCheckout
+	for _, checkout := range checkouts {
+		result = append(result, checkout2Dto(checkout))
+	}
+	return result, nil
+}
+
+func GetEquipmentAvailability(tour string, fromDate string, toDate string) (*dto.EquipmentAvailabilityResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	if tour != "" {
+		tourInt, err := strconv.Atoi(tour)
+		if err != nil {
+			return nil, err
+		}
+		tourModel, err := dbqueries.GetTour(pg, context.Background(), tourInt)
+		if err != nil {
+			return nil, err
+		}
+		if tourModel == nil {
+			return nil, fmt.Errorf("tour %d not found", tourInt)
+		}
+		fromDate, toDate = tourWindow(*tourModel)
+	} else {
+		if fromDate == "" {
+			fromDate = time.Now().Format("2006-01-02")
+		}
+		if toDate == "" {
+			return nil, errors.New("either tour or to_date is required")
+		}
+	}
+
+	items, err := dbqueries.GetEquipmentAvailability(pg, context.Background(), fromDate, toDate, pgtype.Int4{})
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.EquipmentAvailabilityResponse
+	response.From = fromDate
+	response.To = toDate
+	for _, item := range items {
+		var jsonItem dto.EquipmentAvailability
+		jsonItem.Equipment = equipment2Dto(item.Equipment)
+		jsonItem.Allocated = item.Allocated
+		jsonItem.Available = item.Available()
+		response.Items = append(response.Items, jsonItem)
+	}
+	return &response, nil
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+)
+
+func rub2Kopecks(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func kopecks2Rub(amount int64) float64 {
+	return float64(amount) / 100
+}
+
+// splitAmount divides amount proportionally to weights; the kopecks left after
+// rounding down go to the largest fractional parts so the shares add up exactly.
+func splitAmount(amount int64, weights []float64) []int64 {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+
+	shares := make([]int64, len(weights))
+	remainders := make([]float64, len(weights))
+	var distributed int64
+	for i, w := range weights {
+		exact := float64(amount) * w / total
+		shares[i] = int64(math.Floor(exact))
+		remainders[i] = exact - float64(shares[i])
+		distributed += shares[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+	for i := 0; distributed < amount; i++ {
+		shares[order[i%len(order)]]++
+		distributed++
+	}
+	return shares
+}
+
+// settle turns balances into a short list of transfers from debtors to creditors.
+func settle(balances []model.PersonBalance) []dto.Transfer {
+	type party struct {
+		person int32
+		amount int64
+	}
+	var creditors, debtors []party
+	for _, b := range balances {
+		if b.Net() > 0 {
+			creditors = append(creditors, party{b.Person.Id, b.Net()})
+		} else if b.Net() < 0 {
+			debtors = append(debtors, party{b.Person.Id, -b.Net()})
+		}
+	}
+	sort.SliceStable(creditors, func(i, j int) bool { return creditors[i].amount > creditors[j].amount })
+	sort.SliceStable(debtors, func(i, j int) bool { return debtors[i].amount > debtors[j].amount })
+
+	transfers := []dto.Transfer{}
+	for i, j := 0, 0; i < len(debtors) && j < len(creditors); {
+		amount := min(debtors[i].amount, creditors[j].amount)
+		transfers = append(transfers, dto.Transfer{From: debtors[i].person, To: creditors[j].person, Amount: kopecks2Rub(amount)})
+		debtors[i].amount -= amount
+		creditors[j].amount -= amount
+		if debtors[i].amount == 0 {
+			i++
+		}
+		if creditors[j].amount == 0 {
+			j++
+		}
+	}
+	return transfers
+}
+
+func AddTourExpense(req dto.TourExpenseRequest) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	if !slices.Contains(model.ExpenseCategories, req.Category) {
+		return -1, fmt.Errorf("unknown expense category %q", req.Category)
+	}
+
+	var expense model.TourExpense
+	expense.Tour = req.Tour
+	expense.Payer = req.Payer
+	expense.Category = req.Category
+	expense.Amount = rub2Kopecks(req.Amount)
+	expense.Description = req.Description
+	if expense.Amount <= 0 {
+		return -1, errors.New("expense amount must be positive")
+	}
+	expense.SpentOn, err = parseDateOrToday(req.Date)
+	if err != nil {
+		return -1, err
+	}
+
+	var id int
+	ctx := context.Background()
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		participants, err := dbqueries.GetTourParticipants(tx, ctx, int(req.Tour))
+		if err != nil {
+			return err
+		}
+
+		weights := map[int32]float64{}
+		var persons []int32
+		for _, p := range participants {
+			if p.Status == model.EnrollmentEnrolled {
+				weights[p.Person.Id] = 1
+				persons = append(persons, p.Person.Id)
+			}
+		}
+		for _, share := range req.Shares {
+			if _, ok := weights[share.Person]; !ok {
+				return fmt.Errorf("person %d is not a participant of tour %d", share.Person, req.Tour)
+			}
+			if share.Weight < 0 {
+				return fmt.Errorf("negative weight for person %d", share.Person)
+			}
+			weights[share.Person] = share.Weight
+		}
+		for _, person := range req.Excluded {
+			weights[person] = 0
+		}
+
+		var sharedBy []int32
+		var sharedWeights []float64
+		for _, person := range persons {
+			if weights[person] > 0 {
+				sharedBy = append(sharedBy, person)
+				sharedWeights = append(sharedWeights, weights[person])
+			}
+		}
+		if len(sharedBy) == 0 {
+			return fmt.Errorf("tour %d has no participants to split the expense between", req.Tour)
+		}
+
+		id, err = dbqueries.CreateTourExpense(tx, ctx, expense)
+		if err != nil {
+			return err
+		}
+
+		amounts := splitAmount(expense.Amount, sharedWeights)
+		for i, person := range sharedBy {
+			share := model.ExpenseShare{Expense: int32(id), Person: person, Weight: sharedWeights[i], Amount: amounts[i]}
+			err = dbqueries.CreateExpenseShare(tx, ctx, share)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
+func DeleteTourExpense(id string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+
+	return dbqueries.DeleteTourExpense(pg, context.Background(), idInt)
+}
+
+func GetTourExpenses(tour string) ([]dto.TourExpense, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := dbqueries.GetTourExpenses(pg, context.Background(), tourInt)
+	if err != nil {
+		return nil, err
+	}
+	shares, err := dbqueries.GetTourExpenseShares(pg, context.Background(), tourInt)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.TourExpense
+	for _, expense := range expenses {
+		var jsonExpense dto.TourExpense
+		jsonExpense.Id = expense.Id
+		jsonExpense.Tour = expense.Tour
+		jsonExpense.Payer = expense.Payer
+		jsonExpense.Category = expense.Category
+		jsonExpense.Amount = kopecks2Rub(expense.Amount)
+		jsonExpense.Description = expense.Description
+		jsonExpense.Date = expense.SpentOn.Time.Format("2006-01-02")
+		for _, share := range shares {
+			if share.Expense == expense.Id {
+				jsonExpense.Shares = append(jsonExpense.Shares, dto.ExpenseShare{
+					Person: share.Person,
+					Weight: share.Weight,
+					Amount: kopecks2Rub(share.Amount),
+				})
+			}
+		}
+		result = append(result, jsonExpense)
+	}
+	return result, nil
+}
+
+func GetTourSettlement(tour string) (*dto.SettlementResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := dbqueries.GetTourBalances(pg, context.Background(), tourInt)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.SettlementResponse
+	response.Tour = int32(tourInt)
+	var total int64
+	for _, b := range balances {
+		total += b.Paid
+		var jsonBalance dto.PersonBalance
+		jsonBalance.Person.Id = b.Person.Id
+		jsonBalance.Person.Name = b.Person.Name
+		jsonBalance.Person.Surname = b.Person.Surname
+		jsonBalance.Person.Patronymic = b.Person.Patronymic
+		jsonBalance.Paid = kopecks2Rub(b.Paid)
+		jsonBalance.Owed = kopecks2Rub(b.Owed)
+		jsonBalance.Net = kopecks2Rub(b.Net())
+		response.Balances = append(response.Balances, jsonBalance)
+	}
+	response.Total = kopecks2Rub(total)
+	response.Transfers = settle(balances)
+	return &response, nil
+}
+
+func GetSectionExpenseSummary(year string, section string) ([]dto.SectionExpenseSummary, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	yearInt := time.Now().Year()
+	if year != "" {
+		yearInt, err = strconv.Atoi(year)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sectionReady, err := parseOptionalInt4(section)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := dbqueries.GetSectionExpenseSummary(pg, context.Background(), yearInt, sectionReady)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.SectionExpenseSummary
+	for _, s := range summary {
+		var jsonSummary dto.SectionExpenseSummary
+		jsonSummary.Section.Id = s.Section.Id
+		jsonSummary.Section.Title = s.Section.Title
+		jsonSummary.Year = s.Year
+		jsonSummary.Category = s.Category
+		jsonSummary.Amount = kopecks2Rub(s.Amount)
+		result = append(result, jsonSummary)
+	}
+	return result, nil
+}
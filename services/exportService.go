@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/export"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PersonsTable lays out a filtered list of persons for printing, title being
+// one of tourists, trainers, managers or instructors.
+func PersonsTable(title string, data *dto.PersonsListResponse) export.Table {
+	table := export.Table{
+		Title:   title,
+		Columns: []string{"id", "surname", "name", "patronymic"},
+		Rows:    [][]string{},
+	}
+	for _, person := range data.Persons {
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(int(person.Id)), person.Surname, person.Name, person.Patronymic,
+		})
+	}
+	return table
+}
+
+func ChampionshipsTable(data *dto.ChampionshipsListResponse) export.Table {
+	table := export.Table{
+		Title:   "championships",
+		Columns: []string{"id", "title", "date"},
+		Rows:    [][]string{},
+	}
+	for _, championship := range data.Championships {
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(int(championship.Id)), championship.Title, championship.Date,
+		})
+	}
+	return table
+}
+
+// RoutesTable replaces the bare route ids of a filter result with the route
+// type, difficulty, length, places and rating, keeping the order of the ids.
+func RoutesTable(data *dto.RouteIdsListResponse) (*export.Table, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	routes, err := dbqueries.GetAllRoutes(pg, context.Background())
+	if err != nil {
+		return nil, err
+	}
+	places, err := dbqueries.GetAllRoutePlaces(pg, context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := dbqueries.GetRouteRatings(pg, context.Background(), data.RouteIds)
+	if err != nil {
+		return nil, err
+	}
+
+	routeRows := make(map[int32][]string)
+	for _, route := range routes {
+		var titles []string
+		for _, place := range places[route.Id] {
+			titles = append(titles, place.Title)
+		}
+		difficulty := ""
+		if route.Difficulty.Valid {
+			difficulty = strconv.Itoa(int(route.Difficulty.Int32))
+		}
+		length := ""
+		if route.LengthKm.Valid {
+			length = strconv.FormatFloat(route.LengthKm.Float64, 'f', -1, 64)
+		}
+		routeRows[route.Id] = []string{
+			strconv.Itoa(int(route.Id)), route.Type.String, difficulty, length, strings.Join(titles, " - "), "", "0",
+		}
+	}
+	for _, rating := range ratings {
+		row, ok := routeRows[rating.Route]
+		if !ok {
+			continue
+		}
+		if rating.AvgRating.Valid {
+			row[5] = strconv.FormatFloat(*roundRating(rating.AvgRating.Float64), 'f', -1, 64)
+		}
+		row[6] = strconv.Itoa(int(rating.Reviews))
+	}
+
+	table := export.Table{
+		Title:   "routes",
+		Columns: []string{"id", "type", "difficulty", "length_km", "places", "rating", "reviews"},
+		Rows:    [][]string{},
+	}
+	for _, id := range data.RouteIds {
+		if row, ok := routeRows[id]; ok {
+			table.Rows = append(table.Rows, row)
+		}
+	}
+	return &table, nil
+}
+
+// StrainTable prints the workload of a trainer under the trainer's name and
+// the period it covers.
+func StrainTable(trainer string, fromDate string, toDate string, data *dto.StrainListResponse) (*export.Table, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	trainerInt, err := strconv.Atoi(trainer)
+	if err != nil {
+		return nil, err
+	}
+	person, err := dbqueries.GetPerson(pg, context.Background(), trainerInt)
+	if err != nil {
+		return nil, err
+	}
+
+	table := export.Table{
+		Title:    "strain",
+		Subtitle: fullName(*person),
+		Columns:  []string{"workout_type", "duration"},
+		Rows:     [][]string{},
+	}
+	if fromDate != "" || toDate != "" {
+		table.Subtitle += fmt.Sprintf(", %s - %s", fromDate, toDate)
+	}
+	for _, strain := range data.StrainList {
+		table.Rows = append(table.Rows, []string{strain.Strain, fmt.Sprint(strain.Duration)})
+	}
+	return &table, nil
+}
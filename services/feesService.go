@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// checkDues refuses a person with unpaid fees when the club blocks debtors.
+func (c *Club) checkDues(ctx context.Context, person int) error {
+	if !c.blockDebtors {
+		return nil
+	}
+	arrears, err := c.repos.Fees.GetPersonArrears(ctx, person, time.Now().Format("2006-01-02"))
+	if err != nil {
+		return err
+	}
+	if len(arrears) == 0 {
+		return nil
+	}
+
+	var outstanding int64
+	for _, a := range arrears {
+		outstanding += a.Outstanding()
+	}
+	return fmt.Errorf("person %d has unpaid membership fees of %.2f", person, kopecks2Rub(outstanding))
+}
+
+func fee2Dto(fee model.MembershipFee) dto.MembershipFee {
+	var jsonFee dto.MembershipFee
+	jsonFee.Id = fee.Id
+	jsonFee.Section = fee.Section
+	jsonFee.Role = fee.Role
+	jsonFee.Season = fee.Season
+	jsonFee.Amount = kopecks2Rub(fee.Amount)
+	jsonFee.DueOn = fee.DueOn.Time.Format("2006-01-02")
+	return jsonFee
+}
+
+func CreateMembershipFee(fee dto.MembershipFee) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	if fee.Season == "" {
+		return -1, errors.New("fee season is required")
+	}
+	if fee.Amount < 0 {
+		return -1, errors.New("fee amount must not be negative")
+	}
+
+	var feeModel model.MembershipFee
+	feeModel.Section = fee.Section
+	feeModel.Role = fee.Role
+	feeModel.Season = fee.Season
+	feeModel.Amount = rub2Kopecks(fee.Amount)
+	err = feeModel.DueOn.Scan(fee.DueOn)
+	if err != nil {
+		return -1, err
+	}
+
+	return dbqueries.CreateMembershipFee(pg, context.Background(), feeModel)
+}
+
+func DeleteMembershipFee(id string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+
+	return dbqueries.DeleteMembershipFee(pg, context.Background(), idInt)
+}
+
+func GetMembershipFees(section string) ([]dto.MembershipFee, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	sectionReady, err := parseOptionalInt4(section)
+	if err != nil {
+		return nil, err
+	}
+
+	fees, err := dbqueries.GetMembershipFees(pg, context.Background(), sectionReady)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.MembershipFee
+	for _, fee := range fees {
+		result = append(result, fee2Dto(fee))
+	}
+	return result, nil
+}
+
+func AddMembershipPayment(payment dto.MembershipPayment) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	var paymentModel model.MembershipPayment
+	paymentModel.Person = payment.Person
+	paymentModel.Fee = payment.Fee
+	paymentModel.Amount = rub2Kopecks(payment.Amount)
+	paymentModel.Note = payment.Note
+	if paymentModel.Amount <= 0 {
+		return -1, errors.New("payment amount must be positive")
+	}
+	paymentModel.PaidOn, err = parseDateOrToday(payment.PaidOn)
+	if err != nil {
+		return -1, err
+	}
+
+	return dbqueries.CreateMembershipPayment(pg, context.Background(), paymentModel)
+}
+
+func GetMembershipPayments(person string) ([]dto.MembershipPayment, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := dbqueries.GetMembershipPayments(pg, context.Background(), personInt)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.MembershipPayment
+	for _, payment := range payments {
+		var jsonPayment dto.MembershipPayment
+		jsonPayment.Id = payment.Id
+		jsonPayment.Person = payment.Person
+		jsonPayment.Fee = payment.Fee
+		jsonPayment.Amount = kopecks2Rub(payment.Amount)
+		jsonPayment.PaidOn = payment.PaidOn.Time.Format("2006-01-02")
+		jsonPayment.Note = payment.Note
+		result = append(result, jsonPayment)
+	}
+	return result, nil
+}
+
+func GetArrears(section string, date string) (*dto.ArrearsResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	sectionReady, err := parseOptionalInt4(section)
+	if err != nil {
+		return nil, err
+	}
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+
+	arrears, err := dbqueries.GetArrears(pg, context.Background(), date, sectionReady)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.ArrearsResponse
+	response.Date = date
+	var total int64
+	for _, a := range arrears {
+		var jsonArrear dto.Arrear
+		jsonArrear.Person.Id = a.Person.Id
+		jsonArrear.Person.Name = a.Person.Name
+		jsonArrear.Person.Surname = a.Person.Surname
+		jsonArrear.Person.Patronymic = a.Person.Patronymic
+		jsonArrear.Fee = fee2Dto(a.Fee)
+		jsonArrear.Paid = kopecks2Rub(a.Paid)
+		jsonArrear.Outstanding = kopecks2Rub(a.Outstanding())
+		total += a.Outstanding()
+		response.Arrears = append(response.Arrears, jsonArrear)
+	}
+	response.Total = kopecks2Rub(total)
+	return &response, nil
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"db_backend/repository"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+func fitnessTest2Dto(test model.FitnessTest) dto.FitnessTest {
+	var jsonTest dto.FitnessTest
+	jsonTest.Id = test.Id
+	jsonTest.Title = test.Title
+	jsonTest.Unit = test.Unit
+	jsonTest.HigherIsBetter = test.HigherIsBetter
+	return jsonTest
+}
+
+func fitnessResult2Dto(result model.FitnessResult) dto.FitnessResult {
+	var jsonResult dto.FitnessResult
+	jsonResult.Id = result.Id
+	jsonResult.Test = result.Test
+	jsonResult.Person = result.Person
+	jsonResult.Value = result.Value
+	jsonResult.TakenOn = result.TakenOn.Time.Format("2006-01-02")
+	jsonResult.Note = result.Note
+	return jsonResult
+}
+
+// fitnessPeriodMonths is how far back fitness results count when the filter
+// does not say.
+const fitnessPeriodMonths = 12
+
+// filterByFitness keeps the tourists meeting the fitness threshold with a result
+// from the last months when both the test and the value are given.
+func filterByFitness(persons repository.Persons, test string, value string, months string, result []model.Person) ([]model.Person, error) {
+	if test == "" && value == "" {
+		return result, nil
+	}
+	if test == "" || value == "" {
+		return result, errors.New("fitness_test and fitness_value go together")
+	}
+	testReady, err := strconv.Atoi(test)
+	if err != nil {
+		return result, err
+	}
+	valueReady, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return result, err
+	}
+	monthsReady := fitnessPeriodMonths
+	if months != "" {
+		monthsReady, err = strconv.Atoi(months)
+		if err != nil {
+			return result, err
+		}
+		if monthsReady <= 0 {
+			return result, errors.New("fitness_months must be positive")
+		}
+	}
+	since := time.Now().AddDate(0, -monthsReady, 0).Format("2006-01-02")
+
+	resultPart, err := persons.GetTouristsByFitness(context.Background(), testReady, valueReady, since)
+	if err != nil {
+		return result, err
+	}
+	return intersection(result, resultPart), nil
+}
+
+func CreateFitnessTest(test dto.FitnessTest) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	if test.Title == "" || test.Unit == "" {
+		return -1, errors.New("test title and unit are required")
+	}
+
+	var testModel model.FitnessTest
+	testModel.Title = test.Title
+	testModel.Unit = test.Unit
+	testModel.HigherIsBetter = test.HigherIsBetter
+
+	return dbqueries.CreateFitnessTest(pg, context.Background(), testModel)
+}
+
+func GetFitnessTests() ([]dto.FitnessTest, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tests, err := dbqueries.GetFitnessTests(pg, context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.FitnessTest
+	for _, test := range tests {
+		result = append(result, fitnessTest2Dto(test))
+	}
+	return result, nil
+}
+
+func DeleteFitnessTest(id string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+
+	return dbqueries.DeleteFitnessTest(pg, context.Background(), idInt)
+}
+
+func AddFitnessResult(result dto.FitnessResult) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	test, err := dbqueries.GetFitnessTest(pg, context.Background(), int(result.Test))
+	if err != nil {
+		return -1, err
+	}
+	if test == nil {
+		return -1, fmt.Errorf("fitness test %d not found", result.Test)
+	}
+
+	var resultModel model.FitnessResult
+	resultModel.Test = result.Test
+	resultModel.Person = result.Person
+	resultModel.Value = result.Value
+	resultModel.Note = result.Note
+	resultModel.TakenOn, err = parseDateOrToday(result.TakenOn)
+	if err != nil {
+		return -1, err
+	}
+
+	return dbqueries.AddFitnessResult(pg, context.Background(), resultModel)
+}
+
+func DeleteFitnessResult(id string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+
+	return dbqueries.DeleteFitnessResult(pg, context.Background(), idInt)
+}
+
+func GetFitnessResults(person string, test string) ([]dto.FitnessResult, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return nil, err
+	}
+	testReady, err := parseOptionalInt4(test)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := dbqueries.GetFitnessResults(pg, context.Background(), personInt, testReady)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []dto.FitnessResult
+	for _, result := range results {
+		response = append(response, fitnessResult2Dto(result))
+	}
+	return response, nil
+}
+
+// GetPersonalBests returns for every test the person took the current best and
+// the chronological list of results that set a new best when recorded.
+func GetPersonalBests(person string, test string) ([]dto.PersonalBest, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return nil, err
+	}
+	testReady, err := parseOptionalInt4(test)
+	if err != nil {
+		return nil, err
+	}
+
+	tests, err := dbqueries.GetFitnessTests(pg, context.Background())
+	if err != nil {
+		return nil, err
+	}
+	results, err := dbqueries.GetFitnessResults(pg, context.Background(), personInt, testReady)
+	if err != nil {
+		return nil, err
+	}
+
+	catalogue := make(map[int32]model.FitnessTest)
+	for _, t := range tests {
+		catalogue[t.Id] = t
+	}
+
+	var response []dto.PersonalBest
+	for _, result := range results {
+		last := len(response) - 1
+		if last < 0 || response[last].Test.Id != result.Test {
+			t := catalogue[result.Test]
+			response = append(response, dto.PersonalBest{Test: fitnessTest2Dto(t)})
+			last++
+		}
+
+		t := catalogue[result.Test]
+		best := &response[last]
+		if len(best.History) == 0 || t.Better(result.Value, best.Best.Value) {
+			best.Best = fitnessResult2Dto(result)
+			best.History = append(best.History, best.Best)
+		}
+	}
+	return response, nil
+}
+
+// GetFitnessRanking ranks group members by their best result; equal results share a place.
+func GetFitnessRanking(group string, test string, fromDate string, toDate string) (*dto.FitnessRankingResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	groupInt, err := strconv.Atoi(group)
+	if err != nil {
+		return nil, err
+	}
+	testInt, err := strconv.Atoi(test)
+	if err != nil {
+		return nil, err
+	}
+	fromDate, toDate = dateRange(fromDate, toDate)
+
+	testModel, err := dbqueries.GetFitnessTest(pg, context.Background(), testInt)
+	if err != nil {
+		return nil, err
+	}
+	if testModel == nil {
+		return nil, fmt.Errorf("fitness test %d not found", testInt)
+	}
+
+	ranking, err := dbqueries.GetGroupFitnessRanking(pg, context.Background(), groupInt, *testModel, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.FitnessRankingResponse
+	response.Group = int32(groupInt)
+	response.Test = fitnessTest2Dto(*testModel)
+	response.FromDate = fromDate
+	response.ToDate = toDate
+
+	for i, r := range ranking {
+		var jsonRanking dto.FitnessRanking
+		jsonRanking.Place = int32(i + 1)
+		if i > 0 && r.Value == ranking[i-1].Value {
+			jsonRanking.Place = response.Ranking[i-1].Place
+		}
+		jsonRanking.Person = person2Response(r.Person)
+		jsonRanking.Value = r.Value
+		jsonRanking.TakenOn = r.TakenOn.Time.Format("2006-01-02")
+		response.Ranking = append(response.Ranking, jsonRanking)
+	}
+	return &response, nil
+}
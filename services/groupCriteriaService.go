@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"db_backend/dto"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"sort"
+	"strconv"
+)
+
+// groupSpecificity counts the criteria a group sets, so that a tourist who fits
+// a narrow group is suggested that one before a group open to everybody.
+func groupSpecificity(group model.Group) int {
+	count := 0
+	for _, criterion := range []pgtype.Int4{group.MinAge, group.MaxAge, group.Sex, group.MinQualification} {
+		if criterion.Valid {
+			count++
+		}
+	}
+	return count
+}
+
+func candidateAge(candidate model.GroupCandidate, date pgtype.Date) *int {
+	if !candidate.BirthDate.Valid {
+		return nil
+	}
+	age := candidate.AgeOn(date.Time)
+	return &age
+}
+
+func (c *Club) loadSectionGroups(ctx context.Context, section pgtype.Int4) ([]model.Group, map[int32][]int32, error) {
+	var groups []model.Group
+	var err error
+	if section.Valid {
+		groups, err = c.repos.Groups.GetGroupsFromSections(ctx, int(section.Int32))
+	} else {
+		groups, err = c.repos.Groups.GetGroups(ctx)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	memberships, err := c.repos.Groups.GetSectionMemberships(ctx, section)
+	if err != nil {
+		return nil, nil, err
+	}
+	members := map[int32][]int32{}
+	for _, membership := range memberships {
+		members[membership.Group] = append(members[membership.Group], membership.Person)
+	}
+	return groups, members, nil
+}
+
+// SuggestGroup checks a tourist against the criteria of every group and
+// suggests the most specific fitting group, the smallest one among equals.
+func (c *Club) SuggestGroup(person string, section string, date string) (*dto.GroupSuggestion, error) {
+	ctx := context.Background()
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return nil, err
+	}
+	sectionReady, err := parseOptionalInt4(section)
+	if err != nil {
+		return nil, err
+	}
+	dateReady, err := parseDateOrToday(date)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := c.repos.Groups.GetGroupCandidates(ctx, []int32{int32(personInt)})
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("person %d not found", personInt)
+	}
+	candidate := candidates[0]
+	groups, members, err := c.loadSectionGroups(ctx, sectionReady)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.GroupSuggestion
+	response.Person = person2Response(candidate.Person)
+	response.Age = candidateAge(candidate, dateReady)
+	response.Sex = int4Pointer(candidate.Sex)
+	response.Qualification = candidate.Qualification
+	response.Groups = []dto.GroupFit{}
+	for _, group := range groups {
+		var fit dto.GroupFit
+		fit.Group = group2Dto(group)
+		fit.Members = len(members[group.Id])
+		fit.Reasons = group.Misfits(candidate, dateReady.Time)
+		fit.Fits = len(fit.Reasons) == 0
+		response.Groups = append(response.Groups, fit)
+	}
+
+	specificity := map[int32]int{}
+	for _, group := range groups {
+		specificity[group.Id] = groupSpecificity(group)
+	}
+	sort.SliceStable(response.Groups, func(i, j int) bool {
+		a, b := response.Groups[i], response.Groups[j]
+		if a.Fits != b.Fits {
+			return a.Fits
+		}
+		if specificity[a.Group.Id] != specificity[b.Group.Id] {
+			return specificity[a.Group.Id] > specificity[b.Group.Id]
+		}
+		if a.Members != b.Members {
+			return a.Members < b.Members
+		}
+		return a.Group.Id < b.Group.Id
+	})
+	if len(response.Groups) > 0 && response.Groups[0].Fits {
+		response.Suggested = &response.Groups[0].Group.Id
+	}
+	return &response, nil
+}
+
+// GetGroupRebalanceReport lists group members who no longer meet the criteria
+// of their group on the given day, for example after aging out, together with
+// the groups of the same section they would fit.
+func (c *Club) GetGroupRebalanceReport(section string, date string) (*dto.GroupRebalanceReport, error) {
+	ctx := context.Background()
+	sectionReady, err := parseOptionalInt4(section)
+	if err != nil {
+		return nil, err
+	}
+	dateReady, err := parseDateOrToday(date)
+	if err != nil {
+		return nil, err
+	}
+	groups, members, err := c.loadSectionGroups(ctx, sectionReady)
+	if err != nil {
+		return nil, err
+	}
+
+	var persons []int32
+	for _, group := range groups {
+		if groupSpecificity(group) > 0 {
+			persons = append(persons, members[group.Id]...)
+		}
+	}
+	candidateList, err := c.repos.Groups.GetGroupCandidates(ctx, persons)
+	if err != nil {
+		return nil, err
+	}
+	candidates := map[int32]model.GroupCandidate{}
+	for _, candidate := range candidateList {
+		candidates[candidate.Person.Id] = candidate
+	}
+
+	var response dto.GroupRebalanceReport
+	response.Date = dateReady.Time.Format("2006-01-02")
+	response.Section = int4Pointer(sectionReady)
+	response.Misfits = []dto.GroupMisfit{}
+	for _, group := range groups {
+		if groupSpecificity(group) == 0 {
+			continue
+		}
+		for _, person := range members[group.Id] {
+			candidate := candidates[person]
+			reasons := group.Misfits(candidate, dateReady.Time)
+			if len(reasons) == 0 {
+				continue
+			}
+
+			var misfit dto.GroupMisfit
+			misfit.Person = person2Response(candidate.Person)
+			misfit.Group = group2Dto(group)
+			misfit.Age = candidateAge(candidate, dateReady)
+			misfit.Reasons = reasons
+			misfit.Alternatives = []int32{}
+			for _, other := range groups {
+				if other.Id != group.Id && other.Section == group.Section && len(other.Misfits(candidate, dateReady.Time)) == 0 {
+					misfit.Alternatives = append(misfit.Alternatives, other.Id)
+				}
+			}
+			response.Misfits = append(response.Misfits, misfit)
+		}
+	}
+	return &response, nil
+}
@@ -2,96 +2,127 @@ package services
 
 import (
 	"context"
-	"db_backend/db"
-	"db_backend/dbqueries"
 	"db_backend/dto"
 	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
 	"strconv"
 )
 
-func CreateGroup(group dto.Group) (int, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return -1, err
+func optionalInt4(value *int32) pgtype.Int4 {
+	if value == nil {
+		return pgtype.Int4{}
 	}
+	return pgtype.Int4{Int32: *value, Valid: true}
+}
 
-	var groupModel model.Group
+func int4Pointer(value pgtype.Int4) *int32 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int32
+}
 
+func dto2Group(group dto.Group) model.Group {
+	var groupModel model.Group
 	groupModel.Id = group.Id
 	groupModel.GroupNumber = group.GroupNumber
 	groupModel.Section = group.Section
+	groupModel.MinAge = optionalInt4(group.MinAge)
+	groupModel.MaxAge = optionalInt4(group.MaxAge)
+	groupModel.Sex = optionalInt4(group.Sex)
+	groupModel.MinQualification = optionalInt4(group.MinQualification)
+	return groupModel
+}
 
-	newId, err := dbqueries.CreateGroup(pg, context.Background(), groupModel)
+func group2Dto(group model.Group) dto.Group {
+	var groupResponse dto.Group
+	groupResponse.Id = group.Id
+	groupResponse.GroupNumber = group.GroupNumber
+	groupResponse.Section = group.Section
+	groupResponse.MinAge = int4Pointer(group.MinAge)
+	groupResponse.MaxAge = int4Pointer(group.MaxAge)
+	groupResponse.Sex = int4Pointer(group.Sex)
+	groupResponse.MinQualification = int4Pointer(group.MinQualification)
+	return groupResponse
+}
+
+func (c *Club) CreateGroup(group dto.Group) (int, error) {
+	groupModel := dto2Group(group)
+
+	newId, err := c.repos.Groups.CreateGroup(context.Background(), groupModel)
 	if err != nil {
 		return -1, err
 	}
 	return newId, nil
 }
 
-func GetGroup(id string) (*model.Group, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Club) GetGroup(id string) (*dto.Group, error) {
 	idInt, err := strconv.Atoi(id)
 	if err != nil {
 		return nil, err
 	}
 
-	group, err := dbqueries.GetGroup(pg, context.Background(), idInt)
-	if err != nil {
+	group, err := c.repos.Groups.GetGroup(context.Background(), idInt)
+	if err != nil || group == nil {
 		return nil, err
 	}
-	return group, nil
+	groupResponse := group2Dto(*group)
+	return &groupResponse, nil
 }
 
-func UpdateGroup(group dto.Group) error {
-	pg, err := db.NewPG(context.Background())
+// UpdateGroup changes the number and section of a group and those criteria
+// that are among the fields of the request; a criterion sent as null is
+// cleared, one left out keeps its value.
+func (c *Club) UpdateGroup(group dto.Group, fields []string) error {
+	existing, err := c.repos.Groups.GetGroup(context.Background(), int(group.Id))
 	if err != nil {
 		return err
 	}
+	if existing == nil {
+		return fmt.Errorf("group %d not found", group.Id)
+	}
+	groupModel := dto2Group(group)
+	if !slices.Contains(fields, "min_age") {
+		groupModel.MinAge = existing.MinAge
+	}
+	if !slices.Contains(fields, "max_age") {
+		groupModel.MaxAge = existing.MaxAge
+	}
+	if !slices.Contains(fields, "sex") {
+		groupModel.Sex = existing.Sex
+	}
+	if !slices.Contains(fields, "min_qualification") {
+		groupModel.MinQualification = existing.MinQualification
+	}
 
-	var groupModel model.Group
-	groupModel.Id = group.Id
-	groupModel.GroupNumber = group.GroupNumber
-	groupModel.Section = group.Section
-
-	err = dbqueries.UpdateGroup(pg, context.Background(), groupModel)
+	err = c.repos.Groups.UpdateGroup(context.Background(), groupModel)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func DeleteGroup(id string) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) DeleteGroup(id string) error {
 	idInt, err := strconv.Atoi(id)
 	if err != nil {
 		return err
 	}
 
-	err = dbqueries.DeleteGroup(pg, context.Background(), idInt)
+	err = c.repos.Groups.DeleteGroup(context.Background(), idInt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func GetGroupMembers(group string) ([]dto.PersonResponse, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
+func (c *Club) GetGroupMembers(group string) ([]dto.PersonResponse, error) {
 	groupIdInt, err := strconv.Atoi(group)
 	if err != nil {
 		return nil, err
 	}
-	persons, err := dbqueries.GetGroupMembers(pg, context.Background(), groupIdInt)
+	persons, err := c.repos.Groups.GetGroupMembers(context.Background(), groupIdInt)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +130,7 @@ func GetGroupMembers(group string) ([]dto.PersonResponse, error) {
 	var members []model.Person
 
 	for _, person := range persons {
-		member, err := dbqueries.GetPerson(pg, context.Background(), person)
+		member, err := c.repos.Persons.GetPerson(context.Background(), person)
 		if err != nil {
 			return nil, err
 		}
@@ -118,7 +149,7 @@ func GetGroupMembers(group string) ([]dto.PersonResponse, error) {
 	return result, nil
 }
 
-func AddGroupMember(group string, person string) error {
+func (c *Club) AddGroupMember(group string, person string) error {
 
 	groupIdInt, err := strconv.Atoi(group)
 	if err != nil {
@@ -129,18 +160,14 @@ func AddGroupMember(group string, person string) error {
 		return err
 	}
 
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-	err = dbqueries.AddGroupMember(pg, context.Background(), personIdInt, groupIdInt)
+	err = c.repos.Groups.AddGroupMember(context.Background(), personIdInt, groupIdInt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func RemoveGroupMember(group string, person string) error {
+func (c *Club) RemoveGroupMember(group string, person string) error {
 	groupIdInt, err := strconv.Atoi(group)
 	if err != nil {
 		return err
@@ -149,68 +176,45 @@ func RemoveGroupMember(group string, person string) error {
 	if err != nil {
 		return err
 	}
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-	err = dbqueries.RemoveGroupMember(pg, context.Background(), personIdInt, groupIdInt)
+	err = c.repos.Groups.RemoveGroupMember(context.Background(), personIdInt, groupIdInt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func GetAllGroups() ([]dto.Group, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	groups, err := dbqueries.GetGroups(pg, context.Background())
+func (c *Club) GetAllGroups() ([]dto.Group, error) {
+	groups, err := c.repos.Groups.GetGroups(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
 	var result []dto.Group
 	for _, group := range groups {
-		var groupResponse dto.Group
-		groupResponse.Id = group.Id
-		groupResponse.GroupNumber = group.GroupNumber
-		groupResponse.Section = group.Section
-		result = append(result, groupResponse)
+		result = append(result, group2Dto(group))
 	}
 
 	return result, nil
 }
 
-func CreateSection(section dto.Section) (int, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return -1, err
-	}
-
+func (c *Club) CreateSection(section dto.Section) (int, error) {
 	var sectionModel model.Section
 	sectionModel.Title = section.Title
 
-	sectionId, err := dbqueries.CreateSection(pg, context.Background(), sectionModel)
+	sectionId, err := c.repos.Groups.CreateSection(context.Background(), sectionModel)
 	if err != nil {
 		return -1, err
 	}
 	return sectionId, nil
 }
 
-func GetSection(id string) (*model.Section, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Club) GetSection(id string) (*model.Section, error) {
 	idInt, err := strconv.Atoi(id)
 	if err != nil {
 		return nil, err
 	}
 
-	sectionModel, err := dbqueries.GetSection(pg, context.Background(), idInt)
+	sectionModel, err := c.repos.Groups.GetSection(context.Background(), idInt)
 	if err != nil {
 		return nil, err
 	}
@@ -218,45 +222,32 @@ func GetSection(id string) (*model.Section, error) {
 	return sectionModel, nil
 }
 
-func UpdateSection(section dto.Section) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
+func (c *Club) UpdateSection(section dto.Section) error {
 	var sectionModel model.Section
 	sectionModel.Id = section.Id
 	sectionModel.Title = section.Title
 
-	err = dbqueries.UpdateSection(pg, context.Background(), sectionModel)
+	err := c.repos.Groups.UpdateSection(context.Background(), sectionModel)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func DeleteSection(id string) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
+func (c *Club) DeleteSection(id string) error {
 	idInt, err := strconv.Atoi(id)
 	if err != nil {
 		return err
 	}
-	err = dbqueries.DeleteSection(pg, context.Background(), idInt)
+	err = c.repos.Groups.DeleteSection(context.Background(), idInt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func GetAllSections() ([]dto.Section, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	sections, err := dbqueries.GetAllSections(pg, context.Background())
+func (c *Club) GetAllSections() ([]dto.Section, error) {
+	sections, err := c.repos.Groups.GetAllSections(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -272,28 +263,19 @@ func GetAllSections() ([]dto.Section, error) {
 	return result, nil
 }
 
-func GetGroupFromSection(id string) ([]dto.Group, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Club) GetGroupFromSection(id string) ([]dto.Group, error) {
 	idInt, err := strconv.Atoi(id)
 	if err != nil {
 		return nil, err
 	}
 
-	groupsModel, err := dbqueries.GetGroupsFromSections(pg, context.Background(), idInt)
+	groupsModel, err := c.repos.Groups.GetGroupsFromSections(context.Background(), idInt)
 	if err != nil {
 		return nil, err
 	}
 	var groups []dto.Group
 	for _, group := range groupsModel {
-		var groupResponse dto.Group
-		groupResponse.Id = group.Id
-		groupResponse.GroupNumber = group.GroupNumber
-		groupResponse.Section = group.Section
-		groups = append(groups, groupResponse)
+		groups = append(groups, group2Dto(group))
 	}
 	return groups, nil
 }
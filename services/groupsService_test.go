@@ -0,0 +1,71 @@
+package services
+
+import (
+	"db_backend/dto"
+	"testing"
+)
+
+func TestUpdateGroupKeepsCriteriaLeftOut(t *testing.T) {
+	t.Parallel()
+	c := useMemory(t)
+	minAge, sex := int32(18), int32(2)
+	err := c.UpdateGroup(dto.Group{Id: int32(c.groupA1), GroupNumber: 1, Section: int32(c.sectionA), MinAge: &minAge, Sex: &sex},
+		[]string{"id", "group_number", "section", "min_age", "sex"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.UpdateGroup(dto.Group{Id: int32(c.groupA1), GroupNumber: 3, Section: int32(c.sectionA)},
+		[]string{"id", "group_number", "section", "sex"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	group, err := c.GetGroup(itoa(c.groupA1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.GroupNumber != 3 {
+		t.Errorf("got group number %d, want 3", group.GroupNumber)
+	}
+	if group.MinAge == nil || *group.MinAge != 18 {
+		t.Errorf("got min age %v, want 18 kept", group.MinAge)
+	}
+	if group.Sex != nil {
+		t.Errorf("got sex %d, want it cleared", *group.Sex)
+	}
+
+	err = c.UpdateGroup(dto.Group{Id: 1000, GroupNumber: 1, Section: int32(c.sectionA)}, nil)
+	if err == nil {
+		t.Error("expected an error for a missing group")
+	}
+}
+
+func TestGroupCriteriaSuggestAndRebalance(t *testing.T) {
+	t.Parallel()
+	c := useMemory(t)
+	minAge, sex := int32(18), int32(2)
+	err := c.UpdateGroup(dto.Group{Id: int32(c.groupA2), GroupNumber: 2, Section: int32(c.sectionA), MinAge: &minAge, Sex: &sex},
+		[]string{"id", "group_number", "section", "min_age", "sex"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	suggestion, err := c.SuggestGroup(itoa(c.anna), itoa(c.sectionA), "2025-09-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suggestion.Suggested == nil || *suggestion.Suggested != int32(c.groupA2) {
+		t.Errorf("got suggested group %v, want the narrower %d", suggestion.Suggested, c.groupA2)
+	}
+
+	report, err := c.GetGroupRebalanceReport(itoa(c.sectionA), "2025-09-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Misfits) != 1 || report.Misfits[0].Person.Id != int32(c.boris) {
+		t.Fatalf("got misfits %+v, want Борис only", report.Misfits)
+	}
+	if got := report.Misfits[0].Alternatives; len(got) != 1 || got[0] != int32(c.groupA1) {
+		t.Errorf("got alternatives %v, want %d", got, c.groupA1)
+	}
+}
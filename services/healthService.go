@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"strconv"
+	"time"
+)
+
+var ErrForbidden = errors.New("access denied")
+
+func checkHealthAccess(pg *db.Postgres, ctx context.Context, viewer int, person int) error {
+	allowed, err := dbqueries.CanAccessHealth(pg, ctx, viewer, person)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("%w: person %d may not access health record of person %d", ErrForbidden, viewer, person)
+	}
+	return nil
+}
+
+func checkHealthEditAccess(pg *db.Postgres, ctx context.Context, viewer int, person int) error {
+	allowed, err := dbqueries.CanEditHealth(pg, ctx, viewer, person)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("%w: person %d may not change health record of person %d", ErrForbidden, viewer, person)
+	}
+	return nil
+}
+
+// checkContactsEditAccess lets the person keep their own emergency contacts and
+// otherwise asks for the right to change their health record.
+func checkContactsEditAccess(pg *db.Postgres, ctx context.Context, viewer int, person int) error {
+	if viewer == person {
+		return nil
+	}
+	return checkHealthEditAccess(pg, ctx, viewer, person)
+}
+
+func GetHealthRecord(viewer string, person string) (*dto.HealthRecord, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return nil, err
+	}
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkHealthAccess(pg, context.Background(), viewerInt, personInt)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := dbqueries.GetHealthRecord(pg, context.Background(), personInt)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("no health record for person %d", personInt)
+	}
+
+	var response dto.HealthRecord
+	response.Person = record.Person
+	if record.ClearanceUntil.Valid {
+		response.ClearanceUntil = record.ClearanceUntil.Time.Format("2006-01-02")
+	}
+	response.BloodType = record.BloodType
+	response.Allergies = record.Allergies
+	response.MedicalNotes = record.MedicalNotes
+	response.UpdatedAt = record.UpdatedAt.Time.Format("2006-01-02 15:04:05")
+	if record.UpdatedBy.Valid {
+		response.UpdatedBy = &record.UpdatedBy.Int32
+	}
+	return &response, nil
+}
+
+func SetHealthRecord(viewer string, record dto.HealthRecord) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return err
+	}
+	if record.BloodType != "" && !model.BloodTypes[record.BloodType] {
+		return fmt.Errorf("unknown blood type %q", record.BloodType)
+	}
+
+	err = checkHealthEditAccess(pg, context.Background(), viewerInt, int(record.Person))
+	if err != nil {
+		return err
+	}
+
+	var recordModel model.HealthRecord
+	recordModel.Person = record.Person
+	if record.ClearanceUntil != "" {
+		err = recordModel.ClearanceUntil.Scan(record.ClearanceUntil)
+		if err != nil {
+			return err
+		}
+	}
+	recordModel.BloodType = record.BloodType
+	recordModel.Allergies = record.Allergies
+	recordModel.MedicalNotes = record.MedicalNotes
+	recordModel.UpdatedBy.Int32 = int32(viewerInt)
+	recordModel.UpdatedBy.Valid = true
+
+	return dbqueries.SetHealthRecord(pg, context.Background(), recordModel)
+}
+
+func DeleteHealthRecord(viewer string, person string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return err
+	}
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return err
+	}
+
+	err = checkHealthEditAccess(pg, context.Background(), viewerInt, personInt)
+	if err != nil {
+		return err
+	}
+
+	return dbqueries.DeleteHealthRecord(pg, context.Background(), personInt)
+}
+
+// GetClearanceAlerts warns about participants of tours starting within days whose
+// clearance is missing or expires before the start. Managers see every tour,
+// anybody else only the tours they lead.
+func GetClearanceAlerts(viewer string, days string) ([]dto.ClearanceAlert, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return nil, err
+	}
+	daysInt := 30
+	if days != "" {
+		daysInt, err = strconv.Atoi(days)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	manager, err := dbqueries.IsManager(pg, context.Background(), viewerInt)
+	if err != nil {
+		return nil, err
+	}
+	var instructor pgtype.Int4
+	if !manager {
+		instructor.Int32 = int32(viewerInt)
+		instructor.Valid = true
+	}
+
+	until := time.Now().AddDate(0, 0, daysInt).Format("2006-01-02")
+	alerts, err := dbqueries.GetClearanceAlerts(pg, context.Background(), until, instructor)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []dto.ClearanceAlert
+	for _, alert := range alerts {
+		var jsonAlert dto.ClearanceAlert
+		jsonAlert.Person = person2Response(alert.Person)
+		jsonAlert.Tour = alert.Tour
+		jsonAlert.Instructor = alert.Instructor
+		jsonAlert.Start = alert.Start.Time.Format("2006-01-02")
+		if alert.ClearanceUntil.Valid {
+			jsonAlert.ClearanceUntil = alert.ClearanceUntil.Time.Format("2006-01-02")
+			jsonAlert.Reason = "expires"
+		} else {
+			jsonAlert.Reason = "missing"
+		}
+		response = append(response, jsonAlert)
+	}
+	return response, nil
+}
+
+func AddEmergencyContact(viewer string, contact dto.EmergencyContact) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return -1, err
+	}
+	if contact.Name == "" || contact.Phone == "" {
+		return -1, errors.New("contact name and phone are required")
+	}
+
+	err = checkContactsEditAccess(pg, context.Background(), viewerInt, int(contact.Person))
+	if err != nil {
+		return -1, err
+	}
+
+	var contactModel model.EmergencyContact
+	contactModel.Person = contact.Person
+	contactModel.Name = contact.Name
+	contactModel.Phone = contact.Phone
+	contactModel.Relation = contact.Relation
+	contactModel.Priority = contact.Priority
+	if contactModel.Priority == 0 {
+		contactModel.Priority = 1
+	}
+
+	return dbqueries.AddEmergencyContact(pg, context.Background(), contactModel)
+}
+
+func DeleteEmergencyContact(viewer string, person string, id string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return err
+	}
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return err
+	}
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+
+	err = checkContactsEditAccess(pg, context.Background(), viewerInt, personInt)
+	if err != nil {
+		return err
+	}
+
+	return dbqueries.DeleteEmergencyContact(pg, context.Background(), personInt, idInt)
+}
+
+func GetEmergencyContacts(viewer string, person string) ([]dto.EmergencyContact, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return nil, err
+	}
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkHealthAccess(pg, context.Background(), viewerInt, personInt)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, err := dbqueries.GetEmergencyContacts(pg, context.Background(), personInt)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []dto.EmergencyContact
+	for _, contact := range contacts {
+		var jsonContact dto.EmergencyContact
+		jsonContact.Id = contact.Id
+		jsonContact.Person = contact.Person
+		jsonContact.Name = contact.Name
+		jsonContact.Phone = contact.Phone
+		jsonContact.Relation = contact.Relation
+		jsonContact.Priority = contact.Priority
+		response = append(response, jsonContact)
+	}
+	return response, nil
+}
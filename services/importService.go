@@ -0,0 +1,396 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/export"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxImportSize limits the spreadsheets accepted by ImportPersons.
+const MaxImportSize = 10 << 20
+
+var errDryRun = errors.New("dry run")
+
+// importColumns are the columns of an import that are not attributes.
+var importColumns = map[string]bool{
+	"surname": true, "name": true, "patronymic": true, "section": true, "role": true, "group": true,
+}
+
+type importColumn struct {
+	key       string
+	attribute *model.Attribute
+}
+
+type importRow struct {
+	line    int
+	person  model.Person
+	section pgtype.Int4
+	role    pgtype.Int4
+	group   pgtype.Int4
+	ints    []model.PersonIntAttribute
+	floats  []model.PersonFloatAttribute
+	texts   []model.PersonStringAttribute
+	dates   []model.PersonDateAttribute
+}
+
+// importLookup resolves the sections, roles, groups and attributes a
+// spreadsheet refers to by id or by name.
+type importLookup struct {
+	sections   map[string]int32
+	roles      map[string]int32
+	groups     map[[2]int32]int32
+	attributes map[string]model.Attribute
+}
+
+func newImportLookup(pg *db.Postgres, ctx context.Context) (*importLookup, error) {
+	lookup := importLookup{
+		sections:   make(map[string]int32),
+		roles:      make(map[string]int32),
+		groups:     make(map[[2]int32]int32),
+		attributes: make(map[string]model.Attribute),
+	}
+
+	sections, err := dbqueries.GetAllSections(pg, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, section := range sections {
+		lookup.sections[strconv.Itoa(int(section.Id))] = section.Id
+		lookup.sections[strings.ToLower(section.Title)] = section.Id
+	}
+	roles, err := dbqueries.GetAllRoles(pg, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		lookup.roles[strconv.Itoa(int(role.Id))] = role.Id
+		lookup.roles[strings.ToLower(role.Role)] = role.Id
+	}
+	groups, err := dbqueries.GetGroups(pg, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		lookup.groups[[2]int32{group.Section, group.GroupNumber}] = group.Id
+	}
+	attributes, err := dbqueries.GetAllAttributes(pg, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, attribute := range attributes {
+		lookup.attributes[strings.ToLower(strings.TrimSpace(attribute.Name))] = attribute
+	}
+	return &lookup, nil
+}
+
+// importHeader maps every column of the header row either onto a person,
+// membership column or onto an attribute by its name.
+func (l *importLookup) importHeader(header []string) ([]importColumn, []dto.ImportProblem) {
+	problems := []dto.ImportProblem{}
+	columns := make([]importColumn, len(header))
+	seen := make(map[string]bool)
+	for i, title := range header {
+		title = strings.TrimSpace(title)
+		if title == "" {
+			continue
+		}
+		if key, ok := export.ColumnKey(title); ok && importColumns[key] {
+			columns[i].key = key
+		} else if attribute, ok := l.attributes[strings.ToLower(title)]; ok {
+			columns[i].key = "attr:" + attribute.Name
+			columns[i].attribute = &attribute
+		} else {
+			problems = append(problems, dto.ImportProblem{Row: 1, Column: title, Error: "unknown column"})
+			continue
+		}
+		if seen[columns[i].key] {
+			problems = append(problems, dto.ImportProblem{Row: 1, Column: title, Error: "duplicate column"})
+		}
+		seen[columns[i].key] = true
+	}
+	for _, required := range []string{"surname", "name"} {
+		if !seen[required] {
+			problems = append(problems, dto.ImportProblem{Row: 1, Column: required, Error: "column is missing"})
+		}
+	}
+	return columns, problems
+}
+
+// parseImportDate accepts ISO and Russian dates as well as the serial day
+// numbers spreadsheets store dates as.
+func parseImportDate(value string) (pgtype.Date, error) {
+	for _, layout := range []string{"2006-01-02", "02.01.2006"} {
+		day, err := time.Parse(layout, value)
+		if err == nil {
+			return pgtype.Date{Time: day, Valid: true}, nil
+		}
+	}
+	serial, err := strconv.Atoi(value)
+	if err == nil && serial > 0 {
+		day := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, serial)
+		return pgtype.Date{Time: day, Valid: true}, nil
+	}
+	return pgtype.Date{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or DD.MM.YYYY", value)
+}
+
+func setImportAttribute(row *importRow, attribute model.Attribute, value string) error {
+	switch attribute.Type {
+	case model.AttrTypeInt:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		row.ints = append(row.ints, model.PersonIntAttribute{AttributeId: int(attribute.Id), Value: number})
+	case model.AttrTypeFloat:
+		number, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		row.floats = append(row.floats, model.PersonFloatAttribute{AttributeId: int(attribute.Id), Value: number})
+	case model.AttrTypeString:
+		row.texts = append(row.texts, model.PersonStringAttribute{AttributeId: int(attribute.Id), Value: value})
+	case model.AttrTypeDate:
+		day, err := parseImportDate(value)
+		if err != nil {
+			return err
+		}
+		row.dates = append(row.dates, model.PersonDateAttribute{AttributeId: int(attribute.Id), Value: day})
+	default:
+		return fmt.Errorf("attribute %q has unknown type %d", attribute.Name, attribute.Type)
+	}
+	return nil
+}
+
+// importRow validates one line of the spreadsheet, reporting every problem
+// found in it rather than only the first one.
+func (l *importLookup) importRow(line int, columns []importColumn, cells []string) (importRow, []dto.ImportProblem) {
+	row := importRow{line: line}
+	problems := []dto.ImportProblem{}
+	problem := func(column string, err string) {
+		problems = append(problems, dto.ImportProblem{Row: line, Column: column, Error: err})
+	}
+
+	var section, role, group string
+	for i, column := range columns {
+		if column.key == "" || i >= len(cells) {
+			continue
+		}
+		value := strings.TrimSpace(cells[i])
+		switch column.key {
+		case "surname":
+			row.person.Surname = value
+		case "name":
+			row.person.Name = value
+		case "patronymic":
+			row.person.Patronymic = value
+		case "section":
+			section = value
+		case "role":
+			role = value
+		case "group":
+			group = value
+		default:
+			if value == "" {
+				continue
+			}
+			err := setImportAttribute(&row, *column.attribute, value)
+			if err != nil {
+				problem(column.attribute.Name, err.Error())
+			}
+		}
+	}
+
+	if row.person.Surname == "" {
+		problem("surname", "is required")
+	}
+	if row.person.Name == "" {
+		problem("name", "is required")
+	}
+	if section != "" {
+		id, ok := l.sections[strings.ToLower(section)]
+		if !ok {
+			problem("section", fmt.Sprintf("unknown section %q", section))
+		}
+		row.section = pgtype.Int4{Int32: id, Valid: ok}
+		if role == "" {
+			problem("role", "is required together with a section")
+		}
+	}
+	if role != "" {
+		id, ok := l.roles[strings.ToLower(role)]
+		if !ok {
+			problem("role", fmt.Sprintf("unknown role %q", role))
+		}
+		row.role = pgtype.Int4{Int32: id, Valid: ok}
+		if section == "" {
+			problem("section", "is required together with a role")
+		}
+	}
+	if group != "" {
+		number, err := strconv.Atoi(group)
+		if err != nil {
+			problem("group", fmt.Sprintf("invalid group number %q", group))
+		} else if section == "" {
+			problem("section", "is required together with a group")
+		} else if row.section.Valid {
+			id, ok := l.groups[[2]int32{row.section.Int32, int32(number)}]
+			if !ok {
+				problem("group", fmt.Sprintf("section %s has no group %d", section, number))
+			}
+			row.group = pgtype.Int4{Int32: id, Valid: ok}
+		}
+	}
+	return row, problems
+}
+
+func applyImportRow(tx *db.Postgres, ctx context.Context, row importRow) (int, error) {
+	id, err := dbqueries.InsertPerson(tx, ctx, row.person)
+	if err != nil {
+		return 0, err
+	}
+	if row.section.Valid && row.role.Valid {
+		err = dbqueries.UpdatePersonRole(tx, ctx, id, int(row.section.Int32), int(row.role.Int32))
+		if err != nil {
+			return 0, err
+		}
+	}
+	for _, attribute := range row.ints {
+		attribute.PersonId = id
+		err = dbqueries.SetPersonIntAttribute(tx, ctx, attribute)
+		if err != nil {
+			return 0, err
+		}
+	}
+	for _, attribute := range row.floats {
+		attribute.PersonId = id
+		err = dbqueries.SetPersonFloatAttribute(tx, ctx, attribute)
+		if err != nil {
+			return 0, err
+		}
+	}
+	for _, attribute := range row.texts {
+		attribute.PersonId = id
+		err = dbqueries.SetPersonStringAttribute(tx, ctx, attribute)
+		if err != nil {
+			return 0, err
+		}
+	}
+	for _, attribute := range row.dates {
+		attribute.PersonId = id
+		err = dbqueries.SetPersonDateAttribute(tx, ctx, attribute)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if row.group.Valid {
+		err = dbqueries.AddGroupMember(tx, ctx, id, int(row.group.Int32))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// ImportPersons creates a person for every row of a CSV or XLSX spreadsheet
+// with its attributes, section role and group. Nothing is written unless every
+// row is valid, and all rows are written in one transaction. A dry run does the
+// same work and rolls it back.
+func ImportPersons(data []byte, dryRun bool) (*dto.ImportReport, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+
+	table, err := export.ReadTable(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(table) == 0 {
+		return nil, errors.New("the spreadsheet is empty")
+	}
+
+	lookup, err := newImportLookup(pg, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := dto.ImportReport{DryRun: dryRun, Persons: []int32{}, Warnings: []dto.ImportProblem{}}
+	columns, problems := lookup.importHeader(table[0])
+	report.Errors = problems
+	if len(report.Errors) > 0 {
+		return &report, nil
+	}
+
+	var rows []importRow
+	names := make(map[string]int)
+	for i, cells := range table[1:] {
+		line := i + 2
+		if strings.TrimSpace(strings.Join(cells, "")) == "" {
+			continue
+		}
+		row, problems := lookup.importRow(line, columns, cells)
+		report.Errors = append(report.Errors, problems...)
+		rows = append(rows, row)
+
+		if len(problems) > 0 {
+			continue
+		}
+		name := strings.ToLower(fullName(row.person))
+		if first, ok := names[name]; ok {
+			report.Warnings = append(report.Warnings, dto.ImportProblem{Row: line,
+				Error: fmt.Sprintf("%s is also on row %d", fullName(row.person), first)})
+		} else {
+			names[name] = line
+		}
+		namesakes, err := dbqueries.FindPersonsByName(pg, ctx, row.person)
+		if err != nil {
+			return nil, err
+		}
+		for _, namesake := range namesakes {
+			report.Warnings = append(report.Warnings, dto.ImportProblem{Row: line,
+				Error: fmt.Sprintf("a person with this name already exists, id %d", namesake.Id)})
+		}
+	}
+	report.Rows = len(rows)
+	if len(report.Errors) > 0 {
+		return &report, nil
+	}
+
+	var ids []int32
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		for _, row := range rows {
+			id, err := applyImportRow(tx, ctx, row)
+			if err != nil {
+				report.Errors = append(report.Errors, dto.ImportProblem{Row: row.line, Error: err.Error()})
+				return err
+			}
+			ids = append(ids, int32(id))
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if len(report.Errors) > 0 {
+		return &report, nil
+	}
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	report.Imported = len(ids)
+	if !dryRun {
+		report.Persons = ids
+	}
+	return &report, nil
+}
@@ -0,0 +1,400 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func parseClock(value string) (pgtype.Time, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return pgtype.Time{}, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	us := int64(clock.Hour()*3600+clock.Minute()*60) * 1_000_000
+	return pgtype.Time{Microseconds: us, Valid: true}, nil
+}
+
+func formatClock(clock pgtype.Time) string {
+	minutes := clock.Microseconds / 60_000_000
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+func stage2Dto(tour model.Tour, stage model.TourStage) dto.TourStage {
+	var jsonStage dto.TourStage
+	jsonStage.Day = stage.Day
+	jsonStage.Date = tour.Start.Time.AddDate(0, 0, int(stage.Day)-1).Format("2006-01-02")
+	jsonStage.StartPlace = stage.StartPlace.Id
+	jsonStage.StartPlaceTitle = stage.StartPlace.Title
+	jsonStage.EndPlace = stage.EndPlace.Id
+	jsonStage.EndPlaceTitle = stage.EndPlace.Title
+	jsonStage.DistanceKm = stage.DistanceKm
+	jsonStage.Camp = stage.Camp
+	jsonStage.Checkpoints = []dto.StageCheckpoint{}
+	for _, checkpoint := range stage.Checkpoints {
+		var jsonCheckpoint dto.StageCheckpoint
+		if checkpoint.Place.Valid {
+			jsonCheckpoint.Place = &checkpoint.Place.Int32
+		}
+		jsonCheckpoint.Title = checkpoint.Title
+		jsonCheckpoint.PlannedTime = formatClock(checkpoint.PlannedTime)
+		jsonStage.Checkpoints = append(jsonStage.Checkpoints, jsonCheckpoint)
+	}
+	return jsonStage
+}
+
+// validateItinerary checks that the stages follow each other day by day without
+// gaps, fit into the tour, only use places of the route, continue where the
+// previous stage ended and together visit every place of the route. A total
+// distance far off the route length is only a warning, and so is a stage going
+// back against the order of the route when that order is known: the last day
+// may return to the start of a loop, but anything else may be a detour the
+// route does not know about.
+func validateItinerary(tour model.Tour, places []model.Place, ordered bool, routeLength float64, stages []dto.TourStage) ([]string, []string) {
+	problems := []string{}
+	warnings := []string{}
+
+	onRoute := make(map[int32]bool)
+	position := make(map[int32]int)
+	for i, place := range places {
+		onRoute[place.Id] = true
+		if _, seen := position[place.Id]; !seen {
+			position[place.Id] = i
+		}
+	}
+	visited := make(map[int32]bool)
+
+	var distance float64
+	for i, stage := range stages {
+		if stage.Day != int32(i+1) {
+			problems = append(problems, fmt.Sprintf("stage %d should be day %d, got day %d", i+1, i+1, stage.Day))
+		}
+		if stage.Day > tour.DurationDays {
+			problems = append(problems, fmt.Sprintf("day %d is beyond the %d days of the tour", stage.Day, tour.DurationDays))
+		}
+		for _, place := range []int32{stage.StartPlace, stage.EndPlace} {
+			if !onRoute[place] {
+				problems = append(problems, fmt.Sprintf("day %d: place %d is not on route %d", stage.Day, place, tour.Route))
+			}
+			visited[place] = true
+		}
+		returnsToStart := i == len(stages)-1 && len(places) > 0 && stage.EndPlace == places[0].Id
+		if ordered && onRoute[stage.StartPlace] && onRoute[stage.EndPlace] && !returnsToStart &&
+			position[stage.EndPlace] < position[stage.StartPlace] {
+			warnings = append(warnings, fmt.Sprintf("day %d goes back from place %d to place %d against the route order",
+				stage.Day, stage.StartPlace, stage.EndPlace))
+		}
+		if i > 0 && stages[i-1].EndPlace != stage.StartPlace {
+			problems = append(problems, fmt.Sprintf("day %d starts at place %d but day %d ended at place %d",
+				stage.Day, stage.StartPlace, stages[i-1].Day, stages[i-1].EndPlace))
+		}
+		if stage.DistanceKm < 0 {
+			problems = append(problems, fmt.Sprintf("day %d: distance must not be negative", stage.Day))
+		}
+		for _, checkpoint := range stage.Checkpoints {
+			if checkpoint.Place != nil {
+				if !onRoute[*checkpoint.Place] {
+					problems = append(problems, fmt.Sprintf("day %d: checkpoint %q is not on route %d",
+						stage.Day, checkpoint.Title, tour.Route))
+				}
+				visited[*checkpoint.Place] = true
+			}
+			_, err := parseClock(checkpoint.PlannedTime)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("day %d: checkpoint %q: %v", stage.Day, checkpoint.Title, err))
+			}
+		}
+		distance += stage.DistanceKm
+	}
+
+	for _, place := range places {
+		if !visited[place.Id] {
+			problems = append(problems, fmt.Sprintf("place %d (%s) of the route is not covered", place.Id, place.Title))
+		}
+	}
+	if len(stages) > 0 && !ordered {
+		warnings = append(warnings, fmt.Sprintf("the order of the places of route %d is not set, stages are not checked against it", tour.Route))
+	}
+	if len(stages) > 0 && int32(len(stages)) < tour.DurationDays {
+		warnings = append(warnings, fmt.Sprintf("itinerary covers %d of %d days", len(stages), tour.DurationDays))
+	}
+	if routeLength > 0 && math.Abs(distance-routeLength) > routeLength*0.1 {
+		warnings = append(warnings, fmt.Sprintf("stages add up to %.1f km, the route is %.1f km", distance, routeLength))
+	}
+	return problems, warnings
+}
+
+func checkItinerary(pg *db.Postgres, ctx context.Context, req dto.ItineraryRequest) (*model.Tour, []string, []string, error) {
+	tour, err := dbqueries.GetTour(pg, ctx, int(req.Tour))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if tour == nil {
+		return nil, nil, nil, fmt.Errorf("tour %d not found", req.Tour)
+	}
+	places, err := dbqueries.GetRoutePlaceList(pg, ctx, int(tour.Route))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ordered, err := dbqueries.IsRouteOrdered(pg, ctx, int(tour.Route))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	length, err := dbqueries.GetRouteLength(pg, ctx, int(tour.Route))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	problems, warnings := validateItinerary(*tour, places, ordered, length, req.Stages)
+	return tour, problems, warnings, nil
+}
+
+func ValidateTourItinerary(req dto.ItineraryRequest) (*dto.ItineraryResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	_, problems, warnings, err := checkItinerary(pg, context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.ItineraryResponse
+	response.Tour = req.Tour
+	response.Stages = req.Stages
+	response.Errors = problems
+	response.Warnings = warnings
+	return &response, nil
+}
+
+// SetTourItinerary replaces the itinerary of a tour if it passes validation.
+func SetTourItinerary(req dto.ItineraryRequest) (*dto.ItineraryResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	err = pg.InTx(context.Background(), func(tx *db.Postgres) error {
+		ctx := context.Background()
+		_, err := dbqueries.GetTourForUpdate(tx, ctx, int(req.Tour))
+		if err != nil {
+			return err
+		}
+		var problems []string
+		_, problems, warnings, err = checkItinerary(tx, ctx, req)
+		if err != nil {
+			return err
+		}
+		if len(problems) > 0 {
+			return errors.New(strings.Join(problems, "; "))
+		}
+
+		err = dbqueries.DeleteTourStages(tx, ctx, int(req.Tour))
+		if err != nil {
+			return err
+		}
+		for _, stage := range req.Stages {
+			var stageModel model.TourStage
+			stageModel.Tour = req.Tour
+			stageModel.Day = stage.Day
+			stageModel.StartPlace.Id = stage.StartPlace
+			stageModel.EndPlace.Id = stage.EndPlace
+			stageModel.DistanceKm = stage.DistanceKm
+			stageModel.Camp = stage.Camp
+			for i, checkpoint := range stage.Checkpoints {
+				var checkpointModel model.StageCheckpoint
+				checkpointModel.Position = int32(i + 1)
+				if checkpoint.Place != nil {
+					checkpointModel.Place.Int32 = *checkpoint.Place
+					checkpointModel.Place.Valid = true
+				}
+				checkpointModel.Title = checkpoint.Title
+				checkpointModel.PlannedTime, err = parseClock(checkpoint.PlannedTime)
+				if err != nil {
+					return fmt.Errorf("day %d: checkpoint %q: %w", stage.Day, checkpoint.Title, err)
+				}
+				stageModel.Checkpoints = append(stageModel.Checkpoints, checkpointModel)
+			}
+			_, err = dbqueries.AddTourStage(tx, ctx, stageModel)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := GetTourItinerary(strconv.Itoa(int(req.Tour)))
+	if err != nil {
+		return nil, err
+	}
+	response.Warnings = warnings
+	return response, nil
+}
+
+// SetRoutePlaceOrder sets the order a route passes its places in. Only managers
+// may, and the order has to list every place of the route once.
+func SetRoutePlaceOrder(viewer string, req dto.RoutePlaceOrder) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return err
+	}
+	manager, err := dbqueries.IsManager(pg, context.Background(), viewerInt)
+	if err != nil {
+		return err
+	}
+	if !manager {
+		return fmt.Errorf("%w: only managers change routes", ErrForbidden)
+	}
+
+	return pg.InTx(context.Background(), func(tx *db.Postgres) error {
+		ctx := context.Background()
+		places, err := dbqueries.GetRoutePlaceList(tx, ctx, int(req.Route))
+		if err != nil {
+			return err
+		}
+		if len(places) == 0 {
+			return fmt.Errorf("route %d has no places", req.Route)
+		}
+		onRoute := make(map[int32]bool)
+		for _, place := range places {
+			onRoute[place.Id] = true
+		}
+		listed := make(map[int32]bool)
+		for _, place := range req.Places {
+			if !onRoute[place] {
+				return fmt.Errorf("place %d is not on route %d", place, req.Route)
+			}
+			if listed[place] {
+				return fmt.Errorf("place %d is listed twice", place)
+			}
+			listed[place] = true
+		}
+		if len(listed) != len(places) {
+			return fmt.Errorf("the order lists %d of the %d places of route %d", len(listed), len(places), req.Route)
+		}
+
+		for i, place := range req.Places {
+			err = dbqueries.SetRoutePlacePosition(tx, ctx, int(req.Route), int(place), i+1)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetTourItinerary returns the stored itinerary and re-validates it, since the
+// tour or its route may have changed after it was saved.
+func GetTourItinerary(tour string) (*dto.ItineraryResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return nil, err
+	}
+
+	tourModel, err := dbqueries.GetTour(pg, context.Background(), tourInt)
+	if err != nil {
+		return nil, err
+	}
+	if tourModel == nil {
+		return nil, fmt.Errorf("tour %d not found", tourInt)
+	}
+	stages, err := dbqueries.GetTourStages(pg, context.Background(), tourInt, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.ItineraryResponse
+	response.Tour = tourModel.Id
+	response.Stages = []dto.TourStage{}
+	for _, stage := range stages {
+		response.Stages = append(response.Stages, stage2Dto(*tourModel, stage))
+	}
+	if len(stages) > 0 {
+		_, response.Errors, response.Warnings, err = checkItinerary(pg, context.Background(),
+			dto.ItineraryRequest{Tour: tourModel.Id, Stages: response.Stages})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &response, nil
+}
+
+// GetGroupPositions tells for every tour under way on date, or only for the given
+// tour, which stage of the itinerary the group should be on and who is in it.
+func GetGroupPositions(date string, tour string) ([]dto.GroupPosition, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	dateReady, err := parseDateOrToday(date)
+	if err != nil {
+		return nil, err
+	}
+	tourReady, err := parseOptionalInt4(tour)
+	if err != nil {
+		return nil, err
+	}
+
+	tours, err := dbqueries.GetToursOnDate(pg, context.Background(), dateReady.Time.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	response := []dto.GroupPosition{}
+	for _, t := range tours {
+		if tourReady.Valid && t.Id != tourReady.Int32 {
+			continue
+		}
+
+		var position dto.GroupPosition
+		position.Tour = t.Id
+		position.Route = t.Route
+		position.Day = int32(dateReady.Time.Sub(t.Start.Time).Hours()/24) + 1
+
+		stages, err := dbqueries.GetTourStages(pg, context.Background(), int(t.Id), int(position.Day))
+		if err != nil {
+			return nil, err
+		}
+		if len(stages) > 0 {
+			stage := stage2Dto(t, stages[0])
+			position.Stage = &stage
+		}
+
+		participants, err := dbqueries.GetTourParticipants(pg, context.Background(), int(t.Id))
+		if err != nil {
+			return nil, err
+		}
+		position.Participants = []dto.PersonResponse{}
+		for _, participant := range participants {
+			if participant.Status == model.EnrollmentEnrolled {
+				position.Participants = append(position.Participants, person2Response(participant.Person))
+			}
+		}
+		response = append(response, position)
+	}
+	return response, nil
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// parseMonth turns "2006-01" into the first and the last day of that month.
+func parseMonth(month string) (time.Time, time.Time, error) {
+	if month == "" {
+		return time.Time{}, time.Time{}, errors.New("month is required")
+	}
+	first, err := time.Parse("2006-01", month)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return first, first.AddDate(0, 1, -1), nil
+}
+
+func payrollRate2Dto(rate model.PayrollRate) dto.PayrollRate {
+	var jsonRate dto.PayrollRate
+	jsonRate.Id = rate.Id
+	if rate.Trainer.Valid {
+		jsonRate.Trainer = &rate.Trainer.Int32
+	}
+	if rate.WorkoutType.Valid {
+		jsonRate.WorkoutType = &rate.WorkoutType.String
+	}
+	jsonRate.HourlyRate = kopecks2Rub(rate.HourlyRate)
+	return jsonRate
+}
+
+func payPeriod2Dto(period model.PayPeriod) dto.PayPeriod {
+	var jsonPeriod dto.PayPeriod
+	jsonPeriod.Id = period.Id
+	jsonPeriod.Section = period.Section
+	jsonPeriod.Month = period.Month.Time.Format("2006-01")
+	jsonPeriod.ClosedAt = period.ClosedAt.Time.Format("2006-01-02 15:04:05")
+	jsonPeriod.Total = kopecks2Rub(period.Total)
+	return jsonPeriod
+}
+
+func minutes2Hours(minutes int32) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
+
+// payrollReport groups the lines by trainer, keeping the order of the query.
+func payrollReport(lines []model.PayrollLine) ([]dto.TrainerPayroll, int64) {
+	var trainers []dto.TrainerPayroll
+	var total int64
+	index := make(map[int32]int)
+	minutes := make(map[int32]int32)
+	amounts := make(map[int32]int64)
+
+	for _, line := range lines {
+		i, ok := index[line.Trainer.Id]
+		if !ok {
+			i = len(trainers)
+			index[line.Trainer.Id] = i
+			trainers = append(trainers, dto.TrainerPayroll{Trainer: person2Response(line.Trainer)})
+		}
+
+		var jsonLine dto.PayrollLine
+		jsonLine.WorkoutType = line.WorkoutType
+		jsonLine.Hours = minutes2Hours(line.Minutes)
+		if line.Rate.Valid {
+			rate := kopecks2Rub(line.Rate.Int64)
+			jsonLine.Rate = &rate
+		} else {
+			trainers[i].Unrated = true
+		}
+		jsonLine.Amount = kopecks2Rub(line.Amount())
+		trainers[i].Lines = append(trainers[i].Lines, jsonLine)
+
+		minutes[line.Trainer.Id] += line.Minutes
+		amounts[line.Trainer.Id] += line.Amount()
+		total += line.Amount()
+	}
+
+	for i := range trainers {
+		trainers[i].Hours = minutes2Hours(minutes[trainers[i].Trainer.Id])
+		trainers[i].Amount = kopecks2Rub(amounts[trainers[i].Trainer.Id])
+	}
+	return trainers, total
+}
+
+func CreatePayrollRate(rate dto.PayrollRate) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	if rate.HourlyRate < 0 {
+		return -1, errors.New("hourly rate must not be negative")
+	}
+
+	var rateModel model.PayrollRate
+	if rate.Trainer != nil {
+		rateModel.Trainer.Int32 = *rate.Trainer
+		rateModel.Trainer.Valid = true
+	}
+	if rate.WorkoutType != nil {
+		rateModel.WorkoutType.String = *rate.WorkoutType
+		rateModel.WorkoutType.Valid = true
+	}
+	rateModel.HourlyRate = rub2Kopecks(rate.HourlyRate)
+
+	return dbqueries.CreatePayrollRate(pg, context.Background(), rateModel)
+}
+
+func DeletePayrollRate(id string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+
+	return dbqueries.DeletePayrollRate(pg, context.Background(), idInt)
+}
+
+func GetPayrollRates(trainer string) ([]dto.PayrollRate, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	trainerReady, err := parseOptionalInt4(trainer)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := dbqueries.GetPayrollRates(pg, context.Background(), trainerReady)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.PayrollRate
+	for _, rate := range rates {
+		result = append(result, payrollRate2Dto(rate))
+	}
+	return result, nil
+}
+
+// GetPayrollReport returns the stored entries of a closed period and computes
+// the report from delivered workouts otherwise.
+func GetPayrollReport(section string, month string) (*dto.PayrollReport, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	sectionInt, err := strconv.Atoi(section)
+	if err != nil {
+		return nil, err
+	}
+	first, last, err := parseMonth(month)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.PayrollReport
+	response.Section = int32(sectionInt)
+	response.Month = first.Format("2006-01")
+
+	period, err := dbqueries.GetPayPeriod(pg, context.Background(), sectionInt, first.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []model.PayrollLine
+	if period != nil {
+		response.Closed = true
+		response.ClosedAt = period.ClosedAt.Time.Format("2006-01-02 15:04:05")
+		lines, err = dbqueries.GetPayrollEntries(pg, context.Background(), int(period.Id))
+	} else {
+		lines, err = dbqueries.GetDeliveredHours(pg, context.Background(), sectionInt,
+			first.Format("2006-01-02"), last.Format("2006-01-02"))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	response.Trainers, total = payrollReport(lines)
+	response.Total = kopecks2Rub(total)
+	return &response, nil
+}
+
+// ClosePayPeriod freezes the payroll of a finished month so later changes to
+// rates or attendance no longer affect it.
+func ClosePayPeriod(section string, month string) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	sectionInt, err := strconv.Atoi(section)
+	if err != nil {
+		return -1, err
+	}
+	first, last, err := parseMonth(month)
+	if err != nil {
+		return -1, err
+	}
+	if !last.Before(time.Now().Truncate(24 * time.Hour)) {
+		return -1, fmt.Errorf("month %s is not over yet", first.Format("2006-01"))
+	}
+
+	var periodId int
+	err = pg.InTx(context.Background(), func(tx *db.Postgres) error {
+		ctx := context.Background()
+		period, err := dbqueries.GetPayPeriod(tx, ctx, sectionInt, first.Format("2006-01-02"))
+		if err != nil {
+			return err
+		}
+		if period != nil {
+			return fmt.Errorf("pay period %s of section %d is already closed", first.Format("2006-01"), sectionInt)
+		}
+
+		lines, err := dbqueries.GetDeliveredHours(tx, ctx, sectionInt, first.Format("2006-01-02"), last.Format("2006-01-02"))
+		if err != nil {
+			return err
+		}
+
+		var periodModel model.PayPeriod
+		periodModel.Section = int32(sectionInt)
+		err = periodModel.Month.Scan(first.Format("2006-01-02"))
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			periodModel.Total += line.Amount()
+		}
+
+		periodId, err = dbqueries.CreatePayPeriod(tx, ctx, periodModel)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			err = dbqueries.AddPayrollEntry(tx, ctx, periodId, line)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+	return periodId, nil
+}
+
+func GetPayPeriods(section string) ([]dto.PayPeriod, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	sectionReady, err := parseOptionalInt4(section)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := dbqueries.GetPayPeriods(pg, context.Background(), sectionReady)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.PayPeriod
+	for _, period := range periods {
+		result = append(result, payPeriod2Dto(period))
+	}
+	return result, nil
+}
@@ -2,8 +2,6 @@ package services
 
 import (
 	"context"
-	"db_backend/db"
-	"db_backend/dbqueries"
 	"db_backend/dto"
 	"db_backend/model"
 	"strconv"
@@ -14,19 +12,15 @@ func remove[T comparable](s []T, i int) []T {
 	return s[:len(s)-1]
 }
 
-func CreatePerson(personReq dto.PersonCreateRequest) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) CreatePerson(personReq dto.PersonCreateRequest) error {
 	var person model.Person
 
 	person.Name = personReq.Name
 	person.Surname = personReq.Surname
 	person.Patronymic = personReq.Patronymic
 
-	return dbqueries.InsertPerson(pg, context.Background(), person)
+	_, err := c.repos.Persons.InsertPerson(context.Background(), person)
+	return err
 }
 
 func intersection[T comparable](a, b []T) []T {
@@ -49,7 +43,7 @@ func intersection[T comparable](a, b []T) []T {
 	return a
 }
 
-func checkParameter[T comparable](pg *db.Postgres, parameter string, searchFunc func(pg *db.Postgres, ctx context.Context, section int) ([]T, error), result []T) ([]T, error) {
+func checkParameter[T comparable](parameter string, searchFunc func(ctx context.Context, section int) ([]T, error), result []T) ([]T, error) {
 	if len(result) == 0 {
 		return result, nil
 	}
@@ -61,7 +55,7 @@ func checkParameter[T comparable](pg *db.Postgres, parameter string, searchFunc
 			return result, err
 		}
 
-		resultPart, err := searchFunc(pg, context.Background(), parameterReady)
+		resultPart, err := searchFunc(context.Background(), parameterReady)
 
 		if err != nil {
 			return result, err
@@ -71,34 +65,34 @@ func checkParameter[T comparable](pg *db.Postgres, parameter string, searchFunc
 	return result, nil
 }
 
-func GetTouristsWithCondition(section string, group string, sex string, birthYear string, age string) (*dto.PersonsListResponse, error) {
-	pg, err := db.NewPG(context.Background())
+func (c *Club) GetTouristsWithCondition(section string, group string, sex string, birthYear string, age string,
+	fitnessTest string, fitnessValue string, fitnessMonths string) (*dto.PersonsListResponse, error) {
+	result, err := c.repos.Persons.GetAllTourists(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := dbqueries.GetAllTourists(pg, context.Background())
+	result, err = checkParameter(section, c.repos.Persons.GetTouristsBySection, result)
 	if err != nil {
 		return nil, err
 	}
-
-	result, err = checkParameter(pg, section, dbqueries.GetTouristsBySection, result)
+	result, err = checkParameter(group, c.repos.Persons.GetTouristsByGroup, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, group, dbqueries.GetTouristsByGroup, result)
+	result, err = checkParameter(sex, c.repos.Persons.GetTouristsBySex, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, sex, dbqueries.GetTouristsBySex, result)
+	result, err = checkParameter(birthYear, c.repos.Persons.GetTouristsByBirthYear, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, birthYear, dbqueries.GetTouristsByBirthYear, result)
+	result, err = checkParameter(age, c.repos.Persons.GetTouristsByAge, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, age, dbqueries.GetTouristsByAge, result)
+	result, err = filterByFitness(c.repos.Persons, fitnessTest, fitnessValue, fitnessMonths, result)
 	if err != nil {
 		return nil, err
 	}
@@ -121,36 +115,31 @@ func GetTouristsWithCondition(section string, group string, sex string, birthYea
 	return &response, nil
 }
 
-func GetTrainersWithCondition(section string, sex string, age string, salary string, specialization string) (*dto.PersonsListResponse, error) {
-	pg, err := db.NewPG(context.Background())
+func (c *Club) GetTrainersWithCondition(section string, sex string, age string, salary string, specialization string) (*dto.PersonsListResponse, error) {
+	result, err := c.repos.Persons.GetAllTrainers(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := dbqueries.GetAllTrainers(pg, context.Background())
+	result, err = checkParameter(section, c.repos.Persons.GetTrainersBySection, result)
 	if err != nil {
 		return nil, err
 	}
-
-	result, err = checkParameter(pg, section, dbqueries.GetTrainersBySection, result)
+	result, err = checkParameter(sex, c.repos.Persons.GetTrainersBySex, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, sex, dbqueries.GetTrainersBySex, result)
+	result, err = checkParameter(age, c.repos.Persons.GetTrainersByAge, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, age, dbqueries.GetTrainersByAge, result)
-	if err != nil {
-		return nil, err
-	}
-	result, err = checkParameter(pg, salary, dbqueries.GetTrainersBySalary, result)
+	result, err = checkParameter(salary, c.repos.Persons.GetTrainersBySalary, result)
 	if err != nil {
 		return nil, err
 	}
 
 	if specialization != "" {
-		resultPart, err := dbqueries.GetTrainersBySpecialization(pg, context.Background(), specialization)
+		resultPart, err := c.repos.Persons.GetTrainersBySpecialization(context.Background(), specialization)
 		if err != nil {
 			return nil, err
 		}
@@ -175,12 +164,7 @@ func GetTrainersWithCondition(section string, sex string, age string, salary str
 	return &response, nil
 }
 
-func GetTrainersByWorkout(groupNum string, fromDate string, toDate string) (*dto.PersonsListResponse, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Club) GetTrainersByWorkout(groupNum string, fromDate string, toDate string) (*dto.PersonsListResponse, error) {
 	groupNumInt, err := strconv.Atoi(groupNum)
 	if err != nil {
 		return nil, err
@@ -193,7 +177,7 @@ func GetTrainersByWorkout(groupNum string, fromDate string, toDate string) (*dto
 		toDate = "2999-01-01"
 	}
 
-	result, err := dbqueries.GetTrainersByWorkout(pg, context.Background(), groupNumInt, fromDate, toDate)
+	result, err := c.repos.Persons.GetTrainersByWorkout(context.Background(), groupNumInt, fromDate, toDate)
 
 	var response dto.PersonsListResponse
 
@@ -213,34 +197,29 @@ func GetTrainersByWorkout(groupNum string, fromDate string, toDate string) (*dto
 	return &response, nil
 }
 
-func GetManagersWithCondition(salary string, birthYear string, age string, beginYear string, sex string) (*dto.PersonsListResponse, error) {
-	pg, err := db.NewPG(context.Background())
+func (c *Club) GetManagersWithCondition(salary string, birthYear string, age string, beginYear string, sex string) (*dto.PersonsListResponse, error) {
+	result, err := c.repos.Persons.GetAllManagers(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := dbqueries.GetAllManagers(pg, context.Background())
+	result, err = checkParameter(salary, c.repos.Persons.GetManagersBySalary, result)
 	if err != nil {
 		return nil, err
 	}
-
-	result, err = checkParameter(pg, salary, dbqueries.GetManagersBySalary, result)
-	if err != nil {
-		return nil, err
-	}
-	result, err = checkParameter(pg, beginYear, dbqueries.GetManagersByBeginYear, result)
+	result, err = checkParameter(beginYear, c.repos.Persons.GetManagersByBeginYear, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, birthYear, dbqueries.GetManagersByBirthYear, result)
+	result, err = checkParameter(birthYear, c.repos.Persons.GetManagersByBirthYear, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, age, dbqueries.GetManagersByAge, result)
+	result, err = checkParameter(age, c.repos.Persons.GetManagersByAge, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, sex, dbqueries.GetManagersBySex, result)
+	result, err = checkParameter(sex, c.repos.Persons.GetManagersBySex, result)
 	if err != nil {
 		return nil, err
 	}
@@ -263,12 +242,7 @@ func GetManagersWithCondition(salary string, birthYear string, age string, begin
 	return &response, nil
 }
 
-func GetPersonRole(person string, section string) (*dto.PersonRole, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Club) GetPersonRole(person string, section string) (*dto.PersonRole, error) {
 	personInt, err := strconv.Atoi(person)
 	if err != nil {
 		return nil, err
@@ -278,7 +252,7 @@ func GetPersonRole(person string, section string) (*dto.PersonRole, error) {
 		return nil, err
 	}
 
-	role, err := dbqueries.GetPersonRole(pg, context.Background(), personInt, sectionInt)
+	role, err := c.repos.Persons.GetPersonRole(context.Background(), personInt, sectionInt)
 
 	var jsonRole dto.PersonRole
 	if role.Valid {
@@ -288,12 +262,7 @@ func GetPersonRole(person string, section string) (*dto.PersonRole, error) {
 	return nil, nil
 }
 
-func SetPersonRole(person string, section string, role string) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) SetPersonRole(person string, section string, role string) error {
 	personInt, err := strconv.Atoi(person)
 	if err != nil {
 		return err
@@ -307,19 +276,14 @@ func SetPersonRole(person string, section string, role string) error {
 		return err
 	}
 
-	err = dbqueries.UpdatePersonRole(pg, context.Background(), personInt, sectionInt, roleInt)
+	err = c.repos.Persons.UpdatePersonRole(context.Background(), personInt, sectionInt, roleInt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func DeletePersonRole(person string, section string) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) DeletePersonRole(person string, section string) error {
 	personInt, err := strconv.Atoi(person)
 	if err != nil {
 		return err
@@ -329,19 +293,14 @@ func DeletePersonRole(person string, section string) error {
 		return err
 	}
 
-	err = dbqueries.DeletePersonRole(pg, context.Background(), personInt, sectionInt)
+	err = c.repos.Persons.DeletePersonRole(context.Background(), personInt, sectionInt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func CreatePersonAttribute(attr dto.PersonAttribute) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) CreatePersonAttribute(attr dto.PersonAttribute) error {
 	var attrModel model.Attribute
 
 	attrModel.Id = attr.Id
@@ -353,24 +312,19 @@ func CreatePersonAttribute(attr dto.PersonAttribute) error {
 		attrModel.Role.Valid = false
 	}
 
-	err = dbqueries.CreateAttribute(pg, context.Background(), attrModel)
+	err := c.repos.Attributes.CreateAttribute(context.Background(), attrModel)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func GetPersonAttribute(id string) (*dto.PersonAttribute, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Club) GetPersonAttribute(id string) (*dto.PersonAttribute, error) {
 	idInt, err := strconv.Atoi(id)
 
 	var attr dto.PersonAttribute
 	var attrModel *model.Attribute
-	attrModel, err = dbqueries.GetAttribute(pg, context.Background(), idInt)
+	attrModel, err = c.repos.Attributes.GetAttribute(context.Background(), idInt)
 	if err != nil {
 		return nil, err
 	}
@@ -388,12 +342,7 @@ func GetPersonAttribute(id string) (*dto.PersonAttribute, error) {
 	return &attr, nil
 }
 
-func SetPersonAttribute(attr dto.PersonAttribute) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) SetPersonAttribute(attr dto.PersonAttribute) error {
 	var attrModel model.Attribute
 
 	attrModel.Id = attr.Id
@@ -405,7 +354,7 @@ func SetPersonAttribute(attr dto.PersonAttribute) error {
 		attrModel.Role.Valid = false
 	}
 
-	err = dbqueries.UpdateAttribute(pg, context.Background(), attrModel)
+	err := c.repos.Attributes.UpdateAttribute(context.Background(), attrModel)
 	if err != nil {
 		return err
 	}
@@ -413,16 +362,12 @@ func SetPersonAttribute(attr dto.PersonAttribute) error {
 	return nil
 }
 
-func DeletePersonAttribute(attr string) error {
+func (c *Club) DeletePersonAttribute(attr string) error {
 	attrInt, err := strconv.Atoi(attr)
 	if err != nil {
 		return err
 	}
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-	err = dbqueries.DeleteAttribute(pg, context.Background(), attrInt)
+	err = c.repos.Attributes.DeleteAttribute(context.Background(), attrInt)
 	if err != nil {
 		return err
 	}
@@ -430,13 +375,8 @@ func DeletePersonAttribute(attr string) error {
 	return nil
 }
 
-func GetAllRoles() ([]dto.Role, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	rolesModel, err := dbqueries.GetAllRoles(pg, context.Background())
+func (c *Club) GetAllRoles() ([]dto.Role, error) {
+	rolesModel, err := c.repos.Attributes.GetAllRoles(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -451,13 +391,8 @@ func GetAllRoles() ([]dto.Role, error) {
 	return roles, nil
 }
 
-func GetAllAttributes() ([]dto.PersonAttribute, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	attributesModel, err := dbqueries.GetAllAttributes(pg, context.Background())
+func (c *Club) GetAllAttributes() ([]dto.PersonAttribute, error) {
+	attributesModel, err := c.repos.Attributes.GetAllAttributes(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -477,12 +412,7 @@ func GetAllAttributes() ([]dto.PersonAttribute, error) {
 	return attributes, nil
 }
 
-func GetPersonIntAttribute(person string, attribute string) (*int, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Club) GetPersonIntAttribute(person string, attribute string) (*int, error) {
 	personInt, err := strconv.Atoi(person)
 	if err != nil {
 		return nil, err
@@ -492,19 +422,14 @@ func GetPersonIntAttribute(person string, attribute string) (*int, error) {
 		return nil, err
 	}
 
-	val, err := dbqueries.GetPersonIntAttribute(pg, context.Background(), personInt, attributeInt)
+	val, err := c.repos.Attributes.GetPersonIntAttribute(context.Background(), personInt, attributeInt)
 	if err != nil {
 		return nil, err
 	}
 	return val, nil
 }
 
-func GetPersonFloatAttribute(person string, attribute string) (*float64, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Club) GetPersonFloatAttribute(person string, attribute string) (*float64, error) {
 	personInt, err := strconv.Atoi(person)
 	if err != nil {
 		return nil, err
@@ -514,19 +439,14 @@ func GetPersonFloatAttribute(person string, attribute string) (*float64, error)
 		return nil, err
 	}
 
-	val, err := dbqueries.GetPersonFloatAttribute(pg, context.Background(), personInt, attributeInt)
+	val, err := c.repos.Attributes.GetPersonFloatAttribute(context.Background(), personInt, attributeInt)
 	if err != nil {
 		return nil, err
 	}
 	return val, nil
 }
 
-func GetPersonStringAttribute(person string, attribute string) (*string, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Club) GetPersonStringAttribute(person string, attribute string) (*string, error) {
 	personInt, err := strconv.Atoi(person)
 	if err != nil {
 		return nil, err
@@ -536,19 +456,14 @@ func GetPersonStringAttribute(person string, attribute string) (*string, error)
 		return nil, err
 	}
 
-	val, err := dbqueries.GetPersonStringAttribute(pg, context.Background(), personInt, attributeInt)
+	val, err := c.repos.Attributes.GetPersonStringAttribute(context.Background(), personInt, attributeInt)
 	if err != nil {
 		return nil, err
 	}
 	return val, nil
 }
 
-func GetPersonDateAttribute(person string, attribute string) (*string, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Club) GetPersonDateAttribute(person string, attribute string) (*string, error) {
 	personInt, err := strconv.Atoi(person)
 	if err != nil {
 		return nil, err
@@ -558,7 +473,7 @@ func GetPersonDateAttribute(person string, attribute string) (*string, error) {
 		return nil, err
 	}
 
-	val, err := dbqueries.GetPersonDateAttribute(pg, context.Background(), personInt, attributeInt)
+	val, err := c.repos.Attributes.GetPersonDateAttribute(context.Background(), personInt, attributeInt)
 	if err != nil {
 		return nil, err
 	}
@@ -572,27 +487,22 @@ func GetPersonDateAttribute(person string, attribute string) (*string, error) {
 	return &stringVal, nil
 }
 
-func SetPersonIntAttribute(attr dto.PersonIntAttribute) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) SetPersonIntAttribute(attr dto.PersonIntAttribute) error {
 	var attrModel model.PersonIntAttribute
 	attrModel.PersonId = attr.Person
 	attrModel.AttributeId = attr.Attribute
 	attrModel.Value = attr.Value
 
-	val, err := dbqueries.GetPersonIntAttribute(pg, context.Background(), attr.Person, attr.Attribute)
+	val, err := c.repos.Attributes.GetPersonIntAttribute(context.Background(), attr.Person, attr.Attribute)
 	if err != nil {
 		return err
 	}
 
 	if val == nil {
-		err = dbqueries.SetPersonIntAttribute(pg, context.Background(), attrModel)
+		err = c.repos.Attributes.SetPersonIntAttribute(context.Background(), attrModel)
 
 	} else {
-		err = dbqueries.UpdatePersonIntAttribute(pg, context.Background(), attrModel)
+		err = c.repos.Attributes.UpdatePersonIntAttribute(context.Background(), attrModel)
 	}
 
 	if err != nil {
@@ -601,27 +511,22 @@ func SetPersonIntAttribute(attr dto.PersonIntAttribute) error {
 	return nil
 }
 
-func SetPersonFloatAttribute(attr dto.PersonFloatAttribute) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) SetPersonFloatAttribute(attr dto.PersonFloatAttribute) error {
 	var attrModel model.PersonFloatAttribute
 	attrModel.PersonId = attr.Person
 	attrModel.AttributeId = attr.Attribute
 	attrModel.Value = attr.Value
 
-	val, err := dbqueries.GetPersonFloatAttribute(pg, context.Background(), attr.Person, attr.Attribute)
+	val, err := c.repos.Attributes.GetPersonFloatAttribute(context.Background(), attr.Person, attr.Attribute)
 	if err != nil {
 		return err
 	}
 
 	if val == nil {
-		err = dbqueries.SetPersonFloatAttribute(pg, context.Background(), attrModel)
+		err = c.repos.Attributes.SetPersonFloatAttribute(context.Background(), attrModel)
 
 	} else {
-		err = dbqueries.UpdatePersonFloatAttribute(pg, context.Background(), attrModel)
+		err = c.repos.Attributes.UpdatePersonFloatAttribute(context.Background(), attrModel)
 	}
 
 	if err != nil {
@@ -630,27 +535,22 @@ func SetPersonFloatAttribute(attr dto.PersonFloatAttribute) error {
 	return nil
 }
 
-func SetPersonStringAttribute(attr dto.PersonStringAttribute) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) SetPersonStringAttribute(attr dto.PersonStringAttribute) error {
 	var attrModel model.PersonStringAttribute
 	attrModel.PersonId = attr.Person
 	attrModel.AttributeId = attr.Attribute
 	attrModel.Value = attr.Value
 
-	val, err := dbqueries.GetPersonStringAttribute(pg, context.Background(), attr.Person, attr.Attribute)
+	val, err := c.repos.Attributes.GetPersonStringAttribute(context.Background(), attr.Person, attr.Attribute)
 	if err != nil {
 		return err
 	}
 
 	if val == nil {
-		err = dbqueries.SetPersonStringAttribute(pg, context.Background(), attrModel)
+		err = c.repos.Attributes.SetPersonStringAttribute(context.Background(), attrModel)
 
 	} else {
-		err = dbqueries.UpdatePersonStringAttribute(pg, context.Background(), attrModel)
+		err = c.repos.Attributes.UpdatePersonStringAttribute(context.Background(), attrModel)
 	}
 
 	if err != nil {
@@ -659,30 +559,25 @@ func SetPersonStringAttribute(attr dto.PersonStringAttribute) error {
 	return nil
 }
 
-func SetPersonDateAttribute(attr dto.PersonDateAttribute) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) SetPersonDateAttribute(attr dto.PersonDateAttribute) error {
 	var attrModel model.PersonDateAttribute
 	attrModel.PersonId = attr.Person
 	attrModel.AttributeId = attr.Attribute
-	err = attrModel.Value.Scan(attr.Value)
+	err := attrModel.Value.Scan(attr.Value)
 	if err != nil {
 		return err
 	}
 
-	val, err := dbqueries.GetPersonDateAttribute(pg, context.Background(), attr.Person, attr.Attribute)
+	val, err := c.repos.Attributes.GetPersonDateAttribute(context.Background(), attr.Person, attr.Attribute)
 	if err != nil {
 		return err
 	}
 
 	if val == nil {
-		err = dbqueries.SetPersonDateAttribute(pg, context.Background(), attrModel)
+		err = c.repos.Attributes.SetPersonDateAttribute(context.Background(), attrModel)
 
 	} else {
-		err = dbqueries.UpdatePersonDateAttribute(pg, context.Background(), attrModel)
+		err = c.repos.Attributes.UpdatePersonDateAttribute(context.Background(), attrModel)
 	}
 
 	if err != nil {
@@ -691,12 +586,7 @@ func SetPersonDateAttribute(attr dto.PersonDateAttribute) error {
 	return nil
 }
 
-func DeletePersonIntAttribute(person string, attr string) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) DeletePersonIntAttribute(person string, attr string) error {
 	personInt, err := strconv.Atoi(person)
 	if err != nil {
 		return err
@@ -707,19 +597,14 @@ func DeletePersonIntAttribute(person string, attr string) error {
 		return err
 	}
 
-	err = dbqueries.DeletePersonIntAttribute(pg, context.Background(), personInt, attrInt)
+	err = c.repos.Attributes.DeletePersonIntAttribute(context.Background(), personInt, attrInt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func DeletePersonFloatAttribute(person string, attr string) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) DeletePersonFloatAttribute(person string, attr string) error {
 	personInt, err := strconv.Atoi(person)
 	if err != nil {
 		return err
@@ -730,19 +615,14 @@ func DeletePersonFloatAttribute(person string, attr string) error {
 		return err
 	}
 
-	err = dbqueries.DeletePersonFloatAttribute(pg, context.Background(), personInt, attrInt)
+	err = c.repos.Attributes.DeletePersonFloatAttribute(context.Background(), personInt, attrInt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func DeletePersonStringAttribute(person string, attr string) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) DeletePersonStringAttribute(person string, attr string) error {
 	personInt, err := strconv.Atoi(person)
 	if err != nil {
 		return err
@@ -753,19 +633,14 @@ func DeletePersonStringAttribute(person string, attr string) error {
 		return err
 	}
 
-	err = dbqueries.DeletePersonStringAttribute(pg, context.Background(), personInt, attrInt)
+	err = c.repos.Attributes.DeletePersonStringAttribute(context.Background(), personInt, attrInt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func DeletePersonDateAttribute(person string, attr string) error {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return err
-	}
-
+func (c *Club) DeletePersonDateAttribute(person string, attr string) error {
 	personInt, err := strconv.Atoi(person)
 	if err != nil {
 		return err
@@ -776,7 +651,7 @@ func DeletePersonDateAttribute(person string, attr string) error {
 		return err
 	}
 
-	err = dbqueries.DeletePersonDateAttribute(pg, context.Background(), personInt, attrInt)
+	err = c.repos.Attributes.DeletePersonDateAttribute(context.Background(), personInt, attrInt)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"db_backend/dto"
+	"db_backend/model"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGetTouristsWithCondition(t *testing.T) {
+	t.Parallel()
+	c := useMemory(t)
+	run := c.memory.AddFitnessTest(model.FitnessTest{Title: "Бег 3 км", Unit: "мин", HigherIsBetter: false})
+	result := func(person int, value float64, monthsAgo int) {
+		takenOn := time.Now().AddDate(0, -monthsAgo, 0)
+		c.memory.AddFitnessResult(model.FitnessResult{Person: int32(person), Test: int32(run), Value: value,
+			TakenOn: pgtype.Date{Time: takenOn, Valid: true}})
+	}
+	result(c.anna, 14, 1)
+	result(c.anna, 12, 36)
+	result(c.boris, 12.5, 2)
+	result(c.gleb, 11, 1)
+
+	tests := []struct {
+		name                                string
+		section, group, sex, birthYear, age string
+		fitnessTest, fitnessValue, months   string
+		want                                []int32
+	}{
+		{name: "all", want: ids(c.anna, c.boris, c.vera)},
+		{name: "section", section: itoa(c.sectionA), want: ids(c.anna, c.boris)},
+		{name: "group", group: itoa(c.groupB), want: ids(c.vera)},
+		{name: "sex", sex: "2", want: ids(c.anna, c.vera)},
+		{name: "birth year", birthYear: "2000", want: ids(c.anna, c.vera)},
+		{name: "age", age: "24", want: ids(c.vera)},
+		{name: "section and sex", section: itoa(c.sectionA), sex: "2", want: ids(c.anna)},
+		{name: "fitness", fitnessTest: itoa(run), fitnessValue: "13", want: ids(c.boris)},
+		{name: "fitness long ago", fitnessTest: itoa(run), fitnessValue: "13", months: "48", want: ids(c.anna, c.boris)},
+		{name: "fitness this month", fitnessTest: itoa(run), fitnessValue: "13", months: "1", want: ids()},
+		{name: "nobody", section: itoa(c.sectionB), sex: "1", want: ids()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			response, err := c.GetTouristsWithCondition(tt.section, tt.group, tt.sex, tt.birthYear, tt.age,
+				tt.fitnessTest, tt.fitnessValue, tt.months)
+			got := personIds(t, response, err)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTouristsWithConditionRejectsBadParameters(t *testing.T) {
+	t.Parallel()
+	c := useMemory(t)
+	_, err := c.GetTouristsWithCondition("first", "", "", "", "", "", "", "")
+	if err == nil {
+		t.Error("expected an error for a section that is not a number")
+	}
+	_, err = c.GetTouristsWithCondition("", "", "", "", "", "1", "", "")
+	if err == nil {
+		t.Error("expected an error for a fitness test without a value")
+	}
+}
+
+func TestGetTrainersWithCondition(t *testing.T) {
+	t.Parallel()
+	c := useMemory(t)
+	tests := []struct {
+		name                                      string
+		section, sex, age, salary, specialization string
+		want                                      []int32
+	}{
+		{name: "all", want: ids(c.gleb, c.dina)},
+		{name: "section", section: itoa(c.sectionB), want: ids(c.dina)},
+		{name: "sex", sex: "1", want: ids(c.gleb)},
+		{name: "age", age: "40", want: ids(c.gleb)},
+		{name: "salary", salary: "60000", want: ids(c.dina)},
+		{name: "specialization", specialization: "скалолазание", want: ids(c.gleb)},
+		{name: "section and specialization", section: itoa(c.sectionA), specialization: "сплав", want: ids()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			response, err := c.GetTrainersWithCondition(tt.section, tt.sex, tt.age, tt.salary, tt.specialization)
+			got := personIds(t, response, err)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetManagersWithCondition(t *testing.T) {
+	t.Parallel()
+	c := useMemory(t)
+	tests := []struct {
+		name                                   string
+		salary, birthYear, age, beginYear, sex string
+		want                                   []int32
+	}{
+		{name: "all", want: ids(c.egor)},
+		{name: "salary", salary: "90000", want: ids(c.egor)},
+		{name: "begin year", beginYear: "2015", want: ids(c.egor)},
+		{name: "other begin year", beginYear: "2016", want: ids()},
+		{name: "age", age: "45", birthYear: "1980", want: ids(c.egor)},
+		{name: "sex", sex: "2", want: ids()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			response, err := c.GetManagersWithCondition(tt.salary, tt.birthYear, tt.age, tt.beginYear, tt.sex)
+			got := personIds(t, response, err)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPersonRole(t *testing.T) {
+	t.Parallel()
+	c := useMemory(t)
+	role, err := c.GetPersonRole(itoa(c.boris), itoa(c.sectionA))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role == nil || role.Role != 1 {
+		t.Errorf("got %v, want role 1", role)
+	}
+	role, err = c.GetPersonRole(itoa(c.boris), itoa(c.sectionB))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role != nil {
+		t.Errorf("got %v outside the section, want none", role)
+	}
+}
+
+func TestSetPersonIntAttribute(t *testing.T) {
+	t.Parallel()
+	c := useMemory(t)
+	err := c.SetPersonIntAttribute(dto.PersonIntAttribute{Person: c.anna, Attribute: 1, Value: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := c.GetPersonIntAttribute(itoa(c.anna), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value == nil || *value != 1 {
+		t.Errorf("got %v, want 1", value)
+	}
+}
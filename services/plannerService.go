@@ -0,0 +1,571 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DefaultInstructorRatio is how many participants one instructor may lead when
+// the planner request does not say otherwise.
+const DefaultInstructorRatio = 8
+
+type plannerTarget struct {
+	tour      model.PlanDraftTour
+	route     model.Route
+	from      time.Time
+	to        time.Time
+	qualified []int32
+	busy      map[int32]bool
+	capacity  int
+}
+
+func (t *plannerTarget) overlaps(other *plannerTarget) bool {
+	return t.from.Before(other.to) && other.from.Before(t.to)
+}
+
+func (t *plannerTarget) fill() float64 {
+	if t.capacity == 0 {
+		return 1
+	}
+	return float64(len(t.tour.Members)) / float64(t.capacity)
+}
+
+// planUnit is a set of tourists the planner tries to put on the same tour:
+// members of one existing group, or a single tourist.
+type planUnit struct {
+	group   int32
+	members []int32
+}
+
+// assignInstructors gives every target one qualified instructor who is free on
+// its dates, starting with the targets that have the fewest candidates.
+func assignInstructors(targets []*plannerTarget) []string {
+	order := make([]*plannerTarget, len(targets))
+	copy(order, targets)
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(order[i].qualified) < len(order[j].qualified)
+	})
+
+	var warnings []string
+	for _, target := range order {
+		for _, instructor := range target.qualified {
+			if target.busy[instructor] {
+				continue
+			}
+			taken := false
+			for _, other := range targets {
+				if other != target && other.tour.Instructor.Int32 == instructor && other.tour.Instructor.Valid && other.overlaps(target) {
+					taken = true
+					break
+				}
+			}
+			if !taken {
+				target.tour.Instructor.Int32 = instructor
+				target.tour.Instructor.Valid = true
+				break
+			}
+		}
+		if !target.tour.Instructor.Valid {
+			warnings = append(warnings, fmt.Sprintf("no free instructor qualified for route %d starting %s",
+				target.route.Id, target.from.Format("2006-01-02")))
+		}
+	}
+	return warnings
+}
+
+// composeGroups spreads tourists over the targets. Existing groups are placed
+// whole where they fit, biggest first, on the tour that is least full relative
+// to its capacity; a group that fits nowhere is split. Tourists may only go on
+// routes at most one difficulty above what they completed on that route type.
+func composeGroups(targets []*plannerTarget, units []planUnit, levels map[int32]map[int32]int32) ([]model.PlanUnassigned, []string) {
+	eligible := func(target *plannerTarget, person int32) bool {
+		if target.busy[person] {
+			return false
+		}
+		return target.route.Difficulty.Int32 <= levels[person][target.route.TypeId.Int32]+1
+	}
+	place := func(members []int32) *plannerTarget {
+		var best *plannerTarget
+		for _, target := range targets {
+			if target.capacity-len(target.tour.Members) < len(members) {
+				continue
+			}
+			fits := true
+			for _, person := range members {
+				fits = fits && eligible(target, person)
+			}
+			if fits && (best == nil || target.fill() < best.fill()) {
+				best = target
+			}
+		}
+		if best != nil {
+			best.tour.Members = append(best.tour.Members, members...)
+		}
+		return best
+	}
+
+	sort.SliceStable(units, func(i, j int) bool {
+		return len(units[i].members) > len(units[j].members)
+	})
+
+	var unassigned []model.PlanUnassigned
+	var warnings []string
+	for _, unit := range units {
+		if place(unit.members) != nil {
+			continue
+		}
+		if len(unit.members) > 1 {
+			warnings = append(warnings, fmt.Sprintf("group %d could not be kept together", unit.group))
+		}
+		for _, person := range unit.members {
+			if len(unit.members) > 1 && place([]int32{person}) != nil {
+				continue
+			}
+			reason := "not experienced enough for any of the routes"
+			for _, target := range targets {
+				if target.route.Difficulty.Int32 > levels[person][target.route.TypeId.Int32]+1 {
+					continue
+				}
+				if target.busy[person] {
+					reason = "already on a tour on those dates"
+				} else {
+					reason = "all suitable tours are full"
+					break
+				}
+			}
+			unassigned = append(unassigned, model.PlanUnassigned{Person: person, Reason: reason})
+		}
+	}
+
+	for _, target := range targets {
+		slices.Sort(target.tour.Members)
+		if target.tour.MinParticipants.Valid && len(target.tour.Members) < int(target.tour.MinParticipants.Int32) {
+			warnings = append(warnings, fmt.Sprintf("route %d starting %s has %d of at least %d participants",
+				target.route.Id, target.from.Format("2006-01-02"), len(target.tour.Members), target.tour.MinParticipants.Int32))
+		}
+	}
+	return unassigned, warnings
+}
+
+func planDraft2Dto(pg *db.Postgres, ctx context.Context, draft model.PlanDraft) (*dto.PlanDraft, error) {
+	ids := []int32{}
+	for _, tour := range draft.Tours {
+		ids = append(ids, tour.Members...)
+		if tour.Instructor.Valid {
+			ids = append(ids, tour.Instructor.Int32)
+		}
+	}
+	for _, unassigned := range draft.Unassigned {
+		ids = append(ids, unassigned.Person)
+	}
+	persons, err := dbqueries.GetPersonsByIds(pg, ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byId := map[int32]dto.PersonResponse{}
+	for _, person := range persons {
+		byId[person.Id] = person2Response(person)
+	}
+
+	var response dto.PlanDraft
+	response.Id = draft.Id
+	if draft.Section.Valid {
+		response.Section = &draft.Section.Int32
+	}
+	response.Status = draft.Status
+	response.CreatedAt = draft.CreatedAt.Time.Format("2006-01-02 15:04:05")
+	if draft.AcceptedAt.Valid {
+		response.AcceptedAt = draft.AcceptedAt.Time.Format("2006-01-02 15:04:05")
+	}
+	response.InstructorRatio = draft.InstructorRatio
+	response.Tours = []dto.PlanDraftTour{}
+	for _, tour := range draft.Tours {
+		route, err := dbqueries.GetRoute(pg, ctx, int(tour.Route))
+		if err != nil {
+			return nil, err
+		}
+		var jsonTour dto.PlanDraftTour
+		jsonTour.Route = tour.Route
+		if route != nil {
+			jsonTour.RouteType = route.Type.String
+			if route.Difficulty.Valid {
+				jsonTour.Difficulty = &route.Difficulty.Int32
+			}
+		}
+		jsonTour.Start = tour.Start.Time.Format("2006-01-02")
+		jsonTour.DurationDays = tour.DurationDays
+		if tour.Instructor.Valid {
+			instructor := byId[tour.Instructor.Int32]
+			jsonTour.Instructor = &instructor
+		}
+		if tour.MinParticipants.Valid {
+			jsonTour.MinParticipants = &tour.MinParticipants.Int32
+		}
+		jsonTour.MaxParticipants = tour.MaxParticipants
+		jsonTour.Members = []dto.PersonResponse{}
+		for _, member := range tour.Members {
+			jsonTour.Members = append(jsonTour.Members, byId[member])
+		}
+		if tour.Tour.Valid {
+			jsonTour.Tour = &tour.Tour.Int32
+		}
+		response.Tours = append(response.Tours, jsonTour)
+	}
+	response.Unassigned = []dto.PlanUnassigned{}
+	for _, unassigned := range draft.Unassigned {
+		response.Unassigned = append(response.Unassigned, dto.PlanUnassigned{Person: byId[unassigned.Person], Reason: unassigned.Reason})
+	}
+	response.Warnings = draft.Warnings
+	if response.Warnings == nil {
+		response.Warnings = []string{}
+	}
+	return &response, nil
+}
+
+// PlanTourGroups proposes how to split the candidate tourists over parallel
+// tours and stores the proposal as a draft. Every tour gets one instructor, so
+// it takes at most instructor_ratio participants; a route asking for more is
+// warned about rather than silently cut.
+func (c *Club) PlanTourGroups(viewer string, request dto.PlannerRequest) (*dto.PlanDraft, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+
+	if len(request.Routes) == 0 || len(request.Tourists) == 0 {
+		return nil, fmt.Errorf("planner needs at least one route and one tourist")
+	}
+	var draft model.PlanDraft
+	draft.InstructorRatio = request.InstructorRatio
+	if draft.InstructorRatio == 0 {
+		draft.InstructorRatio = DefaultInstructorRatio
+	}
+	if draft.InstructorRatio < 0 {
+		return nil, fmt.Errorf("instructor ratio must be positive")
+	}
+	if request.Section != nil {
+		draft.Section.Int32 = *request.Section
+		draft.Section.Valid = true
+	}
+	if viewerInt, err := strconv.Atoi(viewer); err == nil {
+		draft.CreatedBy.Int32 = int32(viewerInt)
+		draft.CreatedBy.Valid = true
+	}
+
+	candidates := request.Instructors
+	if len(candidates) == 0 {
+		instructors, err := dbqueries.GetAllInstructors(pg, ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, instructor := range instructors {
+			candidates = append(candidates, instructor.Id)
+		}
+	}
+
+	var targets []*plannerTarget
+	var limited []string
+	for _, routeRequest := range request.Routes {
+		route, err := dbqueries.GetRoute(pg, ctx, int(routeRequest.Route))
+		if err != nil {
+			return nil, err
+		}
+		if route == nil {
+			return nil, fmt.Errorf("route %d not found", routeRequest.Route)
+		}
+		start, err := time.Parse("2006-01-02", routeRequest.Start)
+		if err != nil {
+			return nil, err
+		}
+		if routeRequest.DurationDays <= 0 || routeRequest.MaxParticipants <= 0 {
+			return nil, fmt.Errorf("route %d needs a positive duration and participant limit", route.Id)
+		}
+		if routeRequest.MaxParticipants > draft.InstructorRatio {
+			limited = append(limited, fmt.Sprintf("route %d starting %s takes %d participants, not %d: a tour has one instructor, who leads at most %d",
+				route.Id, routeRequest.Start, draft.InstructorRatio, routeRequest.MaxParticipants, draft.InstructorRatio))
+		}
+
+		target := &plannerTarget{route: *route, from: start, to: start.AddDate(0, 0, int(routeRequest.DurationDays))}
+		target.tour.Route = route.Id
+		target.tour.Start.Time = start
+		target.tour.Start.Valid = true
+		target.tour.DurationDays = routeRequest.DurationDays
+		target.tour.MaxParticipants = routeRequest.MaxParticipants
+		if routeRequest.MinParticipants != nil {
+			target.tour.MinParticipants.Int32 = *routeRequest.MinParticipants
+			target.tour.MinParticipants.Valid = true
+		}
+
+		qualified, err := dbqueries.GetInstructorsByCategory(pg, ctx, int(route.TypeId.Int32), int(route.Difficulty.Int32))
+		if err != nil {
+			return nil, err
+		}
+		for _, instructor := range qualified {
+			if slices.Contains(candidates, instructor.Id) {
+				target.qualified = append(target.qualified, instructor.Id)
+			}
+		}
+		busy, err := dbqueries.GetBusyPersons(pg, ctx, start.Format("2006-01-02"), target.to.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+		target.busy = map[int32]bool{}
+		for _, person := range busy {
+			target.busy[person] = true
+		}
+		targets = append(targets, target)
+	}
+
+	draft.Warnings = append(limited, assignInstructors(targets)...)
+	leads := map[int32]bool{}
+	for _, target := range targets {
+		if target.tour.Instructor.Valid {
+			target.capacity = int(min(target.tour.MaxParticipants, draft.InstructorRatio))
+			leads[target.tour.Instructor.Int32] = true
+		}
+	}
+
+	levelList, err := dbqueries.GetRouteTypeLevelsOf(pg, ctx, request.Tourists)
+	if err != nil {
+		return nil, err
+	}
+	levels := map[int32]map[int32]int32{}
+	for _, level := range levelList {
+		if levels[level.Person] == nil {
+			levels[level.Person] = map[int32]int32{}
+		}
+		levels[level.Person][level.Type] = level.MaxDifficulty
+	}
+
+	var tourists []int32
+	seen := map[int32]bool{}
+	for _, tourist := range request.Tourists {
+		if seen[tourist] {
+			continue
+		}
+		seen[tourist] = true
+		if leads[tourist] {
+			draft.Unassigned = append(draft.Unassigned, model.PlanUnassigned{Person: tourist, Reason: "leads a tour of this plan"})
+			continue
+		}
+		err := c.checkDues(ctx, int(tourist))
+		if err != nil {
+			draft.Unassigned = append(draft.Unassigned, model.PlanUnassigned{Person: tourist, Reason: err.Error()})
+			continue
+		}
+		tourists = append(tourists, tourist)
+	}
+	memberships, err := dbqueries.GetGroupMemberships(pg, ctx, tourists, draft.Section)
+	if err != nil {
+		return nil, err
+	}
+	grouped := map[int32]bool{}
+	unitIndex := map[int32]int{}
+	var units []planUnit
+	for _, membership := range memberships {
+		if grouped[membership.Person] {
+			continue
+		}
+		grouped[membership.Person] = true
+		index, ok := unitIndex[membership.Group]
+		if !ok {
+			index = len(units)
+			unitIndex[membership.Group] = index
+			units = append(units, planUnit{group: membership.Group})
+		}
+		units[index].members = append(units[index].members, membership.Person)
+	}
+	for _, tourist := range tourists {
+		if !grouped[tourist] {
+			units = append(units, planUnit{members: []int32{tourist}})
+		}
+	}
+
+	unassigned, warnings := composeGroups(targets, units, levels)
+	draft.Unassigned = append(draft.Unassigned, unassigned...)
+	draft.Warnings = append(draft.Warnings, warnings...)
+	for _, target := range targets {
+		draft.Tours = append(draft.Tours, target.tour)
+	}
+
+	var id int
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		id, err = dbqueries.CreatePlanDraft(tx, ctx, draft)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	saved, err := dbqueries.GetPlanDraft(pg, ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return planDraft2Dto(pg, ctx, *saved)
+}
+
+func GetPlanDraft(id string) (*dto.PlanDraft, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+	draft, err := dbqueries.GetPlanDraft(pg, context.Background(), idInt)
+	if err != nil {
+		return nil, err
+	}
+	if draft == nil {
+		return nil, fmt.Errorf("plan draft %d not found", idInt)
+	}
+	return planDraft2Dto(pg, context.Background(), *draft)
+}
+
+// recheckPlanDraft repeats the checks of PlanTourGroups against the current
+// state, since tours and payments may have changed after the draft was made.
+// An instructor who is no longer free fails the draft; members who went on
+// another tour or fell behind with their dues are taken off their tour and
+// reported among the unassigned.
+func (c *Club) recheckPlanDraft(tx *db.Postgres, ctx context.Context, draft *model.PlanDraft) error {
+	for i := range draft.Tours {
+		draftTour := &draft.Tours[i]
+		if !draftTour.Instructor.Valid {
+			continue
+		}
+		from := draftTour.Start.Time
+		to := from.AddDate(0, 0, int(draftTour.DurationDays))
+		busyList, err := dbqueries.GetBusyPersons(tx, ctx, from.Format("2006-01-02"), to.Format("2006-01-02"))
+		if err != nil {
+			return err
+		}
+		busy := map[int32]bool{}
+		for _, person := range busyList {
+			busy[person] = true
+		}
+		if busy[draftTour.Instructor.Int32] {
+			return fmt.Errorf("instructor %d is no longer free for route %d starting %s",
+				draftTour.Instructor.Int32, draftTour.Route, from.Format("2006-01-02"))
+		}
+
+		var members []int32
+		for _, member := range draftTour.Members {
+			reason := ""
+			if busy[member] {
+				reason = "already on a tour on those dates"
+			} else if err := c.checkDues(ctx, int(member)); err != nil {
+				reason = err.Error()
+			}
+			if reason == "" {
+				members = append(members, member)
+				continue
+			}
+			err = dbqueries.UnassignPlanDraftMember(tx, ctx, int(draft.Id), int(draftTour.Id), int(member), reason)
+			if err != nil {
+				return err
+			}
+		}
+		draftTour.Members = members
+	}
+	return nil
+}
+
+// AcceptPlanDraft creates the planned tours of a draft and enrolls their
+// members, all or nothing, after checking the draft is still feasible.
+func (c *Club) AcceptPlanDraft(id string) (*dto.PlanDraft, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		draft, err := dbqueries.GetPlanDraftForUpdate(tx, ctx, idInt)
+		if err != nil {
+			return err
+		}
+		if draft == nil {
+			return fmt.Errorf("plan draft %d not found", idInt)
+		}
+		if draft.Status != model.DraftOpen {
+			return fmt.Errorf("plan draft %d is %s", idInt, draft.Status)
+		}
+		err = c.recheckPlanDraft(tx, ctx, draft)
+		if err != nil {
+			return err
+		}
+
+		for _, draftTour := range draft.Tours {
+			if !draftTour.Instructor.Valid {
+				if len(draftTour.Members) > 0 {
+					return fmt.Errorf("route %d has participants but no instructor", draftTour.Route)
+				}
+				continue
+			}
+			var tour model.Tour
+			tour.Route = draftTour.Route
+			tour.Instructor = draftTour.Instructor.Int32
+			tour.Start = draftTour.Start
+			tour.DurationDays = draftTour.DurationDays
+			tour.MinParticipants = draftTour.MinParticipants
+			tour.MaxParticipants.Int32 = draftTour.MaxParticipants
+			tour.MaxParticipants.Valid = true
+			tourId, err := dbqueries.CreateTour(tx, ctx, tour)
+			if err != nil {
+				return err
+			}
+			for _, member := range draftTour.Members {
+				err = dbqueries.EnrollPerson(tx, ctx, tourId, int(member), model.EnrollmentEnrolled)
+				if err != nil {
+					return err
+				}
+			}
+			err = dbqueries.SetPlanDraftTour(tx, ctx, int(draftTour.Id), tourId)
+			if err != nil {
+				return err
+			}
+		}
+		return dbqueries.SetPlanDraftStatus(tx, ctx, idInt, model.DraftAccepted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return GetPlanDraft(id)
+}
+
+func DiscardPlanDraft(id string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+	draft, err := dbqueries.GetPlanDraft(pg, context.Background(), idInt)
+	if err != nil {
+		return err
+	}
+	if draft == nil {
+		return fmt.Errorf("plan draft %d not found", idInt)
+	}
+	if draft.Status != model.DraftOpen {
+		return fmt.Errorf("plan draft %d is %s", idInt, draft.Status)
+	}
+	return dbqueries.SetPlanDraftStatus(pg, context.Background(), idInt, model.DraftDiscarded)
+}
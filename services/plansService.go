@@ -0,0 +1,479 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+func parseDateSpan(startsOn string, endsOn string) (model.TrainingCycle, error) {
+	var span model.TrainingCycle
+	err := span.StartsOn.Scan(startsOn)
+	if err != nil {
+		return span, err
+	}
+	err = span.EndsOn.Scan(endsOn)
+	if err != nil {
+		return span, err
+	}
+	if span.EndsOn.Time.Before(span.StartsOn.Time) {
+		return span, errors.New("ends_on is before starts_on")
+	}
+	return span, nil
+}
+
+func trainingPlan2Dto(plan model.TrainingPlan) dto.TrainingPlan {
+	var jsonPlan dto.TrainingPlan
+	jsonPlan.Id = plan.Id
+	jsonPlan.Group = plan.Group
+	jsonPlan.Title = plan.Title
+	jsonPlan.StartsOn = plan.StartsOn.Time.Format("2006-01-02")
+	jsonPlan.EndsOn = plan.EndsOn.Time.Format("2006-01-02")
+	return jsonPlan
+}
+
+func trainingCycle2Dto(cycle model.TrainingCycle) dto.TrainingCycle {
+	var jsonCycle dto.TrainingCycle
+	jsonCycle.Id = cycle.Id
+	jsonCycle.Plan = cycle.Plan
+	if cycle.Parent.Valid {
+		jsonCycle.Parent = &cycle.Parent.Int32
+	}
+	jsonCycle.Kind = cycle.Kind
+	jsonCycle.Title = cycle.Title
+	jsonCycle.StartsOn = cycle.StartsOn.Time.Format("2006-01-02")
+	jsonCycle.EndsOn = cycle.EndsOn.Time.Format("2006-01-02")
+	jsonCycle.Targets = []dto.CycleTarget{}
+	return jsonCycle
+}
+
+func targets2Model(cycle int32, targets []dto.CycleTarget) ([]model.CycleTarget, error) {
+	var result []model.CycleTarget
+	seen := make(map[string]bool)
+	for _, target := range targets {
+		if target.Hours <= 0 {
+			return nil, fmt.Errorf("target volume of %q must be positive", target.WorkoutType)
+		}
+		if seen[target.WorkoutType] {
+			return nil, fmt.Errorf("workout type %q is targeted twice", target.WorkoutType)
+		}
+		seen[target.WorkoutType] = true
+		result = append(result, model.CycleTarget{
+			Cycle:       cycle,
+			WorkoutType: target.WorkoutType,
+			Minutes:     int32(math.Round(target.Hours * 60)),
+		})
+	}
+	return result, nil
+}
+
+func CreateTrainingPlan(plan dto.TrainingPlan) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	group, err := dbqueries.GetGroup(pg, context.Background(), int(plan.Group))
+	if err != nil {
+		return -1, err
+	}
+	if group == nil {
+		return -1, fmt.Errorf("group %d not found", plan.Group)
+	}
+	span, err := parseDateSpan(plan.StartsOn, plan.EndsOn)
+	if err != nil {
+		return -1, err
+	}
+
+	var planModel model.TrainingPlan
+	planModel.Group = plan.Group
+	planModel.Title = plan.Title
+	planModel.StartsOn = span.StartsOn
+	planModel.EndsOn = span.EndsOn
+
+	return dbqueries.CreateTrainingPlan(pg, context.Background(), planModel)
+}
+
+func GetTrainingPlans(group string) ([]dto.TrainingPlan, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	groupInt, err := strconv.Atoi(group)
+	if err != nil {
+		return nil, err
+	}
+
+	plans, err := dbqueries.GetTrainingPlans(pg, context.Background(), groupInt)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.TrainingPlan
+	for _, plan := range plans {
+		result = append(result, trainingPlan2Dto(plan))
+	}
+	return result, nil
+}
+
+// GetTrainingPlan returns the plan with its mesocycles, each holding its microcycles.
+// Microcycles whose mesocycle is not part of the plan are listed as orphans.
+func GetTrainingPlan(id string) (*dto.TrainingPlanResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := dbqueries.GetTrainingPlan(pg, context.Background(), idInt)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, fmt.Errorf("training plan %d not found", idInt)
+	}
+	cycles, err := dbqueries.GetTrainingCycles(pg, context.Background(), idInt)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := dbqueries.GetPlanTargets(pg, context.Background(), idInt)
+	if err != nil {
+		return nil, err
+	}
+
+	byCycle := make(map[int32][]dto.CycleTarget)
+	for _, target := range targets {
+		byCycle[target.Cycle] = append(byCycle[target.Cycle], dto.CycleTarget{
+			WorkoutType: target.WorkoutType,
+			Hours:       minutes2Hours(target.Minutes),
+		})
+	}
+
+	var response dto.TrainingPlanResponse
+	response.TrainingPlan = trainingPlan2Dto(*plan)
+	response.Cycles = []dto.TrainingCycle{}
+
+	jsonCycles := make([]dto.TrainingCycle, len(cycles))
+	for i, cycle := range cycles {
+		jsonCycles[i] = trainingCycle2Dto(cycle)
+		if byCycle[cycle.Id] != nil {
+			jsonCycles[i].Targets = byCycle[cycle.Id]
+		}
+	}
+
+	// mesocycles first, so that microcycles find their parent whatever the order
+	mesoIndex := make(map[int32]int)
+	for i, cycle := range cycles {
+		if cycle.Kind == model.CycleMeso {
+			mesoIndex[cycle.Id] = len(response.Cycles)
+			response.Cycles = append(response.Cycles, jsonCycles[i])
+		}
+	}
+	for i, cycle := range cycles {
+		if cycle.Kind == model.CycleMeso {
+			continue
+		}
+		parent, ok := mesoIndex[cycle.Parent.Int32]
+		if !cycle.Parent.Valid || !ok {
+			response.Orphans = append(response.Orphans, jsonCycles[i])
+			continue
+		}
+		response.Cycles[parent].Cycles = append(response.Cycles[parent].Cycles, jsonCycles[i])
+	}
+	return &response, nil
+}
+
+func DeleteTrainingPlan(id string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+
+	return dbqueries.DeleteTrainingPlan(pg, context.Background(), idInt)
+}
+
+// CreateTrainingCycle adds a mesocycle to a plan or a microcycle to a mesocycle.
+// A cycle has to fit into its parent and must not overlap its siblings.
+func CreateTrainingCycle(cycle dto.TrainingCycle) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	span, err := parseDateSpan(cycle.StartsOn, cycle.EndsOn)
+	if err != nil {
+		return -1, err
+	}
+
+	var cycleId int
+	err = pg.InTx(context.Background(), func(tx *db.Postgres) error {
+		ctx := context.Background()
+		plan, err := dbqueries.GetTrainingPlan(tx, ctx, int(cycle.Plan))
+		if err != nil {
+			return err
+		}
+		if plan == nil {
+			return fmt.Errorf("training plan %d not found", cycle.Plan)
+		}
+		cycles, err := dbqueries.GetTrainingCycles(tx, ctx, int(cycle.Plan))
+		if err != nil {
+			return err
+		}
+
+		var cycleModel model.TrainingCycle
+		cycleModel.Plan = cycle.Plan
+		cycleModel.Kind = cycle.Kind
+		cycleModel.Title = cycle.Title
+		cycleModel.StartsOn = span.StartsOn
+		cycleModel.EndsOn = span.EndsOn
+
+		outer := model.TrainingCycle{StartsOn: plan.StartsOn, EndsOn: plan.EndsOn}
+		switch cycle.Kind {
+		case model.CycleMeso:
+			if cycle.Parent != nil {
+				return errors.New("a mesocycle can not have a parent")
+			}
+		case model.CycleMicro:
+			if cycle.Parent == nil {
+				return errors.New("a microcycle needs a parent mesocycle")
+			}
+			found := false
+			for _, c := range cycles {
+				if c.Id == *cycle.Parent && c.Kind == model.CycleMeso {
+					outer = c
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("mesocycle %d not found in plan %d", *cycle.Parent, cycle.Plan)
+			}
+			cycleModel.Parent.Int32 = *cycle.Parent
+			cycleModel.Parent.Valid = true
+		default:
+			return fmt.Errorf("unknown cycle kind %q", cycle.Kind)
+		}
+
+		if span.StartsOn.Time.Before(outer.StartsOn.Time) || span.EndsOn.Time.After(outer.EndsOn.Time) {
+			return fmt.Errorf("cycle must lie within %s - %s",
+				outer.StartsOn.Time.Format("2006-01-02"), outer.EndsOn.Time.Format("2006-01-02"))
+		}
+		for _, c := range cycles {
+			if c.Kind != cycleModel.Kind || c.Parent != cycleModel.Parent {
+				continue
+			}
+			if !span.EndsOn.Time.Before(c.StartsOn.Time) && !c.EndsOn.Time.Before(span.StartsOn.Time) {
+				return fmt.Errorf("cycle overlaps %s cycle %d", c.Kind, c.Id)
+			}
+		}
+
+		cycleId, err = dbqueries.CreateTrainingCycle(tx, ctx, cycleModel)
+		if err != nil {
+			return err
+		}
+		targets, err := targets2Model(int32(cycleId), cycle.Targets)
+		if err != nil {
+			return err
+		}
+		return dbqueries.SetCycleTargets(tx, ctx, cycleId, targets)
+	})
+	if err != nil {
+		return -1, err
+	}
+	return cycleId, nil
+}
+
+func DeleteTrainingCycle(id string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+
+	return dbqueries.DeleteTrainingCycle(pg, context.Background(), idInt)
+}
+
+func SetCycleTargets(req dto.CycleTargetsRequest) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	targets, err := targets2Model(req.Cycle, req.Targets)
+	if err != nil {
+		return err
+	}
+
+	return pg.InTx(context.Background(), func(tx *db.Postgres) error {
+		cycle, err := dbqueries.GetTrainingCycle(tx, context.Background(), int(req.Cycle))
+		if err != nil {
+			return err
+		}
+		if cycle == nil {
+			return fmt.Errorf("training cycle %d not found", req.Cycle)
+		}
+		return dbqueries.SetCycleTargets(tx, context.Background(), int(req.Cycle), targets)
+	})
+}
+
+// compareVolumes sets planned volume scaled by progress against the actual one.
+// Within the tolerance share of the expected volume a type is on track.
+func compareVolumes(planned map[string]int32, actual map[string]int32, progress float64, tolerance float64) []dto.PlanComparisonLine {
+	var types []string
+	for workoutType := range planned {
+		types = append(types, workoutType)
+	}
+	for workoutType := range actual {
+		if _, ok := planned[workoutType]; !ok {
+			types = append(types, workoutType)
+		}
+	}
+	sort.Strings(types)
+
+	lines := []dto.PlanComparisonLine{}
+	for _, workoutType := range types {
+		var line dto.PlanComparisonLine
+		line.WorkoutType = workoutType
+		line.Planned = minutes2Hours(planned[workoutType])
+		line.Expected = math.Round(line.Planned*progress*100) / 100
+		line.Actual = minutes2Hours(actual[workoutType])
+		line.Deviation = math.Round((line.Actual-line.Expected)*100) / 100
+
+		switch {
+		case planned[workoutType] == 0:
+			line.Status = "unplanned"
+		case progress == 0:
+			line.Status = "upcoming"
+		case line.Actual < line.Expected*(1-tolerance):
+			line.Status = "behind"
+		case line.Actual > line.Expected*(1+tolerance):
+			line.Status = "ahead"
+		default:
+			line.Status = "on_track"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func spanProgress(span model.TrainingCycle, date time.Time) float64 {
+	total := span.EndsOn.Time.Sub(span.StartsOn.Time).Hours()/24 + 1
+	elapsed := date.Sub(span.StartsOn.Time).Hours()/24 + 1
+	progress := math.Max(0, math.Min(1, elapsed/total))
+	return math.Round(progress*1000) / 1000
+}
+
+// ComparePlan shows for the plan and each cycle how far the group is behind or
+// ahead of the planned volume as of date. The plan as a whole is planned by the
+// sum of its mesocycle targets.
+func ComparePlan(id string, date string, tolerance string) (*dto.PlanComparisonResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+	dateReady, err := parseDateOrToday(date)
+	if err != nil {
+		return nil, err
+	}
+	toleranceFloat := 10.0
+	if tolerance != "" {
+		toleranceFloat, err = strconv.ParseFloat(tolerance, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plan, err := dbqueries.GetTrainingPlan(pg, context.Background(), idInt)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, fmt.Errorf("training plan %d not found", idInt)
+	}
+	cycles, err := dbqueries.GetTrainingCycles(pg, context.Background(), idInt)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := dbqueries.GetPlanTargets(pg, context.Background(), idInt)
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := dbqueries.GetPlanVolumes(pg, context.Background(), idInt, dateReady.Time.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := make(map[int32]string)
+	for _, cycle := range cycles {
+		kinds[cycle.Id] = cycle.Kind
+	}
+	planned := make(map[int32]map[string]int32)
+	actual := make(map[int32]map[string]int32)
+	planned[0] = make(map[string]int32)
+	actual[0] = make(map[string]int32)
+	for _, target := range targets {
+		if planned[target.Cycle] == nil {
+			planned[target.Cycle] = make(map[string]int32)
+		}
+		planned[target.Cycle][target.WorkoutType] += target.Minutes
+		if kinds[target.Cycle] == model.CycleMeso {
+			planned[0][target.WorkoutType] += target.Minutes
+		}
+	}
+	for _, volume := range volumes {
+		if actual[volume.Cycle] == nil {
+			actual[volume.Cycle] = make(map[string]int32)
+		}
+		actual[volume.Cycle][volume.WorkoutType] += volume.Minutes
+	}
+
+	var response dto.PlanComparisonResponse
+	response.Plan = trainingPlan2Dto(*plan)
+	response.Date = dateReady.Time.Format("2006-01-02")
+	response.Tolerance = toleranceFloat
+	response.Progress = spanProgress(model.TrainingCycle{StartsOn: plan.StartsOn, EndsOn: plan.EndsOn}, dateReady.Time)
+	response.Lines = compareVolumes(planned[0], actual[0], response.Progress, toleranceFloat/100)
+	response.Cycles = []dto.CycleComparison{}
+
+	for _, cycle := range cycles {
+		var comparison dto.CycleComparison
+		comparison.Cycle = cycle.Id
+		if cycle.Parent.Valid {
+			comparison.Parent = &cycle.Parent.Int32
+		}
+		comparison.Kind = cycle.Kind
+		comparison.Title = cycle.Title
+		comparison.StartsOn = cycle.StartsOn.Time.Format("2006-01-02")
+		comparison.EndsOn = cycle.EndsOn.Time.Format("2006-01-02")
+		comparison.Progress = spanProgress(cycle, dateReady.Time)
+		comparison.Lines = compareVolumes(planned[cycle.Id], actual[cycle.Id], comparison.Progress, toleranceFloat/100)
+		response.Cycles = append(response.Cycles, comparison)
+	}
+	return &response, nil
+}
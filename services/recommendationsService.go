@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+const defaultRecommendations = 10
+
+// recommendRoute decides whether a route the tourist has not completed is worth
+// suggesting and explains why. A route qualifies when it is one difficulty step
+// above what they completed on routes of its type, when it leads to new places
+// without being harder than that, or when it opens a route type they have not
+// tried at its easiest difficulty. Ratings from similar tourists move it up or
+// down the list.
+func recommendRoute(route model.Route, places []model.Place, levels map[int32]model.RouteTypeLevel,
+	easiest map[int32]int32, visited map[int32]bool, similar map[int32]model.RouteRating) (dto.RouteRecommendation, bool) {
+	var recommendation dto.RouteRecommendation
+	if !route.Difficulty.Valid || !route.TypeId.Valid {
+		return recommendation, false
+	}
+	difficulty := route.Difficulty.Int32
+	typeTitle := route.Type.String
+
+	score := 0.0
+	qualifies := false
+	reasons := []string{}
+
+	level, tried := levels[route.TypeId.Int32]
+	switch {
+	case tried && difficulty == level.MaxDifficulty+1:
+		qualifies = true
+		score += 3
+		reasons = append(reasons, fmt.Sprintf("one step up from difficulty %d, the highest you completed on %s routes",
+			level.MaxDifficulty, typeTitle))
+	case tried && difficulty > level.MaxDifficulty+1:
+		return recommendation, false
+	case !tried && difficulty == easiest[route.TypeId.Int32]:
+		qualifies = true
+		score += 1
+		reasons = append(reasons, fmt.Sprintf("you have not tried %s routes yet, this one has their easiest difficulty %d",
+			typeTitle, difficulty))
+	case !tried:
+		return recommendation, false
+	}
+
+	fresh := 0
+	for _, place := range places {
+		if !visited[place.Id] {
+			fresh++
+		}
+	}
+	switch {
+	case len(places) > 0 && fresh == len(places):
+		score += 2
+		reasons = append(reasons, fmt.Sprintf("a new area: you have not been to any of its %d places", len(places)))
+		qualifies = qualifies || tried
+	case fresh > 0:
+		score += float64(fresh) / float64(len(places))
+		reasons = append(reasons, fmt.Sprintf("%d of its %d places are new to you", fresh, len(places)))
+	}
+	if !qualifies {
+		return recommendation, false
+	}
+
+	if rating, ok := similar[route.Id]; ok && rating.AvgRating.Valid {
+		score += rating.AvgRating.Float64 - 3
+		recommendation.SimilarRating = roundRating(rating.AvgRating.Float64)
+		recommendation.SimilarReviews = rating.Reviews
+		reasons = append(reasons, fmt.Sprintf("rated %.1f of 5 by %d tourists who completed the same routes as you",
+			rating.AvgRating.Float64, rating.Reviews))
+		if gap := rating.DifficultyGap(); gap.Valid && gap.Float64 >= 0.5 {
+			reasons = append(reasons, "they found it harder than its official difficulty")
+		}
+	}
+
+	recommendation.Route = route.Id
+	recommendation.Type = typeTitle
+	recommendation.Difficulty = difficulty
+	if route.LengthKm.Valid {
+		recommendation.LengthKm = &route.LengthKm.Float64
+	}
+	recommendation.Places = []string{}
+	for _, place := range places {
+		recommendation.Places = append(recommendation.Places, place.Title)
+	}
+	recommendation.Score = math.Round(score*100) / 100
+	recommendation.Reasons = reasons
+	return recommendation, true
+}
+
+// GetRecommendedRoutes ranks the routes a person has not completed yet. It
+// stays on the queries: the levels, visited places and ratings of similar
+// tourists it reads have no repository, nothing else needs them.
+func GetRecommendedRoutes(person string, limit string) (*dto.RecommendedRoutes, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return nil, err
+	}
+	limitInt := defaultRecommendations
+	if limit != "" {
+		limitInt, err = strconv.Atoi(limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	personModel, err := dbqueries.GetPerson(pg, ctx, personInt)
+	if err != nil {
+		return nil, fmt.Errorf("person %d not found: %w", personInt, err)
+	}
+
+	levelList, err := dbqueries.GetPersonRouteTypeLevels(pg, ctx, personInt)
+	if err != nil {
+		return nil, err
+	}
+	levels := map[int32]model.RouteTypeLevel{}
+	for _, level := range levelList {
+		levels[level.Type] = level
+	}
+	completedList, err := dbqueries.GetPersonCompletedRoutes(pg, ctx, personInt)
+	if err != nil {
+		return nil, err
+	}
+	completed := map[int32]bool{}
+	for _, route := range completedList {
+		completed[route.Id] = true
+	}
+	visitedList, err := dbqueries.GetPersonVisitedPlaces(pg, ctx, personInt)
+	if err != nil {
+		return nil, err
+	}
+	visited := map[int32]bool{}
+	for _, place := range visitedList {
+		visited[place.Id] = true
+	}
+	similarList, err := dbqueries.GetSimilarTouristRatings(pg, ctx, personInt)
+	if err != nil {
+		return nil, err
+	}
+	similar := map[int32]model.RouteRating{}
+	for _, rating := range similarList {
+		similar[rating.Route] = rating
+	}
+
+	routes, err := dbqueries.GetAllRoutes(pg, ctx)
+	if err != nil {
+		return nil, err
+	}
+	routePlaces, err := dbqueries.GetAllRoutePlaces(pg, ctx)
+	if err != nil {
+		return nil, err
+	}
+	easiest := map[int32]int32{}
+	for _, route := range routes {
+		if !route.TypeId.Valid || !route.Difficulty.Valid {
+			continue
+		}
+		current, ok := easiest[route.TypeId.Int32]
+		if !ok || route.Difficulty.Int32 < current {
+			easiest[route.TypeId.Int32] = route.Difficulty.Int32
+		}
+	}
+
+	var response dto.RecommendedRoutes
+	response.Person = person2Response(*personModel)
+	response.Routes = []dto.RouteRecommendation{}
+	for _, route := range routes {
+		if completed[route.Id] {
+			continue
+		}
+		recommendation, ok := recommendRoute(route, routePlaces[route.Id], levels, easiest, visited, similar)
+		if ok {
+			response.Routes = append(response.Routes, recommendation)
+		}
+	}
+	sort.SliceStable(response.Routes, func(i, j int) bool {
+		return response.Routes[i].Score > response.Routes[j].Score
+	})
+	if limitInt > 0 && len(response.Routes) > limitInt {
+		response.Routes = response.Routes[:limitInt]
+	}
+	return &response, nil
+}
@@ -0,0 +1,556 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"db_backend/blobstore"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"db_backend/utils"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Blobs keeps report attachments and their thumbnails.
+var Blobs blobstore.Store
+
+// MaxAttachmentSize limits a single uploaded file.
+var MaxAttachmentSize int64 = 20 << 20
+
+const thumbnailSize = 320
+
+type gpxPoint struct {
+	Lat float64 `xml:"lat,attr"`
+	Lon float64 `xml:"lon,attr"`
+}
+
+type gpxDocument struct {
+	Tracks []struct {
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+	Routes []struct {
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+}
+
+func haversineKm(a gpxPoint, b gpxPoint) float64 {
+	const earthRadiusKm = 6371.0
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat, dLon := lat2-lat1, (b.Lon-a.Lon)*math.Pi/180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// trackLength parses a GPX track and sums the distance between consecutive points
+// of every track segment, or of the routes when the file has no tracks.
+func trackLength(gpx string) (float64, error) {
+	var document gpxDocument
+	err := xml.Unmarshal([]byte(gpx), &document)
+	if err != nil {
+		return 0, fmt.Errorf("invalid GPX track: %w", err)
+	}
+
+	var lines [][]gpxPoint
+	for _, track := range document.Tracks {
+		for _, segment := range track.Segments {
+			lines = append(lines, segment.Points)
+		}
+	}
+	if len(lines) == 0 {
+		for _, route := range document.Routes {
+			lines = append(lines, route.Points)
+		}
+	}
+
+	total, points := 0.0, 0
+	for _, line := range lines {
+		points += len(line)
+		for i := 1; i < len(line); i++ {
+			total += haversineKm(line[i-1], line[i])
+		}
+	}
+	if points == 0 {
+		return 0, fmt.Errorf("GPX track has no points")
+	}
+	return math.Round(total*100) / 100, nil
+}
+
+func attachment2Dto(attachment model.ReportAttachment) dto.ReportAttachment {
+	var jsonAttachment dto.ReportAttachment
+	jsonAttachment.Id = attachment.Id
+	jsonAttachment.Kind = attachment.Kind
+	jsonAttachment.Filename = attachment.Filename
+	jsonAttachment.ContentType = attachment.ContentType
+	jsonAttachment.Size = attachment.Size
+	jsonAttachment.Url = fmt.Sprintf("/reports/attachments/%d", attachment.Id)
+	if attachment.ThumbKey.Valid {
+		jsonAttachment.ThumbnailUrl = fmt.Sprintf("/reports/attachments/%d/thumbnail", attachment.Id)
+	}
+	jsonAttachment.UploadedAt = attachment.UploadedAt.Time.Format("2006-01-02 15:04:05")
+	return jsonAttachment
+}
+
+func report2Dto(pg *db.Postgres, ctx context.Context, report model.TourReport) (dto.TourReport, error) {
+	var jsonReport dto.TourReport
+	author, err := dbqueries.GetPerson(pg, ctx, int(report.Author))
+	if err != nil {
+		return jsonReport, err
+	}
+	attachments, err := dbqueries.GetReportAttachments(pg, ctx, int(report.Id))
+	if err != nil {
+		return jsonReport, err
+	}
+
+	jsonReport.Id = report.Id
+	jsonReport.Tour = report.Tour
+	jsonReport.Author = person2Response(*author)
+	if report.EditedBy.Valid {
+		editor, err := dbqueries.GetPerson(pg, ctx, int(report.EditedBy.Int32))
+		if err != nil {
+			return jsonReport, err
+		}
+		editorResponse := person2Response(*editor)
+		jsonReport.EditedBy = &editorResponse
+	}
+	jsonReport.Title = report.Title
+	jsonReport.Body = report.Body
+	jsonReport.GpsTrack = report.GpsTrack.String
+	if report.TrackKm.Valid {
+		jsonReport.TrackKm = &report.TrackKm.Float64
+	}
+	jsonReport.Published = report.Published
+	jsonReport.CreatedAt = report.CreatedAt.Time.Format("2006-01-02 15:04:05")
+	jsonReport.UpdatedAt = report.UpdatedAt.Time.Format("2006-01-02 15:04:05")
+	jsonReport.Attachments = []dto.ReportAttachment{}
+	for _, attachment := range attachments {
+		jsonReport.Attachments = append(jsonReport.Attachments, attachment2Dto(attachment))
+	}
+	return jsonReport, nil
+}
+
+// checkReportAuthor allows the instructor of the tour and managers to edit its report.
+func checkReportAuthor(pg *db.Postgres, ctx context.Context, viewer int, tour model.Tour) error {
+	if tour.Instructor == int32(viewer) {
+		return nil
+	}
+	manager, err := dbqueries.IsManager(pg, ctx, viewer)
+	if err != nil {
+		return err
+	}
+	if !manager {
+		return fmt.Errorf("%w: only the instructor edits the report of tour %d", ErrForbidden, tour.Id)
+	}
+	return nil
+}
+
+// checkReportReader lets anybody read a published report; drafts are only
+// visible to those who may edit them.
+func checkReportReader(pg *db.Postgres, ctx context.Context, viewer string, report model.TourReport) error {
+	if report.Published {
+		return nil
+	}
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return err
+	}
+	tour, err := dbqueries.GetTour(pg, ctx, int(report.Tour))
+	if err != nil {
+		return err
+	}
+	return checkReportAuthor(pg, ctx, viewerInt, *tour)
+}
+
+// getReportedTour loads a tour whose report the viewer is about to change.
+func getReportedTour(pg *db.Postgres, ctx context.Context, viewer int, tour int) (*model.Tour, error) {
+	tourModel, err := dbqueries.GetTour(pg, ctx, tour)
+	if err != nil {
+		return nil, err
+	}
+	if tourModel == nil {
+		return nil, fmt.Errorf("tour %d not found", tour)
+	}
+	err = checkReportAuthor(pg, ctx, viewer, *tourModel)
+	if err != nil {
+		return nil, err
+	}
+	return tourModel, nil
+}
+
+func SaveTourReport(viewer string, report dto.TourReportRequest) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return -1, err
+	}
+	tour, err := getReportedTour(pg, context.Background(), viewerInt, int(report.Tour))
+	if err != nil {
+		return -1, err
+	}
+	if tour.Status != model.TourActive && tour.Status != model.TourCompleted {
+		return -1, fmt.Errorf("tour %d is %s, reports are written for active or completed tours", tour.Id, tour.Status)
+	}
+	if strings.TrimSpace(report.Title) == "" {
+		return -1, fmt.Errorf("report title is empty")
+	}
+
+	var reportModel model.TourReport
+	reportModel.Tour = tour.Id
+	reportModel.Author = int32(viewerInt)
+	reportModel.Title = report.Title
+	reportModel.Body = report.Body
+	reportModel.Published = report.Published
+	if strings.TrimSpace(report.GpsTrack) != "" {
+		length, err := trackLength(report.GpsTrack)
+		if err != nil {
+			return -1, err
+		}
+		reportModel.GpsTrack.String = report.GpsTrack
+		reportModel.GpsTrack.Valid = true
+		reportModel.TrackKm.Float64 = length
+		reportModel.TrackKm.Valid = true
+	}
+
+	return dbqueries.SaveTourReport(pg, context.Background(), reportModel)
+}
+
+func GetTourReport(viewer string, tour string) (*dto.TourReport, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return nil, err
+	}
+	report, err := dbqueries.GetTourReport(pg, context.Background(), tourInt)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, fmt.Errorf("tour %d has no report", tourInt)
+	}
+	err = checkReportReader(pg, context.Background(), viewer, *report)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonReport, err := report2Dto(pg, context.Background(), *report)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonReport, nil
+}
+
+func deleteAttachmentBlobs(ctx context.Context, attachment model.ReportAttachment) error {
+	err := Blobs.Delete(ctx, attachment.BlobKey)
+	if err != nil {
+		return err
+	}
+	if attachment.ThumbKey.Valid {
+		return Blobs.Delete(ctx, attachment.ThumbKey.String)
+	}
+	return nil
+}
+
+func DeleteTourReport(viewer string, tour string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return err
+	}
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return err
+	}
+	_, err = getReportedTour(pg, context.Background(), viewerInt, tourInt)
+	if err != nil {
+		return err
+	}
+	report, err := dbqueries.GetTourReport(pg, context.Background(), tourInt)
+	if err != nil || report == nil {
+		return err
+	}
+	attachments, err := dbqueries.GetReportAttachments(pg, context.Background(), int(report.Id))
+	if err != nil {
+		return err
+	}
+
+	err = dbqueries.DeleteTourReport(pg, context.Background(), tourInt)
+	if err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		err = deleteAttachmentBlobs(context.Background(), attachment)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newBlobKey(tour int32, filename string) (string, error) {
+	random := make([]byte, 12)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("reports/%d/%s%s", tour, hex.EncodeToString(random), strings.ToLower(filepath.Ext(filename))), nil
+}
+
+// InlineImageTypes are the attachment types served for display in the browser;
+// anything else is only offered for download.
+var InlineImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// AddReportAttachment stores an uploaded photo or document of a tour report.
+// The content type is sniffed from the data rather than taken from the client.
+// JPEG, PNG and GIF photos get a thumbnail next to the original.
+func AddReportAttachment(viewer string, tour string, filename string, file io.Reader) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+	ctx := context.Background()
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return -1, err
+	}
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return -1, err
+	}
+	_, err = getReportedTour(pg, ctx, viewerInt, tourInt)
+	if err != nil {
+		return -1, err
+	}
+	report, err := dbqueries.GetTourReport(pg, ctx, tourInt)
+	if err != nil {
+		return -1, err
+	}
+	if report == nil {
+		return -1, fmt.Errorf("tour %d has no report yet", tourInt)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxAttachmentSize+1))
+	if err != nil {
+		return -1, err
+	}
+	if int64(len(data)) > MaxAttachmentSize {
+		return -1, fmt.Errorf("file is larger than %d bytes", MaxAttachmentSize)
+	}
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return -1, err
+	}
+
+	var attachment model.ReportAttachment
+	attachment.Report = report.Id
+	attachment.Filename = filepath.Base(filename)
+	attachment.ContentType = contentType
+	attachment.Size = int64(len(data))
+	attachment.Kind = model.AttachmentDocument
+	if InlineImageTypes[contentType] {
+		attachment.Kind = model.AttachmentPhoto
+	}
+
+	attachment.BlobKey, err = newBlobKey(report.Tour, attachment.Filename)
+	if err != nil {
+		return -1, err
+	}
+	if attachment.Kind == model.AttachmentPhoto {
+		thumbnail, err := utils.Thumbnail(data, thumbnailSize)
+		if err != nil {
+			return -1, fmt.Errorf("unable to read image %s: %w", attachment.Filename, err)
+		}
+		attachment.ThumbKey.String = strings.TrimSuffix(attachment.BlobKey, filepath.Ext(attachment.BlobKey)) + ".thumb.jpg"
+		attachment.ThumbKey.Valid = true
+		_, err = Blobs.Put(ctx, attachment.ThumbKey.String, bytes.NewReader(thumbnail))
+		if err != nil {
+			return -1, err
+		}
+	}
+	_, err = Blobs.Put(ctx, attachment.BlobKey, bytes.NewReader(data))
+	if err != nil {
+		deleteAttachmentBlobs(ctx, attachment)
+		return -1, err
+	}
+
+	id, err := dbqueries.AddReportAttachment(pg, ctx, attachment)
+	if err != nil {
+		deleteAttachmentBlobs(ctx, attachment)
+		return -1, err
+	}
+	return id, nil
+}
+
+func DeleteReportAttachment(viewer string, id string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return err
+	}
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+	attachment, err := dbqueries.GetReportAttachment(pg, ctx, idInt)
+	if err != nil || attachment == nil {
+		return err
+	}
+	report, err := dbqueries.GetReportById(pg, ctx, int(attachment.Report))
+	if err != nil {
+		return err
+	}
+	_, err = getReportedTour(pg, ctx, viewerInt, int(report.Tour))
+	if err != nil {
+		return err
+	}
+
+	err = dbqueries.DeleteReportAttachment(pg, ctx, idInt)
+	if err != nil {
+		return err
+	}
+	return deleteAttachmentBlobs(ctx, *attachment)
+}
+
+// OpenReportAttachment returns the file, or its thumbnail, for download. The
+// caller closes the reader.
+func OpenReportAttachment(viewer string, id string, thumbnail bool) (io.ReadCloser, *dto.ReportAttachment, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx := context.Background()
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	attachment, err := dbqueries.GetReportAttachment(pg, ctx, idInt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment == nil {
+		return nil, nil, fmt.Errorf("attachment %d not found", idInt)
+	}
+	report, err := dbqueries.GetReportById(pg, ctx, int(attachment.Report))
+	if err != nil {
+		return nil, nil, err
+	}
+	err = checkReportReader(pg, ctx, viewer, *report)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	jsonAttachment := attachment2Dto(*attachment)
+	key := attachment.BlobKey
+	if thumbnail {
+		if !attachment.ThumbKey.Valid {
+			return nil, nil, fmt.Errorf("attachment %d has no thumbnail", idInt)
+		}
+		key = attachment.ThumbKey.String
+		jsonAttachment.ContentType = "image/jpeg"
+	}
+	content, err := Blobs.Get(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, &jsonAttachment, nil
+}
+
+// GetRouteReports is the public view of a route: every published report of
+// its tours together with totals over them.
+func GetRouteReports(route string) (*dto.RouteReports, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+
+	routeInt, err := strconv.Atoi(route)
+	if err != nil {
+		return nil, err
+	}
+	var response dto.RouteReports
+	response.Route = int32(routeInt)
+	response.Type, response.Difficulty, response.LengthKm, err = dbqueries.GetRouteDetails(pg, ctx, routeInt)
+	if err != nil {
+		return nil, err
+	}
+	response.Places, err = dbqueries.GetRoutePlaces(pg, ctx, routeInt)
+	if err != nil {
+		return nil, err
+	}
+	if response.Places == nil {
+		response.Places = []string{}
+	}
+
+	reports, err := dbqueries.GetPublishedRouteReports(pg, ctx, routeInt)
+	if err != nil {
+		return nil, err
+	}
+	response.Reports = []dto.RouteReportEntry{}
+	for _, report := range reports {
+		tour, err := dbqueries.GetTour(pg, ctx, int(report.Tour))
+		if err != nil {
+			return nil, err
+		}
+		instructor, err := dbqueries.GetPerson(pg, ctx, int(tour.Instructor))
+		if err != nil {
+			return nil, err
+		}
+		jsonReport, err := report2Dto(pg, ctx, report)
+		if err != nil {
+			return nil, err
+		}
+
+		var entry dto.RouteReportEntry
+		entry.Tour = tour.Id
+		entry.Start = tour.GetStartAsString()
+		entry.End = tour.GetEndAsString()
+		entry.Instructor = fullName(*instructor)
+		entry.Report = jsonReport
+		response.Reports = append(response.Reports, entry)
+
+		for _, attachment := range jsonReport.Attachments {
+			if attachment.Kind == model.AttachmentPhoto {
+				response.Photos++
+			}
+		}
+		if jsonReport.TrackKm != nil {
+			response.TrackKm += *jsonReport.TrackKm
+		}
+	}
+	return &response, nil
+}
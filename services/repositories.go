@@ -0,0 +1,19 @@
+package services
+
+import (
+	"db_backend/repository"
+)
+
+// Club serves the persons, groups, tours, routes, workouts and championships of
+// the club from the repositories it is made with.
+type Club struct {
+	repos        *repository.Repositories
+	blockDebtors bool
+}
+
+// NewClub serves the club from repos. With blockDebtors set, members with
+// overdue membership fees may neither enroll in tours nor register for
+// championships.
+func NewClub(repos *repository.Repositories, blockDebtors bool) *Club {
+	return &Club{repos: repos, blockDebtors: blockDebtors}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"db_backend/dto"
+	"db_backend/model"
+	"db_backend/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fixture is a small club in two sections: two tourists and an athlete, a
+// trainer in each section and a manager.
+type fixture struct {
+	*Club
+	memory *repository.Memory
+
+	sectionA, sectionB       int
+	groupA1, groupA2, groupB int
+
+	anna, boris, vera int
+	gleb, dina        int
+	egor              int
+}
+
+func date(value string) pgtype.Date {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return pgtype.Date{Time: t, Valid: true}
+}
+
+// useMemory serves a fresh in-memory club to the test. Today is 2025-09-01 there.
+func useMemory(t *testing.T) *fixture {
+	t.Helper()
+	ctx := context.Background()
+	memory := repository.NewMemory()
+	memory.Now = func() time.Time {
+		return time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	}
+
+	c := &fixture{Club: NewClub(memory.Repositories(), false), memory: memory}
+	must := func(id int, err error) int {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	check := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c.sectionA = must(memory.CreateSection(ctx, model.Section{Title: "Альпинизм"}))
+	c.sectionB = must(memory.CreateSection(ctx, model.Section{Title: "Водный туризм"}))
+	c.groupA1 = must(memory.CreateGroup(ctx, model.Group{GroupNumber: 1, Section: int32(c.sectionA)}))
+	c.groupA2 = must(memory.CreateGroup(ctx, model.Group{GroupNumber: 2, Section: int32(c.sectionA)}))
+	c.groupB = must(memory.CreateGroup(ctx, model.Group{GroupNumber: 1, Section: int32(c.sectionB)}))
+
+	person := func(name string, surname string, section int, role int, sex int, birth string) int {
+		t.Helper()
+		id := must(memory.InsertPerson(ctx, model.Person{Name: name, Surname: surname}))
+		check(memory.UpdatePersonRole(ctx, id, section, role))
+		check(memory.SetPersonIntAttribute(ctx, model.PersonIntAttribute{AttributeId: 1, PersonId: id, Value: sex}))
+		check(memory.SetPersonDateAttribute(ctx, model.PersonDateAttribute{AttributeId: 2, PersonId: id, Value: date(birth)}))
+		return id
+	}
+	c.anna = person("Анна", "Иванова", c.sectionA, 0, 2, "2000-03-15")
+	c.boris = person("Борис", "Петров", c.sectionA, 1, 1, "1990-10-01")
+	c.vera = person("Вера", "Сидорова", c.sectionB, 0, 2, "2000-12-31")
+	c.gleb = person("Глеб", "Орлов", c.sectionA, 2, 1, "1985-05-05")
+	c.dina = person("Дина", "Лебедева", c.sectionB, 2, 2, "1988-07-20")
+	c.egor = person("Егор", "Волков", c.sectionA, 3, 1, "1980-02-02")
+
+	check(memory.AddGroupMember(ctx, c.anna, c.groupA1))
+	check(memory.AddGroupMember(ctx, c.boris, c.groupA2))
+	check(memory.AddGroupMember(ctx, c.vera, c.groupB))
+
+	check(memory.SetPersonIntAttribute(ctx, model.PersonIntAttribute{AttributeId: 3, PersonId: c.gleb, Value: 50000}))
+	check(memory.SetPersonIntAttribute(ctx, model.PersonIntAttribute{AttributeId: 3, PersonId: c.dina, Value: 60000}))
+	check(memory.SetPersonStringAttribute(ctx, model.PersonStringAttribute{AttributeId: 4, PersonId: c.gleb, Value: "скалолазание"}))
+	check(memory.SetPersonStringAttribute(ctx, model.PersonStringAttribute{AttributeId: 4, PersonId: c.dina, Value: "сплав"}))
+	check(memory.SetPersonDateAttribute(ctx, model.PersonDateAttribute{AttributeId: 5, PersonId: c.egor, Value: date("2015-01-10")}))
+	check(memory.SetPersonIntAttribute(ctx, model.PersonIntAttribute{AttributeId: 6, PersonId: c.egor, Value: 90000}))
+	return c
+}
+
+func ids(values ...int) []int32 {
+	result := make([]int32, 0, len(values))
+	for _, value := range values {
+		result = append(result, int32(value))
+	}
+	slices.Sort(result)
+	return result
+}
+
+// personIds returns the ids of the persons in the response in ascending order;
+// the filters do not keep any.
+func personIds(t *testing.T, response *dto.PersonsListResponse, err error) []int32 {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := []int32{}
+	for _, person := range response.Persons {
+		result = append(result, person.Id)
+	}
+	slices.Sort(result)
+	return result
+}
+
+func itoa(value int) string {
+	return strconv.Itoa(value)
+}
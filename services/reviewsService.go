@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"db_backend/repository"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+func roundRating(value float64) *float64 {
+	rounded := math.Round(value*100) / 100
+	return &rounded
+}
+
+func rating2Dto(rating model.RouteRating) dto.RouteRating {
+	var jsonRating dto.RouteRating
+	jsonRating.Season = rating.Season
+	jsonRating.Reviews = rating.Reviews
+	if rating.AvgRating.Valid {
+		jsonRating.AvgRating = roundRating(rating.AvgRating.Float64)
+	}
+	if rating.AvgPerceived.Valid {
+		jsonRating.AvgPerceivedDifficulty = roundRating(rating.AvgPerceived.Float64)
+	}
+	if gap := rating.DifficultyGap(); gap.Valid {
+		jsonRating.DifficultyGap = roundRating(gap.Float64)
+	}
+	jsonRating.DifficultyFeel = rating.DifficultyFeel()
+	return jsonRating
+}
+
+func review2Dto(review model.RouteReview) dto.RouteReview {
+	var jsonReview dto.RouteReview
+	jsonReview.Id = review.Id
+	jsonReview.Person = person2Response(review.Person)
+	jsonReview.Tour = review.Tour
+	jsonReview.Rating = review.Rating
+	jsonReview.PerceivedDifficulty = review.PerceivedDifficulty
+	jsonReview.Season = review.Season
+	jsonReview.Conditions = review.Conditions
+	jsonReview.Review = review.Review
+	jsonReview.UpdatedAt = review.UpdatedAt.Time.Format("2006-01-02 15:04:05")
+	return jsonReview
+}
+
+// SaveRouteReview records or replaces the review of a tourist who went through
+// the route to the end. The season is taken from their latest tour on it.
+func SaveRouteReview(viewer string, review dto.RouteReviewRequest) (int, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return -1, err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return -1, err
+	}
+	if review.Rating < 1 || review.Rating > 5 {
+		return -1, fmt.Errorf("rating must be between 1 and 5")
+	}
+	if review.PerceivedDifficulty < 0 {
+		return -1, fmt.Errorf("perceived difficulty must not be negative")
+	}
+
+	tour, err := dbqueries.GetCompletedTourOnRoute(pg, context.Background(), viewerInt, int(review.Route))
+	if err != nil {
+		return -1, err
+	}
+	if tour == nil {
+		return -1, fmt.Errorf("%w: person %d has not completed route %d", ErrForbidden, viewerInt, review.Route)
+	}
+
+	var reviewModel model.RouteReview
+	reviewModel.Route = review.Route
+	reviewModel.Person.Id = int32(viewerInt)
+	reviewModel.Tour = tour.Id
+	reviewModel.Rating = review.Rating
+	reviewModel.PerceivedDifficulty = review.PerceivedDifficulty
+	reviewModel.Season = model.Season(tour.Start.Time)
+	reviewModel.Conditions = review.Conditions
+	reviewModel.Review = review.Review
+	return dbqueries.SaveRouteReview(pg, context.Background(), reviewModel)
+}
+
+func DeleteRouteReview(viewer string, route string) error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return err
+	}
+	routeInt, err := strconv.Atoi(route)
+	if err != nil {
+		return err
+	}
+	return dbqueries.DeleteRouteReview(pg, context.Background(), routeInt, viewerInt)
+}
+
+func GetRouteReviews(route string) ([]dto.RouteReview, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	routeInt, err := strconv.Atoi(route)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := dbqueries.GetRouteReviews(pg, context.Background(), routeInt)
+	if err != nil {
+		return nil, err
+	}
+
+	response := []dto.RouteReview{}
+	for _, review := range reviews {
+		response = append(response, review2Dto(review))
+	}
+	return response, nil
+}
+
+// GetRoute describes a route together with what its participants think of it.
+// It stays on the queries: the Routes repository holds no reviews, places or
+// season ratings, and only the page of one route reads them.
+func GetRoute(id string) (*dto.RouteResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+	route, err := dbqueries.GetRoute(pg, ctx, idInt)
+	if err != nil {
+		return nil, err
+	}
+	if route == nil {
+		return nil, fmt.Errorf("route %d not found", idInt)
+	}
+
+	var response dto.RouteResponse
+	response.Id = route.Id
+	response.Type = route.Type.String
+	if route.Difficulty.Valid {
+		response.Difficulty = &route.Difficulty.Int32
+	}
+	if route.LengthKm.Valid {
+		response.LengthKm = &route.LengthKm.Float64
+	}
+	response.Places, err = dbqueries.GetRoutePlaces(pg, ctx, idInt)
+	if err != nil {
+		return nil, err
+	}
+	if response.Places == nil {
+		response.Places = []string{}
+	}
+	response.Tours, response.CompletedTours, err = dbqueries.GetRouteTourCount(pg, ctx, idInt)
+	if err != nil {
+		return nil, err
+	}
+
+	ratings, err := dbqueries.GetRouteRatings(pg, ctx, []int32{route.Id})
+	if err != nil {
+		return nil, err
+	}
+	if len(ratings) > 0 {
+		response.Rating = rating2Dto(ratings[0])
+	}
+	seasons, err := dbqueries.GetRouteSeasonRatings(pg, ctx, idInt)
+	if err != nil {
+		return nil, err
+	}
+	response.Seasons = []dto.RouteRating{}
+	for _, season := range seasons {
+		response.Seasons = append(response.Seasons, rating2Dto(season))
+	}
+
+	reviews, err := dbqueries.GetRouteReviews(pg, ctx, idInt)
+	if err != nil {
+		return nil, err
+	}
+	response.Reviews = []dto.RouteReview{}
+	for _, review := range reviews {
+		response.Reviews = append(response.Reviews, review2Dto(review))
+	}
+	return &response, nil
+}
+
+// routeSortKeys pick the value routes are ordered by, highest first.
+var routeSortKeys = map[string]func(rating model.RouteRating) (float64, bool){
+	"rating": func(rating model.RouteRating) (float64, bool) {
+		return rating.AvgRating.Float64, rating.AvgRating.Valid
+	},
+	"reviews": func(rating model.RouteRating) (float64, bool) {
+		return float64(rating.Reviews), true
+	},
+	"perceived_difficulty": func(rating model.RouteRating) (float64, bool) {
+		return rating.AvgPerceived.Float64, rating.AvgPerceived.Valid
+	},
+	"difficulty_gap": func(rating model.RouteRating) (float64, bool) {
+		gap := rating.DifficultyGap()
+		return gap.Float64, gap.Valid
+	},
+}
+
+// sortRoutes orders route ids by their review aggregates. Routes without a value
+// for the key go last, ties keep ascending ids.
+func sortRoutes(repo repository.Routes, routes []model.RouteId, by string) ([]model.RouteId, error) {
+	if by == "" {
+		return routes, nil
+	}
+	key, ok := routeSortKeys[by]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort %q", by)
+	}
+
+	var ids []int32
+	for _, route := range routes {
+		ids = append(ids, route.Id)
+	}
+	ratings, err := repo.GetRouteRatings(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+	byRoute := map[int32]model.RouteRating{}
+	for _, rating := range ratings {
+		byRoute[rating.Route] = rating
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		a, aOk := key(byRoute[routes[i].Id])
+		b, bOk := key(byRoute[routes[j].Id])
+		if aOk != bOk {
+			return aOk
+		}
+		if a != b {
+			return a > b
+		}
+		return routes[i].Id < routes[j].Id
+	})
+	return routes, nil
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/export"
+	"db_backend/model"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func fullName(person model.Person) string {
+	return strings.TrimSpace(person.Surname + " " + person.Name + " " + person.Patronymic)
+}
+
+func contactsLine(contacts []model.EmergencyContact) string {
+	var parts []string
+	for _, contact := range contacts {
+		part := contact.Name + " " + contact.Phone
+		if contact.Relation != "" {
+			part += " (" + contact.Relation + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// buildRoster collects enrolled participants with their health records and
+// emergency contacts together with the route plan of the tour.
+func buildRoster(pg *db.Postgres, ctx context.Context, viewer int, tour int) (*model.Roster, error) {
+	tourModel, err := dbqueries.GetTour(pg, ctx, tour)
+	if err != nil {
+		return nil, err
+	}
+	if tourModel == nil {
+		return nil, fmt.Errorf("tour %d not found", tour)
+	}
+
+	manager, err := dbqueries.IsManager(pg, ctx, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !manager && tourModel.Instructor != int32(viewer) {
+		return nil, fmt.Errorf("%w: only the instructor or a manager may print the roster of tour %d", ErrForbidden, tour)
+	}
+
+	var roster model.Roster
+	roster.Tour = *tourModel
+	roster.RouteType, roster.Difficulty, roster.LengthKm, err = dbqueries.GetRouteDetails(pg, ctx, int(tourModel.Route))
+	if err != nil {
+		return nil, err
+	}
+	roster.Places, err = dbqueries.GetRoutePlaces(pg, ctx, int(tourModel.Route))
+	if err != nil {
+		return nil, err
+	}
+	instructor, err := dbqueries.GetPerson(pg, ctx, int(tourModel.Instructor))
+	if err != nil {
+		return nil, err
+	}
+	roster.Instructor = *instructor
+
+	participants, err := dbqueries.GetTourParticipants(pg, ctx, tour)
+	if err != nil {
+		return nil, err
+	}
+	for _, participant := range participants {
+		if participant.Status != model.EnrollmentEnrolled {
+			continue
+		}
+		var entry model.RosterEntry
+		entry.Person = participant.Person
+		entry.Health, err = dbqueries.GetHealthRecord(pg, ctx, int(participant.Person.Id))
+		if err != nil {
+			return nil, err
+		}
+		entry.Contacts, err = dbqueries.GetEmergencyContacts(pg, ctx, int(participant.Person.Id))
+		if err != nil {
+			return nil, err
+		}
+		roster.Participants = append(roster.Participants, entry)
+	}
+	return &roster, nil
+}
+
+func rosterCSV(roster *model.Roster) ([]byte, error) {
+	header := []string{"tour", "route", "route_type", "start", "end", "places", "person", "surname", "name", "patronymic",
+		"clearance_until", "blood_type", "allergies", "medical_notes", "emergency_contacts"}
+
+	var rows [][]string
+	for _, entry := range roster.Participants {
+		row := []string{
+			strconv.Itoa(int(roster.Tour.Id)),
+			strconv.Itoa(int(roster.Tour.Route)),
+			roster.RouteType,
+			roster.Tour.GetStartAsString(),
+			roster.Tour.GetEndAsString(),
+			strings.Join(roster.Places, " - "),
+			strconv.Itoa(int(entry.Person.Id)),
+			entry.Person.Surname,
+			entry.Person.Name,
+			entry.Person.Patronymic,
+		}
+		if entry.Health != nil {
+			clearance := ""
+			if entry.Health.ClearanceUntil.Valid {
+				clearance = entry.Health.ClearanceUntil.Time.Format("2006-01-02")
+			}
+			row = append(row, clearance, entry.Health.BloodType, entry.Health.Allergies, entry.Health.MedicalNotes)
+		} else {
+			row = append(row, "", "", "", "")
+		}
+		row = append(row, contactsLine(entry.Contacts))
+		rows = append(rows, row)
+	}
+
+	var buf bytes.Buffer
+	err := export.WriteCSV(&buf, header, rows)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func rosterPDF(roster *model.Roster) ([]byte, error) {
+	doc := export.NewPDF()
+	doc.Text(16, true, fmt.Sprintf("Safety roster: tour %d", roster.Tour.Id))
+	doc.Gap(6)
+	doc.Text(10, false, fmt.Sprintf("Dates: %s - %s (%d days)",
+		roster.Tour.GetStartAsString(), roster.Tour.GetEndAsString(), roster.Tour.DurationDays))
+	doc.Text(10, false, "Instructor: "+fullName(roster.Instructor))
+	doc.Text(10, false, fmt.Sprintf("Route %d: %s, difficulty %s, %s km",
+		roster.Tour.Route, roster.RouteType, roster.Difficulty, roster.LengthKm))
+	doc.Text(10, false, "Route plan: "+strings.Join(roster.Places, " - "))
+	doc.Gap(10)
+	doc.Text(12, true, fmt.Sprintf("Participants (%d)", len(roster.Participants)))
+
+	for i, entry := range roster.Participants {
+		doc.Gap(6)
+		doc.Text(11, true, fmt.Sprintf("%d. %s", i+1, fullName(entry.Person)))
+		if entry.Health != nil {
+			clearance := "none"
+			if entry.Health.ClearanceUntil.Valid {
+				clearance = entry.Health.ClearanceUntil.Time.Format("2006-01-02")
+			}
+			doc.TextAt(14, 9, false, "Medical clearance until: "+clearance)
+			if entry.Health.BloodType != "" {
+				doc.TextAt(14, 9, false, "Blood type: "+entry.Health.BloodType)
+			}
+			if entry.Health.Allergies != "" {
+				doc.TextAt(14, 9, false, "Allergies: "+entry.Health.Allergies)
+			}
+			if entry.Health.MedicalNotes != "" {
+				doc.TextAt(14, 9, false, "Medical notes: "+entry.Health.MedicalNotes)
+			}
+		} else {
+			doc.TextAt(14, 9, false, "No health record")
+		}
+		if len(entry.Contacts) == 0 {
+			doc.TextAt(14, 9, false, "Emergency contacts: none")
+		}
+		for _, contact := range entry.Contacts {
+			doc.TextAt(14, 9, false, "Emergency contact: "+contactsLine([]model.EmergencyContact{contact}))
+		}
+	}
+
+	var buf bytes.Buffer
+	_, err := doc.WriteTo(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GetTourRoster renders the safety roster of a tour as "pdf" (the default) or
+// "csv" and returns it with its content type.
+func GetTourRoster(viewer string, tour string, format string) ([]byte, string, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, "", err
+	}
+
+	viewerInt, err := parseViewer(viewer)
+	if err != nil {
+		return nil, "", err
+	}
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return nil, "", err
+	}
+
+	roster, err := buildRoster(pg, context.Background(), viewerInt, tourInt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch format {
+	case "", "pdf":
+		data, err := rosterPDF(roster)
+		return data, "application/pdf", err
+	case "csv":
+		data, err := rosterCSV(roster)
+		return data, "text/csv; charset=utf-8", err
+	}
+	return nil, "", fmt.Errorf("unknown roster format %q", format)
+}
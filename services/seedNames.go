@@ -0,0 +1,82 @@
+package services
+
+import "strings"
+
+var seedMaleNames = []string{
+	"Александр", "Алексей", "Андрей", "Антон", "Артём", "Борис", "Вадим", "Василий", "Виктор", "Владимир",
+	"Глеб", "Григорий", "Даниил", "Денис", "Дмитрий", "Евгений", "Егор", "Иван", "Игорь", "Илья",
+	"Кирилл", "Константин", "Лев", "Максим", "Матвей", "Михаил", "Никита", "Николай", "Олег", "Павел",
+	"Пётр", "Роман", "Семён", "Сергей", "Степан", "Тимофей", "Фёдор", "Юрий", "Ярослав",
+}
+
+var seedFemaleNames = []string{
+	"Алёна", "Алина", "Анастасия", "Анна", "Валерия", "Варвара", "Вера", "Виктория", "Галина", "Дарья",
+	"Евгения", "Екатерина", "Елена", "Елизавета", "Жанна", "Зоя", "Ирина", "Кира", "Ксения", "Лариса",
+	"Любовь", "Людмила", "Маргарита", "Марина", "Мария", "Надежда", "Наталья", "Нина", "Ольга", "Полина",
+	"Светлана", "София", "Таисия", "Татьяна", "Ульяна", "Юлия",
+}
+
+// seedPatronymics holds the male and female patronymic derived from a father's name.
+var seedPatronymics = [][2]string{
+	{"Александрович", "Александровна"}, {"Алексеевич", "Алексеевна"}, {"Андреевич", "Андреевна"},
+	{"Борисович", "Борисовна"}, {"Васильевич", "Васильевна"}, {"Викторович", "Викторовна"},
+	{"Владимирович", "Владимировна"}, {"Геннадьевич", "Геннадьевна"}, {"Дмитриевич", "Дмитриевна"},
+	{"Евгеньевич", "Евгеньевна"}, {"Иванович", "Ивановна"}, {"Игоревич", "Игоревна"},
+	{"Ильич", "Ильинична"}, {"Константинович", "Константиновна"}, {"Кузьмич", "Кузьминична"},
+	{"Михайлович", "Михайловна"}, {"Никитич", "Никитична"}, {"Николаевич", "Николаевна"},
+	{"Олегович", "Олеговна"}, {"Павлович", "Павловна"}, {"Петрович", "Петровна"},
+	{"Романович", "Романовна"}, {"Сергеевич", "Сергеевна"}, {"Фёдорович", "Фёдоровна"},
+	{"Юрьевич", "Юрьевна"}, {"Ярославович", "Ярославовна"},
+}
+
+var seedSurnames = []string{
+	"Иванов", "Смирнов", "Кузнецов", "Попов", "Васильев", "Петров", "Соколов", "Михайлов", "Новиков", "Фёдоров",
+	"Морозов", "Волков", "Алексеев", "Лебедев", "Семёнов", "Егоров", "Павлов", "Козлов", "Степанов", "Николаев",
+	"Орлов", "Андреев", "Макаров", "Никитин", "Захаров", "Зайцев", "Соловьёв", "Борисов", "Яковлев", "Григорьев",
+	"Романов", "Воробьёв", "Сергеев", "Кузьмин", "Фролов", "Александров", "Дмитриев", "Королёв", "Гусев", "Киселёв",
+	"Ильин", "Максимов", "Поляков", "Сорокин", "Виноградов", "Ковалёв", "Белов", "Медведев", "Антонов", "Тарасов",
+	"Жуков", "Баранов", "Филиппов", "Комаров", "Давыдов", "Беляев", "Герасимов", "Богданов", "Осипов", "Сидоров",
+	"Матвеев", "Титов", "Марков", "Миронов", "Крылов", "Куликов", "Карпов", "Власов", "Мельников", "Денисов",
+	"Островский", "Вишневский", "Чайковский", "Полянский", "Трубецкой", "Шевченко", "Черных", "Бондаренко",
+}
+
+// femaleSurname turns the male form of a surname into the female one. Surnames
+// such as Шевченко or Черных are the same for both.
+func femaleSurname(surname string) string {
+	switch {
+	case strings.HasSuffix(surname, "ский"), strings.HasSuffix(surname, "цкий"):
+		return strings.TrimSuffix(surname, "ий") + "ая"
+	case strings.HasSuffix(surname, "ой"):
+		return strings.TrimSuffix(surname, "ой") + "ая"
+	case strings.HasSuffix(surname, "ов"), strings.HasSuffix(surname, "ев"), strings.HasSuffix(surname, "ёв"),
+		strings.HasSuffix(surname, "ин"), strings.HasSuffix(surname, "ын"):
+		return surname + "а"
+	}
+	return surname
+}
+
+var seedSections = []string{
+	"Пешеходный туризм", "Водный туризм", "Горный туризм", "Лыжный туризм", "Велотуризм", "Спелеотуризм",
+}
+
+var seedRouteTypes = []string{"пеший", "водный", "горный", "лыжный", "велосипедный", "спелео"}
+
+var seedPlaces = []string{
+	"Приэльбрусье", "Перевал Дятлова", "Хибины", "Ловозёрские тундры", "Озеро Байкал", "Гора Белуха",
+	"Чуйский тракт", "Ладожские шхеры", "Кольский полуостров", "Плато Путорана", "Таганай", "Иремель",
+	"Перевал Кату-Ярык", "Телецкое озеро", "Река Катунь", "Река Чусовая", "Домбай", "Архыз", "Лаго-Наки",
+	"Плато Бермамыт", "Красноярские Столбы", "Ергаки", "Авачинский вулкан", "Мунку-Сардык", "Озеро Селигер",
+	"Валдай", "Мещёра", "Перевал Рамзая", "Безенги", "Цейское ущелье", "Река Умба", "Озеро Имандра",
+	"Кунгурская пещера", "Пещера Шульган-Таш", "Зюраткуль", "Конжаковский Камень",
+}
+
+var seedWorkoutTypes = []string{
+	"ОФП", "Техника пешеходного туризма", "Скалолазание", "Ориентирование", "Водная подготовка",
+	"Лыжная подготовка", "Первая помощь", "Топография",
+}
+
+var seedChampionships = []string{
+	"Первенство области по спортивному туризму", "Кубок города по туристскому многоборью",
+	"Чемпионат клуба по спортивному ориентированию", "Слёт туристов", "Кубок клуба по скалолазанию",
+	"Первенство города по лыжному туризму",
+}
@@ -0,0 +1,543 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"math/rand/v2"
+	"time"
+)
+
+// Ids of the roles and attributes the queries refer to directly.
+const (
+	seedRoleTourist = 0
+	seedRoleAthlete = 1
+	seedRoleTrainer = 2
+	seedRoleManager = 3
+
+	seedAttrSex            = 1
+	seedAttrBirthDate      = 2
+	seedAttrTrainerSalary  = 3
+	seedAttrSpecialization = 4
+	seedAttrManagerSince   = 5
+	seedAttrManagerSalary  = 6
+)
+
+// Per unit of scale: one section with its groups, members and staff, plus a
+// share of the places, routes, tours and championships of the club.
+const (
+	seedGroupsPerSection   = 3
+	seedMembersPerGroup    = 12
+	seedTrainersPerSection = 3
+	seedPlacesPerScale     = 12
+	seedRoutesPerScale     = 8
+	seedToursPerScale      = 12
+	seedChampsPerScale     = 2
+	seedWorkoutWeeksBack   = 26
+	seedWorkoutWeeksAhead  = 4
+)
+
+var seedRoles = []model.Role{
+	{Id: seedRoleTourist, Role: "турист"},
+	{Id: seedRoleAthlete, Role: "спортсмен"},
+	{Id: seedRoleTrainer, Role: "тренер"},
+	{Id: seedRoleManager, Role: "руководитель секции"},
+}
+
+var seedAttributes = []model.Attribute{
+	{Id: seedAttrSex, Name: "пол", Type: model.AttrTypeInt},
+	{Id: seedAttrBirthDate, Name: "дата рождения", Type: model.AttrTypeDate},
+	{Id: seedAttrTrainerSalary, Name: "зарплата тренера", Type: model.AttrTypeInt, Role: pgtype.Int4{Int32: seedRoleTrainer, Valid: true}},
+	{Id: seedAttrSpecialization, Name: "специализация", Type: model.AttrTypeString, Role: pgtype.Int4{Int32: seedRoleTrainer, Valid: true}},
+	{Id: seedAttrManagerSince, Name: "руководит с", Type: model.AttrTypeDate, Role: pgtype.Int4{Int32: seedRoleManager, Valid: true}},
+	{Id: seedAttrManagerSalary, Name: "зарплата руководителя", Type: model.AttrTypeInt, Role: pgtype.Int4{Int32: seedRoleManager, Valid: true}},
+}
+
+type seedSection struct {
+	id       int
+	trainers []int
+	groups   []int
+	members  map[int][]int
+}
+
+type seeder struct {
+	tx      *db.Postgres
+	ctx     context.Context
+	rng     *rand.Rand
+	today   time.Time
+	summary dto.SeedSummary
+
+	sections []seedSection
+	athletes []int
+	routes   []int
+	// busy holds the days taken by tours for every person, so nobody goes on
+	// two tours at once.
+	busy map[int][][2]time.Time
+}
+
+func seedDate(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: t, Valid: true}
+}
+
+func seedTime(hour int, minute int) pgtype.Time {
+	return pgtype.Time{Microseconds: int64(hour*60+minute) * 60 * 1_000_000, Valid: true}
+}
+
+// between returns a number from lo to hi inclusive.
+func (s *seeder) between(lo int, hi int) int {
+	return lo + s.rng.IntN(hi-lo+1)
+}
+
+func (s *seeder) chance(percent int) bool {
+	return s.rng.IntN(100) < percent
+}
+
+func (s *seeder) person(section int, role int, minAge int, maxAge int) (int, error) {
+	male := s.chance(50)
+	surname := seedSurnames[s.rng.IntN(len(seedSurnames))]
+	patronymic := seedPatronymics[s.rng.IntN(len(seedPatronymics))]
+	person := model.Person{Surname: surname, Patronymic: patronymic[0]}
+	sex := 0
+	if male {
+		person.Name = seedMaleNames[s.rng.IntN(len(seedMaleNames))]
+	} else {
+		person.Name = seedFemaleNames[s.rng.IntN(len(seedFemaleNames))]
+		person.Surname = femaleSurname(surname)
+		person.Patronymic = patronymic[1]
+		sex = 1
+	}
+
+	id, err := dbqueries.InsertPerson(s.tx, s.ctx, person)
+	if err != nil {
+		return 0, err
+	}
+	err = dbqueries.UpdatePersonRole(s.tx, s.ctx, id, section, role)
+	if err != nil {
+		return 0, err
+	}
+	err = dbqueries.SetPersonIntAttribute(s.tx, s.ctx, model.PersonIntAttribute{AttributeId: seedAttrSex, PersonId: id, Value: sex})
+	if err != nil {
+		return 0, err
+	}
+	birth := s.today.AddDate(-s.between(minAge, maxAge), 0, -s.rng.IntN(365))
+	err = dbqueries.SetPersonDateAttribute(s.tx, s.ctx, model.PersonDateAttribute{AttributeId: seedAttrBirthDate, PersonId: id, Value: seedDate(birth)})
+	if err != nil {
+		return 0, err
+	}
+	s.summary.Persons++
+	return id, nil
+}
+
+func (s *seeder) dictionaries() error {
+	for _, role := range seedRoles {
+		err := dbqueries.EnsureRole(s.tx, s.ctx, role)
+		if err != nil {
+			return err
+		}
+	}
+	for _, attr := range seedAttributes {
+		err := dbqueries.EnsureAttribute(s.tx, s.ctx, attr)
+		if err != nil {
+			return err
+		}
+	}
+	for _, table := range []string{"roles", "attributes"} {
+		err := dbqueries.ResetSequences(s.tx, s.ctx, table)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// section adds a section with a manager, trainers and groups: the first group
+// takes children, the others adults.
+func (s *seeder) section(number int) error {
+	title := seedSections[number%len(seedSections)]
+	if number >= len(seedSections) {
+		title = fmt.Sprintf("%s %d", title, number/len(seedSections)+1)
+	}
+	id, err := dbqueries.CreateSection(s.tx, s.ctx, model.Section{Title: title})
+	if err != nil {
+		return err
+	}
+	s.summary.Sections++
+	section := seedSection{id: id, members: make(map[int][]int)}
+
+	manager, err := s.person(id, seedRoleManager, 35, 65)
+	if err != nil {
+		return err
+	}
+	since := s.today.AddDate(-s.between(1, 15), -s.rng.IntN(12), 0)
+	err = dbqueries.SetPersonDateAttribute(s.tx, s.ctx, model.PersonDateAttribute{AttributeId: seedAttrManagerSince, PersonId: manager, Value: seedDate(since)})
+	if err != nil {
+		return err
+	}
+	err = dbqueries.SetPersonIntAttribute(s.tx, s.ctx, model.PersonIntAttribute{AttributeId: seedAttrManagerSalary, PersonId: manager, Value: s.between(60, 120) * 1000})
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < seedTrainersPerSection; i++ {
+		trainer, err := s.person(id, seedRoleTrainer, 25, 60)
+		if err != nil {
+			return err
+		}
+		err = dbqueries.SetPersonIntAttribute(s.tx, s.ctx, model.PersonIntAttribute{AttributeId: seedAttrTrainerSalary, PersonId: trainer, Value: s.between(40, 90) * 1000})
+		if err != nil {
+			return err
+		}
+		specialization := seedRouteTypes[s.rng.IntN(len(seedRouteTypes))]
+		err = dbqueries.SetPersonStringAttribute(s.tx, s.ctx, model.PersonStringAttribute{AttributeId: seedAttrSpecialization, PersonId: trainer, Value: specialization})
+		if err != nil {
+			return err
+		}
+		section.trainers = append(section.trainers, trainer)
+	}
+
+	for n := 1; n <= seedGroupsPerSection; n++ {
+		group := model.Group{GroupNumber: int32(n), Section: int32(id)}
+		minAge, maxAge := 18, 60
+		if n == 1 {
+			minAge, maxAge = 10, 17
+			group.MaxAge = pgtype.Int4{Int32: 17, Valid: true}
+		} else {
+			group.MinAge = pgtype.Int4{Int32: 18, Valid: true}
+		}
+		groupId, err := dbqueries.CreateGroup(s.tx, s.ctx, group)
+		if err != nil {
+			return err
+		}
+		s.summary.Groups++
+		section.groups = append(section.groups, groupId)
+
+		for i := 0; i < seedMembersPerGroup; i++ {
+			role := seedRoleTourist
+			if s.chance(30) {
+				role = seedRoleAthlete
+			}
+			member, err := s.person(id, role, minAge, maxAge)
+			if err != nil {
+				return err
+			}
+			err = dbqueries.AddGroupMember(s.tx, s.ctx, member, groupId)
+			if err != nil {
+				return err
+			}
+			section.members[groupId] = append(section.members[groupId], member)
+			if role == seedRoleAthlete {
+				s.athletes = append(s.athletes, member)
+			}
+		}
+	}
+	s.sections = append(s.sections, section)
+	return nil
+}
+
+func (s *seeder) geography(scale int) error {
+	types := make([]int, 0, len(seedRouteTypes))
+	for _, routeType := range seedRouteTypes {
+		id, err := dbqueries.CreateRouteType(s.tx, s.ctx, routeType)
+		if err != nil {
+			return err
+		}
+		types = append(types, id)
+	}
+
+	places := make([]int32, 0, seedPlacesPerScale*scale)
+	for i := 0; i < seedPlacesPerScale*scale; i++ {
+		title := seedPlaces[i%len(seedPlaces)]
+		if i >= len(seedPlaces) {
+			title = fmt.Sprintf("%s, стоянка %d", title, i/len(seedPlaces))
+		}
+		id, err := dbqueries.CreatePlace(s.tx, s.ctx, title)
+		if err != nil {
+			return err
+		}
+		places = append(places, int32(id))
+		s.summary.Places++
+	}
+
+	for i := 0; i < seedRoutesPerScale*scale; i++ {
+		route := model.Route{
+			TypeId:     pgtype.Int4{Int32: int32(types[s.rng.IntN(len(types))]), Valid: true},
+			Difficulty: pgtype.Int4{Int32: int32(s.between(1, 6)), Valid: true},
+			LengthKm:   pgtype.Float8{Float64: float64(s.between(40, 500)) / 2, Valid: true},
+		}
+		order := s.rng.Perm(len(places))[:s.between(3, min(6, len(places)))]
+		passes := make([]int32, len(order))
+		for j, k := range order {
+			passes[j] = places[k]
+		}
+		id, err := dbqueries.CreateRoute(s.tx, s.ctx, route, passes)
+		if err != nil {
+			return err
+		}
+		s.routes = append(s.routes, id)
+		s.summary.Routes++
+	}
+	return nil
+}
+
+func (s *seeder) free(person int, from time.Time, to time.Time) bool {
+	for _, days := range s.busy[person] {
+		if !from.After(days[1]) && !to.Before(days[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// tours spreads tours from a year and a half back to three months ahead.
+// Instructors are trainers of the club and some of them join tours led by
+// their colleagues, which is what the instructor category is computed from.
+func (s *seeder) tours(scale int) error {
+	for i := 0; i < seedToursPerScale*scale; i++ {
+		section := s.sections[s.rng.IntN(len(s.sections))]
+		instructor := section.trainers[s.rng.IntN(len(section.trainers))]
+		start := s.today.AddDate(0, 0, s.between(-540, 90))
+		duration := s.between(2, 14)
+		finish := start.AddDate(0, 0, duration-1)
+		if !s.free(instructor, start, finish) {
+			continue
+		}
+		tour := model.Tour{
+			Route:           int32(s.routes[s.rng.IntN(len(s.routes))]),
+			Instructor:      int32(instructor),
+			Start:           seedDate(start),
+			DurationDays:    int32(duration),
+			MinParticipants: pgtype.Int4{Int32: 4, Valid: true},
+			MaxParticipants: pgtype.Int4{Int32: int32(s.between(12, 16)), Valid: true},
+		}
+		id, err := dbqueries.CreateTour(s.tx, s.ctx, tour)
+		if err != nil {
+			return err
+		}
+		s.summary.Tours++
+		s.busy[instructor] = append(s.busy[instructor], [2]time.Time{start, finish})
+
+		var candidates []int
+		for _, group := range section.groups {
+			candidates = append(candidates, section.members[group]...)
+		}
+		if s.chance(40) {
+			other := s.sections[s.rng.IntN(len(s.sections))]
+			for _, trainer := range other.trainers {
+				if trainer != instructor {
+					candidates = append(candidates, trainer)
+				}
+			}
+		}
+		s.rng.Shuffle(len(candidates), func(a int, b int) {
+			candidates[a], candidates[b] = candidates[b], candidates[a]
+		})
+
+		var participants []int
+		want := s.between(5, int(tour.MaxParticipants.Int32))
+		for _, person := range candidates {
+			if len(participants) == want {
+				break
+			}
+			if !s.free(person, start, finish) {
+				continue
+			}
+			err = dbqueries.EnrollPerson(s.tx, s.ctx, id, person, model.EnrollmentEnrolled)
+			if err != nil {
+				return err
+			}
+			s.busy[person] = append(s.busy[person], [2]time.Time{start, finish})
+			participants = append(participants, person)
+			s.summary.Participants++
+		}
+
+		switch {
+		case finish.Before(s.today):
+			err = s.complete(id, participants, start, duration)
+		case start.After(s.today):
+			continue
+		default:
+			err = dbqueries.UpdateTourStatus(s.tx, s.ctx, id, model.TourActive, seedDate(start), pgtype.Date{})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// complete closes a past tour: a few participants withdrew or were evacuated
+// on the way, the others completed it.
+func (s *seeder) complete(tour int, participants []int, start time.Time, duration int) error {
+	finish := start.AddDate(0, 0, duration-1)
+	err := dbqueries.UpdateTourStatus(s.tx, s.ctx, tour, model.TourCompleted, seedDate(start), seedDate(finish))
+	if err != nil {
+		return err
+	}
+	for _, person := range participants {
+		var outcome string
+		switch roll := s.rng.IntN(100); {
+		case roll < 6:
+			outcome = model.OutcomeWithdrew
+		case roll < 9:
+			outcome = model.OutcomeEvacuated
+		default:
+			continue
+		}
+		date := start.AddDate(0, 0, s.rng.IntN(duration))
+		err = dbqueries.SetParticipantOutcome(s.tx, s.ctx, tour, person, pgtype.Text{String: outcome, Valid: true}, seedDate(date))
+		if err != nil {
+			return err
+		}
+	}
+	_, err = dbqueries.CompleteRemainingParticipants(s.tx, s.ctx, tour, seedDate(finish))
+	return err
+}
+
+// workouts gives every group a weekly workout with one of the section
+// trainers and marks attendance of the sessions already held.
+func (s *seeder) workouts() error {
+	monday := s.today.AddDate(0, 0, -(int(s.today.Weekday())+6)%7)
+	for _, section := range s.sections {
+		for i, group := range section.groups {
+			trainer := section.trainers[i%len(section.trainers)]
+			workoutType := seedWorkoutTypes[s.rng.IntN(len(seedWorkoutTypes))]
+			descr, err := dbqueries.CreateWorkoutDescription(s.tx, s.ctx, trainer, workoutType, []int{group})
+			if err != nil {
+				return err
+			}
+
+			weekday := s.rng.IntN(6)
+			hour := s.between(17, 19)
+			length := 90 + 30*s.rng.IntN(2)
+			for week := -seedWorkoutWeeksBack; week < seedWorkoutWeeksAhead; week++ {
+				day := monday.AddDate(0, 0, 7*week+weekday)
+				session := model.WorkoutSession{
+					Description: int32(descr),
+					Date:        seedDate(day),
+					StartTime:   seedTime(hour, 0),
+					FinishTime:  seedTime(hour+length/60, length%60),
+				}
+				workout, err := dbqueries.CreateWorkoutSession(s.tx, s.ctx, session)
+				if err != nil {
+					return err
+				}
+				s.summary.Workouts++
+				if !day.Before(s.today) {
+					continue
+				}
+				for _, member := range section.members[group] {
+					attendance := model.Attendance{
+						Workout:  int32(workout),
+						Person:   int32(member),
+						Status:   model.AttendancePresent,
+						MarkedBy: pgtype.Int4{Int32: int32(trainer), Valid: true},
+					}
+					switch roll := s.rng.IntN(100); {
+					case roll < 10:
+						attendance.Status = model.AttendanceAbsent
+					case roll < 17:
+						attendance.Status = model.AttendanceExcused
+					case roll < 25:
+						attendance.Status = model.AttendanceLate
+					}
+					err = dbqueries.MarkAttendance(s.tx, s.ctx, attendance)
+					if err != nil {
+						return err
+					}
+					s.summary.Attendance++
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *seeder) championships(scale int) error {
+	for i := 0; i < seedChampsPerScale*scale; i++ {
+		date := s.today.AddDate(0, 0, s.between(-365, 120))
+		title := fmt.Sprintf("%s %d", seedChampionships[s.rng.IntN(len(seedChampionships))], date.Year())
+		id, err := dbqueries.CreateChampionship(s.tx, s.ctx, model.Championship{Title: title, Date: seedDate(date)})
+		if err != nil {
+			return err
+		}
+		s.summary.Championships++
+		for _, athlete := range s.athletes {
+			if !s.chance(35) {
+				continue
+			}
+			err = dbqueries.RegisterForChampionship(s.tx, s.ctx, id, athlete)
+			if err != nil {
+				return err
+			}
+			s.summary.Registrations++
+		}
+	}
+	return nil
+}
+
+// SeedClub fills an empty database with a synthetic club of the given scale,
+// one section per unit. The data depends only on seed, scale and today, so a
+// run can be repeated to reproduce what a query returned on it.
+func SeedClub(seed uint64, scale int, today time.Time) (*dto.SeedSummary, error) {
+	if scale < 1 {
+		return nil, errors.New("scale must be positive")
+	}
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+
+	s := seeder{
+		ctx:   ctx,
+		rng:   rand.New(rand.NewPCG(seed, seed^0x5eed)),
+		today: time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC),
+		busy:  make(map[int][][2]time.Time),
+	}
+	s.summary.Seed = seed
+	s.summary.Scale = scale
+
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		s.tx = tx
+		count, err := dbqueries.CountTableRows(tx, ctx, "persons")
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("seeding needs an empty database, it has %d persons", count)
+		}
+
+		err = s.dictionaries()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < scale; i++ {
+			err = s.section(i)
+			if err != nil {
+				return err
+			}
+		}
+		err = s.geography(scale)
+		if err != nil {
+			return err
+		}
+		err = s.tours(scale)
+		if err != nil {
+			return err
+		}
+		err = s.workouts()
+		if err != nil {
+			return err
+		}
+		return s.championships(scale)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s.summary, nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"log"
+	"math"
+	"strconv"
+	"time"
+)
+
+var ageGroups = []string{"0-17", "18-24", "25-34", "35-44", "45-54", "55+", "unknown"}
+
+// GetStatsOverview reads the club statistics from the summaries as of their last
+// refresh. Monthly figures are limited to the date range, headcounts and the age
+// and sex distribution are current.
+func GetStatsOverview(section string, dateFrom string, dateTo string) (*dto.StatsOverview, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+
+	sectionInt := model.AllSections
+	if section != "" {
+		sectionInt, err = strconv.Atoi(section)
+		if err != nil {
+			return nil, err
+		}
+	}
+	from, to := dateRange(dateFrom, dateTo)
+
+	var response dto.StatsOverview
+	response.DateFrom = dateFrom
+	response.DateTo = dateTo
+	if section != "" {
+		sectionId := int32(sectionInt)
+		response.Section = &sectionId
+	}
+	refreshedAt, err := dbqueries.GetStatsRefreshedAt(pg, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if refreshedAt.Valid {
+		response.RefreshedAt = refreshedAt.Time.Format("2006-01-02 15:04:05")
+	}
+
+	roles, err := dbqueries.GetAllRoles(pg, ctx)
+	if err != nil {
+		return nil, err
+	}
+	roleNames := map[int32]string{}
+	for _, role := range roles {
+		roleNames[role.Id] = role.Role
+	}
+	members, err := dbqueries.GetStatsMembers(pg, ctx, sectionInt)
+	if err != nil {
+		return nil, err
+	}
+	response.Members = []dto.MemberCount{}
+	for _, count := range members {
+		response.Members = append(response.Members, dto.MemberCount{Role: count.Role, RoleName: roleNames[count.Role], Members: count.Members})
+	}
+
+	newMembers, err := dbqueries.GetStatsNewMembers(pg, ctx, sectionInt, from, to)
+	if err != nil {
+		return nil, err
+	}
+	response.NewMembers = []dto.NewMembers{}
+	for _, month := range newMembers {
+		response.NewMembers = append(response.NewMembers, dto.NewMembers{Month: month.Month.Time.Format("2006-01"), Members: month.Members})
+		response.Totals.NewMembers += month.Members
+	}
+
+	tours, err := dbqueries.GetStatsTours(pg, ctx, sectionInt, from, to)
+	if err != nil {
+		return nil, err
+	}
+	response.Tours = []dto.TourStats{}
+	for _, month := range tours {
+		response.Tours = append(response.Tours, dto.TourStats{Month: month.Month.Time.Format("2006-01"), Tours: month.Tours,
+			Km: month.Km, Participants: month.Participants})
+		response.Totals.Tours += month.Tours
+		response.Totals.Km += month.Km
+	}
+
+	workouts, err := dbqueries.GetStatsWorkouts(pg, ctx, sectionInt, from, to)
+	if err != nil {
+		return nil, err
+	}
+	response.Workouts = []dto.WorkoutStats{}
+	for _, month := range workouts {
+		hours := math.Round(month.Hours*100) / 100
+		response.Workouts = append(response.Workouts, dto.WorkoutStats{Month: month.Month.Time.Format("2006-01"),
+			Workouts: month.Workouts, Hours: hours})
+		response.Totals.WorkoutHours += hours
+	}
+
+	championships, err := dbqueries.GetStatsChampionships(pg, ctx, sectionInt, from, to)
+	if err != nil {
+		return nil, err
+	}
+	response.Championships = []dto.ChampionshipStats{}
+	for _, month := range championships {
+		response.Championships = append(response.Championships, dto.ChampionshipStats{Month: month.Month.Time.Format("2006-01"),
+			Championships: month.Championships, Participants: month.Participants})
+		response.Totals.ChampionshipsAttended += month.Championships
+		response.Totals.ChampionshipEntries += month.Participants
+	}
+
+	demographics, err := dbqueries.GetStatsDemographics(pg, ctx, sectionInt)
+	if err != nil {
+		return nil, err
+	}
+	byAge := map[string]int32{}
+	bySex := map[int32]int32{}
+	var sexes []int32
+	for _, count := range demographics {
+		byAge[count.AgeGroup] += count.Members
+		if _, ok := bySex[count.Sex]; !ok {
+			sexes = append(sexes, count.Sex)
+		}
+		bySex[count.Sex] += count.Members
+		response.Totals.Members += count.Members
+	}
+	response.Age = []dto.DistributionEntry{}
+	for _, group := range ageGroups {
+		if byAge[group] > 0 {
+			response.Age = append(response.Age, dto.DistributionEntry{Value: group, Members: byAge[group]})
+		}
+	}
+	response.Sex = []dto.DistributionEntry{}
+	for _, sex := range sexes {
+		value := strconv.Itoa(int(sex))
+		if sex < 0 {
+			value = "unknown"
+		}
+		response.Sex = append(response.Sex, dto.DistributionEntry{Value: value, Members: bySex[sex]})
+	}
+	response.Totals.Km = math.Round(response.Totals.Km*100) / 100
+	response.Totals.WorkoutHours = math.Round(response.Totals.WorkoutHours*100) / 100
+	return &response, nil
+}
+
+func RefreshStats() error {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return err
+	}
+	return dbqueries.RefreshStats(pg, context.Background())
+}
+
+// WatchStats refreshes the statistics summaries every interval until ctx is done.
+func WatchStats(ctx context.Context, interval time.Duration, logger *log.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := RefreshStats()
+		if err != nil {
+			logger.Printf("stats refresh: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"db_backend/db"
+	"db_backend/dbqueries"
+	"db_backend/dto"
+	"db_backend/model"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"strconv"
+	"time"
+)
+
+func parseDateOrToday(value string) (pgtype.Date, error) {
+	var date pgtype.Date
+	if value == "" {
+		value = time.Now().Format("2006-01-02")
+	}
+	err := date.Scan(value)
+	if err != nil {
+		return pgtype.Date{}, err
+	}
+	return date, nil
+}
+
+func GetTour(id string) (*dto.TourResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tour, err := dbqueries.GetTour(pg, context.Background(), idInt)
+	if err != nil {
+		return nil, err
+	}
+	if tour == nil {
+		return nil, nil
+	}
+
+	enrolled, err := dbqueries.CountTourParticipants(pg, context.Background(), idInt, model.EnrollmentEnrolled)
+	if err != nil {
+		return nil, err
+	}
+
+	response := tour2Response(*tour, enrolled)
+	return &response, nil
+}
+
+func ChangeTourStatus(tour string, status string, date string) (*dto.TourStatusResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return nil, err
+	}
+	dateReady, err := parseDateOrToday(date)
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.TourStatusResponse
+	ctx := context.Background()
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		tourModel, err := lockTour(tx, ctx, tourInt)
+		if err != nil {
+			return err
+		}
+		if !tourModel.CanMoveTo(status) {
+			return fmt.Errorf("tour %d cannot move from %s to %s", tourInt, tourModel.Status, status)
+		}
+
+		var startedOn, finishedOn pgtype.Date
+		switch status {
+		case model.TourActive:
+			startedOn = dateReady
+		case model.TourCompleted, model.TourCancelled:
+			finishedOn = dateReady
+			if tourModel.StartedOn.Valid && dateReady.Time.Before(tourModel.StartedOn.Time) {
+				return fmt.Errorf("tour %d cannot finish before it started on %s", tourInt, tourModel.StartedOn.Time.Format("2006-01-02"))
+			}
+		}
+
+		err = dbqueries.UpdateTourStatus(tx, ctx, tourInt, status, startedOn, finishedOn)
+		if err != nil {
+			return err
+		}
+
+		if status == model.TourCompleted {
+			completed, err := dbqueries.CompleteRemainingParticipants(tx, ctx, tourInt, dateReady)
+			if err != nil {
+				return err
+			}
+			response.Completed = int32(completed)
+		}
+
+		if status != model.TourActive && !tourModel.EnrollmentClosed {
+			err = dbqueries.SetTourEnrollmentClosed(tx, ctx, tourInt, true)
+			if err != nil {
+				return err
+			}
+		}
+
+		tourModel, err = dbqueries.GetTour(tx, ctx, tourInt)
+		if err != nil {
+			return err
+		}
+		enrolled, err := dbqueries.CountTourParticipants(tx, ctx, tourInt, model.EnrollmentEnrolled)
+		if err != nil {
+			return err
+		}
+		response.Tour = tour2Response(*tourModel, enrolled)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func SetParticipantOutcome(tour string, person string, outcome string, date string) (*dto.ParticipantOutcomeResponse, error) {
+	pg, err := db.NewPG(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tourInt, err := strconv.Atoi(tour)
+	if err != nil {
+		return nil, err
+	}
+	personInt, err := strconv.Atoi(person)
+	if err != nil {
+		return nil, err
+	}
+	dateReady, err := parseDateOrToday(date)
+	if err != nil {
+		return nil, err
+	}
+
+	switch outcome {
+	case model.OutcomeCompleted, model.OutcomeWithdrew, model.OutcomeEvacuated:
+	default:
+		return nil, fmt.Errorf("unknown outcome %q", outcome)
+	}
+
+	ctx := context.Background()
+	err = pg.InTx(ctx, func(tx *db.Postgres) error {
+		tourModel, err := lockTour(tx, ctx, tourInt)
+		if err != nil {
+			return err
+		}
+		if tourModel.Status != model.TourActive && tourModel.Status != model.TourCompleted {
+			return fmt.Errorf("tour %d is %s, outcomes are recorded for active or completed tours", tourInt, tourModel.Status)
+		}
+		if outcome == model.OutcomeCompleted && tourModel.Status != model.TourCompleted {
+			return fmt.Errorf("tour %d is not completed yet", tourInt)
+		}
+		if tourModel.StartedOn.Valid && dateReady.Time.Before(tourModel.StartedOn.Time) {
+			return fmt.Errorf("outcome date is before the tour started on %s", tourModel.StartedOn.Time.Format("2006-01-02"))
+		}
+
+		current, err := dbqueries.GetEnrollmentStatus(tx, ctx, tourInt, personInt)
+		if err != nil {
+			return err
+		}
+		if current == nil || *current != model.EnrollmentEnrolled {
+			return fmt.Errorf("person %d is not a participant of tour %d", personInt, tourInt)
+		}
+
+		return dbqueries.SetParticipantOutcome(tx, ctx, tourInt, personInt, pgtype.Text{String: outcome, Valid: true}, dateReady)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response dto.ParticipantOutcomeResponse
+	response.Tour = int32(tourInt)
+	response.Person = int32(personInt)
+	response.Outcome = outcome
+	response.OutcomeDate = dateReady.Time.Format("2006-01-02")
+	return &response, nil
+}
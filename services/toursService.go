@@ -2,51 +2,44 @@ package services
 
 import (
 	"context"
-	"db_backend/db"
-	"db_backend/dbqueries"
 	"db_backend/dto"
 	"db_backend/model"
 	"strconv"
 )
 
-func GetTouristsByTour(section string, group string, cntTours string, tourId string, tourTime string, routeId string, placeId string) (*dto.PersonsListResponse, error) {
-	pg, err := db.NewPG(context.Background())
+func (c *Club) GetTouristsByTour(section string, group string, cntTours string, tourId string, tourTime string, routeId string, placeId string) (*dto.PersonsListResponse, error) {
+	result, err := c.repos.Persons.GetAllTourists(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := dbqueries.GetAllTourists(pg, context.Background())
+	result, err = checkParameter(section, c.repos.Persons.GetTouristsBySection, result)
 	if err != nil {
 		return nil, err
 	}
-
-	result, err = checkParameter(pg, section, dbqueries.GetTouristsBySection, result)
+	result, err = checkParameter(group, c.repos.Persons.GetTouristsByGroup, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, group, dbqueries.GetTouristsByGroup, result)
+	result, err = checkParameter(cntTours, c.repos.Tours.GetTouristsByToursCount, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, cntTours, dbqueries.GetTouristsByToursCount, result)
+	result, err = checkParameter(tourId, c.repos.Tours.GetTouristsByTour, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, tourId, dbqueries.GetTouristsByTour, result)
+	result, err = checkParameter(routeId, c.repos.Tours.GetTouristsByTourRoute, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, routeId, dbqueries.GetTouristsByTourRoute, result)
-	if err != nil {
-		return nil, err
-	}
-	result, err = checkParameter(pg, placeId, dbqueries.GetTouristsByTourPlace, result)
+	result, err = checkParameter(placeId, c.repos.Tours.GetTouristsByTourPlace, result)
 	if err != nil {
 		return nil, err
 	}
 
 	if tourTime != "" {
-		resultPart, err := dbqueries.GetTouristsByTourTime(pg, context.Background(), tourTime)
+		resultPart, err := c.repos.Tours.GetTouristsByTourTime(context.Background(), tourTime)
 		if err != nil {
 			return nil, err
 		}
@@ -71,38 +64,38 @@ func GetTouristsByTour(section string, group string, cntTours string, tourId str
 	return &response, nil
 }
 
-func GetRoutesWithConditions(section string, dateFrom string, dateTo string, instructorId string, cntGroups string) (*dto.RouteIdsListResponse, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	result, err := dbqueries.GetAllRouteIds(pg, context.Background())
+func (c *Club) GetRoutesWithConditions(section string, dateFrom string, dateTo string, instructorId string, cntGroups string, sortBy string) (*dto.RouteIdsListResponse, error) {
+	result, err := c.repos.Routes.GetAllRouteIds(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	result, err = checkParameter(pg, section, dbqueries.GetRoutesBySection, result)
+	result, err = checkParameter(section, c.repos.Routes.GetRoutesBySection, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, instructorId, dbqueries.GetRoutesByInstructor, result)
+	result, err = checkParameter(instructorId, c.repos.Routes.GetRoutesByInstructor, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, cntGroups, dbqueries.GetRoutesByCntGroups, result)
+	result, err = checkParameter(cntGroups, c.repos.Routes.GetRoutesByCntGroups, result)
 	if err != nil {
 		return nil, err
 	}
 
 	if dateTo != "" && dateFrom != "" {
-		resultPart, err := dbqueries.GetRoutesByTime(pg, context.Background(), dateFrom, dateTo)
+		resultPart, err := c.repos.Routes.GetRoutesByTime(context.Background(), dateFrom, dateTo)
 		if err != nil {
 			return nil, err
 		}
 		result = intersection(result, resultPart)
 	}
 
+	result, err = sortRoutes(c.repos.Routes, result, sortBy)
+	if err != nil {
+		return nil, err
+	}
+
 	var response dto.RouteIdsListResponse
 
 	for _, routeId := range result {
@@ -115,26 +108,21 @@ func GetRoutesWithConditions(section string, dateFrom string, dateTo string, ins
 	return &response, nil
 }
 
-func GetRoutesWithGeoCond(placeId string, length string, difficulty string) (*dto.RouteIdsListResponse, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	result, err := dbqueries.GetAllRouteIds(pg, context.Background())
+func (c *Club) GetRoutesWithGeoCond(placeId string, length string, difficulty string) (*dto.RouteIdsListResponse, error) {
+	result, err := c.repos.Routes.GetAllRouteIds(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	result, err = checkParameter(pg, placeId, dbqueries.GetRoutesByPlace, result)
+	result, err = checkParameter(placeId, c.repos.Routes.GetRoutesByPlace, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, length, dbqueries.GetRoutesByLength, result)
+	result, err = checkParameter(length, c.repos.Routes.GetRoutesByLength, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, difficulty, dbqueries.GetRoutesByDifficulty, result)
+	result, err = checkParameter(difficulty, c.repos.Routes.GetRoutesByDifficulty, result)
 	if err != nil {
 		return nil, err
 	}
@@ -151,18 +139,13 @@ func GetRoutesWithGeoCond(placeId string, length string, difficulty string) (*dt
 	return &response, nil
 }
 
-func GetInstructorsWithCondition(role string, routeType string, routeDifficulty string, cntTours string, tourId string, placeId string) (*dto.PersonsListResponse, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	result, err := dbqueries.GetAllInstructors(pg, context.Background())
+func (c *Club) GetInstructorsWithCondition(role string, routeType string, routeDifficulty string, cntTours string, tourId string, placeId string) (*dto.PersonsListResponse, error) {
+	result, err := c.repos.Tours.GetAllInstructors(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	result, err = checkParameter(pg, role, dbqueries.GetInstructorsByRole, result)
+	result, err = checkParameter(role, c.repos.Tours.GetInstructorsByRole, result)
 	if err != nil {
 		return nil, err
 	}
@@ -178,7 +161,7 @@ func GetInstructorsWithCondition(role string, routeType string, routeDifficulty
 			return nil, err
 		}
 
-		resultPart, err := dbqueries.GetInstructorsByCategory(pg, context.Background(), typeReady, difficultyReady)
+		resultPart, err := c.repos.Tours.GetInstructorsByCategory(context.Background(), typeReady, difficultyReady)
 		if err != nil {
 			return nil, err
 		}
@@ -186,15 +169,15 @@ func GetInstructorsWithCondition(role string, routeType string, routeDifficulty
 		result = intersection(result, resultPart)
 	}
 
-	result, err = checkParameter(pg, cntTours, dbqueries.GetInstructorsByCntTours, result)
+	result, err = checkParameter(cntTours, c.repos.Tours.GetInstructorsByCntTours, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, tourId, dbqueries.GetInstructorsByTour, result)
+	result, err = checkParameter(tourId, c.repos.Tours.GetInstructorsByTour, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, placeId, dbqueries.GetInstructorsByPlace, result)
+	result, err = checkParameter(placeId, c.repos.Tours.GetInstructorsByPlace, result)
 	if err != nil {
 		return nil, err
 	}
@@ -217,22 +200,17 @@ func GetInstructorsWithCondition(role string, routeType string, routeDifficulty
 	return &response, nil
 }
 
-func GetTouristsWithTrainerInstructor(section string, group string) (*dto.PersonsListResponse, error) {
-	pg, err := db.NewPG(context.Background())
+func (c *Club) GetTouristsWithTrainerInstructor(section string, group string) (*dto.PersonsListResponse, error) {
+	result, err := c.repos.Tours.GetTouristsWithTrainerInstructor(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := dbqueries.GetTouristsWithTrainerInstructor(pg, context.Background())
+	result, err = checkParameter(section, c.repos.Persons.GetTouristsBySection, result)
 	if err != nil {
 		return nil, err
 	}
-
-	result, err = checkParameter(pg, section, dbqueries.GetTouristsBySection, result)
-	if err != nil {
-		return nil, err
-	}
-	result, err = checkParameter(pg, group, dbqueries.GetTouristsByGroup, result)
+	result, err = checkParameter(group, c.repos.Persons.GetTouristsByGroup, result)
 
 	var response dto.PersonsListResponse
 
@@ -252,22 +230,17 @@ func GetTouristsWithTrainerInstructor(section string, group string) (*dto.Person
 	return &response, nil
 }
 
-func GetTouristsCompletedALl(section string, group string) (*dto.PersonsListResponse, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	result, err := dbqueries.GetTouristsCompletedAll(pg, context.Background())
+func (c *Club) GetTouristsCompletedALl(section string, group string) (*dto.PersonsListResponse, error) {
+	result, err := c.repos.Tours.GetTouristsCompletedAll(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	result, err = checkParameter(pg, section, dbqueries.GetTouristsBySection, result)
+	result, err = checkParameter(section, c.repos.Persons.GetTouristsBySection, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, group, dbqueries.GetTouristsByGroup, result)
+	result, err = checkParameter(group, c.repos.Persons.GetTouristsByGroup, result)
 
 	var response dto.PersonsListResponse
 
@@ -287,28 +260,23 @@ func GetTouristsCompletedALl(section string, group string) (*dto.PersonsListResp
 	return &response, nil
 }
 
-func GetTouristsCompletedRoutes(section string, group string, request dto.CompletedRoutesRequest) (*dto.PersonsListResponse, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	result, err := dbqueries.GetAllTourists(pg, context.Background())
+func (c *Club) GetTouristsCompletedRoutes(section string, group string, request dto.CompletedRoutesRequest) (*dto.PersonsListResponse, error) {
+	result, err := c.repos.Persons.GetAllTourists(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	result, err = checkParameter(pg, section, dbqueries.GetTouristsBySection, result)
+	result, err = checkParameter(section, c.repos.Persons.GetTouristsBySection, result)
 	if err != nil {
 		return nil, err
 	}
-	result, err = checkParameter(pg, group, dbqueries.GetTouristsByGroup, result)
+	result, err = checkParameter(group, c.repos.Persons.GetTouristsByGroup, result)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, route := range request.Routes {
-		resultPart, err := dbqueries.GetTouristsCompletedRoute(pg, context.Background(), int(route))
+		resultPart, err := c.repos.Tours.GetTouristsCompletedRoute(context.Background(), int(route))
 
 		if err != nil {
 			return nil, err
@@ -334,13 +302,8 @@ func GetTouristsCompletedRoutes(section string, group string, request dto.Comple
 	return &response, nil
 }
 
-func GetAllRouteTypes() ([]dto.RouteType, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	typesM, err := dbqueries.GetAllRouteTypes(pg, context.Background())
+func (c *Club) GetAllRouteTypes() ([]dto.RouteType, error) {
+	typesM, err := c.repos.Routes.GetAllRouteTypes(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -355,12 +318,7 @@ func GetAllRouteTypes() ([]dto.RouteType, error) {
 	return response, nil
 }
 
-func GetSuitablePersonsByRoute(routeType string, difficulty string) (*dto.PersonsListResponse, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Club) GetSuitablePersonsByRoute(routeType string, difficulty string) (*dto.PersonsListResponse, error) {
 	routeTypeInt, err := strconv.Atoi(routeType)
 	if err != nil {
 		return nil, err
@@ -369,12 +327,12 @@ func GetSuitablePersonsByRoute(routeType string, difficulty string) (*dto.Person
 	var result []model.Person
 
 	if routeTypeInt == 1 {
-		result, err = dbqueries.GetTouristsBySection(pg, context.Background(), 2)
+		result, err = c.repos.Persons.GetTouristsBySection(context.Background(), 2)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		result, err = dbqueries.GetAllTourists(pg, context.Background())
+		result, err = c.repos.Persons.GetAllTourists(context.Background())
 		if err != nil {
 			return nil, err
 		}
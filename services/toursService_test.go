@@ -0,0 +1,241 @@
+package services
+
+import (
+	"db_backend/dto"
+	"db_backend/model"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+	"testing"
+)
+
+// tours are two routes sharing a place and three tours on them: one finished in
+// July, one in June and one starting today.
+type tours struct {
+	*fixture
+	mountains, water   int
+	peak, lake         int
+	ridge, river       int
+	july, june, autumn int
+}
+
+func useTours(t *testing.T) *tours {
+	c := &tours{fixture: useMemory(t)}
+	m := c.memory
+	c.mountains = m.AddRouteType("горный")
+	c.water = m.AddRouteType("водный")
+	c.peak = m.AddPlace("Эльбрус")
+	c.lake = m.AddPlace("Селигер")
+	c.ridge = m.AddRoute(model.Route{
+		TypeId:     pgtype.Int4{Int32: int32(c.mountains), Valid: true},
+		Difficulty: pgtype.Int4{Int32: 3, Valid: true},
+		LengthKm:   pgtype.Float8{Float64: 40, Valid: true},
+	}, c.peak, c.lake)
+	c.river = m.AddRoute(model.Route{
+		TypeId:     pgtype.Int4{Int32: int32(c.water), Valid: true},
+		Difficulty: pgtype.Int4{Int32: 1, Valid: true},
+		LengthKm:   pgtype.Float8{Float64: 12, Valid: true},
+	}, c.lake)
+
+	c.july = m.AddTour(model.Tour{Route: int32(c.ridge), Instructor: int32(c.gleb), Start: date("2025-07-01"), DurationDays: 5})
+	c.june = m.AddTour(model.Tour{Route: int32(c.river), Instructor: int32(c.gleb), Start: date("2025-06-01"), DurationDays: 2})
+	c.autumn = m.AddTour(model.Tour{Route: int32(c.river), Instructor: int32(c.dina), Start: date("2025-09-01"), DurationDays: 3})
+	m.AddParticipant(c.july, c.anna, model.EnrollmentEnrolled, model.OutcomeCompleted)
+	m.AddParticipant(c.july, c.boris, model.EnrollmentEnrolled, model.OutcomeCompleted)
+	m.AddParticipant(c.july, c.gleb, model.EnrollmentEnrolled, model.OutcomeCompleted)
+	m.AddParticipant(c.june, c.boris, model.EnrollmentEnrolled, model.OutcomeCompleted)
+	m.AddParticipant(c.autumn, c.anna, model.EnrollmentEnrolled, "")
+	m.AddParticipant(c.autumn, c.vera, model.EnrollmentEnrolled, "")
+	m.AddParticipant(c.autumn, c.egor, model.EnrollmentCancelled, "")
+	return c
+}
+
+func routeIds(t *testing.T, response *dto.RouteIdsListResponse, err error) []int32 {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := append([]int32{}, response.RouteIds...)
+	slices.Sort(result)
+	return result
+}
+
+func TestGetTouristsByTour(t *testing.T) {
+	t.Parallel()
+	c := useTours(t)
+	tests := []struct {
+		name                                               string
+		section, group, cntTours, tour, time, route, place string
+		want                                               []int32
+	}{
+		{name: "all", want: ids(c.anna, c.boris, c.vera)},
+		{name: "tours count", cntTours: "2", want: ids(c.boris)},
+		{name: "tour", tour: itoa(c.autumn), want: ids(c.anna, c.vera)},
+		{name: "tour time", time: "2025-09-02", want: ids(c.anna, c.vera)},
+		{name: "after the tour", time: "2025-09-04", want: ids()},
+		{name: "route", route: itoa(c.ridge), want: ids(c.anna, c.boris)},
+		{name: "place", place: itoa(c.peak), want: ids(c.anna, c.boris)},
+		{name: "section and route", section: itoa(c.sectionA), route: itoa(c.river), want: ids(c.anna, c.boris)},
+		{name: "group and tour", group: itoa(c.groupB), tour: itoa(c.july), want: ids()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			response, err := c.GetTouristsByTour(tt.section, tt.group, tt.cntTours, tt.tour, tt.time, tt.route, tt.place)
+			got := personIds(t, response, err)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRoutesWithConditions(t *testing.T) {
+	t.Parallel()
+	c := useTours(t)
+	tests := []struct {
+		name                                     string
+		section, from, to, instructor, cntGroups string
+		want                                     []int32
+	}{
+		{name: "all", want: ids(c.ridge, c.river)},
+		{name: "section", section: itoa(c.sectionB), want: ids(c.river)},
+		{name: "instructor", instructor: itoa(c.dina), want: ids(c.river)},
+		{name: "tours count", cntGroups: "2", want: ids(c.river)},
+		{name: "time", from: "2025-05-01", to: "2025-08-01", want: ids(c.ridge, c.river)},
+		{name: "time after june", from: "2025-06-15", to: "2025-08-01", want: ids(c.ridge)},
+		{name: "from without to", from: "2025-06-15", want: ids(c.ridge, c.river)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			response, err := c.GetRoutesWithConditions(tt.section, tt.from, tt.to, tt.instructor, tt.cntGroups, "")
+			got := routeIds(t, response, err)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRoutesWithConditionsSorted(t *testing.T) {
+	t.Parallel()
+	c := useTours(t)
+	c.memory.AddRouteReview(c.ridge, 3, 4)
+	c.memory.AddRouteReview(c.river, 5, 1)
+	c.memory.AddRouteReview(c.river, 4, 2)
+
+	response, err := c.GetRoutesWithConditions("", "", "", "", "", "rating")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int32{int32(c.river), int32(c.ridge)}
+	if !slices.Equal(response.RouteIds, want) {
+		t.Errorf("got %v, want %v", response.RouteIds, want)
+	}
+
+	response, err = c.GetRoutesWithConditions("", "", "", "", "", "difficulty_gap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []int32{int32(c.ridge), int32(c.river)}
+	if !slices.Equal(response.RouteIds, want) {
+		t.Errorf("got %v, want %v", response.RouteIds, want)
+	}
+
+	_, err = c.GetRoutesWithConditions("", "", "", "", "", "length")
+	if err == nil {
+		t.Error("expected an error for an unknown sort")
+	}
+}
+
+func TestGetRoutesWithGeoCond(t *testing.T) {
+	t.Parallel()
+	c := useTours(t)
+	tests := []struct {
+		name                      string
+		place, length, difficulty string
+		want                      []int32
+	}{
+		{name: "all", want: ids(c.ridge, c.river)},
+		{name: "place", place: itoa(c.peak), want: ids(c.ridge)},
+		{name: "shared place", place: itoa(c.lake), want: ids(c.ridge, c.river)},
+		{name: "length", length: "20", want: ids(c.ridge)},
+		{name: "difficulty", difficulty: "2", want: ids(c.ridge)},
+		{name: "place and difficulty", place: itoa(c.lake), difficulty: "4", want: ids()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			response, err := c.GetRoutesWithGeoCond(tt.place, tt.length, tt.difficulty)
+			got := routeIds(t, response, err)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetInstructorsWithCondition(t *testing.T) {
+	t.Parallel()
+	c := useTours(t)
+	tests := []struct {
+		name                                                string
+		role, routeType, difficulty, cntTours, route, place string
+		want                                                []int32
+	}{
+		{name: "all", want: ids(c.gleb, c.dina)},
+		{name: "role", role: "2", want: ids(c.gleb, c.dina)},
+		{name: "other role", role: "0", want: ids()},
+		{name: "category", routeType: itoa(c.mountains), difficulty: "3", want: ids(c.gleb)},
+		{name: "category too hard", routeType: itoa(c.mountains), difficulty: "4", want: ids()},
+		{name: "type without difficulty", routeType: itoa(c.water), want: ids(c.gleb, c.dina)},
+		{name: "tours count", cntTours: "2", want: ids(c.gleb)},
+		{name: "route", route: itoa(c.river), want: ids(c.gleb, c.dina)},
+		{name: "place", place: itoa(c.peak), want: ids(c.gleb)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			response, err := c.GetInstructorsWithCondition(tt.role, tt.routeType, tt.difficulty, tt.cntTours, tt.route, tt.place)
+			got := personIds(t, response, err)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTouristsCompletedRoutes(t *testing.T) {
+	t.Parallel()
+	c := useTours(t)
+	request := dto.CompletedRoutesRequest{Routes: ids(c.ridge, c.river)}
+	response, err := c.GetTouristsCompletedRoutes("", "", request)
+	got := personIds(t, response, err)
+	if want := ids(c.boris); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	response, err = c.GetTouristsCompletedALl(itoa(c.sectionA), "")
+	got = personIds(t, response, err)
+	if want := ids(c.boris); !slices.Equal(got, want) {
+		t.Errorf("completed all: got %v, want %v", got, want)
+	}
+}
+
+func TestGetTouristsWithTrainerInstructor(t *testing.T) {
+	t.Parallel()
+	c := useTours(t)
+	c.memory.AddWorkoutDescription(c.dina, "ОФП", c.groupB, c.groupA1)
+
+	response, err := c.GetTouristsWithTrainerInstructor("", "")
+	got := personIds(t, response, err)
+	if want := ids(c.anna, c.vera); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	response, err = c.GetTouristsWithTrainerInstructor("", itoa(c.groupA1))
+	got = personIds(t, response, err)
+	if want := ids(c.anna); !slices.Equal(got, want) {
+		t.Errorf("in group: got %v, want %v", got, want)
+	}
+}
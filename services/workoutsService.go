@@ -2,18 +2,31 @@ package services
 
 import (
 	"context"
-	"db_backend/db"
 	"db_backend/dbqueries"
 	"db_backend/dto"
+	"db_backend/model"
+	"errors"
+	"fmt"
+	"math"
 	"strconv"
+	"time"
 )
 
-func GetStrainForTrainer(trainer string, fromDate string, toDate string) (*dto.StrainListResponse, error) {
-	pg, err := db.NewPG(context.Background())
-	if err != nil {
-		return nil, err
+// formatDuration renders microseconds as "HH:MM:SS" (the default), whole minutes
+// or an ISO-8601 duration.
+func formatDuration(us int64, format string) (any, error) {
+	switch format {
+	case "", "hms":
+		return model.FormatHMS(us), nil
+	case "minutes":
+		return us / 60_000_000, nil
+	case "iso":
+		return model.FormatISO8601(us), nil
 	}
+	return nil, fmt.Errorf("unknown duration format %q", format)
+}
 
+func (c *Club) GetStrainForTrainer(trainer string, fromDate string, toDate string, format string) (*dto.StrainListResponse, error) {
 	if fromDate == "" {
 		fromDate = "0001-01-01"
 	}
@@ -27,7 +40,7 @@ func GetStrainForTrainer(trainer string, fromDate string, toDate string) (*dto.S
 		return nil, err
 	}
 
-	result, err := dbqueries.GetStrainForTrainer(pg, context.Background(), idReady, fromDate, toDate)
+	result, err := c.repos.Workouts.GetStrainForTrainer(context.Background(), idReady, fromDate, toDate)
 	if err != nil {
 		return nil, err
 	}
@@ -37,7 +50,10 @@ func GetStrainForTrainer(trainer string, fromDate string, toDate string) (*dto.S
 	for _, strain := range result {
 		var jsonStrain dto.StrainResponse
 		jsonStrain.Strain = strain.Type
-		jsonStrain.Duration = strain.GetTimeAsString()
+		jsonStrain.Duration, err = formatDuration(model.IntervalMicroseconds(strain.Duration), format)
+		if err != nil {
+			return nil, err
+		}
 
 		response.StrainList = append(response.StrainList, jsonStrain)
 	}
@@ -47,3 +63,121 @@ func GetStrainForTrainer(trainer string, fromDate string, toDate string) (*dto.S
 
 	return &response, nil
 }
+
+type strainKey struct {
+	key   int32
+	label string
+}
+
+// GetStrainReport buckets workout load by week or month for every trainer, group,
+// section or workout type and compares the totals with the period of the same
+// length right before fromDate.
+func (c *Club) GetStrainReport(groupBy string, bucket string, fromDate string, toDate string, format string,
+	section string, trainer string) (*dto.StrainReportResponse, error) {
+	if groupBy == "" {
+		groupBy = "trainer"
+	}
+	if !dbqueries.IsStrainGrouping(groupBy) {
+		return nil, fmt.Errorf("unknown strain grouping %q", groupBy)
+	}
+	if bucket == "" {
+		bucket = "month"
+	}
+	if bucket != "week" && bucket != "month" {
+		return nil, fmt.Errorf("unknown strain bucket %q", bucket)
+	}
+	_, err := formatDuration(0, format)
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now()
+	if toDate != "" {
+		to, err = time.Parse("2006-01-02", toDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	from := to.AddDate(0, -3, 1)
+	if fromDate != "" {
+		from, err = time.Parse("2006-01-02", fromDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if to.Before(from) {
+		return nil, errors.New("to_date is before from_date")
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	previousTo := from.AddDate(0, 0, -1)
+	previousFrom := previousTo.AddDate(0, 0, 1-days)
+
+	sectionReady, err := parseOptionalInt4(section)
+	if err != nil {
+		return nil, err
+	}
+	trainerReady, err := parseOptionalInt4(trainer)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := c.repos.Workouts.GetStrainBuckets(context.Background(), groupBy, bucket,
+		from.Format("2006-01-02"), to.Format("2006-01-02"), sectionReady, trainerReady)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := c.repos.Workouts.GetStrainBuckets(context.Background(), groupBy, bucket,
+		previousFrom.Format("2006-01-02"), previousTo.Format("2006-01-02"), sectionReady, trainerReady)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []strainKey
+	buckets := make(map[strainKey][]dto.StrainBucket)
+	totals := make(map[strainKey]int64)
+	previousTotals := make(map[strainKey]int64)
+	for _, b := range current {
+		key := strainKey{b.Key, b.Label}
+		if _, ok := buckets[key]; !ok {
+			keys = append(keys, key)
+		}
+		us := model.IntervalMicroseconds(b.Duration)
+		var jsonBucket dto.StrainBucket
+		jsonBucket.Bucket = b.Bucket.Time.Format("2006-01-02")
+		jsonBucket.Duration, _ = formatDuration(us, format)
+		buckets[key] = append(buckets[key], jsonBucket)
+		totals[key] += us
+	}
+	for _, b := range previous {
+		key := strainKey{b.Key, b.Label}
+		if _, ok := buckets[key]; !ok {
+			keys = append(keys, key)
+			buckets[key] = []dto.StrainBucket{}
+		}
+		previousTotals[key] += model.IntervalMicroseconds(b.Duration)
+	}
+
+	var response dto.StrainReportResponse
+	response.GroupBy = groupBy
+	response.Bucket = bucket
+	response.FromDate = from.Format("2006-01-02")
+	response.ToDate = to.Format("2006-01-02")
+	response.PreviousFrom = previousFrom.Format("2006-01-02")
+	response.PreviousTo = previousTo.Format("2006-01-02")
+
+	for _, key := range keys {
+		var entry dto.StrainReportEntry
+		entry.Key = key.key
+		entry.Label = key.label
+		entry.Buckets = buckets[key]
+		entry.Total, _ = formatDuration(totals[key], format)
+		entry.Previous, _ = formatDuration(previousTotals[key], format)
+		if previousTotals[key] > 0 {
+			change := float64(totals[key]-previousTotals[key]) / float64(previousTotals[key]) * 100
+			change = math.Round(change*10) / 10
+			entry.ChangePercent = &change
+		}
+		response.Entries = append(response.Entries, entry)
+	}
+	return &response, nil
+}
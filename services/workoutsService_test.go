@@ -0,0 +1,124 @@
+package services
+
+import (
+	"db_backend/model"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+	"testing"
+	"time"
+)
+
+func clock(value string) pgtype.Time {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		panic(err)
+	}
+	return pgtype.Time{Microseconds: int64(t.Hour()*60+t.Minute()) * 60_000_000, Valid: true}
+}
+
+// useWorkouts adds general training for both groups of section A and climbing
+// for its first group, led by gleb, and general training in section B by dina.
+func useWorkouts(t *testing.T) *fixture {
+	c := useMemory(t)
+	m := c.memory
+	general := m.AddWorkoutDescription(c.gleb, "ОФП", c.groupA1, c.groupA2)
+	climbing := m.AddWorkoutDescription(c.gleb, "скалолазание", c.groupA1)
+	rafting := m.AddWorkoutDescription(c.dina, "ОФП", c.groupB)
+	session := func(description int, trainer int, day string, start string, finish string) {
+		m.AddWorkout(model.WorkoutSession{
+			Description: int32(description),
+			Trainer:     int32(trainer),
+			Date:        date(day),
+			StartTime:   clock(start),
+			FinishTime:  clock(finish),
+		})
+	}
+	session(general, c.gleb, "2025-08-25", "18:00", "19:00")
+	session(general, c.gleb, "2025-09-01", "18:00", "19:30")
+	session(climbing, c.gleb, "2025-09-03", "10:00", "12:00")
+	session(general, c.gleb, "2025-09-08", "18:00", "19:00")
+	session(rafting, c.dina, "2025-09-02", "18:00", "20:00")
+	return c
+}
+
+func TestGetStrainForTrainer(t *testing.T) {
+	t.Parallel()
+	c := useWorkouts(t)
+	response, err := c.GetStrainForTrainer(itoa(c.gleb), "2025-09-01", "2025-09-30", "minutes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]any{}
+	for _, strain := range response.StrainList {
+		got[strain.Strain] = strain.Duration
+	}
+	want := map[string]any{"ОФП": int64(150), "скалолазание": int64(120)}
+	if len(got) != len(want) || got["ОФП"] != want["ОФП"] || got["скалолазание"] != want["скалолазание"] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	response, err = c.GetStrainForTrainer(itoa(c.dina), "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.StrainList) != 1 || response.StrainList[0].Duration != "02:00:00" {
+		t.Errorf("got %v, want two hours of ОФП", response.StrainList)
+	}
+}
+
+func TestGetStrainReportByGroup(t *testing.T) {
+	t.Parallel()
+	c := useWorkouts(t)
+	response, err := c.GetStrainReport("group", "week", "2025-09-01", "2025-09-14", "minutes", "", itoa(c.gleb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.PreviousFrom != "2025-08-18" || response.PreviousTo != "2025-08-31" {
+		t.Errorf("got previous period %s..%s", response.PreviousFrom, response.PreviousTo)
+	}
+	if len(response.Entries) != 2 {
+		t.Fatalf("got %d entries, want one per group", len(response.Entries))
+	}
+
+	first, second := response.Entries[0], response.Entries[1]
+	if first.Key != int32(c.groupA1) || first.Total != int64(270) || first.Previous != int64(60) {
+		t.Errorf("first group: got %+v", first)
+	}
+	var buckets []string
+	for _, bucket := range first.Buckets {
+		buckets = append(buckets, bucket.Bucket)
+	}
+	if !slices.Equal(buckets, []string{"2025-09-01", "2025-09-08"}) {
+		t.Errorf("first group buckets: got %v", buckets)
+	}
+	if first.ChangePercent == nil || *first.ChangePercent != 350 {
+		t.Errorf("first group change: got %v, want 350", first.ChangePercent)
+	}
+	if second.Key != int32(c.groupA2) || second.Total != int64(150) {
+		t.Errorf("second group: got %+v", second)
+	}
+}
+
+func TestGetStrainReportBySection(t *testing.T) {
+	t.Parallel()
+	c := useWorkouts(t)
+	response, err := c.GetStrainReport("section", "month", "2025-09-01", "2025-09-30", "minutes", itoa(c.sectionB), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Entries) != 1 {
+		t.Fatalf("got %d entries, want only section B", len(response.Entries))
+	}
+	entry := response.Entries[0]
+	if entry.Key != int32(c.sectionB) || entry.Label != "Водный туризм" || entry.Total != int64(120) {
+		t.Errorf("got %+v", entry)
+	}
+	if entry.ChangePercent != nil {
+		t.Errorf("got change %v without a previous period", *entry.ChangePercent)
+	}
+
+	_, err = c.GetStrainReport("place", "", "", "", "", "", "")
+	if err == nil {
+		t.Error("expected an error for an unknown grouping")
+	}
+}
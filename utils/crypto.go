@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var gcm cipher.AEAD
+
+var ErrNoKey = errors.New("encryption key is not configured")
+
+// SetEncryptionKey derives the AES-256 key for field-level encryption from secret.
+func SetEncryptionKey(secret string) error {
+	if secret == "" {
+		gcm = nil
+		return nil
+	}
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return err
+	}
+	gcm, err = cipher.NewGCM(block)
+	return err
+}
+
+// fieldBinding is the additional authenticated data of an encrypted field, so a
+// value only opens for the same person and field it was sealed for.
+func fieldBinding(person int32, field string) []byte {
+	return []byte(field + ":" + strconv.Itoa(int(person)))
+}
+
+// Encrypt seals value with AES-GCM, bound to the person and field it belongs to;
+// the random nonce is prepended to the result. Empty values stay empty so that
+// missing fields are not stored as ciphertext.
+func Encrypt(value string, person int32, field string) ([]byte, error) {
+	if gcm == nil {
+		return nil, ErrNoKey
+	}
+	if value == "" {
+		return nil, nil
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, []byte(value), fieldBinding(person, field)), nil
+}
+
+func Decrypt(data []byte, person int32, field string) (string, error) {
+	if gcm == nil {
+		return "", ErrNoKey
+	}
+	if len(data) == 0 {
+		return "", nil
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("ciphertext is too short")
+	}
+	nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	value, err := gcm.Open(nil, nonce, sealed, fieldBinding(person, field))
+	if err != nil {
+		return "", fmt.Errorf("unable to decrypt value: %w", err)
+	}
+	return string(value), nil
+}